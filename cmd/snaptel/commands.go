@@ -101,13 +101,14 @@ var (
 			Subcommands: []cli.Command{
 				{
 					Name:   "load",
-					Usage:  "load <plugin_path> [--plugin-cert=<plugin_cert_path> --plugin-key=<plugin_key_path> --plugin-ca-certs=<ca_cert_paths>]",
+					Usage:  "load <plugin_path> [--plugin-cert=<plugin_cert_path> --plugin-key=<plugin_key_path> --plugin-ca-certs=<ca_cert_paths>] [--lifecycle=<lifecycle>]",
 					Action: loadPlugin,
 					Flags: []cli.Flag{
 						flPluginAsc,
 						flPluginCert,
 						flPluginKey,
 						flPluginCACerts,
+						flPluginLifecycle,
 					},
 				},
 				{
@@ -117,7 +118,7 @@ var (
 				},
 				{
 					Name:   "swap",
-					Usage:  "swap <load_plugin_path> <unload_plugin_type>:<unload_plugin_name>:<unload_plugin_version> or swap <load_plugin_path> -t <unload_plugin_type> -n <unload_plugin_name> -v <unload_plugin_version> [--plugin-cert=<plugin_cert_path> --plugin-key=<plugin_key_path> --plugin-ca-certs=<ca_cert_paths>]",
+					Usage:  "swap <load_plugin_path> <unload_plugin_type>:<unload_plugin_name>:<unload_plugin_version> or swap <load_plugin_path> -t <unload_plugin_type> -n <unload_plugin_name> -v <unload_plugin_version> [--plugin-cert=<plugin_cert_path> --plugin-key=<plugin_key_path> --plugin-ca-certs=<ca_cert_paths>] [--canary [--canary-fraction=<fraction>] [--canary-tasks=<task_ids>] [--canary-window=<duration>] [--canary-max-failure-rate=<rate>] [--canary-min-calls=<calls>]]",
 					Action: swapPlugins,
 					Flags: []cli.Flag{
 						flPluginAsc,
@@ -127,6 +128,12 @@ var (
 						flPluginCert,
 						flPluginKey,
 						flPluginCACerts,
+						flCanary,
+						flCanaryFraction,
+						flCanaryTasks,
+						flCanaryWindow,
+						flCanaryMaxFailureRate,
+						flCanaryMinCalls,
 					},
 				},
 				{
@@ -135,6 +142,47 @@ var (
 					Action: listPlugins,
 					Flags: []cli.Flag{
 						flRunning,
+						flVerbose,
+					},
+				},
+				{
+					Name:   "info",
+					Usage:  "info <plugin_type> <plugin_name> <plugin_version>",
+					Action: pluginInfo,
+				},
+				{
+					Name:   "test",
+					Usage:  "test <plugin_path> [--plugin-config=<json>] [--plugin-timeout=<duration>]",
+					Action: testPlugin,
+					Flags: []cli.Flag{
+						flPluginTestConfig,
+						flPluginTestTimeout,
+					},
+				},
+				{
+					Name: "repo",
+					Subcommands: []cli.Command{
+						{
+							Name:   "list",
+							Usage:  "list",
+							Action: listRepository,
+						},
+						{
+							Name:   "gc",
+							Usage:  "gc [--keep=<versions>]",
+							Action: gcRepository,
+							Flags: []cli.Flag{
+								flRepoKeep,
+							},
+						},
+						{
+							Name:   "export",
+							Usage:  "export <checksum> [--output=<path>]",
+							Action: exportPlugin,
+							Flags: []cli.Flag{
+								flRepoOutput,
+							},
+						},
 					},
 				},
 				{
@@ -23,6 +23,9 @@ import (
 	"time"
 
 	"github.com/urfave/cli"
+
+	"github.com/intelsdi-x/snap/control"
+	"github.com/intelsdi-x/snap/control/plugin/conformance"
 )
 
 var (
@@ -83,6 +86,55 @@ var (
 		Name:  "plugin-ca-certs, r",
 		Usage: "List of CA cert paths (directory/file) for plugin to verify TLS clients",
 	}
+	flPluginLifecycle = cli.StringFlag{
+		Name:  "lifecycle, l",
+		Usage: "The plugin lifecycle: lazy, eager[:instances] or on-demand[:idle-timeout]",
+	}
+	flCanary = cli.BoolFlag{
+		Name:  "canary",
+		Usage: "Route part of the tasks to the loaded plugin and roll back the swap if it fails",
+	}
+	flCanaryFraction = cli.Float64Flag{
+		Name:  "canary-fraction",
+		Usage: "The fraction of the tasks routed to the loaded plugin",
+		Value: control.DefaultCanaryFraction,
+	}
+	flCanaryTasks = cli.StringFlag{
+		Name:  "canary-tasks",
+		Usage: "Comma separated ids of the tasks routed to the loaded plugin",
+	}
+	flCanaryWindow = cli.DurationFlag{
+		Name:  "canary-window",
+		Usage: "The time during which the failure rate of the loaded plugin is watched",
+		Value: control.DefaultCanaryWindow,
+	}
+	flCanaryMaxFailureRate = cli.Float64Flag{
+		Name:  "canary-max-failure-rate",
+		Usage: "The failure rate of the loaded plugin above which the swap is rolled back",
+		Value: control.DefaultCanaryMaxFailureRate,
+	}
+	flCanaryMinCalls = cli.IntFlag{
+		Name:  "canary-min-calls",
+		Usage: "The number of calls the loaded plugin must serve for the swap to complete",
+		Value: control.DefaultCanaryMinCalls,
+	}
+	flRepoKeep = cli.IntFlag{
+		Name:  "keep, k",
+		Usage: "The number of unloaded versions of each plugin kept in the repository",
+	}
+	flRepoOutput = cli.StringFlag{
+		Name:  "output, o",
+		Usage: "The path the exported plugin is written to, defaults to its file name",
+	}
+	flPluginTestConfig = cli.StringFlag{
+		Name:  "plugin-config",
+		Usage: "JSON object of config values passed to the tested plugin, e.g. '{\"password\": \"secret\"}'",
+	}
+	flPluginTestTimeout = cli.DurationFlag{
+		Name:  "plugin-timeout",
+		Usage: "Timeout to be set on the start of the tested plugin and on each call made to it",
+		Value: conformance.DefaultTimeout,
+	}
 	flPluginType = cli.StringFlag{
 		Name:  "plugin-type, t",
 		Usage: "The plugin type",
@@ -20,15 +20,21 @@ limitations under the License.
 package main
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"text/tabwriter"
 	"time"
 
+	"github.com/intelsdi-x/snap/control/plugin/conformance"
+	"github.com/intelsdi-x/snap/core"
+	"github.com/intelsdi-x/snap/core/cdata"
 	"github.com/intelsdi-x/snap/mgmt/rest/v1"
 	"github.com/urfave/cli"
 )
@@ -49,6 +55,9 @@ func loadPlugin(ctx *cli.Context) error {
 	if paths, err = storeTLSPaths(ctx, paths); err != nil {
 		return err
 	}
+	if paths, err = storeLifecycle(ctx, paths); err != nil {
+		return err
+	}
 	r := pClient.LoadPlugin(paths)
 	if r.Err != nil {
 		if r.Err.Fields()["error"] != nil {
@@ -155,6 +164,10 @@ func swapPlugins(ctx *cli.Context) error {
 		return newUsageError("Plugin version must be greater than zero", ctx)
 	}
 
+	if ctx.Bool("canary") {
+		return canarySwapPlugins(ctx, paths, pType, pName, pVer)
+	}
+
 	r := pClient.SwapPlugin(paths, pType, pName, pVer)
 	if r.Err != nil {
 		return fmt.Errorf("Error swapping plugins:\n%v\n", r.Err.Error())
@@ -175,6 +188,56 @@ func swapPlugins(ctx *cli.Context) error {
 	return nil
 }
 
+// canarySwapPlugins loads the plugin alongside the plugin to unload and waits
+// for snapteld to complete or roll back the swap.
+func canarySwapPlugins(ctx *cli.Context, paths []string, pType, pName string, pVer int) error {
+	opts := core.CanaryOptions{
+		Window:         ctx.Duration("canary-window"),
+		MaxFailureRate: ctx.Float64("canary-max-failure-rate"),
+		MinCalls:       uint64(ctx.Int("canary-min-calls")),
+	}
+	if tasks := ctx.String("canary-tasks"); tasks != "" {
+		opts.Tasks = strings.Split(tasks, ",")
+	} else {
+		opts.Fraction = ctx.Float64("canary-fraction")
+	}
+
+	r := pClient.CanarySwapPlugin(paths, pType, pName, pVer, opts)
+	if r.Err != nil {
+		return fmt.Errorf("Error swapping plugins:\n%v\n", r.Err.Error())
+	}
+	lp := r.LoadedPlugins[0]
+	fmt.Println("Plugin loaded")
+	fmt.Printf("Name: %s\n", lp.Name)
+	fmt.Printf("Version: %d\n", lp.Version)
+	fmt.Printf("Type: %s\n", lp.Type)
+	fmt.Printf("Signed: %v\n", lp.Signed)
+	fmt.Printf("Loaded Time: %s\n\n", lp.LoadedTime().Format(timeFormat))
+	fmt.Printf("Canary swap started, watching failures for %s\n", opts.Window)
+
+	for {
+		time.Sleep(time.Second)
+		c := pClient.GetCanary(pType, pName, pVer)
+		if c.Err != nil {
+			return fmt.Errorf("Error getting canary swap:\n%v\n", c.Err.Error())
+		}
+		fmt.Printf("\r%-12s v%d: %d/%d failed  v%d: %d/%d failed",
+			c.State, c.From.Version, c.From.Failures, c.From.Calls, c.To.Version, c.To.Failures, c.To.Calls)
+		switch c.State {
+		case core.CanaryCompleted:
+			fmt.Printf("\n\nPlugin unloaded\nName: %s\nVersion: %d\nType: %s\n", c.Name, c.From.Version, c.Type)
+			return nil
+		case core.CanaryRolledBack:
+			if c.To.Calls < c.MinCalls {
+				return fmt.Errorf("\n\nCanary swap rolled back: version %d served %d calls (min %d)\n",
+					c.To.Version, c.To.Calls, c.MinCalls)
+			}
+			return fmt.Errorf("\n\nCanary swap rolled back: failure rate of version %d was %.2f (max %.2f)\n",
+				c.To.Version, c.To.FailureRate, c.MaxFailureRate)
+		}
+	}
+}
+
 func listPlugins(ctx *cli.Context) error {
 	plugins := pClient.GetPlugins(ctx.Bool("running"))
 	if plugins.Err != nil {
@@ -195,16 +258,188 @@ func listPlugins(ctx *cli.Context) error {
 			fmt.Println("No plugins found. Have you loaded a plugin?")
 			return nil
 		}
-		printFields(w, false, 0, "NAME", "VERSION", "TYPE", "SIGNED", "STATUS", "LOADED TIME")
-		for _, lp := range plugins.LoadedPlugins {
-			printFields(w, false, 0, lp.Name, lp.Version, lp.Type, lp.Signed, lp.Status, lp.LoadedTime().Format(timeFormat))
+		if ctx.Bool("verbose") {
+			printFields(w, false, 0, "NAME", "VERSION", "TYPE", "SIGNED", "STATUS", "LOADED TIME", "KEY ID", "ALGORITHM", "SIGNED BY")
+			for _, lp := range plugins.LoadedPlugins {
+				keyID, algorithm, signedBy := "", "", ""
+				if lp.Signer != nil {
+					keyID, algorithm, signedBy = lp.Signer.KeyID, lp.Signer.Algorithm, lp.Signer.Identity
+				}
+				printFields(w, false, 0, lp.Name, lp.Version, lp.Type, lp.Signed, lp.Status, lp.LoadedTime().Format(timeFormat), keyID, algorithm, signedBy)
+			}
+		} else {
+			printFields(w, false, 0, "NAME", "VERSION", "TYPE", "SIGNED", "STATUS", "LOADED TIME")
+			for _, lp := range plugins.LoadedPlugins {
+				printFields(w, false, 0, lp.Name, lp.Version, lp.Type, lp.Signed, lp.Status, lp.LoadedTime().Format(timeFormat))
+			}
+		}
+	}
+	w.Flush()
+
+	return nil
+}
+
+func pluginInfo(ctx *cli.Context) error {
+	pType := ctx.Args().Get(0)
+	pName := ctx.Args().Get(1)
+	pVerStr := ctx.Args().Get(2)
+
+	if pType == "" {
+		return newUsageError("Must provide plugin type", ctx)
+	}
+	if pName == "" {
+		return newUsageError("Must provide plugin name", ctx)
+	}
+	if pVerStr == "" {
+		return newUsageError("Must provide plugin version", ctx)
+	}
+
+	pVer, err := strconv.Atoi(pVerStr)
+	if err != nil {
+		return newUsageError("Can't convert version string to integer", ctx)
+	}
+	if pVer < 1 {
+		return newUsageError("Plugin version must be greater than zero", ctx)
+	}
+
+	r := pClient.GetPlugin(pType, pName, pVer)
+	if r.Err != nil {
+		return fmt.Errorf("Error getting plugin:\n%v\n", r.Err.Error())
+	}
+
+	p := r.ReturnedPlugin
+	fmt.Printf("Name: %s\n", p.Name)
+	fmt.Printf("Version: %d\n", p.Version)
+	fmt.Printf("Type: %s\n", p.Type)
+	fmt.Printf("Signed: %v\n", p.Signed)
+	if p.Signer != nil {
+		fmt.Printf("Signed By: %s %s %s\n", p.Signer.KeyID, p.Signer.Algorithm, p.Signer.Identity)
+	}
+	fmt.Printf("Status: %s\n", p.Status)
+	fmt.Printf("Loaded Time: %s\n", time.Unix(p.LoadedTimestamp, 0).Format(timeFormat))
+	md := p.Metadata
+	if md == nil {
+		return nil
+	}
+	printInfo := func(name, value string) {
+		if value != "" {
+			fmt.Printf("%s: %s\n", name, value)
+		}
+	}
+	printInfo("Description", md.Description)
+	printInfo("Maintainers", strings.Join(md.Maintainers, ", "))
+	printInfo("License", md.License)
+	printInfo("Homepage", md.Homepage)
+	printInfo("Tags", strings.Join(md.Tags, ", "))
+	printInfo("Minimum Snap Version", md.MinSnapVersion)
+	printInfo("Platforms", strings.Join(md.Platforms, ", "))
+	printInfo("Required Environment", strings.Join(md.RequiredEnv, ", "))
+	keys := make([]string, 0, len(md.Extra))
+	for k := range md.Extra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		printInfo(k, md.Extra[k])
+	}
+
+	return nil
+}
+
+func testPlugin(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return newUsageError("Incorrect usage:", ctx)
+	}
+	path := ctx.Args().First()
+	opts := conformance.Options{Timeout: ctx.Duration("plugin-timeout")}
+	if cfg := ctx.String("plugin-config"); cfg != "" {
+		node := cdata.NewNode()
+		if err := json.Unmarshal([]byte(cfg), node); err != nil {
+			return newUsageError(fmt.Sprintf("Invalid plugin config: %v", err), ctx)
 		}
+		opts.Config = node.Table()
+	}
+
+	r := conformance.Run(path, opts)
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	printFields(w, false, 0, "CHECK", "STATUS", "MESSAGE")
+	for _, res := range r.Results {
+		printFields(w, false, 0, res.Check, res.Status, res.Message)
+	}
+	w.Flush()
+	if !r.Passed() {
+		return fmt.Errorf("Plugin %s failed the conformance checks", path)
+	}
+	fmt.Printf("Plugin %s passed the conformance checks\n", path)
+	return nil
+}
+
+func listRepository(ctx *cli.Context) error {
+	repo := pClient.GetRepository()
+	if repo.Err != nil {
+		return fmt.Errorf("Error: %v\n", repo.Err)
+	}
+	if len(repo.Plugins) == 0 {
+		fmt.Println("No plugins found in repository. Have you loaded a plugin?")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	printFields(w, false, 0, "CHECKSUM", "NAME", "VERSION", "TYPE", "SIGNED", "SIZE", "LOADED", "STORED TIME")
+	for _, rp := range repo.Plugins {
+		printFields(w, false, 0, rp.CheckSum[:12], rp.Name, rp.Version, rp.Type, rp.Signed, rp.Size, rp.Loaded, rp.StoredTime().Format(timeFormat))
+	}
+	w.Flush()
+
+	return nil
+}
+
+func gcRepository(ctx *cli.Context) error {
+	keep := ctx.Int("keep")
+	if keep < 0 {
+		return newUsageError("Must provide a non negative number of versions to keep", ctx)
+	}
+	repo := pClient.GCRepository(keep)
+	if repo.Err != nil {
+		return fmt.Errorf("Error removing plugins from repository:\n%v\n", repo.Err)
+	}
+	if len(repo.Plugins) == 0 {
+		fmt.Println("No plugins removed from repository")
+		return nil
+	}
+	fmt.Println("Plugins removed from repository")
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	printFields(w, false, 0, "CHECKSUM", "NAME", "VERSION", "TYPE", "SIZE")
+	for _, rp := range repo.Plugins {
+		printFields(w, false, 0, rp.CheckSum[:12], rp.Name, rp.Version, rp.Type, rp.Size)
 	}
 	w.Flush()
 
 	return nil
 }
 
+func exportPlugin(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return newUsageError("Incorrect usage", ctx)
+	}
+	var b bytes.Buffer
+	fileName, err := pClient.ExportPlugin(ctx.Args().First(), &b)
+	if err != nil {
+		return fmt.Errorf("Error exporting plugin:\n%v\n", err)
+	}
+	output := ctx.String("output")
+	if output == "" {
+		output = filepath.Base(fileName)
+	}
+	if err := ioutil.WriteFile(output, b.Bytes(), 0755); err != nil {
+		return fmt.Errorf("Error exporting plugin:\n%v\n", err)
+	}
+	fmt.Println("Plugin exported")
+	fmt.Printf("Path: %s\n", output)
+	fmt.Printf("Size: %d\n", b.Len())
+
+	return nil
+}
+
 // storeTLSPaths extracts paths related to TLS (certificate, key, plugin CA certs)
 // from command line context into temporary files. Those files are appended to
 // list of paths returned from this function.
@@ -250,3 +485,20 @@ func storeTLSPaths(ctx *cli.Context, paths []string) ([]string, error) {
 	}
 	return paths, nil
 }
+
+func storeLifecycle(ctx *cli.Context, paths []string) ([]string, error) {
+	lifecycle := ctx.String("lifecycle")
+	if lifecycle == "" {
+		return paths, nil
+	}
+	tmpFile, err := ioutil.TempFile("", v1.LifecyclePrefix)
+	if err != nil {
+		return paths, fmt.Errorf("Error processing plugin lifecycle - unable to create link:\n%v", err.Error())
+	}
+	defer tmpFile.Close()
+	_, err = tmpFile.WriteString(lifecycle)
+	if err != nil {
+		return paths, fmt.Errorf("Error processing plugin lifecycle - unable to write link:\n%v", err.Error())
+	}
+	return append(paths, tmpFile.Name()), nil
+}
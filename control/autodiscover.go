@@ -0,0 +1,114 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package control
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/intelsdi-x/snap/core"
+	"github.com/intelsdi-x/snap/pkg/dirwatch"
+)
+
+// watchAutodiscoverPath loads the plugins added to an auto discover path
+// after the start of control.  A plugin replaces the older versions of the
+// same plugin, unless they are still needed by tasks.
+func (p *pluginControl) watchAutodiscoverPath(fullPath string) {
+	w, err := dirwatch.Watch(fullPath, dirwatch.DefaultSettleDelay, func(e dirwatch.Event) {
+		p.handleAutodiscoverEvent(fullPath, e)
+	})
+	if err != nil {
+		controlLogger.WithFields(log.Fields{
+			"_block":           "watch-autodiscover-path",
+			"autodiscoverpath": fullPath,
+		}).Error(err)
+		return
+	}
+	p.watchers = append(p.watchers, w)
+	controlLogger.WithFields(log.Fields{
+		"_block": "watch-autodiscover-path",
+	}).Info("watching plugins in: ", fullPath)
+}
+
+func (p *pluginControl) handleAutodiscoverEvent(fullPath string, e dirwatch.Event) {
+	fileName := filepath.Base(e.Path)
+	f := log.Fields{
+		"_block":           "handle-autodiscover-event",
+		"autodiscoverpath": fullPath,
+		"plugin":           fileName,
+	}
+	// tasks files are handled by the scheduler
+	fname := strings.ToLower(fileName)
+	if strings.HasSuffix(fname, ".json") || strings.HasSuffix(fname, ".yaml") || strings.HasSuffix(fname, ".yml") {
+		return
+	}
+	if e.Op == dirwatch.Removed {
+		if !strings.HasSuffix(fileName, ".asc") {
+			controlLogger.WithFields(f).Info("plugin file removed, the loaded plugin is kept")
+		}
+		return
+	}
+	// a signature written after its plugin loads the plugin
+	if strings.HasSuffix(fileName, ".asc") {
+		fileName = strings.TrimSuffix(fileName, ".asc")
+	}
+	file, err := os.Lstat(filepath.Join(fullPath, fileName))
+	if err != nil {
+		return
+	}
+	pl := p.autoLoadPlugin(fullPath, fullPath, file)
+	if pl == nil {
+		return
+	}
+	p.unloadOlderVersions(pl)
+}
+
+// unloadOlderVersions unloads the versions of a plugin older than the given
+// one, moving the tasks using them to the given one.  The versions still
+// needed by tasks are kept.
+func (p *pluginControl) unloadOlderVersions(pl core.CatalogedPlugin) {
+	var older []core.CatalogedPlugin
+	for _, lp := range p.PluginCatalog() {
+		if lp.TypeName() == pl.TypeName() && lp.Name() == pl.Name() && lp.Version() < pl.Version() {
+			older = append(older, lp)
+		}
+	}
+	for _, lp := range older {
+		f := log.Fields{
+			"_block":               "unload-older-versions",
+			"plugin-name":          pl.Name(),
+			"plugin-type":          pl.TypeName(),
+			"plugin-version":       pl.Version(),
+			"older-plugin-version": lp.Version(),
+		}
+		if p.canaries.running(lp.TypeName(), lp.Name()) != nil {
+			controlLogger.WithFields(f).Warn("staged swap in progress, older version kept")
+			continue
+		}
+		if _, err := p.Unload(lp); err != nil {
+			controlLogger.WithFields(f).Warn("older version kept: ", err)
+			continue
+		}
+		controlLogger.WithFields(f).Info("older version unloaded")
+	}
+}
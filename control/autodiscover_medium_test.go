@@ -0,0 +1,89 @@
+// +build medium
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package control
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/intelsdi-x/snap/control/fixtures"
+	"github.com/intelsdi-x/snap/pkg/dirwatch"
+)
+
+// catalogedVersions waits for the versions of the collector mock in the
+// catalog to be want and returns them, or what they are after the timeout.
+func catalogedVersions(c *pluginControl, want []int, timeout time.Duration) []int {
+	deadline := time.Now().Add(timeout)
+	for {
+		var versions []int
+		for _, lp := range c.PluginCatalog() {
+			if lp.TypeName() == "collector" && lp.Name() == "mock" {
+				versions = append(versions, lp.Version())
+			}
+		}
+		if reflect.DeepEqual(versions, want) || time.Now().After(deadline) {
+			return versions
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func TestAutodiscoverWatch(t *testing.T) {
+	Convey("Given control watching an auto discover path", t, func() {
+		delay := dirwatch.DefaultSettleDelay
+		dirwatch.DefaultSettleDelay = 100 * time.Millisecond
+		defer func() { dirwatch.DefaultSettleDelay = delay }()
+
+		dir, err := ioutil.TempDir("", "snap-autodiscover-")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+
+		l, _ := net.Listen("tcp", ":0")
+		l.Close()
+		cfg := GetDefaultConfig()
+		cfg.ListenPort = l.Addr().(*net.TCPAddr).Port
+		cfg.PluginTrust = PluginTrustDisabled
+		cfg.AutoDiscoverPath = dir
+		cfg.AutoDiscoverWatch = true
+		c := New(cfg)
+		So(c.Start(), ShouldBeNil)
+		defer c.Stop()
+		So(catalogedVersions(c, nil, 0), ShouldBeEmpty)
+
+		Convey("a plugin dropped into the path is loaded", func() {
+			So(os.Symlink(fixtures.PluginPathMock1, filepath.Join(dir, fixtures.PluginNameMock1)), ShouldBeNil)
+			So(catalogedVersions(c, []int{1}, 10*time.Second), ShouldResemble, []int{1})
+
+			Convey("and unloaded once a newer version is dropped into the path", func() {
+				So(os.Symlink(fixtures.PluginPathMock2, filepath.Join(dir, fixtures.PluginNameMock2)), ShouldBeNil)
+				So(catalogedVersions(c, []int{2}, 10*time.Second), ShouldResemble, []int{2})
+			})
+		})
+	})
+}
@@ -0,0 +1,160 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package control
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/intelsdi-x/snap/control/strategy"
+)
+
+const (
+	// AutoscalerStopped - enum representation of autoscaler stopped state
+	AutoscalerStopped autoscalerState = iota - 1 // default is stopped
+	// AutoscalerStarted - enum representation of autoscaler started state
+	AutoscalerStarted
+
+	// DefaultAutoscaleInterval - the default interval between two evaluations
+	// of the load of the pools.
+	DefaultAutoscaleInterval = time.Second * 5
+)
+
+type autoscalerState int
+
+// autoscaler grows the pools which are overloaded or run less than their
+// warm instances, and reaps the idle instances of the pools.
+type autoscaler struct {
+	State autoscalerState
+
+	interval time.Duration
+	quit     chan struct{}
+}
+
+type autoscalerOption func(a *autoscaler) autoscalerOption
+
+// Option sets the options specified.
+// Returns an option to optionally restore the last arg's previous value.
+func (a *autoscaler) Option(opts ...autoscalerOption) autoscalerOption {
+	var previous autoscalerOption
+	for _, opt := range opts {
+		previous = opt(a)
+	}
+	return previous
+}
+
+// AutoscaleIntervalOption sets autoscaler's interval to v.
+func AutoscaleIntervalOption(v time.Duration) autoscalerOption {
+	return func(a *autoscaler) autoscalerOption {
+		previous := a.interval
+		a.interval = v
+		return AutoscaleIntervalOption(previous)
+	}
+}
+
+func newAutoscaler(opts ...autoscalerOption) *autoscaler {
+	a := &autoscaler{
+		State:    AutoscalerStopped,
+		interval: DefaultAutoscaleInterval,
+	}
+	//set options
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Start starts the autoscaler
+func (a *autoscaler) Start(r *runner) {
+	ticker := time.NewTicker(a.interval)
+	a.quit = make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				a.scale(r, time.Now())
+			case <-a.quit:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	a.State = AutoscalerStarted
+}
+
+// Stop stops the autoscaler
+func (a *autoscaler) Stop() {
+	if a.State != AutoscalerStarted {
+		return
+	}
+	close(a.quit)
+	a.State = AutoscalerStopped
+}
+
+// scale adds an instance to each overloaded pool and reaps the instances
+// idle at the given time.
+func (a *autoscaler) scale(r *runner, now time.Time) {
+	pools := map[string]strategy.Pool{}
+	r.availablePlugins.RLock()
+	for key, pool := range r.availablePlugins.table {
+		pools[key] = pool
+	}
+	r.availablePlugins.RUnlock()
+
+	for key, pool := range pools {
+		// remote plugins are not started by snapteld
+		if isRemotePool(pool) {
+			continue
+		}
+		if pool.Cold() || pool.Overloaded() {
+			runnerLog.WithFields(log.Fields{
+				"_block":    "autoscale",
+				"pool":      key,
+				"instances": pool.Count(),
+			}).Info("starting a new instance")
+			if err := r.restartPlugin(key); err != nil {
+				runnerLog.WithFields(log.Fields{
+					"_block": "autoscale",
+					"pool":   key,
+				}).Error(err)
+			}
+			continue
+		}
+		if n := pool.Reap(now, "idle"); n > 0 {
+			runnerLog.WithFields(log.Fields{
+				"_block": "autoscale",
+				"pool":   key,
+				"reaped": n,
+			}).Info("reaped idle instances")
+		}
+	}
+}
+
+func isRemotePool(pool strategy.Pool) bool {
+	pool.RLock()
+	defer pool.RUnlock()
+	for _, ap := range pool.Plugins() {
+		if ap.IsRemote() {
+			return true
+		}
+	}
+	return false
+}
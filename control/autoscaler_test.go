@@ -0,0 +1,93 @@
+// +build legacy
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package control
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vrischmann/jsonutil"
+
+	"github.com/intelsdi-x/snap/control/strategy"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAutoscaler(t *testing.T) {
+	Convey("autoscaler", t, func() {
+		Convey("newAutoscaler", func() {
+			a := newAutoscaler(AutoscaleIntervalOption(time.Millisecond * 123))
+			So(a, ShouldHaveSameTypeAs, &autoscaler{})
+			So(a.interval, ShouldResemble, time.Millisecond*123)
+			So(a.State, ShouldEqual, AutoscalerStopped)
+		})
+		Convey("start and stop", func() {
+			r := newRunner()
+			a := newAutoscaler(AutoscaleIntervalOption(time.Millisecond * 10))
+			a.Start(r)
+			So(a.State, ShouldEqual, AutoscalerStarted)
+			time.Sleep(50 * time.Millisecond)
+			a.Stop()
+			So(a.State, ShouldEqual, AutoscalerStopped)
+			// stopping a stopped autoscaler is a no-op
+			a.Stop()
+			So(a.State, ShouldEqual, AutoscalerStopped)
+		})
+		Convey("override AutoscaleInterval", func() {
+			a := newAutoscaler()
+			oldOpt := a.Option(AutoscaleIntervalOption(time.Millisecond * 200))
+			So(a.interval, ShouldResemble, time.Millisecond*200)
+			a.Option(oldOpt)
+			So(a.interval, ShouldResemble, DefaultAutoscaleInterval)
+		})
+	})
+}
+
+func TestAutoscaleOpt(t *testing.T) {
+	Convey("Given an autoscale config", t, func() {
+		defer func(e bool, d strategy.AutoscalePolicy, p map[string]strategy.AutoscalePolicy) {
+			strategy.AutoscalingEnabled, strategy.DefaultAutoscalePolicy, strategy.AutoscalePolicies = e, d, p
+		}(strategy.AutoscalingEnabled, strategy.DefaultAutoscalePolicy, strategy.AutoscalePolicies)
+		cfg := newAutoscaleConfig()
+		cfg.LatencyThreshold = jsonutil.Duration{time.Second}
+		cfg.InFlightThreshold = 4
+		cfg.Plugins["collector:mock"] = &autoscalePluginConfig{MinRunningPlugins: 2, MaxRunningPlugins: 6}
+		c := &pluginControl{pluginRunner: newRunner()}
+		Convey("Autoscaling is disabled by default", func() {
+			Autoscale(cfg)(c)
+			So(strategy.AutoscalingEnabled, ShouldBeFalse)
+			So(c.pluginRunner.Autoscaler().State, ShouldEqual, AutoscalerStopped)
+		})
+		Convey("Enabling autoscaling sets the policies and starts the autoscaler", func() {
+			cfg.Enable = true
+			Autoscale(cfg)(c)
+			defer c.pluginRunner.Autoscaler().Stop()
+			So(strategy.AutoscalingEnabled, ShouldBeTrue)
+			So(strategy.DefaultAutoscalePolicy.LatencyThreshold, ShouldEqual, time.Second)
+			So(strategy.DefaultAutoscalePolicy.InFlightThreshold, ShouldEqual, 4)
+			So(strategy.DefaultAutoscalePolicy.IdleTimeout, ShouldEqual, defaultIdleTimeout)
+			So(strategy.AutoscalePolicies["collector:mock"].Min, ShouldEqual, 2)
+			So(strategy.AutoscalePolicies["collector:mock"].Max, ShouldEqual, 6)
+			So(c.pluginRunner.Autoscaler().State, ShouldEqual, AutoscalerStarted)
+		})
+	})
+}
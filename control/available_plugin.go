@@ -30,15 +30,18 @@ import (
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
 
 	"github.com/intelsdi-x/gomit"
 	"github.com/intelsdi-x/snap/control/plugin"
 	"github.com/intelsdi-x/snap/control/plugin/client"
+	"github.com/intelsdi-x/snap/control/plugin/nativeshim"
 	"github.com/intelsdi-x/snap/control/strategy"
 	"github.com/intelsdi-x/snap/core"
 	"github.com/intelsdi-x/snap/core/control_event"
 	"github.com/intelsdi-x/snap/core/ctypes"
 	"github.com/intelsdi-x/snap/core/serror"
+	"github.com/intelsdi-x/snap/pkg/rpcutil"
 )
 
 const (
@@ -57,6 +60,17 @@ var (
 	ErrMsgInsecureClient = "insecure framework can't connect to secure plugin"
 )
 
+// pluginChannels holds the settings of the gRPC channels to the plugins.
+type pluginChannels struct {
+	// compressors in order of preference
+	compression      []string
+	maxMessageSize   int
+	collectChunkSize int
+	// hands out the Unix sockets the plugins and the shims of native plugins
+	// listen on
+	sockets *pluginSockets
+}
+
 // availablePlugin represents a plugin which is
 // running and available to respond to requests
 type availablePlugin struct {
@@ -77,18 +91,25 @@ type availablePlugin struct {
 	fromPackage        bool
 	pprofPort          string
 	isRemote           bool
+	// Unix socket the plugin manager handed out to the plugin in its socket
+	// directory, removed once the plugin is stopped
+	socketPath string
+	// version of the plugin protocol agreed on with the plugin
+	protocolVersion int
+	// shim serving the plugin over gRPC when it speaks native RPC
+	shim *nativeshim.Shim
 }
 
 // newAvailablePlugin returns an availablePlugin with information from a
 // plugin.Response
-func newAvailablePlugin(resp plugin.Response, emitter gomit.Emitter, ep executablePlugin, security client.GRPCSecurity) (*availablePlugin, error) {
+func newAvailablePlugin(resp plugin.Response, emitter gomit.Emitter, ep executablePlugin, security client.GRPCSecurity, channels pluginChannels) (*availablePlugin, error) {
 	if security.TLSEnabled && !resp.Meta.TLSEnabled {
 		return nil, errors.New(ErrMsgInsecurePlugin + "; plugin_name: " + resp.Meta.Name)
 	}
 	if !security.TLSEnabled && resp.Meta.TLSEnabled {
 		return nil, errors.New(ErrMsgInsecureClient + "; plugin_name: " + resp.Meta.Name)
 	}
-	if resp.Type != plugin.CollectorPluginType && resp.Type != plugin.ProcessorPluginType && resp.Type != plugin.PublisherPluginType && resp.Type != plugin.StreamCollectorPluginType {
+	if resp.Type != plugin.CollectorPluginType && resp.Type != plugin.ProcessorPluginType && resp.Type != plugin.PublisherPluginType && resp.Type != plugin.StreamCollectorPluginType && resp.Type != plugin.StreamPublisherPluginType {
 		return nil, strategy.ErrBadType
 	}
 	ap := &availablePlugin{
@@ -103,25 +124,56 @@ func newAvailablePlugin(resp plugin.Response, emitter gomit.Emitter, ep executab
 		pprofPort:   resp.PprofAddress,
 		isRemote:    false,
 	}
+	ap.protocolVersion = negotiateProtocolVersion(resp.Meta)
+	if ep != nil && strings.HasPrefix(resp.ListenAddress, plugin.UnixSocketScheme) {
+		// only the socket the plugin manager handed out, which the plugin
+		// was checked to listen on, is removed with the plugin
+		ap.socketPath = ep.ListenSocket()
+	}
 	ap.key = fmt.Sprintf("%s"+core.Separator+"%s"+core.Separator+"%d", ap.pluginType.String(), ap.name, ap.version)
 
+	channel := channels.channel(ap)
+
+	// plugins speaking the deprecated native RPC protocol are reached through
+	// a shim serving them over gRPC
+	if resp.Meta.RPCType == plugin.NativeRPC {
+		log.WithFields(log.Fields{
+			"_module":     "control-aplugin",
+			"_block":      "newAvailablePlugin",
+			"plugin_name": ap.name,
+		}).Warning("This plugin is using a deprecated RPC protocol. Find more information here: https://github.com/intelsdi-x/snap/issues/1289 ")
+		// the shim serves without TLS, so only on a Unix socket in a
+		// directory snapteld's user alone may access
+		if channels.sockets == nil {
+			return nil, errors.New("error while creating client connection: no socket for the native plugin shim")
+		}
+		socket, err := channels.sockets.next()
+		if err != nil {
+			return nil, errors.New("error while creating client connection: " + err.Error())
+		}
+		shim, err := nativeshim.Start(resp, DefaultClientTimeout, socket)
+		if err != nil {
+			return nil, errors.New("error while creating client connection: " + err.Error())
+		}
+		ap.shim = shim
+		defer func() {
+			// the shim is not needed when no client could be created
+			if ap.client == nil {
+				shim.Stop()
+			}
+		}()
+		resp.Meta.RPCType = plugin.GRPC
+		resp.ListenAddress = shim.Address()
+		security = client.SecurityTLSOff()
+		channel = client.GRPCChannel{MaxMessageSize: channels.maxMessageSize, NativeTypes: true}
+	}
+
 	// Create RPC Client
 	switch resp.Type {
 	case plugin.CollectorPluginType:
 		switch resp.Meta.RPCType {
-		case plugin.NativeRPC:
-			log.WithFields(log.Fields{
-				"_module":     "control-aplugin",
-				"_block":      "newAvailablePlugin",
-				"plugin_name": ap.name,
-			}).Warning("This plugin is using a deprecated RPC protocol. Find more information here: https://github.com/intelsdi-x/snap/issues/1289 ")
-			c, e := client.NewCollectorNativeClient(resp.ListenAddress, DefaultClientTimeout, resp.PublicKey, !resp.Meta.Unsecure)
-			if e != nil {
-				return nil, errors.New("error while creating client connection: " + e.Error())
-			}
-			ap.client = c
 		case plugin.GRPC:
-			c, e := client.NewCollectorGrpcClient(resp.ListenAddress, DefaultClientTimeout, security)
+			c, e := client.NewCollectorGrpcClient(resp.ListenAddress, DefaultClientTimeout, security, channel)
 			if e != nil {
 				return nil, errors.New("error while creating client connection: " + e.Error())
 			}
@@ -130,7 +182,7 @@ func newAvailablePlugin(resp plugin.Response, emitter gomit.Emitter, ep executab
 			c, e := client.NewStreamCollectorGrpcClient(
 				resp.ListenAddress,
 				DefaultClientTimeout,
-				security)
+				security, channel)
 			if e != nil {
 				return nil, errors.New("error while creating client connection: " + e.Error())
 			}
@@ -140,14 +192,8 @@ func newAvailablePlugin(resp plugin.Response, emitter gomit.Emitter, ep executab
 		}
 	case plugin.PublisherPluginType:
 		switch resp.Meta.RPCType {
-		case plugin.NativeRPC:
-			c, e := client.NewPublisherNativeClient(resp.ListenAddress, DefaultClientTimeout, resp.PublicKey, !resp.Meta.Unsecure)
-			if e != nil {
-				return nil, errors.New("error while creating client connection: " + e.Error())
-			}
-			ap.client = c
 		case plugin.GRPC:
-			c, e := client.NewPublisherGrpcClient(resp.ListenAddress, DefaultClientTimeout, security)
+			c, e := client.NewPublisherGrpcClient(resp.ListenAddress, DefaultClientTimeout, security, channel)
 			if e != nil {
 				return nil, errors.New("error while creating client connection: " + e.Error())
 			}
@@ -157,14 +203,22 @@ func newAvailablePlugin(resp plugin.Response, emitter gomit.Emitter, ep executab
 		}
 	case plugin.ProcessorPluginType:
 		switch resp.Meta.RPCType {
-		case plugin.NativeRPC:
-			c, e := client.NewProcessorNativeClient(resp.ListenAddress, DefaultClientTimeout, resp.PublicKey, !resp.Meta.Unsecure)
+		case plugin.GRPC:
+			c, e := client.NewProcessorGrpcClient(resp.ListenAddress, DefaultClientTimeout, security, channel)
 			if e != nil {
 				return nil, errors.New("error while creating client connection: " + e.Error())
 			}
 			ap.client = c
-		case plugin.GRPC:
-			c, e := client.NewProcessorGrpcClient(resp.ListenAddress, DefaultClientTimeout, security)
+		default:
+			return nil, errors.New("Invalid RPCTYPE")
+		}
+	case plugin.StreamCollectorPluginType:
+		switch resp.Meta.RPCType {
+		case plugin.STREAMGRPC:
+			c, e := client.NewStreamCollectorGrpcClient(
+				resp.ListenAddress,
+				DefaultClientTimeout,
+				security, channel)
 			if e != nil {
 				return nil, errors.New("error while creating client connection: " + e.Error())
 			}
@@ -172,13 +226,13 @@ func newAvailablePlugin(resp plugin.Response, emitter gomit.Emitter, ep executab
 		default:
 			return nil, errors.New("Invalid RPCTYPE")
 		}
-	case plugin.StreamCollectorPluginType:
+	case plugin.StreamPublisherPluginType:
 		switch resp.Meta.RPCType {
 		case plugin.STREAMGRPC:
-			c, e := client.NewStreamCollectorGrpcClient(
+			c, e := client.NewStreamPublisherGrpcClient(
 				resp.ListenAddress,
 				DefaultClientTimeout,
-				security)
+				security, channel)
 			if e != nil {
 				return nil, errors.New("error while creating client connection: " + e.Error())
 			}
@@ -193,6 +247,65 @@ func newAvailablePlugin(resp plugin.Response, emitter gomit.Emitter, ep executab
 	return ap, nil
 }
 
+// negotiateProtocolVersion returns the version of the plugin protocol spoken
+// with a plugin, the older of the versions of snapteld and of the plugin.
+func negotiateProtocolVersion(meta plugin.PluginMeta) int {
+	if meta.ProtocolVersion > plugin.ProtocolVersion {
+		log.WithFields(log.Fields{
+			"_module":           "control-aplugin",
+			"_block":            "negotiate-protocol-version",
+			"plugin_name":       meta.Name,
+			"plugin_protocol":   meta.ProtocolVersion,
+			"snapteld_protocol": plugin.ProtocolVersion,
+		}).Info("plugin speaks a newer plugin protocol, falling back to the protocol of snapteld")
+	}
+	return plugin.NegotiateProtocolVersion(meta.ProtocolVersion)
+}
+
+// channel returns the settings of the gRPC channel to a plugin, its calls are
+// compressed with the first preferred compressor the plugin accepts and
+// collections are chunked when the plugin serves CollectMetricsChunked.
+func (c pluginChannels) channel(a *availablePlugin) client.GRPCChannel {
+	channel := client.GRPCChannel{
+		MaxMessageSize: c.maxMessageSize,
+	}
+	if a.supports(plugin.CapabilityCompression) {
+		channel.Compression = rpcutil.NegotiateCompression(c.compression, a.meta.Compression)
+	}
+	if a.supports(plugin.CapabilityChunkedCollect) {
+		channel.CollectChunkSize = c.collectChunkSize
+	}
+	return channel
+}
+
+// supports returns true when the plugin supports the optional feature c of
+// the plugin protocol in the version negotiated with it.
+func (a *availablePlugin) supports(c plugin.Capability) bool {
+	return plugin.HasCapability(a.protocolVersion, a.meta.Capabilities, c)
+}
+
+// callAbandoned logs a call to the plugin given up on since the task run it
+// was made for ended.  The call is cancelled on snap's side whatever the
+// plugin supports, a plugin without the cancellation capability keeps working
+// on it.
+func (a *availablePlugin) callAbandoned(ctx context.Context, call string) {
+	if ctx.Err() == nil || a.supports(plugin.CapabilityCancellation) {
+		return
+	}
+	log.WithFields(log.Fields{
+		"_block":      "call-abandoned",
+		"plugin_name": a.name,
+		"call":        call,
+		"error":       ctx.Err(),
+	}).Debug("call abandoned, the plugin does not support cancellation and may keep working on it")
+}
+
+// ProtocolVersion returns the version of the plugin protocol agreed on with
+// the plugin.
+func (a *availablePlugin) ProtocolVersion() int {
+	return a.protocolVersion
+}
+
 func (a *availablePlugin) Port() string {
 	return a.pprofPort
 }
@@ -267,6 +380,7 @@ func (a *availablePlugin) Stop(r string) error {
 	if a.IsRemote() {
 		return a.client.Close()
 	}
+	defer a.removeSocket()
 	return a.client.Kill(r)
 }
 
@@ -293,16 +407,41 @@ func (a *availablePlugin) Kill(r string) error {
 		c.Killed()
 	}
 
+	defer a.removeSocket()
+	if a.shim != nil {
+		a.shim.Stop()
+	}
 	if a.ePlugin != nil {
 		return a.ePlugin.Kill()
 	}
 	return nil
 }
 
+// removeSocket removes the Unix socket the plugin listened on, if any.
+func (a *availablePlugin) removeSocket() {
+	if a.socketPath == "" {
+		return
+	}
+	if err := os.Remove(a.socketPath); err != nil && !os.IsNotExist(err) {
+		log.WithFields(log.Fields{
+			"_module":     "control-aplugin",
+			"block":       "remove-socket",
+			"plugin_name": a,
+			"path":        a.socketPath,
+		}).Warn(err)
+	}
+}
+
 // CheckHealth checks the health of a plugin and updates
 // a.failedHealthChecks
 func (a *availablePlugin) CheckHealth() {
 	go func() {
+		// plugins with the health detail capability may report themselves
+		// unhealthy
+		if c, ok := a.client.(client.PluginHealthDetailClient); ok && a.supports(plugin.CapabilityHealthDetail) {
+			a.healthChan <- c.PingHealth()
+			return
+		}
 		a.healthChan <- a.client.Ping()
 	}()
 	select {
@@ -318,20 +457,21 @@ func (a *availablePlugin) CheckHealth() {
 			}
 			a.failedHealthChecks = 0
 		} else {
-			a.healthCheckFailed()
+			a.healthCheckFailed(err)
 		}
 	case <-time.After(DefaultHealthCheckTimeout):
-		a.healthCheckFailed()
+		a.healthCheckFailed(errors.New("health check timed out"))
 	}
 }
 
 // healthCheckFailed increments a.failedHealthChecks and emits a DisabledPluginEvent
 // and a HealthCheckFailedEvent
-func (a *availablePlugin) healthCheckFailed() {
+func (a *availablePlugin) healthCheckFailed(err error) {
 	log.WithFields(log.Fields{
 		"_module":     "control-aplugin",
 		"block":       "check-health",
 		"plugin_name": a,
+		"error":       err.Error(),
 	}).Warning("heartbeat missed")
 	a.failedHealthChecks++
 	if a.failedHealthChecks >= DefaultHealthCheckFailureLimit {
@@ -365,17 +505,22 @@ type availablePlugins struct {
 	// The Pools' primary keys are equal to
 	// {plugin_type}:{plugin_name}:{plugin_version}
 	table map[string]strategy.Pool
+	// streamResumes holds the position reached in the streams of the tasks
+	// from streaming collectors, keyed by {task_id}:{plugin_key}
+	streamResumesMutex sync.Mutex
+	streamResumes      map[string]*client.StreamResume
 }
 
 func newAvailablePlugins() *availablePlugins {
 	return &availablePlugins{
-		RWMutex: &sync.RWMutex{},
-		table:   make(map[string]strategy.Pool),
+		RWMutex:       &sync.RWMutex{},
+		table:         make(map[string]strategy.Pool),
+		streamResumes: make(map[string]*client.StreamResume),
 	}
 }
 
 func (ap *availablePlugins) insert(pl *availablePlugin) error {
-	if pl.pluginType != plugin.CollectorPluginType && pl.pluginType != plugin.ProcessorPluginType && pl.pluginType != plugin.PublisherPluginType && pl.pluginType != plugin.StreamCollectorPluginType {
+	if pl.pluginType != plugin.CollectorPluginType && pl.pluginType != plugin.ProcessorPluginType && pl.pluginType != plugin.PublisherPluginType && pl.pluginType != plugin.StreamCollectorPluginType && pl.pluginType != plugin.StreamPublisherPluginType {
 		return strategy.ErrBadType
 	}
 
@@ -427,7 +572,7 @@ func (ap *availablePlugins) getPool(key string) (strategy.Pool, serror.SnapError
 	return pool, nil
 }
 
-func (ap *availablePlugins) collectMetrics(pluginKey string, metricTypes []core.Metric, taskID string) ([]core.Metric, error) {
+func (ap *availablePlugins) collectMetrics(ctx context.Context, pluginKey string, metricTypes []core.Metric, taskID string, bypassCache bool) ([]core.Metric, error) {
 	var results []core.Metric
 	pool, serr := ap.getPool(pluginKey)
 	if serr != nil {
@@ -443,21 +588,26 @@ func (ap *availablePlugins) collectMetrics(pluginKey string, metricTypes []core.
 		return nil, errors.New("Plugin strategy not set")
 	}
 
-	metricsToCollect, metricsFromCache := pool.CheckCache(metricTypes, taskID)
-
-	if len(metricsToCollect) == 0 {
-		return metricsFromCache, nil
-	}
-
 	config := metricTypes[0].Config()
 	cfg := map[string]ctypes.ConfigValue{}
 	if config != nil {
 		cfg = config.Table()
 	}
 
+	// tasks requesting the same metrics with the same config share the cache
+	cacheID := pool.CacheID(taskID, cfg, metricTypes[0].Tags())
+	metricsToCollect, metricsFromCache := metricTypes, []core.Metric(nil)
+	if !bypassCache {
+		metricsToCollect, metricsFromCache = pool.CheckCache(metricTypes, cacheID)
+	}
+
+	if len(metricsToCollect) == 0 {
+		return metricsFromCache, nil
+	}
+
 	pool.RLock()
 	defer pool.RUnlock()
-	p, serr := pool.SelectAP(taskID, cfg)
+	p, serr := pool.SelectAP(taskID, cfg, metricTypes[0].Tags())
 	if serr != nil {
 		return nil, serr
 	}
@@ -469,12 +619,16 @@ func (ap *availablePlugins) collectMetrics(pluginKey string, metricTypes []core.
 	}
 
 	// collect metrics
-	metrics, err := cli.CollectMetrics(metricsToCollect)
+	pool.RequestStarted()
+	start := time.Now()
+	metrics, err := cli.CollectMetrics(ctx, metricsToCollect)
+	pool.RequestFinished(time.Since(start))
 	if err != nil {
+		p.(*availablePlugin).callAbandoned(ctx, "CollectMetrics")
 		return nil, serror.New(err)
 	}
 
-	pool.UpdateCache(metrics, taskID)
+	pool.UpdateCache(metricsToCollect, metrics, cacheID)
 
 	results = make([]core.Metric, len(metricsFromCache)+len(metrics))
 	idx := 0
@@ -494,12 +648,15 @@ func (ap *availablePlugins) collectMetrics(pluginKey string, metricTypes []core.
 	return results, nil
 }
 
+// streamMetrics opens a stream of the metrics of the task from the streaming
+// collector, which is closed once done is closed.
 func (ap *availablePlugins) streamMetrics(
 	pluginKey string,
 	metricTypes []core.Metric,
 	taskID string,
 	maxCollectDuration time.Duration,
-	maxMetricsBuffer int64) (chan []core.Metric, chan error, error) {
+	maxMetricsBuffer int64,
+	done <-chan struct{}) (chan []core.Metric, chan error, error) {
 
 	pool, serr := ap.getPool(pluginKey)
 	if serr != nil {
@@ -521,7 +678,7 @@ func (ap *availablePlugins) streamMetrics(
 
 	pool.RLock()
 	defer pool.RUnlock()
-	p, serr := pool.SelectAP(taskID, cfg)
+	p, serr := pool.SelectAP(taskID, cfg, metricTypes[0].Tags())
 	if serr != nil {
 		return nil, nil, serr
 	}
@@ -531,7 +688,7 @@ func (ap *availablePlugins) streamMetrics(
 		return nil, nil, serror.New(errors.New("Invalid streaming client"))
 	}
 
-	metricChan, errChan, err := cli.StreamMetrics(taskID, metricTypes)
+	metricChan, errChan, err := cli.StreamMetrics(taskID, metricTypes, ap.streamResume(taskID, pluginKey), done)
 	if err != nil {
 		return nil, nil, serror.New(err)
 	}
@@ -547,8 +704,34 @@ func (ap *availablePlugins) streamMetrics(
 	return metricChan, errChan, nil
 }
 
-func (ap *availablePlugins) publishMetrics(metrics []core.Metric, pluginName string, pluginVersion int, config map[string]ctypes.ConfigValue, taskID string) []error {
-	key := fmt.Sprintf("%s"+core.Separator+"%s"+core.Separator+"%d", plugin.PublisherPluginType.String(), pluginName, pluginVersion)
+// streamResume returns the position reached in the stream of the task from
+// the given streaming collector, which a new stream resumes from.
+func (ap *availablePlugins) streamResume(taskID, pluginKey string) *client.StreamResume {
+	ap.streamResumesMutex.Lock()
+	defer ap.streamResumesMutex.Unlock()
+	key := taskID + core.Separator + pluginKey
+	resume, ok := ap.streamResumes[key]
+	if !ok {
+		resume = &client.StreamResume{}
+		ap.streamResumes[key] = resume
+	}
+	return resume
+}
+
+// forgetStreamResumes drops the positions reached in the streams of the task,
+// the streams of the task start over the next time it is started.
+func (ap *availablePlugins) forgetStreamResumes(taskID string) {
+	ap.streamResumesMutex.Lock()
+	defer ap.streamResumesMutex.Unlock()
+	for key := range ap.streamResumes {
+		if strings.HasPrefix(key, taskID+core.Separator) {
+			delete(ap.streamResumes, key)
+		}
+	}
+}
+
+func (ap *availablePlugins) publishMetrics(ctx context.Context, metrics []core.Metric, pluginType plugin.PluginType, pluginName string, pluginVersion int, config map[string]ctypes.ConfigValue, taskID string) []error {
+	key := fmt.Sprintf("%s"+core.Separator+"%s"+core.Separator+"%d", pluginType.String(), pluginName, pluginVersion)
 	pool, serr := ap.getPool(key)
 	if serr != nil {
 		return []error{serr}
@@ -560,18 +743,43 @@ func (ap *availablePlugins) publishMetrics(metrics []core.Metric, pluginName str
 	pool.RLock()
 	defer pool.RUnlock()
 
-	p, serr := pool.SelectAP(taskID, config)
+	p, serr := pool.SelectAP(taskID, config, nil)
 	if serr != nil {
 		return []error{serr}
 	}
+	if !p.(*availablePlugin).supports(plugin.CapabilityHistograms) {
+		var skipped []core.Metric
+		if metrics, skipped = splitStructuredMetrics(metrics); len(skipped) > 0 {
+			log.WithFields(log.Fields{
+				"_block":     "publish-metrics",
+				"plugin-key": key,
+				"task-id":    taskID,
+				"skipped":    len(skipped),
+			}).Warn("plugin does not support histogram, summary and map values, skipping them")
+		}
+	}
 
-	cli, ok := p.(*availablePlugin).client.(client.PluginPublisherClient)
-	if !ok {
-		return []error{errors.New("unable to cast client to PluginPublisherClient")}
+	var publish func() error
+	if pluginType == plugin.StreamPublisherPluginType {
+		cli, ok := p.(*availablePlugin).client.(client.PluginStreamPublisherClient)
+		if !ok {
+			return []error{errors.New("unable to cast client to PluginStreamPublisherClient")}
+		}
+		publish = func() error { return cli.StreamPublish(ctx, taskID, metrics, config) }
+	} else {
+		cli, ok := p.(*availablePlugin).client.(client.PluginPublisherClient)
+		if !ok {
+			return []error{errors.New("unable to cast client to PluginPublisherClient")}
+		}
+		publish = func() error { return cli.Publish(ctx, metrics, config) }
 	}
 
-	err := cli.Publish(metrics, config)
+	pool.RequestStarted()
+	start := time.Now()
+	err := publish()
+	pool.RequestFinished(time.Since(start))
 	if err != nil {
+		p.(*availablePlugin).callAbandoned(ctx, "Publish")
 		return []error{err}
 	}
 	p.(*availablePlugin).hitCount++
@@ -579,7 +787,32 @@ func (ap *availablePlugins) publishMetrics(metrics []core.Metric, pluginName str
 	return nil
 }
 
-func (ap *availablePlugins) processMetrics(metrics []core.Metric, pluginName string, pluginVersion int, config map[string]ctypes.ConfigValue, taskID string) ([]core.Metric, []error) {
+// closePublishStreams closes the streams the running instances of a
+// streaming publisher keep open for the task.
+func (ap *availablePlugins) closePublishStreams(pluginKey, taskID string) {
+	pool, serr := ap.getPool(pluginKey)
+	if serr != nil || pool == nil {
+		return
+	}
+	pool.RLock()
+	defer pool.RUnlock()
+	for _, p := range pool.Plugins() {
+		cli, ok := p.(*availablePlugin).client.(client.PluginStreamPublisherClient)
+		if !ok {
+			continue
+		}
+		if err := cli.ClosePublishStream(taskID); err != nil {
+			log.WithFields(log.Fields{
+				"_block":     "close-publish-streams",
+				"plugin-key": pluginKey,
+				"task-id":    taskID,
+				"error":      err,
+			}).Warn("error closing publisher stream")
+		}
+	}
+}
+
+func (ap *availablePlugins) processMetrics(ctx context.Context, metrics []core.Metric, pluginName string, pluginVersion int, config map[string]ctypes.ConfigValue, taskID string) ([]core.Metric, []error) {
 	var errs []error
 	key := fmt.Sprintf("%s"+core.Separator+"%s"+core.Separator+"%d", plugin.ProcessorPluginType.String(), pluginName, pluginVersion)
 	pool, serr := ap.getPool(key)
@@ -593,7 +826,7 @@ func (ap *availablePlugins) processMetrics(metrics []core.Metric, pluginName str
 
 	pool.RLock()
 	defer pool.RUnlock()
-	p, err := pool.SelectAP(taskID, config)
+	p, err := pool.SelectAP(taskID, config, nil)
 	if err != nil {
 		errs = append(errs, err)
 		return nil, errs
@@ -603,14 +836,41 @@ func (ap *availablePlugins) processMetrics(metrics []core.Metric, pluginName str
 	if !ok {
 		return nil, []error{errors.New("unable to cast client to PluginProcessorClient")}
 	}
+	// metrics the processor can not decode are passed on as they are
+	var skipped []core.Metric
+	if !p.(*availablePlugin).supports(plugin.CapabilityHistograms) {
+		metrics, skipped = splitStructuredMetrics(metrics)
+	}
 
-	mts, errp := cli.Process(metrics, config)
+	pool.RequestStarted()
+	start := time.Now()
+	mts, errp := cli.Process(ctx, metrics, config)
+	pool.RequestFinished(time.Since(start))
 	if errp != nil {
+		p.(*availablePlugin).callAbandoned(ctx, "Process")
 		return nil, []error{errp}
 	}
 	p.(*availablePlugin).hitCount++
 	p.(*availablePlugin).lastHitTime = time.Now()
-	return mts, nil
+	return append(mts, skipped...), nil
+}
+
+// splitStructuredMetrics splits the metrics carrying histogram, summary or
+// map values, which plugins without the histograms capability can not
+// decode, from the others.
+func splitStructuredMetrics(metrics []core.Metric) (scalar, structured []core.Metric) {
+	for _, mt := range metrics {
+		switch mt.Data().(type) {
+		case core.Histogram, *core.Histogram, core.Summary, *core.Summary, map[string]float64, map[string]string:
+			structured = append(structured, mt)
+		default:
+			scalar = append(scalar, mt)
+		}
+	}
+	if structured == nil {
+		return metrics, nil
+	}
+	return scalar, structured
 }
 
 func (ap *availablePlugins) findLatestPool(pType, name string) (strategy.Pool, serror.SnapError) {
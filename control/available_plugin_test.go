@@ -46,7 +46,9 @@ func TestAvailablePlugin(t *testing.T) {
 				Type:          plugin.CollectorPluginType,
 				ListenAddress: "127.0.0.1:4000",
 			}
-			ap, err := newAvailablePlugin(resp, nil, nil, client.SecurityTLSOff())
+			sockets := newPluginSockets("")
+			defer sockets.remove()
+			ap, err := newAvailablePlugin(resp, nil, nil, client.SecurityTLSOff(), pluginChannels{sockets: sockets})
 			So(ap, ShouldHaveSameTypeAs, new(availablePlugin))
 			So(err, ShouldBeNil)
 		})
@@ -113,7 +115,7 @@ func TestAvailablePlugins(t *testing.T) {
 			Type:          plugin.CollectorPluginType,
 			ListenAddress: "localhost:asdf",
 		}
-		ap, err := newAvailablePlugin(resp, nil, nil, client.SecurityTLSOff())
+		ap, err := newAvailablePlugin(resp, nil, nil, client.SecurityTLSOff(), pluginChannels{})
 		So(ap, ShouldBeNil)
 		So(err, ShouldNotBeNil)
 	})
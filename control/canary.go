@@ -0,0 +1,452 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package control
+
+import (
+	"errors"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/intelsdi-x/snap/core"
+	"github.com/intelsdi-x/snap/core/control_event"
+	"github.com/intelsdi-x/snap/core/serror"
+)
+
+const (
+	// DefaultCanaryFraction - the default fraction of the tasks routed to the
+	// new version of the plugin
+	DefaultCanaryFraction = 0.1
+	// DefaultCanaryWindow - the default time during which the failure rates
+	// are watched
+	DefaultCanaryWindow = 5 * time.Minute
+	// DefaultCanaryMaxFailureRate - the default failure rate of the new version
+	// above which the swap is rolled back
+	DefaultCanaryMaxFailureRate = 0.1
+	// DefaultCanaryMinCalls - the default number of calls the new version must
+	// serve for the swap to complete
+	DefaultCanaryMinCalls = 10
+)
+
+var (
+	// ErrCanaryInProgress - error message when a staged swap of the plugin is
+	// already running
+	ErrCanaryInProgress = errors.New("Canary swap already in progress for this plugin")
+	// ErrCanaryNotFound - error message when no staged swap of the plugin
+	// exists
+	ErrCanaryNotFound = errors.New("Canary swap not found")
+	// ErrBadCanaryOptions - error message when the options of a staged swap
+	// are invalid
+	ErrBadCanaryOptions = errors.New("Canary fraction and max failure rate must be between 0 and 1 and the window must be positive")
+)
+
+// canary routes a fraction of the tasks to the new version of a plugin and
+// counts the failures of both versions until the swap ends.
+type canary struct {
+	sync.Mutex
+	status core.CanaryStatus
+	tasks  map[string]bool
+	timer  *time.Timer
+	// whether the window was extended for the new version to serve enough
+	// calls
+	extended bool
+}
+
+func newCanary(typeName, name string, from, to int, opts core.CanaryOptions) *canary {
+	c := &canary{
+		status: core.CanaryStatus{
+			Type:    typeName,
+			Name:    name,
+			From:    core.CanaryVersionStats{Version: from},
+			To:      core.CanaryVersionStats{Version: to},
+			Options: opts,
+			State:   core.CanaryRunning,
+			Started: time.Now(),
+		},
+		tasks: map[string]bool{},
+	}
+	for _, id := range opts.Tasks {
+		c.tasks[id] = true
+	}
+	return c
+}
+
+// routed returns whether the task is routed to the new version.  The named
+// tasks always are, the others are spread by hashing their id.
+func (c *canary) routed(taskID string) bool {
+	if c.tasks[taskID] {
+		return true
+	}
+	h := fnv.New32a()
+	h.Write([]byte(taskID))
+	return float64(h.Sum32()%10000)/10000 < c.status.Options.Fraction
+}
+
+// failed returns whether the new version failed more than allowed
+func (c *canary) failed() bool {
+	return c.status.To.FailureRate() > c.status.Options.MaxFailureRate
+}
+
+// tooFewCalls returns whether the new version served too few calls for its
+// failure rate to tell whether it works
+func (c *canary) tooFewCalls() bool {
+	return c.status.To.Calls < c.status.Options.MinCalls
+}
+
+// canaries holds the staged swaps keyed by {plugin_type}:{plugin_name}.
+// Ended swaps are kept until the plugin is swapped again to report their
+// outcome.
+type canaries struct {
+	sync.RWMutex
+	table map[string]*canary
+}
+
+func newCanaries() *canaries {
+	return &canaries{table: map[string]*canary{}}
+}
+
+func (c *canaries) add(cn *canary) error {
+	c.Lock()
+	defer c.Unlock()
+	k := cn.status.Type + ":" + cn.status.Name
+	if prev, ok := c.table[k]; ok && prev.State() == core.CanaryRunning {
+		return ErrCanaryInProgress
+	}
+	c.table[k] = cn
+	return nil
+}
+
+// active returns whether a staged swap is running
+func (c *canaries) active() bool {
+	if c == nil {
+		return false
+	}
+	c.RLock()
+	defer c.RUnlock()
+	for _, cn := range c.table {
+		if cn.State() == core.CanaryRunning {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *canaries) get(typeName, name string) *canary {
+	if c == nil {
+		return nil
+	}
+	c.RLock()
+	defer c.RUnlock()
+	return c.table[typeName+":"+name]
+}
+
+func (c *canaries) running(typeName, name string) *canary {
+	cn := c.get(typeName, name)
+	if cn == nil || cn.State() != core.CanaryRunning {
+		return nil
+	}
+	return cn
+}
+
+// version returns the version of the plugin the task is routed to.  Only
+// the tasks which did not request a specific version are routed.
+func (c *canaries) version(typeName, name string, version int, taskID string) int {
+	if version > 0 {
+		return version
+	}
+	cn := c.running(typeName, name)
+	if cn == nil {
+		return version
+	}
+	cn.Lock()
+	defer cn.Unlock()
+	if cn.routed(taskID) {
+		return cn.status.To.Version
+	}
+	return cn.status.From.Version
+}
+
+// record counts a request served by the plugin with the given key:
+// {plugin_type}{core.Separator}{plugin_name}{core.Separator}{plugin_version}
+func (c *canaries) record(key string, failed bool) {
+	tnv := strings.Split(key, core.Separator)
+	if len(tnv) != 3 {
+		return
+	}
+	cn := c.running(tnv[0], tnv[1])
+	if cn == nil {
+		return
+	}
+	v, _ := strconv.Atoi(tnv[2])
+	cn.Lock()
+	defer cn.Unlock()
+	var stats *core.CanaryVersionStats
+	switch v {
+	case cn.status.From.Version:
+		stats = &cn.status.From
+	case cn.status.To.Version:
+		stats = &cn.status.To
+	default:
+		return
+	}
+	stats.Calls++
+	if failed {
+		stats.Failures++
+	}
+}
+
+func (c *canaries) stop() {
+	if c == nil {
+		return
+	}
+	c.RLock()
+	defer c.RUnlock()
+	for _, cn := range c.table {
+		cn.Lock()
+		if cn.timer != nil {
+			cn.timer.Stop()
+		}
+		cn.Unlock()
+	}
+}
+
+// State returns the state of the staged swap
+func (c *canary) State() string {
+	c.Lock()
+	defer c.Unlock()
+	return c.status.State
+}
+
+// Status returns a copy of the status of the staged swap
+func (c *canary) Status() core.CanaryStatus {
+	c.Lock()
+	defer c.Unlock()
+	s := c.status
+	s.Options.Tasks = append([]string(nil), c.status.Options.Tasks...)
+	return s
+}
+
+func (c *canary) end(state string) {
+	c.Lock()
+	defer c.Unlock()
+	c.status.State = state
+	c.status.Ended = time.Now()
+}
+
+// pinnedMetric is a requested metric pinned to the version of the plugin a
+// task is routed to
+type pinnedMetric struct {
+	namespace core.Namespace
+	version   int
+}
+
+func (m pinnedMetric) Namespace() core.Namespace { return m.namespace }
+func (m pinnedMetric) Version() int              { return m.version }
+
+// CanarySwap starts the new version of a plugin alongside the old one and
+// routes a fraction of the tasks (or the named tasks) to it.  Once the window
+// elapsed the old version is unloaded, unless the failure rate of the new
+// version exceeded the maximum, in which case the new version is unloaded.
+// When the new version served fewer calls than the minimum the window is
+// extended once, and the new version is unloaded if it still did not serve
+// enough calls.
+func (p *pluginControl) CanarySwap(in *core.RequestedPlugin, out core.Plugin, opts core.CanaryOptions) (core.CatalogedPlugin, serror.SnapError) {
+	if opts.Window == 0 {
+		opts.Window = DefaultCanaryWindow
+	}
+	if opts.Fraction == 0 && len(opts.Tasks) == 0 {
+		opts.Fraction = DefaultCanaryFraction
+	}
+	if opts.MaxFailureRate == 0 {
+		opts.MaxFailureRate = DefaultCanaryMaxFailureRate
+	}
+	if opts.MinCalls == 0 {
+		opts.MinCalls = DefaultCanaryMinCalls
+	}
+	if opts.Fraction < 0 || opts.Fraction > 1 || opts.MaxFailureRate < 0 || opts.MaxFailureRate > 1 || opts.Window < 0 {
+		return nil, serror.New(ErrBadCanaryOptions)
+	}
+	f := map[string]interface{}{
+		"plugin-name":    out.Name(),
+		"plugin-version": out.Version(),
+		"plugin-type":    out.TypeName(),
+	}
+	up, err := p.pluginManager.get(out.TypeName() + core.Separator + out.Name() + core.Separator + strconv.Itoa(out.Version()))
+	if err != nil {
+		return nil, serror.New(ErrPluginNotFound, f)
+	}
+	if cn := p.canaries.running(out.TypeName(), out.Name()); cn != nil {
+		return nil, serror.New(ErrCanaryInProgress, f)
+	}
+
+	details, serr := p.returnPluginDetails(in)
+	if serr != nil {
+		return nil, serr
+	}
+	if details.IsPackage {
+		defer os.RemoveAll(filepath.Dir(details.ExecPath))
+	}
+	lp, serr := p.loadTrustedPlugin(details)
+	if serr != nil {
+		return nil, serr
+	}
+	if details.IsPackage {
+		lp.Details.ExecPath = ""
+	}
+	if lp.TypeName() != up.TypeName() || lp.Name() != up.Name() {
+		serr := serror.New(errors.New("Plugin types and names must match."))
+		serr.SetFields(map[string]interface{}{
+			"in-type":  lp.TypeName(),
+			"out-type": up.TypeName(),
+			"in-name":  lp.Name(),
+			"out-name": up.Name(),
+		})
+		if _, err := p.pluginManager.UnloadPlugin(lp); err != nil {
+			se := serror.New(errors.New("Failed to rollback after error"))
+			se.SetFields(map[string]interface{}{
+				"original-unload-error": serr.Error(),
+				"rollback-unload-error": err.Error(),
+			})
+			return nil, se
+		}
+		return nil, serr
+	}
+
+	// the canary is registered before the subscriptions are processed so
+	// that only the routed tasks move to the new version
+	cn := newCanary(up.TypeName(), up.Name(), up.Version(), lp.Version(), opts)
+	if err := p.canaries.add(cn); err != nil {
+		p.pluginManager.UnloadPlugin(lp)
+		return nil, serror.New(err, f)
+	}
+	cn.Lock()
+	cn.timer = time.AfterFunc(opts.Window, func() { p.endCanary(cn, up, lp) })
+	cn.Unlock()
+	p.storePlugin(in, lp)
+
+	controlLogger.WithFields(log.Fields{
+		"_block":      "canary-swap",
+		"plugin-name": lp.Name(),
+		"plugin-type": lp.TypeName(),
+		"from":        up.Version(),
+		"to":          lp.Version(),
+		"fraction":    opts.Fraction,
+		"tasks":       opts.Tasks,
+		"window":      opts.Window,
+		"min-calls":   opts.MinCalls,
+	}).Info("canary swap started")
+
+	p.applyLifecycle(lp)
+
+	event := &control_event.LoadPluginEvent{
+		Name:    lp.Meta.Name,
+		Version: lp.Meta.Version,
+		Type:    int(lp.Meta.Type),
+		Signed:  lp.Details.Signed,
+	}
+	defer p.eventManager.Emit(event)
+	return lp, nil
+}
+
+// CanaryStatus returns the status of the last staged swap of a plugin
+func (p *pluginControl) CanaryStatus(typeName, name string) (core.CanaryStatus, serror.SnapError) {
+	cn := p.canaries.get(typeName, name)
+	if cn == nil {
+		return core.CanaryStatus{}, serror.New(ErrCanaryNotFound, map[string]interface{}{
+			"plugin-name": name,
+			"plugin-type": typeName,
+		})
+	}
+	return cn.Status(), nil
+}
+
+// endCanary completes or rolls back a staged swap depending on the failure
+// rate of the new version, and on the number of calls it served.  The version
+// which is kept serves all the tasks once the other one is unloaded.
+func (p *pluginControl) endCanary(cn *canary, from, to *loadedPlugin) {
+	cn.Lock()
+	tooFewCalls := cn.tooFewCalls()
+	status := cn.status
+	if tooFewCalls && !cn.extended {
+		cn.extended = true
+		cn.timer = time.AfterFunc(status.Options.Window, func() { p.endCanary(cn, from, to) })
+		cn.Unlock()
+		controlLogger.WithFields(log.Fields{
+			"_block":      "canary-swap",
+			"plugin-name": status.Name,
+			"plugin-type": status.Type,
+			"to":          status.To.Version,
+			"to-calls":    status.To.Calls,
+			"min-calls":   status.Options.MinCalls,
+			"window":      status.Options.Window,
+		}).Warn("canary swap window extended, the new version served too few calls")
+		return
+	}
+	failed := cn.failed() || tooFewCalls
+	cn.Unlock()
+
+	fields := log.Fields{
+		"_block":            "canary-swap",
+		"plugin-name":       status.Name,
+		"plugin-type":       status.Type,
+		"from":              status.From.Version,
+		"to":                status.To.Version,
+		"from-failure-rate": status.From.FailureRate(),
+		"to-failure-rate":   status.To.FailureRate(),
+		"to-calls":          status.To.Calls,
+	}
+	unloaded, kept, state := from, to, core.CanaryCompleted
+	if failed {
+		unloaded, kept, state = to, from, core.CanaryRolledBack
+	}
+	if _, err := p.pluginManager.UnloadPlugin(unloaded); err != nil {
+		controlLogger.WithFields(fields).Error(err)
+	}
+	p.unstorePlugin(unloaded)
+	cn.end(state)
+	p.stopLifecycle(unloaded.Key())
+
+	if failed {
+		controlLogger.WithFields(fields).Warn("canary swap rolled back")
+	} else {
+		controlLogger.WithFields(fields).Info("canary swap completed")
+	}
+
+	p.eventManager.Emit(&control_event.UnloadPluginEvent{
+		Name:    unloaded.Meta.Name,
+		Version: unloaded.Meta.Version,
+		Type:    int(unloaded.Meta.Type),
+	})
+	if !failed {
+		p.eventManager.Emit(&control_event.SwapPluginsEvent{
+			LoadedPluginName:      kept.Meta.Name,
+			LoadedPluginVersion:   kept.Meta.Version,
+			UnloadedPluginName:    unloaded.Meta.Name,
+			UnloadedPluginVersion: unloaded.Meta.Version,
+			PluginType:            int(kept.Meta.Type),
+		})
+	}
+}
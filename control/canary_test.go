@@ -0,0 +1,102 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package control
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/intelsdi-x/snap/core"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func canaryKey(version int) string {
+	return fmt.Sprintf("collector"+core.Separator+"mock"+core.Separator+"%d", version)
+}
+
+func TestCanaries(t *testing.T) {
+	Convey("Given a staged swap of collector:mock from v1 to v2", t, func() {
+		c := newCanaries()
+		cn := newCanary("collector", "mock", 1, 2, core.CanaryOptions{Tasks: []string{"canary"}, MaxFailureRate: 0.5})
+		So(c.add(cn), ShouldBeNil)
+		So(c.active(), ShouldBeTrue)
+
+		Convey("The named tasks are routed to the new version", func() {
+			So(c.version("collector", "mock", -1, "canary"), ShouldEqual, 2)
+			So(c.version("collector", "mock", -1, "other"), ShouldEqual, 1)
+		})
+		Convey("The tasks requesting a version are not routed", func() {
+			So(c.version("collector", "mock", 1, "canary"), ShouldEqual, 1)
+			So(c.version("collector", "other", -1, "canary"), ShouldEqual, -1)
+		})
+		Convey("A fraction of the tasks is routed to the new version", func() {
+			cn.status.Options.Fraction = 1
+			So(c.version("collector", "mock", -1, "other"), ShouldEqual, 2)
+			cn.status.Options.Fraction = 0
+			So(c.version("collector", "mock", -1, "other"), ShouldEqual, 1)
+		})
+		Convey("The failures of both versions are counted", func() {
+			c.record(canaryKey(1), false)
+			c.record(canaryKey(2), false)
+			c.record(canaryKey(2), true)
+			c.record(canaryKey(3), true)
+			s := cn.Status()
+			So(s.From.Calls, ShouldEqual, 1)
+			So(s.From.Failures, ShouldEqual, 0)
+			So(s.To.Calls, ShouldEqual, 2)
+			So(s.To.Failures, ShouldEqual, 1)
+			So(cn.failed(), ShouldBeFalse)
+			c.record(canaryKey(2), true)
+			So(cn.failed(), ShouldBeTrue)
+		})
+		Convey("The new version must serve the minimum number of calls", func() {
+			cn.status.Options.MinCalls = 2
+			So(cn.tooFewCalls(), ShouldBeTrue)
+			c.record(canaryKey(1), false)
+			c.record(canaryKey(2), false)
+			So(cn.tooFewCalls(), ShouldBeTrue)
+			c.record(canaryKey(2), false)
+			So(cn.tooFewCalls(), ShouldBeFalse)
+		})
+		Convey("Another swap of the plugin cannot start until the first ends", func() {
+			next := newCanary("collector", "mock", 2, 3, core.CanaryOptions{})
+			So(c.add(next), ShouldEqual, ErrCanaryInProgress)
+			cn.end(core.CanaryRolledBack)
+			So(c.active(), ShouldBeFalse)
+			So(c.version("collector", "mock", -1, "canary"), ShouldEqual, -1)
+			So(c.add(next), ShouldBeNil)
+		})
+		Convey("Ended swaps do not count requests", func() {
+			cn.end(core.CanaryCompleted)
+			c.record(canaryKey(2), true)
+			So(cn.Status().To.Calls, ShouldEqual, 0)
+			So(cn.Status().Ended.IsZero(), ShouldBeFalse)
+		})
+	})
+	Convey("Routing without staged swaps is a no-op", t, func() {
+		var c *canaries
+		So(c.active(), ShouldBeFalse)
+		So(c.version("collector", "mock", -1, "task"), ShouldEqual, -1)
+		c.record(canaryKey(1), true)
+	})
+}
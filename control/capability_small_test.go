@@ -0,0 +1,158 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package control
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/intelsdi-x/gomit"
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/net/context"
+
+	"github.com/intelsdi-x/snap/control/plugin"
+	"github.com/intelsdi-x/snap/control/plugin/client"
+	"github.com/intelsdi-x/snap/core"
+	"github.com/intelsdi-x/snap/core/ctypes"
+)
+
+// mockHealthDetailClient is the client of a plugin reporting itself
+// unhealthy.
+type mockHealthDetailClient struct {
+	client.PluginClient
+}
+
+func (m *mockHealthDetailClient) Ping() error {
+	return nil
+}
+
+func (m *mockHealthDetailClient) PingHealth() error {
+	return errors.New("disk full")
+}
+
+// mockContextProcessorClient is the client of a processor recording the
+// error of the context of the last call.
+type mockContextProcessorClient struct {
+	client.PluginProcessorClient
+	err error
+}
+
+func (m *mockContextProcessorClient) Process(ctx context.Context, metrics []core.Metric, config map[string]ctypes.ConfigValue) ([]core.Metric, error) {
+	m.err = ctx.Err()
+	return metrics, nil
+}
+
+// newCapableAvailablePlugin returns an available plugin negotiating the
+// protocol version with meta.
+func newCapableAvailablePlugin(meta plugin.PluginMeta) *availablePlugin {
+	return &availablePlugin{
+		meta:            meta,
+		protocolVersion: negotiateProtocolVersion(meta),
+		healthChan:      make(chan error, 1),
+		emitter:         gomit.NewEventController(),
+	}
+}
+
+func TestNegotiateProtocolVersion(t *testing.T) {
+	Convey("The older of the protocol versions is spoken", t, func() {
+		So(negotiateProtocolVersion(plugin.PluginMeta{}), ShouldEqual, 0)
+		So(negotiateProtocolVersion(plugin.PluginMeta{ProtocolVersion: plugin.ProtocolVersion}), ShouldEqual, plugin.ProtocolVersion)
+		So(negotiateProtocolVersion(plugin.PluginMeta{ProtocolVersion: plugin.ProtocolVersion + 1}), ShouldEqual, plugin.ProtocolVersion)
+	})
+}
+
+func TestPluginChannel(t *testing.T) {
+	Convey("Given the channel settings of snapteld", t, func() {
+		channels := pluginChannels{compression: []string{"gzip"}, collectChunkSize: 100}
+		meta := plugin.PluginMeta{ProtocolVersion: 1, Compression: []string{"gzip"}}
+
+		Convey("optional features are off for a plugin without capabilities", func() {
+			channel := channels.channel(newCapableAvailablePlugin(meta))
+			So(channel.Compression, ShouldEqual, "")
+			So(channel.CollectChunkSize, ShouldEqual, 0)
+		})
+		Convey("optional features are used by a plugin with the capabilities", func() {
+			meta.Capabilities = []plugin.Capability{plugin.CapabilityCompression, plugin.CapabilityChunkedCollect}
+			channel := channels.channel(newCapableAvailablePlugin(meta))
+			So(channel.Compression, ShouldEqual, "gzip")
+			So(channel.CollectChunkSize, ShouldEqual, 100)
+		})
+	})
+}
+
+func TestAvailablePluginCapabilities(t *testing.T) {
+	Convey("Given plugins listing every capability", t, func() {
+		meta := plugin.PluginMeta{
+			ProtocolVersion: 1,
+			Capabilities:    []plugin.Capability{plugin.CapabilityCancellation, plugin.CapabilityHealthDetail},
+		}
+		capable := newCapableAvailablePlugin(meta)
+		meta.ProtocolVersion = 0
+		older := newCapableAvailablePlugin(meta)
+
+		Convey("they are only used in the negotiated protocol version", func() {
+			So(capable.supports(plugin.CapabilityCancellation), ShouldBeTrue)
+			So(older.supports(plugin.CapabilityCancellation), ShouldBeFalse)
+			So(capable.supports(plugin.CapabilityHistograms), ShouldBeFalse)
+		})
+		Convey("the calls to plugins without cancellation are cancelled with the task too", func() {
+			processor := &mockContextProcessorClient{}
+			older.name = "older"
+			older.version = 1
+			older.pluginType = plugin.ProcessorPluginType
+			older.client = processor
+			aps := newAvailablePlugins()
+			So(aps.insert(older), ShouldBeNil)
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			_, errs := aps.processMetrics(ctx, nil, "older", 1, nil, "task")
+			So(errs, ShouldBeEmpty)
+			So(processor.err, ShouldEqual, context.Canceled)
+		})
+		Convey("only plugins with health detail may report themselves unhealthy", func() {
+			capable.client = &mockHealthDetailClient{}
+			capable.CheckHealth()
+			So(capable.failedHealthChecks, ShouldEqual, 1)
+			older.client = &mockHealthDetailClient{}
+			older.CheckHealth()
+			So(older.failedHealthChecks, ShouldEqual, 0)
+		})
+	})
+}
+
+func TestSplitStructuredMetrics(t *testing.T) {
+	Convey("Metrics with histogram, summary and map values are split from the others", t, func() {
+		metrics := []core.Metric{
+			plugin.MetricType{Namespace_: core.NewNamespace("a"), Data_: 1},
+			plugin.MetricType{Namespace_: core.NewNamespace("b"), Data_: core.Histogram{Counts: []uint64{1}, Count: 1}},
+			plugin.MetricType{Namespace_: core.NewNamespace("c"), Data_: "text"},
+			plugin.MetricType{Namespace_: core.NewNamespace("d"), Data_: map[string]float64{"x": 1}},
+		}
+		scalar, structured := splitStructuredMetrics(metrics)
+		So(len(scalar), ShouldEqual, 2)
+		So(scalar[0].Namespace().String(), ShouldEqual, "/a")
+		So(scalar[1].Namespace().String(), ShouldEqual, "/c")
+		So(len(structured), ShouldEqual, 2)
+		So(structured[0].Namespace().String(), ShouldEqual, "/b")
+		So(structured[1].Namespace().String(), ShouldEqual, "/d")
+	})
+}
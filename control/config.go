@@ -26,6 +26,7 @@ import (
 	"os"
 	"reflect"
 	"strconv"
+	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -44,21 +45,66 @@ var (
 	defaultPluginLoadTimeout = 3
 	defaultPluginTrust       = 1
 	defaultAutoDiscoverPath  = ""
+	defaultAutoDiscoverWatch = false
 	defaultKeyringPaths      = ""
+	defaultPluginTrustPolicy = ""
 	defaultCacheExpiration   = 500 * time.Millisecond
 	defaultPprof             = false
 	defaultTempDirPath       = os.TempDir()
 	defaultTLSCertPath       = ""
 	defaultTLSKeyPath        = ""
 	defaultCACertPaths       = ""
+	defaultRoutingHashKey    = "config"
+	defaultCacheMaxEntries   = 100000
+	defaultCacheMaxBytes     = 256 * 1024 * 1024
+	defaultAutoscaleInterval = 5 * time.Second
+	defaultIdleTimeout       = 5 * time.Minute
+	defaultAutoUpgradeTasks  = true
+	defaultPluginRepoPath    = ""
+	defaultPluginConfigPath  = ""
+	defaultPluginCompression = []string{"gzip"}
+	defaultPluginMaxMsgSize  = 0
+	defaultPluginChunkSize   = 1000
+	defaultPluginUnixSockets = false
+	defaultPluginDownloadMax = 256 * 1024 * 1024
 )
 
+// autoscaleConfig holds the settings of the load based autoscaling of the
+// plugin pools.
+type autoscaleConfig struct {
+	Enable            bool                              `json:"enable"yaml:"enable"`
+	Interval          jsonutil.Duration                 `json:"interval"yaml:"interval"`
+	LatencyThreshold  jsonutil.Duration                 `json:"latency_threshold"yaml:"latency_threshold"`
+	InFlightThreshold int                               `json:"in_flight_threshold"yaml:"in_flight_threshold"`
+	IdleTimeout       jsonutil.Duration                 `json:"idle_timeout"yaml:"idle_timeout"`
+	MinRunningPlugins int                               `json:"min_running_plugins"yaml:"min_running_plugins"`
+	Plugins           map[string]*autoscalePluginConfig `json:"plugins"yaml:"plugins"`
+}
+
+// autoscalePluginConfig holds the pool size limits of a plugin, overriding
+// the global ones.
+type autoscalePluginConfig struct {
+	MinRunningPlugins int `json:"min_running_plugins"yaml:"min_running_plugins"`
+	MaxRunningPlugins int `json:"max_running_plugins"yaml:"max_running_plugins"`
+}
+
+func newAutoscaleConfig() *autoscaleConfig {
+	return &autoscaleConfig{
+		Interval:          jsonutil.Duration{defaultAutoscaleInterval},
+		IdleTimeout:       jsonutil.Duration{defaultIdleTimeout},
+		MinRunningPlugins: 1,
+		Plugins:           map[string]*autoscalePluginConfig{},
+	}
+}
+
 type pluginConfig struct {
 	All         *cdata.ConfigDataNode `json:"all"`
 	Collector   *pluginTypeConfigItem `json:"collector"`
 	Publisher   *pluginTypeConfigItem `json:"publisher"`
 	Processor   *pluginTypeConfigItem `json:"processor"`
 	pluginCache map[string]*cdata.ConfigDataNode
+	// guards the config and the cache, which is filled by the readers
+	mutex sync.RWMutex
 }
 
 type pluginTypeConfigItem struct {
@@ -76,22 +122,50 @@ type pluginConfigItem struct {
 //         UnmarshalJSON method in this same file needs to be modified to
 //         match the field mapping that is defined here
 type Config struct {
-	MaxRunningPlugins int                          `json:"max_running_plugins"yaml:"max_running_plugins"`
-	PluginLoadTimeout int                          `json:"plugin_load_timeout"yaml:"plugin_load_timeout"`
-	PluginTrust       int                          `json:"plugin_trust_level"yaml:"plugin_trust_level"`
-	AutoDiscoverPath  string                       `json:"auto_discover_path"yaml:"auto_discover_path"`
-	KeyringPaths      string                       `json:"keyring_paths"yaml:"keyring_paths"`
-	CacheExpiration   jsonutil.Duration            `json:"cache_expiration"yaml:"cache_expiration"`
-	Plugins           *pluginConfig                `json:"plugins"yaml:"plugins"`
-	Tags              map[string]map[string]string `json:"tags,omitempty"yaml:"tags"`
-	ListenAddr        string                       `json:"listen_addr,omitempty"yaml:"listen_addr"`
-	ListenPort        int                          `json:"listen_port,omitempty"yaml:"listen_port"`
-	Pprof             bool                         `json:"pprof"yaml:"pprof"`
-	MaxPluginRestarts int                          `json:"max_plugin_restarts"yaml:"max_plugin_restarts"`
-	TempDirPath       string                       `json:"temp_dir_path"yaml:"temp_dir_path"`
-	TLSCertPath       string                       `json:"tls_cert_path"yaml:"tls_cert_path"`
-	TLSKeyPath        string                       `json:"tls_key_path"yaml:"tls_key_path"`
-	CACertPaths       string                       `json:"ca_cert_paths"yaml:"ca_cert_paths"`
+	MaxRunningPlugins      int                          `json:"max_running_plugins"yaml:"max_running_plugins"`
+	PluginLoadTimeout      int                          `json:"plugin_load_timeout"yaml:"plugin_load_timeout"`
+	PluginTrust            int                          `json:"plugin_trust_level"yaml:"plugin_trust_level"`
+	AutoDiscoverPath       string                       `json:"auto_discover_path"yaml:"auto_discover_path"`
+	AutoDiscoverWatch      bool                         `json:"auto_discover_watch"yaml:"auto_discover_watch"`
+	KeyringPaths           string                       `json:"keyring_paths"yaml:"keyring_paths"`
+	PluginTrustPolicy      string                       `json:"plugin_trust_policy"yaml:"plugin_trust_policy"`
+	CacheExpiration        jsonutil.Duration            `json:"cache_expiration"yaml:"cache_expiration"`
+	CacheMaxEntries        int                          `json:"cache_max_entries"yaml:"cache_max_entries"`
+	CacheMaxBytes          int                          `json:"cache_max_bytes"yaml:"cache_max_bytes"`
+	Plugins                *pluginConfig                `json:"plugins"yaml:"plugins"`
+	Tags                   map[string]map[string]string `json:"tags,omitempty"yaml:"tags"`
+	ListenAddr             string                       `json:"listen_addr,omitempty"yaml:"listen_addr"`
+	ListenPort             int                          `json:"listen_port,omitempty"yaml:"listen_port"`
+	Pprof                  bool                         `json:"pprof"yaml:"pprof"`
+	MaxPluginRestarts      int                          `json:"max_plugin_restarts"yaml:"max_plugin_restarts"`
+	TempDirPath            string                       `json:"temp_dir_path"yaml:"temp_dir_path"`
+	TLSCertPath            string                       `json:"tls_cert_path"yaml:"tls_cert_path"`
+	TLSKeyPath             string                       `json:"tls_key_path"yaml:"tls_key_path"`
+	CACertPaths            string                       `json:"ca_cert_paths"yaml:"ca_cert_paths"`
+	RoutingHashKey         string                       `json:"routing_hash_key"yaml:"routing_hash_key"`
+	Autoscale              *autoscaleConfig             `json:"autoscale"yaml:"autoscale"`
+	PluginLifecycle        map[string]string            `json:"plugin_lifecycle"yaml:"plugin_lifecycle"`
+	AutoUpgradeTasks       bool                         `json:"auto_upgrade_tasks"yaml:"auto_upgrade_tasks"`
+	PluginRepoPath         string                       `json:"plugin_repo_path"yaml:"plugin_repo_path"`
+	PluginConfigPath       string                       `json:"plugin_config_path"yaml:"plugin_config_path"`
+	PluginCompression      []string                     `json:"plugin_compression"yaml:"plugin_compression"`
+	PluginMaxMessageSize   int                          `json:"plugin_max_message_size"yaml:"plugin_max_message_size"`
+	PluginCollectChunkSize int                          `json:"plugin_collect_chunk_size"yaml:"plugin_collect_chunk_size"`
+	PluginUnixSockets      bool                         `json:"plugin_unix_sockets"yaml:"plugin_unix_sockets"`
+	PluginDownloadMaxBytes int                          `json:"plugin_download_max_bytes"yaml:"plugin_download_max_bytes"`
+
+	// state of the changes made to the global plugin config, behind a
+	// pointer so that the config can be copied
+	pluginConfigState *pluginConfigState
+}
+
+// pluginConfigState holds the changes made to the global plugin config since
+// snapteld started or loaded from the plugin config store.
+type pluginConfigState struct {
+	mutex   sync.RWMutex
+	history []core.PluginConfigChange
+	// called once the global plugin config changed
+	changed func()
 }
 
 const (
@@ -107,9 +181,20 @@ const (
 					"auto_discover_path": {
 						"type": "string"
 					},
+					"auto_discover_watch": {
+						"type": "boolean"
+					},
 					"cache_expiration": {
 						"type": "string"
 					},
+					"cache_max_entries": {
+						"type": "integer",
+						"minimum": 0
+					},
+					"cache_max_bytes": {
+						"type": "integer",
+						"minimum": 0
+					},
 					"max_running_plugins": {
 						"type": "integer",
 						"minimum": 1
@@ -122,6 +207,9 @@ const (
 					"keyring_paths" : {
 						"type": "string"
 					},
+					"plugin_trust_policy" : {
+						"type": "string"
+					},
 					"plugins": {
 						"type": ["object", "null"],
 						"properties" : {},
@@ -155,6 +243,88 @@ const (
 					},
 					"ca_cert_paths": {
 						"type": "string"
+					},
+					"routing_hash_key": {
+						"type": "string"
+					},
+					"autoscale": {
+						"type": ["object", "null"],
+						"properties": {
+							"enable": {
+								"type": "boolean"
+							},
+							"interval": {
+								"type": "string"
+							},
+							"latency_threshold": {
+								"type": "string"
+							},
+							"in_flight_threshold": {
+								"type": "integer",
+								"minimum": 0
+							},
+							"idle_timeout": {
+								"type": "string"
+							},
+							"min_running_plugins": {
+								"type": "integer",
+								"minimum": 1
+							},
+							"plugins": {
+								"type": ["object", "null"],
+								"additionalProperties": {
+									"type": "object",
+									"properties": {
+										"min_running_plugins": {
+											"type": "integer",
+											"minimum": 1
+										},
+										"max_running_plugins": {
+											"type": "integer",
+											"minimum": 1
+										}
+									},
+									"additionalProperties": false
+								}
+							}
+						},
+						"additionalProperties": false
+					},
+					"plugin_lifecycle": {
+						"type": ["object", "null"],
+						"additionalProperties": {
+							"type": "string"
+						}
+					},
+					"auto_upgrade_tasks": {
+						"type": "boolean"
+					},
+					"plugin_repo_path": {
+						"type": "string"
+					},
+					"plugin_config_path": {
+						"type": "string"
+					},
+					"plugin_compression": {
+						"type": ["array", "null"],
+						"items": {
+							"type": "string"
+						}
+					},
+					"plugin_max_message_size": {
+						"type": "integer",
+						"minimum": 0
+					},
+					"plugin_collect_chunk_size": {
+						"type": "integer",
+						"minimum": 0
+					},
+					"plugin_unix_sockets": {
+						"type": "boolean"
+					},
+					"plugin_download_max_bytes": {
+						"type": "integer",
+						"minimum": 0
 					}
 				},
 				"additionalProperties": false
@@ -171,8 +341,12 @@ func GetDefaultConfig() *Config {
 		PluginLoadTimeout: defaultPluginLoadTimeout,
 		PluginTrust:       defaultPluginTrust,
 		AutoDiscoverPath:  defaultAutoDiscoverPath,
+		AutoDiscoverWatch: defaultAutoDiscoverWatch,
 		KeyringPaths:      defaultKeyringPaths,
+		PluginTrustPolicy: defaultPluginTrustPolicy,
 		CacheExpiration:   jsonutil.Duration{defaultCacheExpiration},
+		CacheMaxEntries:   defaultCacheMaxEntries,
+		CacheMaxBytes:     defaultCacheMaxBytes,
 		Plugins:           newPluginConfig(),
 		Tags:              newPluginTags(),
 		Pprof:             defaultPprof,
@@ -181,6 +355,20 @@ func GetDefaultConfig() *Config {
 		TLSCertPath:       defaultTLSCertPath,
 		TLSKeyPath:        defaultTLSKeyPath,
 		CACertPaths:       defaultCACertPaths,
+		RoutingHashKey:    defaultRoutingHashKey,
+		Autoscale:         newAutoscaleConfig(),
+		PluginLifecycle:   map[string]string{},
+		AutoUpgradeTasks:  defaultAutoUpgradeTasks,
+		PluginRepoPath:    defaultPluginRepoPath,
+		PluginConfigPath:  defaultPluginConfigPath,
+
+		PluginCompression:      defaultPluginCompression,
+		PluginMaxMessageSize:   defaultPluginMaxMsgSize,
+		PluginCollectChunkSize: defaultPluginChunkSize,
+		PluginUnixSockets:      defaultPluginUnixSockets,
+		PluginDownloadMaxBytes: defaultPluginDownloadMax,
+
+		pluginConfigState: &pluginConfigState{},
 	}
 }
 
@@ -223,31 +411,45 @@ func (p *Config) GetPluginConfigDataNode(pluginType core.PluginType, name string
 }
 
 func (p *Config) MergePluginConfigDataNode(pluginType core.PluginType, name string, ver int, cdn *cdata.ConfigDataNode) cdata.ConfigDataNode {
-	p.Plugins.mergePluginConfigDataNode(pluginType, name, ver, cdn)
-	return *p.Plugins.getPluginConfigDataNode(pluginType, name, ver)
+	res, _ := p.ChangePluginConfig(core.PluginConfigChange{
+		Action:  core.PluginConfigSet,
+		Type:    pluginType.String(),
+		Name:    name,
+		Version: ver,
+		Config:  cdn,
+	})
+	return res
 }
 
 func (p *Config) MergePluginConfigDataNodeAll(cdn *cdata.ConfigDataNode) cdata.ConfigDataNode {
-	p.Plugins.mergePluginConfigDataNodeAll(cdn)
-	return *p.Plugins.All
+	res, _ := p.ChangePluginConfig(core.PluginConfigChange{
+		Action: core.PluginConfigSet,
+		Config: cdn,
+	})
+	return res
 }
 
 func (p *Config) DeletePluginConfigDataNodeField(pluginType core.PluginType, name string, ver int, fields ...string) cdata.ConfigDataNode {
-	for _, field := range fields {
-		p.Plugins.deletePluginConfigDataNodeField(pluginType, name, ver, field)
-	}
-	return *p.Plugins.getPluginConfigDataNode(pluginType, name, ver)
+	res, _ := p.ChangePluginConfig(core.PluginConfigChange{
+		Action:  core.PluginConfigDelete,
+		Type:    pluginType.String(),
+		Name:    name,
+		Version: ver,
+		Fields:  fields,
+	})
+	return res
 }
 
 func (p *Config) DeletePluginConfigDataNodeFieldAll(fields ...string) cdata.ConfigDataNode {
-	for _, field := range fields {
-		p.Plugins.deletePluginConfigDataNodeFieldAll(field)
-	}
-	return *p.Plugins.All
+	res, _ := p.ChangePluginConfig(core.PluginConfigChange{
+		Action: core.PluginConfigDelete,
+		Fields: fields,
+	})
+	return res
 }
 
 func (p *Config) GetPluginConfigDataNodeAll() cdata.ConfigDataNode {
-	return *p.Plugins.All
+	return p.Plugins.all()
 }
 
 // IsTLSEnabled returns true if config values enable TLS in plugin communication
@@ -336,7 +538,7 @@ func (p *pluginConfig) switchPluginConfigType(pluginType core.PluginType) *plugi
 		return p.Collector
 	case pluginType == core.ProcessorPluginType:
 		return p.Processor
-	case pluginType == core.PublisherPluginType:
+	case pluginType == core.PublisherPluginType || pluginType == core.StreamingPublisherPluginType:
 		return p.Publisher
 	}
 	return nil
@@ -356,6 +558,12 @@ func (p *pluginConfig) mergePluginConfigDataNode(pluginType core.PluginType, nam
 			res2.Merge(cdn)
 			return
 		}
+		if ver > 0 {
+			cn := cdata.NewNode()
+			cn.Merge(cdn)
+			res.Versions[ver] = cn
+			return
+		}
 		res.Merge(cdn)
 		return
 	}
@@ -386,16 +594,50 @@ func (p *pluginConfig) deletePluginConfigDataNodeField(pluginType core.PluginTyp
 			res2.DeleteItem(key)
 			return
 		}
-		res.DeleteItem(key)
+		if ver < 1 {
+			res.DeleteItem(key)
+		}
 		return
 	}
-	configItem.All.DeleteItem(key)
+	if name == "" {
+		configItem.All.DeleteItem(key)
+	}
+
+}
 
+// all returns a copy of the config of all the plugins
+func (p *pluginConfig) all() cdata.ConfigDataNode {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	cdn := cdata.NewNode()
+	cdn.Merge(p.All)
+	return *cdn
 }
 
+// getPluginConfigDataNode returns the config of a plugin, from the cache when
+// it was already computed
 func (p *pluginConfig) getPluginConfigDataNode(pluginType core.PluginType, name string, ver int) *cdata.ConfigDataNode {
+	key := pluginConfigCacheKey(pluginType, name, ver)
+	p.mutex.RLock()
+	res, ok := p.pluginCache[key]
+	p.mutex.RUnlock()
+	if ok {
+		return res
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.pluginConfigDataNode(pluginType, name, ver)
+}
+
+func pluginConfigCacheKey(pluginType core.PluginType, name string, ver int) string {
+	return fmt.Sprintf("%d"+core.Separator+"%s"+core.Separator+"%d", pluginType, name, ver)
+}
+
+// pluginConfigDataNode returns the config of a plugin and caches it, the
+// caller holds the write lock
+func (p *pluginConfig) pluginConfigDataNode(pluginType core.PluginType, name string, ver int) *cdata.ConfigDataNode {
 	// check cache
-	key := fmt.Sprintf("%d"+core.Separator+"%s"+core.Separator+"%d", pluginType, name, ver)
+	key := pluginConfigCacheKey(pluginType, name, ver)
 	if res, ok := p.pluginCache[key]; ok {
 		return res
 	}
@@ -0,0 +1,231 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package control
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/intelsdi-x/snap/core"
+	"github.com/intelsdi-x/snap/core/cdata"
+)
+
+var (
+	// ErrUnknownPluginConfigAction - error message when a change of the
+	// global plugin config is neither a set nor a delete
+	ErrUnknownPluginConfigAction = errors.New("Unknown plugin config action")
+)
+
+// pluginConfigStore is the content of the plugin config store, the changes
+// made to the global plugin config through the REST API.  They are applied
+// again, in order, on top of the config file when snapteld starts.
+type pluginConfigStore struct {
+	Changes []core.PluginConfigChange `json:"changes"`
+}
+
+// PluginConfigStore is the PluginControlOpt which loads the changes of the
+// global plugin config from the plugin config store of the configuration,
+// if any, and updates the subscriptions when the global plugin config
+// changes.
+func PluginConfigStore(cfg *Config) PluginControlOpt {
+	return func(c *pluginControl) {
+		if err := cfg.loadPluginConfigStore(); err != nil {
+			controlLogger.WithFields(log.Fields{
+				"_block": "plugin-config-store",
+				"path":   cfg.PluginConfigPath,
+			}).Error(err)
+		}
+		cfg.pluginConfigState.mutex.Lock()
+		cfg.pluginConfigState.changed = c.pluginConfigChanged
+		cfg.pluginConfigState.mutex.Unlock()
+	}
+}
+
+// pluginConfigChanged processes the subscription groups again so that the
+// running tasks collect with the new global plugin config
+func (p *pluginControl) pluginConfigChanged() {
+	for _, err := range p.subscriptionGroups.Process() {
+		controlLogger.WithFields(log.Fields{
+			"_block": "plugin-config-changed",
+		}).Error(err)
+	}
+}
+
+// ChangePluginConfig records a change of the global plugin config in the
+// history and in the plugin config store, then applies it.  A change which
+// cannot be stored is not applied.  It returns the resulting config of the
+// level of the change.
+func (p *Config) ChangePluginConfig(change core.PluginConfigChange) (cdata.ConfigDataNode, error) {
+	p.pluginConfigState.mutex.Lock()
+	if change.Time.IsZero() {
+		change.Time = time.Now()
+	}
+	if change.Version < 1 {
+		change.Version = 0
+	}
+	if err := checkPluginConfigChange(change); err != nil {
+		p.pluginConfigState.mutex.Unlock()
+		return *cdata.NewNode(), err
+	}
+	history := append(p.pluginConfigState.history[:len(p.pluginConfigState.history):len(p.pluginConfigState.history)], change)
+	if err := p.writePluginConfigStore(history); err != nil {
+		p.pluginConfigState.mutex.Unlock()
+		log.WithFields(log.Fields{
+			"_module": "config",
+			"_block":  "change-plugin-config",
+			"path":    p.PluginConfigPath,
+		}).Error(err)
+		return *cdata.NewNode(), err
+	}
+	res, _ := p.Plugins.apply(change)
+	p.pluginConfigState.history = history
+	changed := p.pluginConfigState.changed
+	p.pluginConfigState.mutex.Unlock()
+
+	if changed != nil {
+		changed()
+	}
+	return res, nil
+}
+
+// checkPluginConfigChange returns an error for a change which cannot be
+// applied to the plugin config
+func checkPluginConfigChange(change core.PluginConfigChange) error {
+	if change.Action != core.PluginConfigSet && change.Action != core.PluginConfigDelete {
+		return fmt.Errorf("%v: %v", ErrUnknownPluginConfigAction, change.Action)
+	}
+	if change.Type != "" {
+		if _, err := core.GetPluginType(change.Type); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PluginConfigHistory returns the changes made to the global plugin config,
+// the oldest first
+func (p *Config) PluginConfigHistory() []core.PluginConfigChange {
+	p.pluginConfigState.mutex.RLock()
+	defer p.pluginConfigState.mutex.RUnlock()
+	history := make([]core.PluginConfigChange, len(p.pluginConfigState.history))
+	copy(history, p.pluginConfigState.history)
+	return history
+}
+
+// loadPluginConfigStore applies the changes of the plugin config store
+func (p *Config) loadPluginConfigStore() error {
+	if p.PluginConfigPath == "" {
+		return nil
+	}
+	b, err := ioutil.ReadFile(p.PluginConfigPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var store pluginConfigStore
+	if err := json.Unmarshal(b, &store); err != nil {
+		return fmt.Errorf("%v: %v", p.PluginConfigPath, err)
+	}
+	p.pluginConfigState.mutex.Lock()
+	defer p.pluginConfigState.mutex.Unlock()
+	for _, change := range store.Changes {
+		if _, err := p.Plugins.apply(change); err != nil {
+			return fmt.Errorf("%v: %v", p.PluginConfigPath, err)
+		}
+		p.pluginConfigState.history = append(p.pluginConfigState.history, change)
+	}
+	log.WithFields(log.Fields{
+		"_module": "config",
+		"_block":  "load-plugin-config-store",
+		"path":    p.PluginConfigPath,
+		"changes": len(store.Changes),
+	}).Info("plugin config changes loaded")
+	return nil
+}
+
+// writePluginConfigStore replaces the plugin config store with history
+func (p *Config) writePluginConfigStore(history []core.PluginConfigChange) error {
+	if p.PluginConfigPath == "" {
+		return nil
+	}
+	b, err := json.MarshalIndent(pluginConfigStore{Changes: history}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p.PluginConfigPath), 0755); err != nil {
+		return err
+	}
+	tmp := p.PluginConfigPath + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p.PluginConfigPath)
+}
+
+// apply applies a change to the plugin config
+func (p *pluginConfig) apply(change core.PluginConfigChange) (cdata.ConfigDataNode, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if change.Type == "" {
+		switch change.Action {
+		case core.PluginConfigSet:
+			if change.Config != nil {
+				p.mergePluginConfigDataNodeAll(change.Config)
+			}
+		case core.PluginConfigDelete:
+			for _, field := range change.Fields {
+				p.deletePluginConfigDataNodeFieldAll(field)
+			}
+		default:
+			return *cdata.NewNode(), fmt.Errorf("%v: %v", ErrUnknownPluginConfigAction, change.Action)
+		}
+		return *p.All, nil
+	}
+	typ, err := core.GetPluginType(change.Type)
+	if err != nil {
+		return *cdata.NewNode(), err
+	}
+	ver := change.Version
+	if ver < 1 {
+		ver = -2
+	}
+	switch change.Action {
+	case core.PluginConfigSet:
+		if change.Config != nil {
+			p.mergePluginConfigDataNode(typ, change.Name, ver, change.Config)
+		}
+	case core.PluginConfigDelete:
+		for _, field := range change.Fields {
+			p.deletePluginConfigDataNodeField(typ, change.Name, ver, field)
+		}
+	default:
+		return *cdata.NewNode(), fmt.Errorf("%v: %v", ErrUnknownPluginConfigAction, change.Action)
+	}
+	return *p.pluginConfigDataNode(typ, change.Name, ver), nil
+}
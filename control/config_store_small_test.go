@@ -0,0 +1,115 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package control
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/intelsdi-x/snap/core"
+	"github.com/intelsdi-x/snap/core/cdata"
+	"github.com/intelsdi-x/snap/core/ctypes"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPluginConfigStore(t *testing.T) {
+	Convey("Given a plugin config store", t, func() {
+		dir, err := ioutil.TempDir("", "snap-plugin-config")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		cfg := GetDefaultConfig()
+		cfg.PluginConfigPath = filepath.Join(dir, "store", "plugin_config.json")
+		changed := 0
+		cfg.pluginConfigState.changed = func() { changed++ }
+
+		cdn := cdata.NewNode()
+		cdn.AddItem("user", ctypes.ConfigValueStr{Value: "jane"})
+		res, err := cfg.ChangePluginConfig(core.PluginConfigChange{
+			User:    "admin",
+			Action:  core.PluginConfigSet,
+			Type:    "collector",
+			Name:    "mock",
+			Version: 2,
+			Config:  cdn,
+		})
+		So(err, ShouldBeNil)
+		So(res.Table()["user"], ShouldResemble, ctypes.ConfigValueStr{Value: "jane"})
+		cfg.MergePluginConfigDataNodeAll(cdn)
+		cfg.DeletePluginConfigDataNodeFieldAll("user")
+
+		Convey("The changes are applied at their level and recorded", func() {
+			So(changed, ShouldEqual, 3)
+			So(cfg.Plugins.getPluginConfigDataNode(core.CollectorPluginType, "mock", 2).Table(), ShouldContainKey, "user")
+			So(cfg.Plugins.getPluginConfigDataNode(core.CollectorPluginType, "mock", 1).Table(), ShouldNotContainKey, "user")
+			So(cfg.Plugins.All.Table(), ShouldNotContainKey, "user")
+			history := cfg.PluginConfigHistory()
+			So(history, ShouldHaveLength, 3)
+			So(history[0].User, ShouldEqual, "admin")
+			So(history[0].Time.IsZero(), ShouldBeFalse)
+			So(history[2].Action, ShouldEqual, core.PluginConfigDelete)
+		})
+		Convey("The changes are replayed on top of the config file", func() {
+			cfg2 := GetDefaultConfig()
+			cfg2.PluginConfigPath = cfg.PluginConfigPath
+			So(cfg2.loadPluginConfigStore(), ShouldBeNil)
+			So(cfg2.PluginConfigHistory(), ShouldHaveLength, 3)
+			So(cfg2.Plugins.getPluginConfigDataNode(core.CollectorPluginType, "mock", 2).Table()["user"], ShouldResemble, ctypes.ConfigValueStr{Value: "jane"})
+			So(cfg2.Plugins.All.Table(), ShouldNotContainKey, "user")
+		})
+		Convey("An unknown action is rejected", func() {
+			_, err := cfg.ChangePluginConfig(core.PluginConfigChange{Action: "replace"})
+			So(err, ShouldNotBeNil)
+			So(cfg.PluginConfigHistory(), ShouldHaveLength, 3)
+		})
+		Convey("A change which cannot be stored is not applied", func() {
+			// the directory of the store is a file
+			blocker := filepath.Join(dir, "blocker")
+			So(ioutil.WriteFile(blocker, nil, 0600), ShouldBeNil)
+			cfg.PluginConfigPath = filepath.Join(blocker, "plugin_config.json")
+			cdn := cdata.NewNode()
+			cdn.AddItem("password", ctypes.ConfigValueStr{Value: "secret"})
+			_, err := cfg.ChangePluginConfig(core.PluginConfigChange{Action: core.PluginConfigSet, Config: cdn})
+			So(err, ShouldNotBeNil)
+			So(cfg.Plugins.All.Table(), ShouldNotContainKey, "password")
+			So(cfg.PluginConfigHistory(), ShouldHaveLength, 3)
+			So(changed, ShouldEqual, 3)
+		})
+		Convey("The config can be read while it changes", func() {
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for i := 0; i < 100; i++ {
+					cfg.MergePluginConfigDataNode(core.CollectorPluginType, "mock", 2, cdn)
+				}
+			}()
+			for i := 0; i < 100; i++ {
+				cfg.GetPluginConfigDataNode(core.CollectorPluginType, "mock", 2)
+				cfg.GetPluginConfigDataNodeAll()
+				cfg.PluginConfigHistory()
+			}
+			<-done
+			So(cfg.PluginConfigHistory(), ShouldHaveLength, 103)
+		})
+	})
+}
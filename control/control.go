@@ -33,6 +33,7 @@ import (
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 
 	"github.com/intelsdi-x/gomit"
@@ -44,9 +45,12 @@ import (
 	"github.com/intelsdi-x/snap/core/control_event"
 	"github.com/intelsdi-x/snap/core/ctypes"
 	"github.com/intelsdi-x/snap/core/serror"
+	"github.com/intelsdi-x/snap/grpc/controlproxy"
 	"github.com/intelsdi-x/snap/grpc/controlproxy/rpc"
-	"github.com/intelsdi-x/snap/pkg/aci"
+	"github.com/intelsdi-x/snap/pkg/bundle"
+	"github.com/intelsdi-x/snap/pkg/dirwatch"
 	"github.com/intelsdi-x/snap/pkg/psigning"
+	"github.com/intelsdi-x/snap/pkg/rpcutil"
 )
 
 const (
@@ -68,6 +72,9 @@ var (
 
 	// ErrControllerNotStarted - error message when the Controller was not started
 	ErrControllerNotStarted = errors.New("Must start Controller before use")
+
+	// ErrNoStreamingPlugins - error message when metrics are streamed for a task without streaming collectors
+	ErrNoStreamingPlugins = errors.New("No streaming collecting plugin in task")
 )
 
 type pluginControl struct {
@@ -85,13 +92,35 @@ type pluginControl struct {
 
 	pluginTrust  int
 	keyringFiles []string
+	trustPolicy  *psigning.TrustPolicy
 	// used to cleanly shutdown the GRPC server
 	grpcServer  *grpc.Server
 	closingChan chan bool
 	wg          sync.WaitGroup
+	// closed when control stops, to end the work started in the background
+	quitChan chan struct{}
 
 	subscriptionGroups ManagesSubscriptionGroups
 	grpcSecurity       client.GRPCSecurity
+	// settings of the gRPC channels to the plugins
+	pluginChannels pluginChannels
+
+	// tasks which metrics are always collected from the plugins
+	cacheBypass      map[string]bool
+	cacheBypassMutex sync.RWMutex
+
+	// stop the merged streams of the tasks, keyed by task id
+	streamStops      map[string]chan struct{}
+	streamStopsMutex sync.Mutex
+
+	// staged swaps of plugins
+	canaries *canaries
+
+	// plugins loaded through the REST API, loaded again on restart
+	repository *pluginRepository
+
+	// watchers of the auto discover paths
+	watchers []*dirwatch.Watcher
 }
 
 type subscribedPlugin struct {
@@ -115,7 +144,12 @@ type runsPlugins interface {
 	SetMetricCatalog(catalogsMetrics)
 	SetPluginManager(managesPlugins)
 	Monitor() *monitor
+	Autoscaler() *autoscaler
+	StartAutoscaler(time.Duration)
 	runPlugin(string, *pluginDetails) error
+	startOnDemand(string) error
+	connectRemote(string) error
+	remoteStatus(string) []core.RemoteEndpointStatus
 	SetPluginLoadTimeout(int)
 }
 
@@ -150,7 +184,7 @@ type catalogsMetrics interface {
 }
 
 type managesSigning interface {
-	ValidateSignature([]string, string, []byte) error
+	Verify([]string, string, []byte) (*psigning.Signer, error)
 }
 
 // PluginControlOpt is used to set optional parameters on the pluginControl struct
@@ -170,6 +204,113 @@ func CacheExpiration(t time.Duration) PluginControlOpt {
 	}
 }
 
+// CacheLimits is the PluginControlOpt which sets the maximum number of entries
+// and the approximate maximum size in bytes of the metric cache.  A zero value
+// disables the respective limit.
+func CacheLimits(entries, bytes int) PluginControlOpt {
+	return func(c *pluginControl) {
+		strategy.GlobalCacheMaxEntries = entries
+		strategy.GlobalCacheMaxBytes = int64(bytes)
+	}
+}
+
+// RoutingHashKey is the PluginControlOpt which sets the key used by the
+// consistent-hash routing strategy
+func RoutingHashKey(key string) PluginControlOpt {
+	return func(c *pluginControl) {
+		hk, err := strategy.ParseHashKey(key)
+		if err != nil {
+			controlLogger.WithFields(log.Fields{
+				"_block":           "routing-hash-key",
+				"routing-hash-key": key,
+				"default":          strategy.ConsistentHashKey.String(),
+			}).Error(err)
+			return
+		}
+		strategy.ConsistentHashKey = hk
+	}
+}
+
+// Autoscale is the PluginControlOpt which sets the autoscale policies of the
+// plugin pools and starts the autoscaler when autoscaling is enabled
+func Autoscale(cfg *autoscaleConfig) PluginControlOpt {
+	return func(c *pluginControl) {
+		if cfg == nil || !cfg.Enable {
+			strategy.AutoscalingEnabled = false
+			return
+		}
+		strategy.AutoscalingEnabled = true
+		strategy.DefaultAutoscalePolicy = strategy.AutoscalePolicy{
+			Min:               cfg.MinRunningPlugins,
+			LatencyThreshold:  cfg.LatencyThreshold.Duration,
+			InFlightThreshold: cfg.InFlightThreshold,
+			IdleTimeout:       cfg.IdleTimeout.Duration,
+		}
+		policies := map[string]strategy.AutoscalePolicy{}
+		for key, limits := range cfg.Plugins {
+			if limits == nil {
+				continue
+			}
+			policies[key] = strategy.AutoscalePolicy{
+				Min: limits.MinRunningPlugins,
+				Max: limits.MaxRunningPlugins,
+			}
+		}
+		strategy.AutoscalePolicies = policies
+		interval := cfg.Interval.Duration
+		if interval <= 0 {
+			interval = DefaultAutoscaleInterval
+		}
+		c.pluginRunner.StartAutoscaler(interval)
+	}
+}
+
+// PluginLifecycles is the PluginControlOpt which sets the lifecycle of the
+// plugins keyed by {plugin_type}:{plugin_name}.  The lifecycle keyed by
+// "all" applies to the plugins without a specific lifecycle.
+func PluginLifecycles(lifecycles map[string]string) PluginControlOpt {
+	return func(c *pluginControl) {
+		strategy.DefaultLifecycle = strategy.Lifecycle{Mode: strategy.LazyLifecycle}
+		strategy.Lifecycles = map[string]strategy.Lifecycle{}
+		for key, value := range lifecycles {
+			l, err := strategy.ParseLifecycle(value)
+			if err != nil {
+				controlLogger.WithFields(log.Fields{
+					"_block":    "plugin-lifecycle",
+					"plugin":    key,
+					"lifecycle": value,
+				}).Error(err)
+				continue
+			}
+			if key == "all" {
+				strategy.DefaultLifecycle = l
+				continue
+			}
+			strategy.Lifecycles[key] = l
+		}
+	}
+}
+
+// PluginRepository is the PluginControlOpt which sets the directory where
+// the plugins loaded through the REST API are stored.  An empty path disables
+// the plugin repository.
+func PluginRepository(path string) PluginControlOpt {
+	return func(c *pluginControl) {
+		if path == "" {
+			return
+		}
+		r, err := newPluginRepository(path)
+		if err != nil {
+			controlLogger.WithFields(log.Fields{
+				"_block": "plugin-repository",
+				"path":   path,
+			}).Error(err)
+			return
+		}
+		c.repository = r
+	}
+}
+
 // OptSetConfig sets the plugin control configuration.
 func OptSetConfig(cfg *Config) PluginControlOpt {
 	return func(c *pluginControl) {
@@ -200,12 +341,19 @@ func New(cfg *Config) *pluginControl {
 	opts := []PluginControlOpt{
 		MaxRunningPlugins(cfg.MaxRunningPlugins),
 		CacheExpiration(cfg.CacheExpiration.Duration),
+		CacheLimits(cfg.CacheMaxEntries, cfg.CacheMaxBytes),
+		RoutingHashKey(cfg.RoutingHashKey),
+		Autoscale(cfg.Autoscale),
+		PluginLifecycles(cfg.PluginLifecycle),
+		PluginRepository(cfg.PluginRepoPath),
 		OptSetConfig(cfg),
+		PluginConfigStore(cfg),
 		OptSetTags(cfg.Tags),
 		MaxPluginRestarts(cfg),
 	}
 	c := &pluginControl{}
 	c.Config = cfg
+	c.canaries = newCanaries()
 	// Initialize components
 	// Event Manager
 	c.eventManager = gomit.NewEventController()
@@ -220,11 +368,22 @@ func New(cfg *Config) *pluginControl {
 		"_block": "new",
 	}).Debug("metric catalog created")
 
+	c.pluginChannels = pluginChannels{
+		compression:      cfg.PluginCompression,
+		maxMessageSize:   cfg.PluginMaxMessageSize,
+		collectChunkSize: cfg.PluginCollectChunkSize,
+		sockets:          newPluginSockets(cfg.TempDirPath),
+	}
+
 	managerOpts := []pluginManagerOpt{
 		OptSetPprof(cfg.Pprof),
 		OptSetTempDirPath(cfg.TempDirPath),
+		OptSetUnixSockets(cfg.PluginUnixSockets),
+		OptSetManagerChannels(c.pluginChannels),
+	}
+	runnerOpts := []pluginRunnerOpt{
+		OptSetRunnerChannels(c.pluginChannels),
 	}
-	runnerOpts := []pluginRunnerOpt{}
 	if cfg.IsTLSEnabled() {
 		if cfg.CACertPaths != "" {
 			certPaths := filepath.SplitList(cfg.CACertPaths)
@@ -322,6 +481,7 @@ func (p *pluginControl) RegisterEventHandler(name string, h gomit.Handler) error
 func (p *pluginControl) Start() error {
 	// Start pluginManager when pluginControl starts
 	p.Started = true
+	p.quitChan = make(chan struct{})
 	controlLogger.WithFields(log.Fields{
 		"_block": "start",
 	}).Info("control started")
@@ -353,100 +513,10 @@ func (p *pluginControl) Start() error {
 				}).Fatal(err)
 			}
 			for _, file := range files {
-				fileName := file.Name()
-
-				statCheck := file
-				if file.Mode()&os.ModeSymlink != 0 {
-					realPath, err := filepath.EvalSymlinks(filepath.Join(fullPath, fileName))
-					if err != nil {
-						controlLogger.WithFields(log.Fields{
-							"_block":           "start",
-							"autodiscoverpath": pa,
-							"error":            err,
-							"plugin":           fileName,
-						}).Error("Cannot follow symlink")
-						continue
-					}
-					statCheck, err = os.Stat(realPath)
-					if err != nil {
-						controlLogger.WithFields(log.Fields{
-							"_block":           "start",
-							"autodiscoverpath": pa,
-							"error":            err,
-							"plugin":           fileName,
-							"target-path":      realPath,
-						}).Error("Target of symlink inacessible")
-						continue
-					}
-				}
-
-				if statCheck.IsDir() {
-					controlLogger.WithFields(log.Fields{
-						"_block":           "start",
-						"autodiscoverpath": pa,
-					}).Warning("Ignoring subdirectory: ", fileName)
-					continue
-				}
-				// Ignore tasks files (JSON and YAML)
-				fname := strings.ToLower(fileName)
-				if strings.HasSuffix(fname, ".json") || strings.HasSuffix(fname, ".yaml") || strings.HasSuffix(fname, ".yml") {
-					controlLogger.WithFields(log.Fields{
-						"_block":           "start",
-						"autodiscoverpath": pa,
-					}).Warning("Ignoring JSON/Yaml file: ", fileName)
-					continue
-				}
-				// if the file is a plugin package (which would have a suffix of '.aci') or if the file
-				// is not a plugin signing file (which would have a suffix of '.asc'), then attempt to
-				// automatically load the file as a plugin
-				if strings.HasSuffix(fileName, ".aci") || !(strings.HasSuffix(fileName, ".asc")) {
-					// check to makd sure the file is executable by someone (even if it isn't you); if no one
-					// can execute this file then skip it (and include a warning in the log output)
-					if (statCheck.Mode() & 0111) == 0 {
-						controlLogger.WithFields(log.Fields{
-							"_block":           "start",
-							"autodiscoverpath": pa,
-							"plugin":           fileName,
-						}).Warn("Auto-loading of plugin '", fileName, "' skipped (plugin not executable)")
-						continue
-					}
-					rp, err := core.NewRequestedPlugin(path.Join(fullPath, fileName), p.GetTempDir(), nil)
-					if err != nil {
-						controlLogger.WithFields(log.Fields{
-							"_block":           "start",
-							"autodiscoverpath": pa,
-							"plugin":           fileName,
-						}).Error(err)
-					}
-					signatureFile := fileName + ".asc"
-					if _, err := os.Stat(path.Join(fullPath, signatureFile)); err == nil {
-						err = rp.ReadSignatureFile(path.Join(fullPath, signatureFile))
-						if err != nil {
-							controlLogger.WithFields(log.Fields{
-								"_block":           "start",
-								"autodiscoverpath": pa,
-								"plugin":           fileName + ".asc",
-							}).Error(err)
-						}
-					}
-					pl, err := p.Load(rp)
-					if err != nil {
-						controlLogger.WithFields(log.Fields{
-							"_block":           "start",
-							"autodiscoverpath": fullPath,
-							"plugin":           fileName,
-						}).Error(err)
-					} else {
-						controlLogger.WithFields(log.Fields{
-							"_block":           "start",
-							"autodiscoverpath": fullPath,
-							"plugin-file-name": fileName,
-							"plugin-name":      pl.Name(),
-							"plugin-version":   pl.Version(),
-							"plugin-type":      pl.TypeName(),
-						}).Info("Loading plugin")
-					}
-				}
+				p.autoLoadPlugin(pa, fullPath, file)
+			}
+			if p.Config.AutoDiscoverWatch {
+				p.watchAutodiscoverPath(fullPath)
 			}
 		}
 	} else {
@@ -455,13 +525,22 @@ func (p *pluginControl) Start() error {
 		}).Info("auto discover path is disabled")
 	}
 
+	// load the plugins left loaded in the plugin repository
+	p.loadRepository()
+
 	lis, err := net.Listen("tcp", fmt.Sprintf("%v:%v", p.Config.ListenAddr, p.Config.ListenPort))
 	if err != nil {
 		controlLogger.WithField("error", err.Error()).Error("Failed to start control grpc listener")
 		return err
 	}
 
-	opts := []grpc.ServerOption{}
+	compression := rpcutil.NegotiateCompression(p.pluginChannels.compression, p.pluginChannels.compression)
+	opts, err := rpcutil.ChannelServerOptions(compression, p.pluginChannels.maxMessageSize)
+	if err != nil {
+		return err
+	}
+	// the proxies of other snapteld negotiate the compression of their calls
+	opts = append(opts, rpcutil.AnnounceCompression(compression))
 	p.closingChan = make(chan bool, 1)
 	p.grpcServer = grpc.NewServer(opts...)
 	rpc.RegisterMetricManagerServer(p.grpcServer, &ControlGRPCServer{p})
@@ -483,9 +562,116 @@ func (p *pluginControl) Start() error {
 	return nil
 }
 
+// autoLoadPlugin loads the plugin file found in an auto discover path, along
+// with its signature if any.  It returns nil if the file is not a plugin or
+// the plugin failed to load.
+func (p *pluginControl) autoLoadPlugin(pa, fullPath string, file os.FileInfo) core.CatalogedPlugin {
+	fileName := file.Name()
+
+	statCheck := file
+	if file.Mode()&os.ModeSymlink != 0 {
+		realPath, err := filepath.EvalSymlinks(filepath.Join(fullPath, fileName))
+		if err != nil {
+			controlLogger.WithFields(log.Fields{
+				"_block":           "auto-load-plugin",
+				"autodiscoverpath": pa,
+				"error":            err,
+				"plugin":           fileName,
+			}).Error("Cannot follow symlink")
+			return nil
+		}
+		statCheck, err = os.Stat(realPath)
+		if err != nil {
+			controlLogger.WithFields(log.Fields{
+				"_block":           "auto-load-plugin",
+				"autodiscoverpath": pa,
+				"error":            err,
+				"plugin":           fileName,
+				"target-path":      realPath,
+			}).Error("Target of symlink inacessible")
+			return nil
+		}
+	}
+
+	if statCheck.IsDir() {
+		controlLogger.WithFields(log.Fields{
+			"_block":           "auto-load-plugin",
+			"autodiscoverpath": pa,
+		}).Warning("Ignoring subdirectory: ", fileName)
+		return nil
+	}
+	// Ignore tasks files (JSON and YAML)
+	fname := strings.ToLower(fileName)
+	if strings.HasSuffix(fname, ".json") || strings.HasSuffix(fname, ".yaml") || strings.HasSuffix(fname, ".yml") {
+		controlLogger.WithFields(log.Fields{
+			"_block":           "auto-load-plugin",
+			"autodiscoverpath": pa,
+		}).Warning("Ignoring JSON/Yaml file: ", fileName)
+		return nil
+	}
+	// if the file is a plugin bundle (which would have a suffix of '.tar.gz' or '.tgz') or if
+	// the file is not a plugin signing file (which would have a suffix of '.asc'), then attempt
+	// to automatically load the file as a plugin
+	if bundle.IsBundle(fileName) || !(strings.HasSuffix(fileName, ".asc")) {
+		// check to makd sure the file is executable by someone (even if it isn't you); if no one
+		// can execute this file then skip it (and include a warning in the log output)
+		if (statCheck.Mode() & 0111) == 0 {
+			controlLogger.WithFields(log.Fields{
+				"_block":           "auto-load-plugin",
+				"autodiscoverpath": pa,
+				"plugin":           fileName,
+			}).Warn("Auto-loading of plugin '", fileName, "' skipped (plugin not executable)")
+			return nil
+		}
+		rp, err := core.NewRequestedPlugin(path.Join(fullPath, fileName), p.GetTempDir(), nil)
+		if err != nil {
+			controlLogger.WithFields(log.Fields{
+				"_block":           "auto-load-plugin",
+				"autodiscoverpath": pa,
+				"plugin":           fileName,
+			}).Error(err)
+			return nil
+		}
+		rp.SetAutoLoaded(true)
+		signatureFile := fileName + ".asc"
+		if _, err := os.Stat(path.Join(fullPath, signatureFile)); err == nil {
+			err = rp.ReadSignatureFile(path.Join(fullPath, signatureFile))
+			if err != nil {
+				controlLogger.WithFields(log.Fields{
+					"_block":           "auto-load-plugin",
+					"autodiscoverpath": pa,
+					"plugin":           fileName + ".asc",
+				}).Error(err)
+			}
+		}
+		pl, err := p.Load(rp)
+		if err != nil {
+			controlLogger.WithFields(log.Fields{
+				"_block":           "auto-load-plugin",
+				"autodiscoverpath": fullPath,
+				"plugin":           fileName,
+			}).Error(err)
+			return nil
+		}
+		controlLogger.WithFields(log.Fields{
+			"_block":           "auto-load-plugin",
+			"autodiscoverpath": fullPath,
+			"plugin-file-name": fileName,
+			"plugin-name":      pl.Name(),
+			"plugin-version":   pl.Version(),
+			"plugin-type":      pl.TypeName(),
+		}).Info("Loading plugin")
+		return pl
+	}
+	return nil
+}
+
 func (p *pluginControl) Stop() {
 	// set the Started flag to false (since we're stopping the server)
 	p.Started = false
+	if p.quitChan != nil {
+		close(p.quitChan)
+	}
 
 	// and add a boolean to the p.closingChan (used for error handling in the
 	// goroutine that is listening for connections)
@@ -495,6 +681,15 @@ func (p *pluginControl) Stop() {
 	p.grpcServer.Stop()
 	p.wg.Wait()
 
+	// stop watching the auto discover paths
+	for _, w := range p.watchers {
+		w.Close()
+	}
+	p.watchers = nil
+
+	// stop the pending staged swaps
+	p.canaries.stop()
+
 	// stop runner
 	err := p.pluginRunner.Stop()
 	if err != nil {
@@ -541,10 +736,11 @@ func (p *pluginControl) Load(rp *core.RequestedPlugin) (core.CatalogedPlugin, se
 		return nil, se
 	}
 
-	pl, se := p.pluginManager.LoadPlugin(details, p.eventManager)
+	pl, se := p.loadTrustedPlugin(details)
 	if se != nil {
 		return nil, se
 	}
+	p.storePlugin(rp, pl)
 
 	// If plugin was loaded from a package, remove ExecPath for
 	// the temporary plugin that was used for load
@@ -552,6 +748,8 @@ func (p *pluginControl) Load(rp *core.RequestedPlugin) (core.CatalogedPlugin, se
 		pl.Details.ExecPath = ""
 	}
 
+	p.applyLifecycle(pl)
+
 	// defer sending event
 	event := &control_event.LoadPluginEvent{
 		Name:    pl.Meta.Name,
@@ -563,41 +761,152 @@ func (p *pluginControl) Load(rp *core.RequestedPlugin) (core.CatalogedPlugin, se
 	return pl, nil
 }
 
-func (p *pluginControl) verifySignature(rp *core.RequestedPlugin) (bool, serror.SnapError) {
+// applyLifecycle sets the lifecycle of the pool of a loaded plugin and starts
+// its warm instances.  The lifecycle requested at load time takes precedence
+// over the one of the config.
+func (p *pluginControl) applyLifecycle(lp *loadedPlugin) {
+	f := map[string]interface{}{
+		"_block":         "apply-lifecycle",
+		"plugin-name":    lp.Name(),
+		"plugin-version": lp.Version(),
+		"plugin-type":    lp.TypeName(),
+	}
+	l := strategy.LifecycleFor(lp.Key())
+	if lp.Details.Lifecycle != nil {
+		l = *lp.Details.Lifecycle
+	}
+	// standalone plugins are not started by snapteld
+	if lp.Details.Uri != nil && l.Mode != strategy.LazyLifecycle {
+		controlLogger.WithFields(f).Warn("lifecycle ignored for a standalone plugin")
+		l = strategy.Lifecycle{Mode: strategy.LazyLifecycle}
+	}
+	if l.Mode == strategy.LazyLifecycle {
+		// a pool may remain from a previous load of the plugin
+		if pool, _ := p.pluginRunner.AvailablePlugins().getPool(lp.Key()); pool != nil {
+			pool.SetLifecycle(l)
+		}
+		return
+	}
+	pool, err := p.pluginRunner.AvailablePlugins().getOrCreatePool(lp.Key())
+	if err != nil {
+		controlLogger.WithFields(f).Error(err)
+		return
+	}
+	pool.SetLifecycle(l)
+	controlLogger.WithFields(f).Infof("plugin lifecycle set to %s", l)
+	for pool.Cold() {
+		if err := p.pluginRunner.runPlugin(lp.Name(), lp.Details); err != nil {
+			controlLogger.WithFields(f).Error(err)
+			break
+		}
+	}
+	// the autoscaler keeps the warm instances and reaps the on-demand ones
+	p.pluginRunner.StartAutoscaler(DefaultAutoscaleInterval)
+}
+
+// verifySignature validates the signature of the file at the given path
+// according to the plugin trust level and returns the signer, nil if the
+// plugin is not signed
+func (p *pluginControl) verifySignature(path string, signature []byte) (*psigning.Signer, serror.SnapError) {
 	f := map[string]interface{}{
 		"_block": "verifySignature",
 	}
 	switch p.pluginTrust {
 	case PluginTrustDisabled:
-		return false, nil
-	case PluginTrustEnabled:
-		err := p.signingManager.ValidateSignature(p.keyringFiles, rp.Path(), rp.Signature())
-		if err != nil {
-			return false, serror.New(err)
-		}
+		return nil, nil
 	case PluginTrustWarn:
-		if rp.Signature() == nil {
-			controlLogger.WithFields(f).Warn("Loading unsigned plugin ", rp.Path())
-			return false, nil
+		if signature == nil {
+			controlLogger.WithFields(f).Warn("Loading unsigned plugin ", path)
+			return nil, nil
+		}
+	}
+	signer, err := p.signingManager.Verify(p.keyringFiles, path, signature)
+	if err != nil {
+		return nil, serror.New(err)
+	}
+	if p.trustPolicy.Revoked(signer.KeyID) {
+		return nil, serror.New(psigning.ErrKeyRevoked, map[string]interface{}{
+			"key-id": signer.KeyID,
+		})
+	}
+	return signer, nil
+}
+
+// loadTrustedPlugin loads a plugin and checks it against the trust policy,
+// the plugin is unloaded if it is not allowed.  When the trust level is
+// enabled, a plugin whose file is named after its type and name is checked
+// before it is executed.
+func (p *pluginControl) loadTrustedPlugin(details *pluginDetails) (*loadedPlugin, serror.SnapError) {
+	// the signer is checked before the plugin is executed, against the rule
+	// of the plugin when its file name tells its type and name, or else
+	// against every rule
+	if p.pluginTrust == PluginTrustEnabled && len(details.Exec) > 0 {
+		f := map[string]interface{}{
+			"_block":      "load-trusted-plugin",
+			"plugin-file": details.Exec[0],
+		}
+		var err error
+		if typeName, name, ok := pluginFileType(filepath.Base(details.Exec[0])); ok {
+			f["plugin-name"] = name
+			f["plugin-type"] = typeName
+			err = p.trustPolicy.Allowed(typeName, name, details.Signer)
+		} else {
+			err = p.trustPolicy.AllowedByAnyRule(details.Signer)
 		}
-		err := p.signingManager.ValidateSignature(p.keyringFiles, rp.Path(), rp.Signature())
 		if err != nil {
-			return false, serror.New(err)
+			return nil, serror.New(err, f)
 		}
 	}
-	return true, nil
+	lp, serr := p.pluginManager.LoadPlugin(details, p.eventManager)
+	if serr != nil {
+		return nil, serr
+	}
+	if p.pluginTrust == PluginTrustDisabled {
+		return lp, nil
+	}
+	err := p.trustPolicy.Allowed(lp.TypeName(), lp.Name(), details.Signer)
+	if err == nil {
+		return lp, nil
+	}
+	f := map[string]interface{}{
+		"_block":         "load-trusted-plugin",
+		"plugin-name":    lp.Name(),
+		"plugin-version": lp.Version(),
+		"plugin-type":    lp.TypeName(),
+	}
+	if p.pluginTrust == PluginTrustWarn {
+		controlLogger.WithFields(f).Warn(err)
+		return lp, nil
+	}
+	if _, uerr := p.pluginManager.UnloadPlugin(lp); uerr != nil {
+		controlLogger.WithFields(f).Error(uerr)
+	}
+	return nil, serror.New(err, f)
+}
 
+// pluginFileType returns the type and the name of a plugin from the name of
+// its file, snap-plugin-<type>-<name>.  ok is false for the files which do
+// not follow this convention.
+func pluginFileType(fileName string) (typeName, name string, ok bool) {
+	if !strings.HasPrefix(fileName, "snap-plugin-") {
+		return "", "", false
+	}
+	rest := strings.TrimSuffix(strings.TrimPrefix(fileName, "snap-plugin-"), ".exe")
+	for _, t := range []string{"streaming-collector", "streaming-publisher", "collector", "processor", "publisher"} {
+		if strings.HasPrefix(rest, t+"-") && len(rest) > len(t)+1 {
+			return t, rest[len(t)+1:], true
+		}
+	}
+	return "", "", false
 }
 
 func (p *pluginControl) returnPluginDetails(rp *core.RequestedPlugin) (*pluginDetails, serror.SnapError) {
-	details := &pluginDetails{}
-	var serr serror.SnapError
-	//Check plugin signing
-	details.Signed, serr = p.verifySignature(rp)
-	if serr != nil {
+	// Download the plugin if it was requested from a URL
+	if serr := p.fetchPlugin(rp); serr != nil {
 		return nil, serr
 	}
 
+	details := &pluginDetails{}
 	details.Path = rp.Path()
 	details.CheckSum = rp.CheckSum()
 	details.Signature = rp.Signature()
@@ -606,34 +915,68 @@ func (p *pluginControl) returnPluginDetails(rp *core.RequestedPlugin) (*pluginDe
 	details.CACertPaths = rp.CACertPaths()
 	details.TLSEnabled = rp.TLSEnabled()
 	details.Uri = rp.Uri()
+	details.Endpoints = rp.Endpoints()
+	if rp.Lifecycle() != "" {
+		l, err := strategy.ParseLifecycle(rp.Lifecycle())
+		if err != nil {
+			return nil, serror.New(err, map[string]interface{}{"lifecycle": rp.Lifecycle()})
+		}
+		details.Lifecycle = &l
+	}
 
+	// The signature sent along with a plugin signs the plugin file; when a
+	// bundle is sent unsigned, the signature in the bundle signs its binary.
+	signedPath, signature := rp.Path(), rp.Signature()
 	if rp.Uri() != nil {
 		// Is a standalone plugin
-	} else if filepath.Ext(rp.Path()) == ".aci" {
+	} else if bundle.IsBundle(rp.Path()) {
 		f, err := os.Open(rp.Path())
 		if err != nil {
 			return nil, serror.New(err)
 		}
 		defer f.Close()
-		if err := aci.Validate(f); err != nil {
+		if err := bundle.Validate(f); err != nil {
 			return nil, serror.New(err)
 		}
-		tempPath, err := aci.Extract(f)
-		if err != nil {
+		if details.Manifest, err = bundle.ReadManifest(f); err != nil {
 			return nil, serror.New(err)
 		}
-		details.ExecPath = path.Join(tempPath, "rootfs")
-		if details.Manifest, err = aci.Manifest(f); err != nil {
+		tempPath, err := bundle.Extract(f, p.GetTempDir())
+		if err != nil {
 			return nil, serror.New(err)
 		}
-		details.Exec = details.Manifest.App.Exec
+		details.ExecPath = path.Join(tempPath, bundle.RootFS)
+		// the paths named by the manifest are kept inside the root of
+		// the bundle
+		details.Exec = make([]string, len(details.Manifest.Exec))
+		for i, e := range details.Manifest.Exec {
+			details.Exec[i] = filepath.FromSlash(bundle.Clean(e))
+		}
 		details.IsPackage = true
+		if signature == nil && details.Manifest.Signature != "" {
+			signedPath = filepath.Join(details.ExecPath, details.Exec[0])
+			if signature, err = ioutil.ReadFile(filepath.Join(details.ExecPath, filepath.FromSlash(bundle.Clean(details.Manifest.Signature)))); err != nil {
+				os.RemoveAll(tempPath)
+				return nil, serror.New(err)
+			}
+		}
 	} else {
 		details.IsPackage = false
 		details.Exec = []string{filepath.Base(rp.Path())}
 		details.ExecPath = filepath.Dir(rp.Path())
 	}
 
+	//Check plugin signing
+	var serr serror.SnapError
+	details.Signer, serr = p.verifySignature(signedPath, signature)
+	details.Signed = details.Signer != nil
+	if serr != nil {
+		if details.IsPackage {
+			os.RemoveAll(filepath.Dir(details.ExecPath))
+		}
+		return nil, serr
+	}
+
 	return details, nil
 }
 
@@ -668,6 +1011,9 @@ func (p *pluginControl) Unload(pl core.Plugin) (core.CatalogedPlugin, serror.Sna
 	if _, err := p.pluginManager.UnloadPlugin(pl); err != nil {
 		return nil, err
 	}
+	p.unstorePlugin(up)
+
+	p.stopLifecycle(up.Key())
 
 	event := &control_event.UnloadPluginEvent{
 		Name:    up.Meta.Name,
@@ -678,6 +1024,17 @@ func (p *pluginControl) Unload(pl core.Plugin) (core.CatalogedPlugin, serror.Sna
 	return up, nil
 }
 
+// stopLifecycle stops the instances kept running by the lifecycle of an
+// unloaded plugin.
+func (p *pluginControl) stopLifecycle(key string) {
+	if pool, _ := p.pluginRunner.AvailablePlugins().getPool(key); pool != nil {
+		if pool.Lifecycle().Mode != strategy.LazyLifecycle {
+			pool.SetLifecycle(strategy.Lifecycle{Mode: strategy.LazyLifecycle})
+			pool.KillAll("plugin unloaded")
+		}
+	}
+}
+
 func (p *pluginControl) SwapPlugins(in *core.RequestedPlugin, out core.CatalogedPlugin) serror.SnapError {
 	details, serr := p.returnPluginDetails(in)
 	if serr != nil {
@@ -687,7 +1044,7 @@ func (p *pluginControl) SwapPlugins(in *core.RequestedPlugin, out core.Cataloged
 		defer os.RemoveAll(filepath.Dir(details.ExecPath))
 	}
 
-	lp, err := p.pluginManager.LoadPlugin(details, p.eventManager)
+	lp, err := p.loadTrustedPlugin(details)
 	if err != nil {
 		return err
 	}
@@ -725,6 +1082,10 @@ func (p *pluginControl) SwapPlugins(in *core.RequestedPlugin, out core.Cataloged
 		}
 		return err
 	}
+	p.storePlugin(in, lp)
+	p.unstorePlugin(up)
+
+	p.applyLifecycle(lp)
 
 	event := &control_event.SwapPluginsEvent{
 		LoadedPluginName:      lp.Meta.Name,
@@ -751,10 +1112,72 @@ func (p *pluginControl) SubscribeDeps(id string, requested []core.RequestedMetri
 
 // UnsubscribeDeps unsubscribes a group of dependencies provided the subscription group ID
 func (p *pluginControl) UnsubscribeDeps(id string) []serror.SnapError {
+	p.SetCacheBypass(id, false)
+	// close the streams kept open for the task to streaming publishers
+	for _, sp := range p.subscriptionGroups.subscribedPlugins(id) {
+		if sp.TypeName() != core.StreamingPublisherPluginType.String() {
+			continue
+		}
+		key := fmt.Sprintf("%s"+core.Separator+"%s"+core.Separator+"%d", sp.TypeName(), sp.Name(), sp.Version())
+		p.pluginRunner.AvailablePlugins().closePublishStreams(key, id)
+	}
+	p.pluginRunner.AvailablePlugins().forgetStreamResumes(id)
+	p.stopStreams(id)
 	// update view and unsubscribe to plugins
 	return p.subscriptionGroups.Remove(id)
 }
 
+// ProxyChannel returns the settings of the gRPC channels of the proxies to the
+// control of other snapteld.
+func (p *pluginControl) ProxyChannel() controlproxy.Channel {
+	return controlproxy.Channel{
+		Compression:    p.pluginChannels.compression,
+		MaxMessageSize: p.pluginChannels.maxMessageSize,
+	}
+}
+
+// SetCacheBypass sets whether the metrics of the given task are always
+// collected from the plugins instead of being served from the metric cache.
+// The metrics collected for such a task still refresh the cache.
+func (p *pluginControl) SetCacheBypass(id string, bypass bool) {
+	p.cacheBypassMutex.Lock()
+	defer p.cacheBypassMutex.Unlock()
+	if !bypass {
+		delete(p.cacheBypass, id)
+		return
+	}
+	if p.cacheBypass == nil {
+		p.cacheBypass = map[string]bool{}
+	}
+	p.cacheBypass[id] = true
+}
+
+// streamStop returns the channel stopping the merged stream of a task, the
+// merged stream set up before for the task, if any, is stopped
+func (p *pluginControl) streamStop(id string) chan struct{} {
+	p.streamStopsMutex.Lock()
+	defer p.streamStopsMutex.Unlock()
+	if stop, ok := p.streamStops[id]; ok {
+		close(stop)
+	}
+	if p.streamStops == nil {
+		p.streamStops = map[string]chan struct{}{}
+	}
+	stop := make(chan struct{})
+	p.streamStops[id] = stop
+	return stop
+}
+
+// stopStreams stops the merged stream of a task, if any
+func (p *pluginControl) stopStreams(id string) {
+	p.streamStopsMutex.Lock()
+	defer p.streamStopsMutex.Unlock()
+	if stop, ok := p.streamStops[id]; ok {
+		close(stop)
+		delete(p.streamStops, id)
+	}
+}
+
 func (p *pluginControl) verifyPlugin(lp *loadedPlugin) error {
 	if lp.Details.Uri != nil {
 		// remote plugin
@@ -771,8 +1194,16 @@ func (p *pluginControl) verifyPlugin(lp *loadedPlugin) error {
 	if lp.Details.CheckSum != cs {
 		return fmt.Errorf(fmt.Sprintf("Current plugin checksum (%x) does not match checksum when plugin was first loaded (%x).", cs, lp.Details.CheckSum))
 	}
-	if lp.Details.Signed {
-		return p.signingManager.ValidateSignature(p.keyringFiles, lp.Details.Path, lp.Details.Signature)
+	// the signature of a bundle signed from the inside is covered by the
+	// checksum of the bundle
+	if lp.Details.Signed && lp.Details.Signature != nil {
+		signer, err := p.signingManager.Verify(p.keyringFiles, lp.Details.Path, lp.Details.Signature)
+		if err != nil {
+			return err
+		}
+		if p.trustPolicy.Revoked(signer.KeyID) {
+			return fmt.Errorf("%v: %v", psigning.ErrKeyRevoked, signer.KeyID)
+		}
 	}
 	return nil
 }
@@ -809,9 +1240,11 @@ func (p *pluginControl) getMetricsAndCollectors(requested []core.RequestedMetric
 		for _, mt := range newMetrics {
 			// in case config tree doesn't have any configuration for current namespace
 			// it's needed to initialize config, otherwise it will stay nil and panic later on
-			cfg := configTree.Get(mt.Namespace().Strings())
-			if cfg == nil {
-				cfg = cdata.NewNode()
+			// the config is copied so that the global config applied below
+			// does not stick to the task config once the global config changed
+			cfg := cdata.NewNode()
+			if c := configTree.Get(mt.Namespace().Strings()); c != nil {
+				cfg.Merge(c)
 			}
 			// set config to metric
 			mt.config = cfg
@@ -984,8 +1417,9 @@ func (p *pluginControl) MetricExists(mns core.Namespace, ver int) bool {
 
 // CollectMetrics is a blocking call to collector plugins returning a collection
 // of metrics and errors.  If an error is encountered no metrics will be
-// returned.
-func (p *pluginControl) CollectMetrics(id string, allTags map[string]map[string]string) (metrics []core.Metric, errs []error) {
+// returned. Cancelling ctx, or passing its deadline, ends the calls to the
+// plugins which have not replied yet.
+func (p *pluginControl) CollectMetrics(ctx context.Context, id string, allTags map[string]map[string]string) (metrics []core.Metric, errs []error) {
 	// If control is not started we don't want tasks to be able to
 	// go through a workflow.
 	if !p.Started {
@@ -1023,12 +1457,20 @@ func (p *pluginControl) CollectMetrics(id string, allTags map[string]map[string]
 		}
 	}
 
+	p.cacheBypassMutex.RLock()
+	bypassCache := p.cacheBypass[id]
+	p.cacheBypassMutex.RUnlock()
+
 	cMetrics := make(chan []core.Metric)
 	cError := make(chan error)
 	var wg sync.WaitGroup
 
 	// For each available plugin call available plugin using RPC client and wait for response (goroutines)
 	for pluginKey, pmt := range pluginToMetricMap {
+		// the metrics of streaming collectors are only received over their stream
+		if pmt.plugin.TypeName() == core.StreamingCollectorPluginType.String() {
+			continue
+		}
 		// merge global plugin config into the config for the metric
 		for _, mt := range pmt.metricTypes {
 			if mt.Config() != nil {
@@ -1039,7 +1481,14 @@ func (p *pluginControl) CollectMetrics(id string, allTags map[string]map[string]
 		wg.Add(1)
 
 		go func(pluginKey string, mt []core.Metric) {
-			mts, err := p.pluginRunner.AvailablePlugins().collectMetrics(pluginKey, mt, id)
+			if err := p.pluginRunner.startOnDemand(pluginKey); err != nil {
+				controlLogger.WithFields(log.Fields{
+					"_block":     "CollectMetrics",
+					"plugin-key": pluginKey,
+				}).Error(err)
+			}
+			mts, err := p.pluginRunner.AvailablePlugins().collectMetrics(ctx, pluginKey, mt, id, bypassCache)
+			p.canaries.record(pluginKey, err != nil)
 			if err != nil {
 				cError <- err
 			} else {
@@ -1102,12 +1551,15 @@ func (p *pluginControl) StreamMetrics(
 			errs = append(errs, e)
 		}
 	}
-	if len(pluginToMetricMap) > 1 {
-		return nil, nil, append(errs, errors.New("Only 1 streaming collecting plugin per task"))
-	}
-	var metricChan chan []core.Metric
-	var errChan chan error
+	var metricChans []chan []core.Metric
+	var errChans []chan error
+	// closing done closes the streams opened for the task
+	done := make(chan struct{})
 	for pluginKey, pmt := range pluginToMetricMap {
+		// collectors which are polled are collected on the interval of the task
+		if pmt.plugin.TypeName() != core.StreamingCollectorPluginType.String() {
+			continue
+		}
 		for _, mt := range pmt.metricTypes {
 			if mt.Config() != nil {
 				mt.Config().ReverseMergeInPlace(
@@ -1117,25 +1569,99 @@ func (p *pluginControl) StreamMetrics(
 						pmt.plugin.Version()))
 			}
 		}
-		metricChan, errChan, err = p.pluginRunner.AvailablePlugins().streamMetrics(pluginKey, pmt.metricTypes, id, maxCollectDuration, maxMetricsBuffer)
+		if err := p.pluginRunner.startOnDemand(pluginKey); err != nil {
+			close(done)
+			errs = append(errs, err)
+			return nil, nil, errs
+		}
+		metricChan, errChan, err := p.pluginRunner.AvailablePlugins().streamMetrics(pluginKey, pmt.metricTypes, id, maxCollectDuration, maxMetricsBuffer, done)
 		if err != nil {
+			close(done)
 			errs = append(errs, err)
 			return nil, nil, errs
 		}
+		metricChans = append(metricChans, metricChan)
+		errChans = append(errChans, errChan)
+	}
+	if len(metricChans) == 0 {
+		close(done)
+		return nil, nil, append(errs, ErrNoStreamingPlugins)
 	}
+	metricChan, errChan := mergeStreams(metricChans, errChans, p.streamStop(id), done)
 	return metricChan, errChan, nil
 }
 
+// mergeStreams fans the streams of the streaming collectors of a task in to
+// a single stream.  A task sets up all of its streams again once one of them
+// breaks, so forwarding stops for every stream after the first one reports
+// a broken connection, or once stop is closed when the task stops.  The
+// streams are then closed by closing done.
+func mergeStreams(metricChans []chan []core.Metric, errChans []chan error, stop <-chan struct{}, done chan struct{}) (chan []core.Metric, chan error) {
+	metricChan := make(chan []core.Metric)
+	errChan := make(chan error)
+	broken := make(chan struct{})
+	var once sync.Once
+	go func() {
+		select {
+		case <-stop:
+			once.Do(func() { close(broken) })
+		case <-broken:
+		}
+		close(done)
+	}()
+	for i := range metricChans {
+		go func(in chan []core.Metric) {
+			for {
+				select {
+				case mts := <-in:
+					select {
+					case metricChan <- mts:
+					case <-broken:
+						return
+					}
+				case <-broken:
+					return
+				}
+			}
+		}(metricChans[i])
+		go func(in chan error) {
+			for {
+				select {
+				case err := <-in:
+					select {
+					case errChan <- err:
+					case <-broken:
+						return
+					}
+					if err.Error() == "connection broken" {
+						once.Do(func() { close(broken) })
+						return
+					}
+				case <-broken:
+					return
+				}
+			}
+		}(errChans[i])
+	}
+	return metricChan, errChan
+}
+
 // PublishMetrics
-func (p *pluginControl) PublishMetrics(metrics []core.Metric, config map[string]ctypes.ConfigValue, taskID, pluginName string, pluginVersion int) []error {
+func (p *pluginControl) PublishMetrics(ctx context.Context, metrics []core.Metric, config map[string]ctypes.ConfigValue, taskID, pluginName string, pluginVersion int) []error {
 	// If control is not started we don't want tasks to be able to
 	// go through a workflow.
 	if !p.Started {
 		return []error{ErrControllerNotStarted}
 	}
+	pluginType := p.publisherType(taskID, pluginName)
+	// use the version the task is subscribed to, which follows its version
+	// constraint and its routing during a staged swap of the plugin
+	pluginVersion = p.subscriptionGroups.pluginVersion(taskID, pluginType.String(), pluginName, pluginVersion)
+	pluginVersion = p.canaries.version(pluginType.String(), pluginName, pluginVersion, taskID)
+
 	// merge global plugin config into the config for this request
 	// without over-writing the task specific config
-	cfg := p.Config.Plugins.getPluginConfigDataNode(core.PublisherPluginType, pluginName, pluginVersion).Table()
+	cfg := p.Config.Plugins.getPluginConfigDataNode(pluginType, pluginName, pluginVersion).Table()
 	merged := make(map[string]ctypes.ConfigValue)
 	for k, v := range cfg {
 		merged[k] = v
@@ -1144,16 +1670,39 @@ func (p *pluginControl) PublishMetrics(metrics []core.Metric, config map[string]
 		merged[k] = v
 	}
 
-	return p.pluginRunner.AvailablePlugins().publishMetrics(metrics, pluginName, pluginVersion, merged, taskID)
+	key := fmt.Sprintf("%s"+core.Separator+"%s"+core.Separator+"%d", pluginType.String(), pluginName, pluginVersion)
+	if err := p.pluginRunner.startOnDemand(key); err != nil {
+		return []error{err}
+	}
+	errs := p.pluginRunner.AvailablePlugins().publishMetrics(ctx, metrics, plugin.PluginType(pluginType), pluginName, pluginVersion, merged, taskID)
+	p.canaries.record(key, len(errs) > 0)
+	return errs
+}
+
+// publisherType returns the type of the publisher of the given name the task
+// is subscribed to. Batches are only sent over a stream when the task asked
+// for a streaming publisher.
+func (p *pluginControl) publisherType(taskID, pluginName string) core.PluginType {
+	for _, sp := range p.subscriptionGroups.subscribedPlugins(taskID) {
+		if sp.Name() == pluginName && sp.TypeName() == core.StreamingPublisherPluginType.String() {
+			return core.StreamingPublisherPluginType
+		}
+	}
+	return core.PublisherPluginType
 }
 
 // ProcessMetrics
-func (p *pluginControl) ProcessMetrics(metrics []core.Metric, config map[string]ctypes.ConfigValue, taskID, pluginName string, pluginVersion int) ([]core.Metric, []error) {
+func (p *pluginControl) ProcessMetrics(ctx context.Context, metrics []core.Metric, config map[string]ctypes.ConfigValue, taskID, pluginName string, pluginVersion int) ([]core.Metric, []error) {
 	// If control is not started we don't want tasks to be able to
 	// go through a workflow.
 	if !p.Started {
 		return nil, []error{ErrControllerNotStarted}
 	}
+	// use the version the task is subscribed to, which follows its version
+	// constraint and its routing during a staged swap of the plugin
+	pluginVersion = p.subscriptionGroups.pluginVersion(taskID, core.ProcessorPluginType.String(), pluginName, pluginVersion)
+	pluginVersion = p.canaries.version(core.ProcessorPluginType.String(), pluginName, pluginVersion, taskID)
+
 	// merge global plugin config into the config for this request
 	// without over-writing the task specific config
 	cfg := p.Config.Plugins.getPluginConfigDataNode(core.ProcessorPluginType, pluginName, pluginVersion).Table()
@@ -1165,7 +1714,13 @@ func (p *pluginControl) ProcessMetrics(metrics []core.Metric, config map[string]
 		merged[k] = v
 	}
 
-	return p.pluginRunner.AvailablePlugins().processMetrics(metrics, pluginName, pluginVersion, merged, taskID)
+	key := fmt.Sprintf("%s"+core.Separator+"%s"+core.Separator+"%d", core.ProcessorPluginType.String(), pluginName, pluginVersion)
+	if err := p.pluginRunner.startOnDemand(key); err != nil {
+		return nil, []error{err}
+	}
+	mts, errs := p.pluginRunner.AvailablePlugins().processMetrics(ctx, metrics, pluginName, pluginVersion, merged, taskID)
+	p.canaries.record(key, len(errs) > 0)
+	return mts, errs
 }
 
 func (p *pluginControl) SetAutodiscoverPaths(paths []string) {
@@ -1180,6 +1735,11 @@ func (p *pluginControl) GetTempDir() string {
 	return p.Config.TempDirPath
 }
 
+// MetricCacheStats returns the usage of the metric cache for each plugin
+func (p *pluginControl) MetricCacheStats() []core.CacheStats {
+	return strategy.CacheStats()
+}
+
 func (p *pluginControl) SetPluginTrustLevel(trust int) {
 	p.pluginTrust = trust
 }
@@ -1188,6 +1748,17 @@ func (p *pluginControl) SetKeyringFile(keyring string) {
 	p.keyringFiles = append(p.keyringFiles, keyring)
 }
 
+// SetPluginTrustPolicy loads the policy restricting the keys allowed to sign
+// each plugin
+func (p *pluginControl) SetPluginTrustPolicy(policyFile string) error {
+	t, err := psigning.LoadTrustPolicy(policyFile)
+	if err != nil {
+		return err
+	}
+	p.trustPolicy = t
+	return nil
+}
+
 type requestedPlugin struct {
 	name    string
 	version int
@@ -35,6 +35,7 @@ type ControlGRPCServer struct {
 func (pc *ControlGRPCServer) PublishMetrics(ctx context.Context, r *rpc.PubProcMetricsRequest) (*rpc.ErrorReply, error) {
 	metrics := common.ToCoreMetrics(r.Metrics)
 	errs := pc.control.PublishMetrics(
+		ctx,
 		metrics,
 		common.ParseConfig(r.Config),
 		r.TaskId, r.PluginName,
@@ -46,6 +47,7 @@ func (pc *ControlGRPCServer) PublishMetrics(ctx context.Context, r *rpc.PubProcM
 func (pc *ControlGRPCServer) ProcessMetrics(ctx context.Context, r *rpc.PubProcMetricsRequest) (*rpc.ProcessMetricsReply, error) {
 	metrics := common.ToCoreMetrics(r.Metrics)
 	mts, errs := pc.control.ProcessMetrics(
+		ctx,
 		metrics,
 		common.ParseConfig(r.Config),
 		r.TaskId, r.PluginName,
@@ -66,7 +68,12 @@ func (pc *ControlGRPCServer) CollectMetrics(ctx context.Context, r *rpc.CollectM
 			AllTags[k][entry.Key] = entry.Value
 		}
 	}
-	mts, errs := pc.control.CollectMetrics(r.TaskID, AllTags)
+	// a proxy sends the cache bypass of the task with each collection, the
+	// setting is dropped once the task unsubscribes
+	if r.CacheBypass {
+		pc.control.SetCacheBypass(r.TaskID, true)
+	}
+	mts, errs := pc.control.CollectMetrics(ctx, r.TaskID, AllTags)
 	var reply *rpc.CollectMetricsResponse
 	if mts == nil {
 		reply = &rpc.CollectMetricsResponse{
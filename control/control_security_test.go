@@ -34,6 +34,7 @@ import (
 	"github.com/intelsdi-x/gomit"
 	log "github.com/sirupsen/logrus"
 	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/net/context"
 
 	"github.com/intelsdi-x/snap/control/fixtures"
 	"github.com/intelsdi-x/snap/control/plugin"
@@ -112,7 +113,7 @@ func TestSecureCollector(t *testing.T) {
 					So(err, ShouldBeNil)
 				})
 				Convey("CollectMetrics should not fail", func() {
-					_, err := cli.CollectMetrics([]core.Metric{})
+					_, err := cli.CollectMetrics(context.Background(), []core.Metric{})
 					So(err, ShouldBeNil)
 				})
 			})
@@ -150,7 +151,7 @@ func TestSecureProcessor(t *testing.T) {
 				})
 				Convey("Process should not fail", func() {
 					cfg := map[string]ctypes.ConfigValue{}
-					_, err := cli.Process([]core.Metric{}, cfg)
+					_, err := cli.Process(context.Background(), []core.Metric{}, cfg)
 					So(err, ShouldBeNil)
 				})
 			})
@@ -195,7 +196,7 @@ func TestSecurePublisher(t *testing.T) {
 					testFilesToRemove = append(testFilesToRemove, tf.Name())
 					tf.Close()
 					cfg["file"] = ctypes.ConfigValueStr{Value: tf.Name()}
-					err = cli.Publish([]core.Metric{}, cfg)
+					err = cli.Publish(context.Background(), []core.Metric{}, cfg)
 					So(err, ShouldBeNil)
 				})
 			})
@@ -242,7 +243,9 @@ func TestSecureStreamingCollector(t *testing.T) {
 					mtsin := []core.Metric{}
 					m := plugin.MetricType{Namespace_: core.NewNamespace(strings.Fields("a b integer")...)}
 					mtsin = append(mtsin, m)
-					mch, errch, err := cli.StreamMetrics("test-taskID", mtsin)
+					done := make(chan struct{})
+					defer close(done)
+					mch, errch, err := cli.StreamMetrics("test-taskID", mtsin, nil, done)
 					So(err, ShouldBeNil)
 					Convey("streaming should deliver metrics rather than error", func() {
 						select {
@@ -0,0 +1,52 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package control
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPluginFileType(t *testing.T) {
+	Convey("The type and name of a plugin are read from its file name", t, func() {
+		for _, c := range []struct {
+			file, typeName, name string
+		}{
+			{"snap-plugin-collector-mock1", "collector", "mock1"},
+			{"snap-plugin-streaming-collector-rand1", "streaming-collector", "rand1"},
+			{"snap-plugin-publisher-mock-file", "publisher", "mock-file"},
+			{"snap-plugin-processor-passthru.exe", "processor", "passthru"},
+		} {
+			typeName, name, ok := pluginFileType(c.file)
+			So(ok, ShouldBeTrue)
+			So(typeName, ShouldEqual, c.typeName)
+			So(name, ShouldEqual, c.name)
+		}
+	})
+	Convey("Other file names are not read", t, func() {
+		for _, file := range []string{"mock", "snap-plugin-collector-", "snap-plugin-mock", "plugin-collector-mock.exe"} {
+			_, _, ok := pluginFileType(file)
+			So(ok, ShouldBeFalse)
+		}
+	})
+}
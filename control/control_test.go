@@ -27,7 +27,6 @@ import (
 	"math/rand"
 	"net"
 	"os/exec"
-	"path"
 	"strings"
 	"testing"
 	"time"
@@ -36,6 +35,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/vrischmann/jsonutil"
+	"golang.org/x/net/context"
 
 	"github.com/intelsdi-x/gomit"
 	"github.com/intelsdi-x/snap/control/fixtures"
@@ -47,6 +47,7 @@ import (
 	"github.com/intelsdi-x/snap/core/control_event"
 	"github.com/intelsdi-x/snap/core/ctypes"
 	"github.com/intelsdi-x/snap/core/serror"
+	"github.com/intelsdi-x/snap/pkg/psigning"
 	"github.com/intelsdi-x/snap/plugin/helper"
 )
 
@@ -342,20 +343,15 @@ func (l *listenToPluginEvent) HandleGomitEvent(e gomit.Event) {
 	}
 }
 
-var (
-	AciPath = path.Join(strings.TrimRight(fixtures.SnapPath, "build"), "pkg/unpackage/")
-	AciFile = "snap-collector-plugin-mock1.darwin-x86_64.aci"
-)
-
 type mocksigningManager struct {
 	signed bool
 }
 
-func (ps *mocksigningManager) ValidateSignature(_ []string, _ string, signature []byte) error {
+func (ps *mocksigningManager) Verify(_ []string, _ string, signature []byte) (*psigning.Signer, error) {
 	if signature != nil {
-		return nil
+		return &psigning.Signer{KeyID: "0123456789ABCDEF", Algorithm: "openpgp"}, nil
 	}
-	return errors.New("fake")
+	return nil, errors.New("fake")
 }
 
 // Uses the mock collector plugin to simulate Loading
@@ -554,6 +550,54 @@ func TestLoadWithSetPluginTrustLevel(t *testing.T) {
 		})
 		c.Stop()
 	})
+	Convey("pluginControl.Load with a trust policy", t, func() {
+		c := New(getTestConfig())
+		c.pluginTrust = PluginTrustEnabled
+		c.signingManager = &mocksigningManager{}
+		c.Start()
+		Convey("Loading a plugin signed by an allowed key", func() {
+			c.trustPolicy = &psigning.TrustPolicy{
+				Plugins: []psigning.PluginTrust{{Name: "collector:mock", Signers: []string{"0123456789ABCDEF"}}},
+			}
+			lp, err := load(c, fixtures.PluginPathMock2, "mock.asc")
+			Convey("Should not return an error", func() {
+				So(err, ShouldBeNil)
+				So(lp.Signer(), ShouldNotBeNil)
+				So(lp.Signer().KeyID, ShouldEqual, "0123456789ABCDEF")
+			})
+		})
+		Convey("Loading a plugin signed by a key not allowed", func() {
+			c.trustPolicy = &psigning.TrustPolicy{
+				Plugins: []psigning.PluginTrust{{Name: "collector:mock*", Signers: []string{"FFFFFFFFFFFFFFFF"}}},
+			}
+			_, err := load(c, fixtures.PluginPathMock2, "mock.asc")
+			Convey("Should return an error and unload the plugin", func() {
+				So(err, ShouldNotBeNil)
+				So(len(c.pluginManager.all()), ShouldEqual, 0)
+			})
+		})
+		Convey("Loading a plugin whose file name is not allowed", func() {
+			c.trustPolicy = &psigning.TrustPolicy{
+				Plugins: []psigning.PluginTrust{{Name: "collector:mock2", Signers: []string{"FFFFFFFFFFFFFFFF"}}},
+			}
+			_, err := load(c, fixtures.PluginPathMock2, "mock.asc")
+			Convey("Should return an error before the plugin is executed", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldStartWith, psigning.ErrSignerNotAllowed.Error())
+				So(len(c.pluginManager.all()), ShouldEqual, 0)
+			})
+		})
+		Convey("Loading a plugin signed by a revoked key", func() {
+			c.trustPolicy = &psigning.TrustPolicy{
+				RevokedKeys: []string{"0123456789ABCDEF"},
+			}
+			_, err := load(c, fixtures.PluginPathMock2, "mock.asc")
+			Convey("Should return an error", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+		c.Stop()
+	})
 	Convey("pluginControl.Load with trust disabled", t, func() {
 		c := New(getTestConfig())
 		c.pluginTrust = PluginTrustDisabled
@@ -1058,7 +1102,7 @@ func TestRoutingCachingStrategy(t *testing.T) {
 			Convey("Collect metrics", func() {
 				taskID := tasks[rand.Intn(len(tasks))]
 				for i := 0; i < 10; i++ {
-					_, errs := c.CollectMetrics(taskID, nil)
+					_, errs := c.CollectMetrics(context.Background(), taskID, nil)
 					So(errs, ShouldBeEmpty)
 				}
 				Convey("Check cache stats", func() {
@@ -1129,7 +1173,7 @@ func TestRoutingCachingStrategy(t *testing.T) {
 			Convey("Collect metrics", func() {
 				taskID := tasks[rand.Intn(len(tasks))]
 				for i := 0; i < 10; i++ {
-					cr, errs := c.CollectMetrics(taskID, nil)
+					cr, errs := c.CollectMetrics(context.Background(), taskID, nil)
 					So(errs, ShouldBeEmpty)
 					for i := range cr {
 						So(cr[i].Data(), ShouldContainSubstring, "The mock collected data!")
@@ -1244,7 +1288,7 @@ func TestCollectDynamicMetrics(t *testing.T) {
 			So(ttl, ShouldEqual, strategy.GlobalCacheExpiration)
 
 			// first collection
-			mts, errs := c.CollectMetrics(taskID, nil)
+			mts, errs := c.CollectMetrics(context.Background(), taskID, nil)
 			So(errs, ShouldBeNil)
 			So(len(mts), ShouldEqual, 11)
 			hits, err := pool.CacheHits(mut.Namespace().String(), 2, taskID)
@@ -1252,7 +1296,7 @@ func TestCollectDynamicMetrics(t *testing.T) {
 			So(hits, ShouldEqual, 0)
 
 			// second collection
-			mts, errs = c.CollectMetrics(taskID, nil)
+			mts, errs = c.CollectMetrics(context.Background(), taskID, nil)
 			So(errs, ShouldBeNil)
 			So(len(mts), ShouldEqual, 11)
 			hits, err = pool.CacheHits(mut.Namespace().String(), 2, taskID)
@@ -1260,7 +1304,7 @@ func TestCollectDynamicMetrics(t *testing.T) {
 			So(hits, ShouldEqual, 1)
 
 			// third collection
-			mts, errs = c.CollectMetrics(taskID, nil)
+			mts, errs = c.CollectMetrics(context.Background(), taskID, nil)
 			So(errs, ShouldBeNil)
 			So(len(mts), ShouldEqual, 11)
 			hits, err = pool.CacheHits(mut.Namespace().String(), 2, taskID)
@@ -1329,7 +1373,7 @@ func TestFailedPlugin(t *testing.T) {
 				var cr []core.Metric
 				eventMap := map[string]int{}
 				for i := 0; i < MaxPluginRestartCount+1; i++ {
-					cr, errs = c.CollectMetrics(taskID, nil)
+					cr, errs = c.CollectMetrics(context.Background(), taskID, nil)
 					So(errs, ShouldNotBeNil)
 					So(cr, ShouldBeNil)
 					<-lpe.done
@@ -1508,7 +1552,7 @@ func TestCollectMetrics(t *testing.T) {
 
 			Convey("collect metrics", func() {
 				for x := 0; x < 4; x++ {
-					cr, err := c.CollectMetrics(taskHit, nil)
+					cr, err := c.CollectMetrics(context.Background(), taskHit, nil)
 					So(err, ShouldBeNil)
 					for i := range cr {
 						So(cr[i].Data(), ShouldContainSubstring, "The mock collected data!")
@@ -1587,7 +1631,7 @@ func TestCollectNonSpecifiedDynamicMetrics(t *testing.T) {
 
 			Convey("collect metrics", func() {
 				for x := 0; x < 4; x++ {
-					mts, err := c.CollectMetrics(taskHit, nil)
+					mts, err := c.CollectMetrics(context.Background(), taskHit, nil)
 					So(err, ShouldBeNil)
 					So(mts, ShouldNotBeEmpty)
 					So(len(mts), ShouldBeGreaterThan, len(requested))
@@ -1656,7 +1700,7 @@ func TestCollectSpecifiedDynamicMetrics(t *testing.T) {
 
 				Convey("collect metrics", func() {
 					for x := 0; x < 4; x++ {
-						mts, err := c.CollectMetrics(taskID, nil)
+						mts, err := c.CollectMetrics(context.Background(), taskID, nil)
 						So(err, ShouldBeNil)
 						So(mts, ShouldNotBeEmpty)
 						So(len(mts), ShouldEqual, len(requested))
@@ -1684,7 +1728,7 @@ func TestCollectSpecifiedDynamicMetrics(t *testing.T) {
 
 				Convey("collect metrics", func() {
 					for x := 0; x < 4; x++ {
-						mts, err := c.CollectMetrics(taskID, nil)
+						mts, err := c.CollectMetrics(context.Background(), taskID, nil)
 						So(err, ShouldNotBeNil)
 						So(mts, ShouldBeNil)
 						So(err[0].Error(), ShouldContainSubstring, "requested hostname `host10` is not available")
@@ -1731,7 +1775,7 @@ func TestPublishMetrics(t *testing.T) {
 				metrics := []core.Metric{
 					*plugin.NewMetricType(core.NewNamespace("foo"), time.Now(), nil, "", 1),
 				}
-				errs := c.PublishMetrics(metrics, n.Table(), uuid.New(), "mock-file", 3)
+				errs := c.PublishMetrics(context.Background(), metrics, n.Table(), uuid.New(), "mock-file", 3)
 				So(errs, ShouldBeNil)
 				ap := c.AvailablePlugins()
 				So(ap, ShouldNotBeEmpty)
@@ -1778,7 +1822,7 @@ func TestProcessMetrics(t *testing.T) {
 				metrics := []core.Metric{
 					*plugin.NewMetricType(core.NewNamespace("foo"), time.Now(), nil, "", 1),
 				}
-				mts, errs := c.ProcessMetrics(metrics, n.Table(), uuid.New(), "passthru", 1)
+				mts, errs := c.ProcessMetrics(context.Background(), metrics, n.Table(), uuid.New(), "passthru", 1)
 				So(errs, ShouldBeNil)
 				So(mts[0].Data(), ShouldEqual, 2)
 			})
@@ -1862,7 +1906,7 @@ func TestMetricSubscriptionToNewVersion(t *testing.T) {
 		<-lpe.started
 		So(serr, ShouldBeNil)
 		// collect metrics as a sanity check that everything is setup correctly
-		mts, errs := c.CollectMetrics("testTaskID", nil)
+		mts, errs := c.CollectMetrics(context.Background(), "testTaskID", nil)
 		So(errs, ShouldBeNil)
 		So(len(mts), ShouldEqual, 1)
 		Convey("ensure the data coming back is from v1", func() {
@@ -1894,7 +1938,7 @@ func TestMetricSubscriptionToNewVersion(t *testing.T) {
 			So(pool2, ShouldNotBeNil)
 			So(pool2.SubscriptionCount(), ShouldEqual, 1)
 
-			mts, errs = c.CollectMetrics("testTaskID", nil)
+			mts, errs = c.CollectMetrics(context.Background(), "testTaskID", nil)
 			So(errs, ShouldBeNil)
 			So(len(mts), ShouldEqual, 1)
 			Convey("ensure the data coming back is from v2", func() {
@@ -1930,7 +1974,7 @@ func TestMetricSubscriptionToOlderVersion(t *testing.T) {
 		<-lpe.started
 		So(serr, ShouldBeNil)
 		// collect metrics as a sanity check that everything is setup correctly
-		mts, errs := c.CollectMetrics("testTaskID", nil)
+		mts, errs := c.CollectMetrics(context.Background(), "testTaskID", nil)
 		So(errs, ShouldBeNil)
 		So(len(mts), ShouldEqual, 1)
 		Convey("ensure the data coming back is from v2", func() {
@@ -1980,7 +2024,7 @@ func TestMetricSubscriptionToOlderVersion(t *testing.T) {
 			So(pool2, ShouldNotBeNil)
 			So(pool2.SubscriptionCount(), ShouldEqual, 1)
 
-			mts, errs := c.CollectMetrics("testTaskID", nil)
+			mts, errs := c.CollectMetrics(context.Background(), "testTaskID", nil)
 			So(errs, ShouldBeEmpty)
 			So(len(mts), ShouldEqual, 1)
 			Convey("ensure the data coming back is from v1", func() {
@@ -2021,7 +2065,7 @@ func TestDynamicMetricSubscriptionLoad(t *testing.T) {
 		<-lpe.sub  // wait for subscription event
 		So(serr, ShouldBeNil)
 		// collect metrics as a sanity check that everything is setup correctly
-		mts1, errs := c.CollectMetrics("testTaskID", nil)
+		mts1, errs := c.CollectMetrics(context.Background(), "testTaskID", nil)
 		So(errs, ShouldBeNil)
 		So(len(mts1), ShouldBeGreaterThan, 1)
 		Convey("ensure the data coming back is from v1", func() {
@@ -2054,7 +2098,7 @@ func TestDynamicMetricSubscriptionLoad(t *testing.T) {
 			So(errp, ShouldBeNil)
 			So(pool2.SubscriptionCount(), ShouldEqual, 1)
 
-			mts2, errs := c.CollectMetrics("testTaskID", nil)
+			mts2, errs := c.CollectMetrics(context.Background(), "testTaskID", nil)
 			So(errs, ShouldBeNil)
 			So(len(mts2), ShouldEqual, len(mts1))
 			Convey("ensure the data coming back is from v2", func() {
@@ -2088,7 +2132,7 @@ func TestDynamicMetricSubscriptionLoad(t *testing.T) {
 				So(pool3, ShouldNotBeNil)
 				So(pool3.SubscriptionCount(), ShouldEqual, 1)
 
-				mts3, errs := c.CollectMetrics("testTaskID", nil)
+				mts3, errs := c.CollectMetrics(context.Background(), "testTaskID", nil)
 				So(errs, ShouldBeNil)
 				So(len(mts3), ShouldBeGreaterThan, len(mts2))
 				Convey("ensure the data coming back from both mock(v2) and anothermock(v1)", func() {
@@ -2157,7 +2201,7 @@ func TestDynamicMetricSubscriptionUnload(t *testing.T) {
 			}
 		}
 		// collect metrics as a sanity check that everything is setup correctly
-		mts1, errs := c.CollectMetrics("testTaskID", nil)
+		mts1, errs := c.CollectMetrics(context.Background(), "testTaskID", nil)
 		So(errs, ShouldBeNil)
 		So(len(mts1), ShouldBeGreaterThan, 1)
 		Convey("Unloading mock plugin should remove its subscriptions", func() {
@@ -2174,7 +2218,7 @@ func TestDynamicMetricSubscriptionUnload(t *testing.T) {
 			So(errp, ShouldBeNil)
 			So(pool2, ShouldNotBeNil)
 			So(pool2.SubscriptionCount(), ShouldEqual, 1)
-			mts2, errs := c.CollectMetrics("testTaskID", nil)
+			mts2, errs := c.CollectMetrics(context.Background(), "testTaskID", nil)
 			So(errs, ShouldBeNil)
 			So(len(mts2), ShouldBeLessThan, len(mts1))
 			Convey("ensure te data coming back is from anothermock", func() {
@@ -2225,7 +2269,7 @@ func TestDynamicMetricSubscriptionLoadLessMetrics(t *testing.T) {
 		So(err2, ShouldBeNil)
 		So(lpMock.Name(), ShouldResemble, "mock")
 		// collect metrics as a sanity check that everything is setup correctly
-		mts1, errs := c.CollectMetrics("testTaskID", nil)
+		mts1, errs := c.CollectMetrics(context.Background(), "testTaskID", nil)
 		So(errs, ShouldBeNil)
 		So(len(mts1), ShouldBeGreaterThan, 1)
 		Convey("metrics are collected from mock1", func() {
@@ -2255,7 +2299,7 @@ func TestDynamicMetricSubscriptionLoadLessMetrics(t *testing.T) {
 			So(errp, ShouldBeNil)
 			So(pool2.SubscriptionCount(), ShouldEqual, 1)
 
-			mts2, errs := c.CollectMetrics("testTaskID", nil)
+			mts2, errs := c.CollectMetrics(context.Background(), "testTaskID", nil)
 			So(errs, ShouldBeNil)
 			So(len(mts2), ShouldEqual, len(mts1))
 
@@ -2287,7 +2331,7 @@ func TestDynamicMetricSubscriptionLoadLessMetrics(t *testing.T) {
 				So(errp, ShouldBeNil)
 				So(pool2.SubscriptionCount(), ShouldEqual, 1)
 
-				mts3, errs := c.CollectMetrics("testTaskID", nil)
+				mts3, errs := c.CollectMetrics(context.Background(), "testTaskID", nil)
 				So(errs, ShouldBeNil)
 				So(len(mts3), ShouldBeLessThan, len(mts2))
 
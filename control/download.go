@@ -0,0 +1,158 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package control
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/intelsdi-x/snap/core"
+	"github.com/intelsdi-x/snap/core/serror"
+)
+
+var (
+	// DefaultDownloadTimeout - default timeout for downloading a plugin
+	DefaultDownloadTimeout = time.Minute * 5
+
+	// ErrDownloadCheckSumMismatch - error message when a downloaded plugin
+	// does not match its expected checksum
+	ErrDownloadCheckSumMismatch = errors.New("Downloaded plugin does not match the expected checksum")
+
+	// ErrDownloadScheme - error message when a plugin is not downloaded over
+	// HTTP(S)
+	ErrDownloadScheme = errors.New("Plugins can only be downloaded over http or https")
+
+	// ErrDownloadTooLarge - error message when a downloaded plugin or its
+	// signature exceeds the maximal download size
+	ErrDownloadTooLarge = errors.New("Downloaded plugin exceeds the maximal download size")
+)
+
+var downloadClient = &http.Client{Timeout: DefaultDownloadTimeout}
+
+// fetchPlugin downloads the requested plugin, and its detached signature if
+// any, to the temp dir and verifies its checksum.  Plugins which are not
+// downloaded or are already downloaded are left untouched.
+func (p *pluginControl) fetchPlugin(rp *core.RequestedPlugin) serror.SnapError {
+	u := rp.DownloadUrl()
+	if u == nil || rp.Path() != "" {
+		return nil
+	}
+	f := map[string]interface{}{
+		"_block": "fetch-plugin",
+		"url":    u.String(),
+	}
+	name := path.Base(u.Path)
+	if name == "." || name == "/" {
+		name = "plugin"
+	}
+	dir, err := ioutil.TempDir(p.GetTempDir(), "snap-plugin-")
+	if err != nil {
+		return serror.New(err, f)
+	}
+	fetched := false
+	defer func() {
+		if !fetched {
+			os.RemoveAll(dir)
+		}
+	}()
+	max := int64(p.Config.PluginDownloadMaxBytes)
+	tmpFile := filepath.Join(dir, name)
+	cs, err := downloadFile(u, tmpFile, max)
+	if err != nil {
+		return serror.New(err, f)
+	}
+	if cs != rp.CheckSum() {
+		f["expected-checksum"] = fmt.Sprintf("%x", rp.CheckSum())
+		f["checksum"] = fmt.Sprintf("%x", cs)
+		return serror.New(ErrDownloadCheckSumMismatch, f)
+	}
+	if su := rp.SignatureUrl(); su != nil {
+		var sig bytes.Buffer
+		if err := download(su, &sig, max); err != nil {
+			f["signature-url"] = su.String()
+			return serror.New(err, f)
+		}
+		rp.SetSignature(sig.Bytes())
+	}
+	fetched = true
+	rp.SetPath(tmpFile)
+	controlLogger.WithFields(log.Fields(f)).Info("plugin downloaded to ", tmpFile)
+	return nil
+}
+
+// downloadFile writes the content found at an HTTP(S) URL to a new file at
+// path and returns its checksum.
+func downloadFile(u *url.URL, path string, max int64) ([sha256.Size]byte, error) {
+	var cs [sha256.Size]byte
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0700)
+	if err != nil {
+		return cs, err
+	}
+	defer file.Close()
+	h := sha256.New()
+	if err := download(u, io.MultiWriter(file, h), max); err != nil {
+		return cs, err
+	}
+	copy(cs[:], h.Sum(nil))
+	return cs, nil
+}
+
+// download copies the content found at an HTTP(S) URL to w, content larger
+// than max bytes is rejected unless max is 0.
+func download(u *url.URL, w io.Writer, max int64) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ErrDownloadScheme
+	}
+	resp, err := downloadClient.Get(u.String())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Error downloading %s: %s", u, resp.Status)
+	}
+	if max <= 0 {
+		_, err = io.Copy(w, resp.Body)
+		return err
+	}
+	if resp.ContentLength > max {
+		return ErrDownloadTooLarge
+	}
+	n, err := io.Copy(w, io.LimitReader(resp.Body, max+1))
+	if err != nil {
+		return err
+	}
+	if n > max {
+		return ErrDownloadTooLarge
+	}
+	return nil
+}
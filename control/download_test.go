@@ -0,0 +1,87 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package control
+
+import (
+	"crypto/sha256"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/intelsdi-x/snap/core"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFetchPlugin(t *testing.T) {
+	Convey("Given a plugin served over HTTP", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/snap-plugin-collector-mock1":
+				w.Write([]byte("plugin"))
+			case "/snap-plugin-collector-mock1.asc":
+				w.Write([]byte("signature"))
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer ts.Close()
+		p := &pluginControl{Config: GetDefaultConfig()}
+		u, _ := url.Parse(ts.URL + "/snap-plugin-collector-mock1")
+
+		Convey("The plugin and its signature are downloaded", func() {
+			rp := core.NewRequestedPluginDownload(u, sha256.Sum256([]byte("plugin")))
+			su, _ := url.Parse(ts.URL + "/snap-plugin-collector-mock1.asc")
+			rp.SetSignatureUrl(su)
+			So(p.fetchPlugin(rp), ShouldBeNil)
+			defer os.RemoveAll(filepath.Dir(rp.Path()))
+			So(filepath.Base(rp.Path()), ShouldEqual, "snap-plugin-collector-mock1")
+			b, err := ioutil.ReadFile(rp.Path())
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, "plugin")
+			So(string(rp.Signature()), ShouldEqual, "signature")
+		})
+		Convey("A plugin which does not match its checksum is rejected", func() {
+			rp := core.NewRequestedPluginDownload(u, sha256.Sum256([]byte("other")))
+			serr := p.fetchPlugin(rp)
+			So(serr, ShouldNotBeNil)
+			So(serr.Error(), ShouldEqual, ErrDownloadCheckSumMismatch.Error())
+			So(rp.Path(), ShouldBeEmpty)
+		})
+		Convey("A missing plugin is an error", func() {
+			m, _ := url.Parse(ts.URL + "/missing")
+			So(p.fetchPlugin(core.NewRequestedPluginDownload(m, sha256.Sum256(nil))), ShouldNotBeNil)
+		})
+		Convey("A plugin larger than the maximal download size is rejected", func() {
+			p.Config.PluginDownloadMaxBytes = len("plugin") - 1
+			rp := core.NewRequestedPluginDownload(u, sha256.Sum256([]byte("plugin")))
+			serr := p.fetchPlugin(rp)
+			So(serr, ShouldNotBeNil)
+			So(serr.Error(), ShouldEqual, ErrDownloadTooLarge.Error())
+			So(rp.Path(), ShouldBeEmpty)
+		})
+	})
+}
@@ -76,6 +76,10 @@ func (m MockMetricType) Unit() string {
 	return ""
 }
 
+func (m MockMetricType) Kind() core.MetricKind {
+	return core.UnknownMetricKind
+}
+
 func (m MockMetricType) LastAdvertisedTime() time.Time {
 	return time.Now()
 }
@@ -47,16 +47,36 @@ var (
 		Usage:  "Auto discover paths separated by colons.",
 		EnvVar: "SNAP_AUTODISCOVER_PATH",
 	}
+	flAutoDiscoverWatch = cli.BoolFlag{
+		Name:   "auto-discover-watch",
+		Usage:  "Watch the auto discover paths to load the plugins and create the tasks added later",
+		EnvVar: "SNAP_AUTODISCOVER_WATCH",
+	}
 	flKeyringPaths = cli.StringFlag{
 		Name:   "keyring-paths, k",
 		Usage:  "Keyring paths for signing verification separated by colons",
 		EnvVar: "SNAP_KEYRING_PATHS",
 	}
+	flPluginTrustPolicy = cli.StringFlag{
+		Name:   "plugin-trust-policy",
+		Usage:  "Path to the policy file mapping plugins to the keys allowed to sign them",
+		EnvVar: "SNAP_PLUGIN_TRUST_POLICY",
+	}
 	flCache = cli.StringFlag{
 		Name:   "cache-expiration",
 		Usage:  fmt.Sprintf("The time limit for which a metric cache entry is valid (default: %v)", defaultCacheExpiration),
 		EnvVar: "SNAP_CACHE_EXPIRATION",
 	}
+	flCacheMaxEntries = cli.StringFlag{
+		Name:   "cache-max-entries",
+		Usage:  fmt.Sprintf("The maximum number of entries in the metric cache, 0 for no limit (default: %v)", defaultCacheMaxEntries),
+		EnvVar: "SNAP_CACHE_MAX_ENTRIES",
+	}
+	flCacheMaxBytes = cli.StringFlag{
+		Name:   "cache-max-bytes",
+		Usage:  fmt.Sprintf("The approximate maximum size in bytes of the metric cache, 0 for no limit (default: %v)", defaultCacheMaxBytes),
+		EnvVar: "SNAP_CACHE_MAX_BYTES",
+	}
 
 	flTLSCert = cli.StringFlag{
 		Name:  "tls-cert",
@@ -89,5 +109,5 @@ var (
 		EnvVar: "SNAP_TEMP_DIR_PATH",
 	}
 
-	Flags = []cli.Flag{flNumberOfPLs, flPluginLoadTimeout, flAutoDiscover, flPluginTrust, flKeyringPaths, flCache, flControlRpcPort, flControlRpcAddr, flTempDirPath, flTLSCert, flTLSKey, flCACertPaths}
+	Flags = []cli.Flag{flNumberOfPLs, flPluginLoadTimeout, flAutoDiscover, flAutoDiscoverWatch, flPluginTrust, flKeyringPaths, flPluginTrustPolicy, flCache, flCacheMaxEntries, flCacheMaxBytes, flControlRpcPort, flControlRpcAddr, flTempDirPath, flTLSCert, flTLSKey, flCACertPaths}
 )
@@ -0,0 +1,130 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package control
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/intelsdi-x/snap/core"
+)
+
+var (
+	// SnapVersion is the version of snapteld the minimum snap version of the
+	// plugins is checked against, the check is skipped if it is not a release
+	// version
+	SnapVersion = ""
+
+	// ErrSnapVersionTooOld - error message when a plugin requires a newer snapteld
+	ErrSnapVersionTooOld = errors.New("Plugin requires a newer version of snapteld")
+	// ErrBadMinSnapVersion - error message when the minimum snap version of a plugin is invalid
+	ErrBadMinSnapVersion = errors.New("Invalid minimum snap version")
+	// ErrPlatformNotSupported - error message when a plugin does not support the platform of snapteld
+	ErrPlatformNotSupported = errors.New("Plugin does not support this platform")
+	// ErrRequiredEnvNotSet - error message when an environment variable required by a plugin is not set
+	ErrRequiredEnvNotSet = errors.New("Environment variable required by plugin is not set")
+)
+
+// validateMetadata checks that the plugin described by md can run with this
+// snapteld.  The platform and the environment of a standalone plugin are the
+// ones of its host so only its minimum snap version is checked.
+func validateMetadata(md *core.PluginMetadata, remote bool) error {
+	if md == nil {
+		return nil
+	}
+	if md.MinSnapVersion != "" {
+		min, ok := parseVersion(md.MinSnapVersion)
+		if !ok {
+			return fmt.Errorf("%v: %v", ErrBadMinSnapVersion, md.MinSnapVersion)
+		}
+		if current, ok := parseVersion(SnapVersion); ok && compareVersions(current, min) < 0 {
+			return fmt.Errorf("%v: %v < %v", ErrSnapVersionTooOld, SnapVersion, md.MinSnapVersion)
+		}
+	}
+	if remote {
+		return nil
+	}
+	if len(md.Platforms) > 0 && !supportsPlatform(md.Platforms, runtime.GOOS, runtime.GOARCH) {
+		return fmt.Errorf("%v: %v/%v not in %v", ErrPlatformNotSupported, runtime.GOOS, runtime.GOARCH, strings.Join(md.Platforms, ", "))
+	}
+	for _, env := range md.RequiredEnv {
+		if _, ok := os.LookupEnv(env); !ok {
+			return fmt.Errorf("%v: %v", ErrRequiredEnvNotSet, env)
+		}
+	}
+	return nil
+}
+
+// supportsPlatform returns true when goos or goos/goarch is one of platforms
+func supportsPlatform(platforms []string, goos, goarch string) bool {
+	for _, p := range platforms {
+		if p == goos || p == goos+"/"+goarch {
+			return true
+		}
+	}
+	return false
+}
+
+// parseVersion returns the numbers of a version like 2.0.0, v1.3 or
+// 2.0.0-12-g1234abc, anything following the dotted numbers is ignored
+func parseVersion(v string) ([]int, bool) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+ "); i >= 0 {
+		v = v[:i]
+	}
+	if v == "" {
+		return nil, false
+	}
+	parts := strings.Split(v, ".")
+	nums := make([]int, len(parts))
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		nums[i] = n
+	}
+	return nums, true
+}
+
+// compareVersions returns -1, 0 or 1 when a is older than, the same as or
+// newer than b, missing numbers count as 0
+func compareVersions(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
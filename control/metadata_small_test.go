@@ -0,0 +1,82 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package control
+
+import (
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/intelsdi-x/snap/core"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestValidateMetadata(t *testing.T) {
+	Convey("Given snapteld version 2.1.0", t, func() {
+		version := SnapVersion
+		SnapVersion = "2.1.0-5-g1234abc"
+		defer func() { SnapVersion = version }()
+
+		Convey("Plugins without metadata are valid", func() {
+			So(validateMetadata(nil, false), ShouldBeNil)
+			So(validateMetadata(&core.PluginMetadata{Description: "mock"}, false), ShouldBeNil)
+		})
+		Convey("The minimum snap version is checked", func() {
+			So(validateMetadata(&core.PluginMetadata{MinSnapVersion: "v2.1"}, false), ShouldBeNil)
+			So(validateMetadata(&core.PluginMetadata{MinSnapVersion: "2.0.9"}, true), ShouldBeNil)
+			err := validateMetadata(&core.PluginMetadata{MinSnapVersion: "2.2.0"}, true)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldStartWith, ErrSnapVersionTooOld.Error())
+			err = validateMetadata(&core.PluginMetadata{MinSnapVersion: "latest"}, false)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldStartWith, ErrBadMinSnapVersion.Error())
+		})
+		Convey("The check is skipped for a development build", func() {
+			SnapVersion = "unknown"
+			So(validateMetadata(&core.PluginMetadata{MinSnapVersion: "9.0.0"}, false), ShouldBeNil)
+		})
+		Convey("The platform is checked", func() {
+			So(validateMetadata(&core.PluginMetadata{Platforms: []string{"plan9", runtime.GOOS}}, false), ShouldBeNil)
+			So(validateMetadata(&core.PluginMetadata{Platforms: []string{runtime.GOOS + "/" + runtime.GOARCH}}, false), ShouldBeNil)
+			err := validateMetadata(&core.PluginMetadata{Platforms: []string{"plan9"}}, false)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldStartWith, ErrPlatformNotSupported.Error())
+			So(validateMetadata(&core.PluginMetadata{Platforms: []string{"plan9"}}, true), ShouldBeNil)
+		})
+		Convey("The required environment is checked", func() {
+			os.Setenv("SNAP_TEST_METADATA_ENV", "")
+			defer os.Unsetenv("SNAP_TEST_METADATA_ENV")
+			So(validateMetadata(&core.PluginMetadata{RequiredEnv: []string{"SNAP_TEST_METADATA_ENV"}}, false), ShouldBeNil)
+			err := validateMetadata(&core.PluginMetadata{RequiredEnv: []string{"SNAP_TEST_METADATA_UNSET"}}, false)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldStartWith, ErrRequiredEnvNotSet.Error())
+		})
+	})
+	Convey("Versions are compared number by number", t, func() {
+		a, _ := parseVersion("1.10")
+		b, _ := parseVersion("1.9.3")
+		So(compareVersions(a, b), ShouldEqual, 1)
+		So(compareVersions(b, a), ShouldEqual, -1)
+		c, _ := parseVersion("1.10.0")
+		So(compareVersions(a, c), ShouldEqual, 0)
+	})
+}
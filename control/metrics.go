@@ -101,22 +101,6 @@ func errorMetricsNotFound(ns string, ver ...int) error {
 	return fmt.Errorf("No metric found below the given namespace: %s", ns)
 }
 
-func errorMetricEndsWithAsterisk(ns string) error {
-	return fmt.Errorf("Metric namespace %s ends with an asterisk is not allowed", ns)
-}
-
-func errorMetricStaticElementHasName(value, name, ns string) error {
-	return fmt.Errorf("A static element %s should not define name %s for namespace %s.", value, name, ns)
-}
-
-func errorMetricDynamicElementHasNoName(value, ns string) error {
-	return fmt.Errorf("A dynamic element %s requires a name for namespace %s.", value, ns)
-}
-
-func errorMetricElementHasTuple(value, ns string) error {
-	return fmt.Errorf("A element %s should not define tuple for namespace %s.", value, ns)
-}
-
 func errorEmptyNamespace() error {
 	return fmt.Errorf("Incorrect format of requested metric, empty list of namespace elements")
 }
@@ -147,6 +131,7 @@ type metricType struct {
 	timestamp          time.Time
 	description        string
 	unit               string
+	kind               core.MetricKind
 }
 
 type metric struct {
@@ -266,10 +251,16 @@ func (m *metricType) Unit() string {
 	return m.unit
 }
 
+func (m *metricType) Kind() core.MetricKind {
+	return m.kind
+}
+
 type catalogedPlugin struct {
 	name         string
 	version      int
 	signed       bool
+	signer       *core.PluginSigner
+	metadata     *core.PluginMetadata
 	typeName     plugin.PluginType
 	state        pluginState
 	path         string
@@ -297,6 +288,14 @@ func (cp *catalogedPlugin) IsSigned() bool {
 	return cp.signed
 }
 
+func (cp *catalogedPlugin) Signer() *core.PluginSigner {
+	return cp.signer
+}
+
+func (cp *catalogedPlugin) Metadata() *core.PluginMetadata {
+	return cp.metadata
+}
+
 func (cp *catalogedPlugin) Status() string {
 	return string(cp.state)
 }
@@ -334,6 +333,8 @@ func newCatalogedPlugin(lp *loadedPlugin) core.CatalogedPlugin {
 		name:         lp.Name(),
 		version:      lp.Version(),
 		signed:       lp.IsSigned(),
+		signer:       lp.Signer(),
+		metadata:     lp.Metadata(),
 		typeName:     lp.Type,
 		state:        lp.State,
 		path:         lp.PluginPath(),
@@ -390,6 +391,7 @@ func (mc *metricCatalog) AddLoadedMetricType(lp *loadedPlugin, mt core.Metric) e
 		policy:             lp.ConfigPolicy.Get(mt.Namespace().Strings()),
 		description:        mt.Description(),
 		unit:               mt.Unit(),
+		kind:               mt.Kind(),
 	}
 	mc.Add(&newMt)
 	return nil
@@ -461,6 +463,7 @@ func (mc *metricCatalog) GetMetric(requested core.Namespace, version int) (*metr
 		policy:             catalogedmt.Plugin.Policy().Get(catalogedmt.Namespace().Strings()),
 		config:             catalogedmt.Config(),
 		unit:               catalogedmt.Unit(),
+		kind:               catalogedmt.Kind(),
 		description:        catalogedmt.Description(),
 		subscriptions:      catalogedmt.SubscriptionCount(),
 	}
@@ -509,6 +512,7 @@ func (mc *metricCatalog) GetMetrics(requested core.Namespace, version int) ([]*m
 				policy:             catalogedmt.Plugin.Policy().Get(catalogedmt.Namespace().Strings()),
 				config:             catalogedmt.Config(),
 				unit:               catalogedmt.Unit(),
+				kind:               catalogedmt.Kind(),
 				description:        catalogedmt.Description(),
 				subscriptions:      catalogedmt.SubscriptionCount(),
 			}
@@ -757,23 +761,5 @@ func specifyInstanceOfDynamicMetric(catalogedNamespace core.Namespace, requested
 // validateMetricNamespace validates metric namespace in terms of containing properly defined dynamic elements,
 // not ending with an asterisk and not contain elements which might be erroneously recognized as a tuple
 func validateMetricNamespace(ns core.Namespace) error {
-	value := ""
-	for _, i := range ns {
-		// A dynamic element requires the name while a static element does not.
-		if i.Name != "" && i.Value != "*" {
-			return errorMetricStaticElementHasName(i.Value, i.Name, ns.String())
-		}
-		if i.Name == "" && i.Value == "*" {
-			return errorMetricDynamicElementHasNoName(i.Value, ns.String())
-		}
-		if isTuple(i.Value) {
-			return errorMetricElementHasTuple(i.Value, ns.String())
-		}
-		value += i.Value
-	}
-	// plugin should NOT advertise metrics ending with a wildcard
-	if strings.HasSuffix(value, "*") {
-		return errorMetricEndsWithAsterisk(ns.String())
-	}
-	return nil
+	return ns.Validate()
 }
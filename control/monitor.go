@@ -86,9 +86,7 @@ func (m *monitor) Start(availablePlugins *availablePlugins) {
 				go func() {
 					availablePlugins.RLock()
 					for _, ap := range availablePlugins.all() {
-						if !ap.IsRemote() {
-							go ap.CheckHealth()
-						}
+						go ap.CheckHealth()
 					}
 					availablePlugins.RUnlock()
 				}()
@@ -22,6 +22,8 @@ package client
 import (
 	"time"
 
+	"golang.org/x/net/context"
+
 	"github.com/intelsdi-x/snap/control/plugin"
 	"github.com/intelsdi-x/snap/control/plugin/cpolicy"
 	"github.com/intelsdi-x/snap/core"
@@ -37,16 +39,22 @@ type PluginClient interface {
 	GetConfigPolicy() (*cpolicy.ConfigPolicy, error)
 }
 
+// PluginHealthDetailClient A client of a plugin which may report itself
+// unhealthy in its reply to Ping.
+type PluginHealthDetailClient interface {
+	PingHealth() error
+}
+
 // PluginCollectorClient A client providing collector specific plugin method calls.
 type PluginCollectorClient interface {
 	PluginClient
-	CollectMetrics([]core.Metric) ([]core.Metric, error)
+	CollectMetrics(context.Context, []core.Metric) ([]core.Metric, error)
 	GetMetricTypes(plugin.ConfigType) ([]core.Metric, error)
 }
 
 type PluginStreamCollectorClient interface {
 	PluginClient
-	StreamMetrics(string, []core.Metric) (chan []core.Metric, chan error, error)
+	StreamMetrics(string, []core.Metric, *StreamResume, <-chan struct{}) (chan []core.Metric, chan error, error)
 	GetMetricTypes(plugin.ConfigType) ([]core.Metric, error)
 	UpdateCollectedMetrics([]core.Metric) error
 	UpdatePluginConfig([]byte) error
@@ -58,11 +66,19 @@ type PluginStreamCollectorClient interface {
 // PluginProcessorClient A client providing processor specific plugin method calls.
 type PluginProcessorClient interface {
 	PluginClient
-	Process([]core.Metric, map[string]ctypes.ConfigValue) ([]core.Metric, error)
+	Process(context.Context, []core.Metric, map[string]ctypes.ConfigValue) ([]core.Metric, error)
 }
 
 // PluginPublisherClient A client providing publishing specific plugin method calls.
 type PluginPublisherClient interface {
 	PluginClient
-	Publish([]core.Metric, map[string]ctypes.ConfigValue) error
+	Publish(context.Context, []core.Metric, map[string]ctypes.ConfigValue) error
+}
+
+// PluginStreamPublisherClient A client providing streaming publisher specific plugin method calls.
+// Batches of a task are sent over a stream kept open for the task until it is closed.
+type PluginStreamPublisherClient interface {
+	PluginClient
+	StreamPublish(ctx context.Context, taskID string, metrics []core.Metric, config map[string]ctypes.ConfigValue) error
+	ClosePublishStream(taskID string) error
 }
@@ -0,0 +1,112 @@
+// +build medium
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	"github.com/intelsdi-x/snap/control/plugin/rpc"
+	"github.com/intelsdi-x/snap/core"
+	"github.com/intelsdi-x/snap/pkg/rpcutil"
+)
+
+// chunkedCollectorServer collects the metrics it is asked for and serves
+// chunked collections with rpc.CollectChunked.
+type chunkedCollectorServer struct {
+	rpc.CollectorServer
+	err     string
+	replies int
+}
+
+func (s *chunkedCollectorServer) CollectMetrics(ctx context.Context, arg *rpc.MetricsArg) (*rpc.MetricsReply, error) {
+	if s.err != "" {
+		return &rpc.MetricsReply{Error: s.err}, nil
+	}
+	return &rpc.MetricsReply{Metrics: arg.Metrics}, nil
+}
+
+func (s *chunkedCollectorServer) CollectMetricsChunked(arg *rpc.CollectChunkedArg, stream rpc.Collector_CollectMetricsChunkedServer) error {
+	return rpc.CollectChunked(arg, &countingStream{stream, s}, s.CollectMetrics)
+}
+
+func (s *chunkedCollectorServer) Ping(ctx context.Context, in *rpc.Empty) (*rpc.ErrReply, error) {
+	return &rpc.ErrReply{}, nil
+}
+
+// countingStream counts the replies sent by the server.
+type countingStream struct {
+	rpc.Collector_CollectMetricsChunkedServer
+	server *chunkedCollectorServer
+}
+
+func (s *countingStream) Send(reply *rpc.MetricsReply) error {
+	s.server.replies++
+	return s.Collector_CollectMetricsChunkedServer.Send(reply)
+}
+
+func TestCollectMetricsChunkedServer(t *testing.T) {
+	Convey("Given a collector serving chunked collections over gRPC", t, func() {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		So(err, ShouldBeNil)
+		opts, err := rpcutil.ChannelServerOptions(rpcutil.CompressionZstd, 0)
+		So(err, ShouldBeNil)
+		server := grpc.NewServer(opts...)
+		collector := &chunkedCollectorServer{}
+		rpc.RegisterCollectorServer(server, collector)
+		go server.Serve(lis)
+		defer server.Stop()
+
+		c, err := NewCollectorGrpcClient(lis.Addr().String(), time.Second, SecurityTLSOff(), GRPCChannel{
+			Compression:      rpcutil.CompressionZstd,
+			CollectChunkSize: 2,
+		})
+		So(err, ShouldBeNil)
+		defer c.Close()
+		mts := []core.Metric{}
+		for _, ns := range []string{"a", "b", "c", "d", "e"} {
+			mts = append(mts, &metric{namespace: core.NewNamespace("intel", ns), data: 1})
+		}
+
+		Convey("the collection is sent in chunks and gathered by the client", func() {
+			collected, err := c.CollectMetrics(context.Background(), mts)
+			So(err, ShouldBeNil)
+			So(collector.replies, ShouldEqual, 3)
+			So(len(collected), ShouldEqual, 5)
+			for i, mt := range collected {
+				So(mt.Namespace().String(), ShouldEqual, mts[i].Namespace().String())
+			}
+		})
+		Convey("an error of the collection fails it", func() {
+			collector.err = "collection failed"
+			_, err := c.CollectMetrics(context.Background(), mts)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "collection failed")
+			So(collector.replies, ShouldEqual, 1)
+		})
+	})
+}
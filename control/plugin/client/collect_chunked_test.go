@@ -0,0 +1,110 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"io"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	"github.com/intelsdi-x/snap/control/plugin/rpc"
+	"github.com/intelsdi-x/snap/core"
+)
+
+// mockChunkedCollector returns the collection of its metrics in replies of at
+// most the chunk size asked for.
+type mockChunkedCollector struct {
+	rpc.CollectorClient
+	chunkSize int64
+	err       string
+}
+
+func (m *mockChunkedCollector) CollectMetricsChunked(ctx context.Context, in *rpc.CollectChunkedArg, opts ...grpc.CallOption) (rpc.Collector_CollectMetricsChunkedClient, error) {
+	m.chunkSize = in.ChunkSize
+	replies := []*rpc.MetricsReply{}
+	mts := in.Metrics_Arg.Metrics
+	for len(mts) > 0 {
+		n := int(in.ChunkSize)
+		if n > len(mts) {
+			n = len(mts)
+		}
+		replies = append(replies, &rpc.MetricsReply{Metrics: mts[:n]})
+		mts = mts[n:]
+	}
+	if m.err != "" {
+		replies = append(replies, &rpc.MetricsReply{Error: m.err})
+	}
+	return &mockChunkedStream{replies: replies}, nil
+}
+
+type mockChunkedStream struct {
+	grpc.ClientStream
+	replies []*rpc.MetricsReply
+}
+
+func (m *mockChunkedStream) Recv() (*rpc.MetricsReply, error) {
+	if len(m.replies) == 0 {
+		return nil, io.EOF
+	}
+	reply := m.replies[0]
+	m.replies = m.replies[1:]
+	return reply, nil
+}
+
+func TestCollectMetricsChunked(t *testing.T) {
+	Convey("Given a collector serving chunked collections", t, func() {
+		collector := &mockChunkedCollector{}
+		g := &grpcClient{collector: collector, collectChunkSize: 2}
+		mts := []core.Metric{}
+		for _, ns := range []string{"a", "b", "c", "d", "e"} {
+			mts = append(mts, &metric{namespace: core.NewNamespace("intel", ns), data: 1})
+		}
+
+		Convey("the replies are gathered into one collection", func() {
+			collected, err := g.CollectMetrics(context.Background(), mts)
+			So(err, ShouldBeNil)
+			So(collector.chunkSize, ShouldEqual, 2)
+			So(len(collected), ShouldEqual, 5)
+			for i, mt := range collected {
+				So(mt.Namespace().String(), ShouldEqual, mts[i].Namespace().String())
+			}
+		})
+		Convey("an error in a reply fails the collection", func() {
+			collector.err = "collection failed"
+			_, err := g.CollectMetrics(context.Background(), mts)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "collection failed")
+		})
+		Convey("an inconsistent histogram fails the collection", func() {
+			mts[3] = &metric{
+				namespace: core.NewNamespace("intel", "d"),
+				data:      core.Histogram{Bounds: []float64{5, 1}, Counts: []uint64{1, 2, 3}},
+			}
+			_, err := g.CollectMetrics(context.Background(), mts)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, core.ErrHistogramBounds.Error())
+		})
+	})
+}
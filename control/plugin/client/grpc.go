@@ -24,12 +24,14 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -69,6 +71,7 @@ type grpcClient struct {
 	streamCollector rpc.StreamCollectorClient
 	processor       rpc.ProcessorClient
 	publisher       rpc.PublisherClient
+	streamPublisher rpc.StreamPublisherClient
 	plugin          pluginClient
 	context         context.Context
 
@@ -77,6 +80,16 @@ type grpcClient struct {
 	// stream connection to stream collector
 	stream rpc.StreamCollector_StreamMetricsClient
 
+	// streams to a streaming publisher, one per task
+	publishStreamsMutex sync.Mutex
+	publishStreams      map[string]*publishStream
+
+	// number of metrics per reply of a chunked collection, 0 when the
+	// collector does not serve CollectMetricsChunked
+	collectChunkSize int
+	// whether the metrics are converted with ToNativeMetrics
+	nativeTypes bool
+
 	pluginType plugin.PluginType
 	timeout    time.Duration
 	conn       *grpc.ClientConn
@@ -92,6 +105,22 @@ type GRPCSecurity struct {
 	CACertPaths []string
 }
 
+// GRPCChannel contains the settings of the gRPC channel to a plugin
+type GRPCChannel struct {
+	// Compression is the compressor of the calls, none when empty
+	Compression string
+	// MaxMessageSize overrides the gRPC maximum size of the messages
+	// exchanged with the plugin when it is above 0
+	MaxMessageSize int
+	// CollectChunkSize is the number of metrics per reply a collector is
+	// asked for, the collection is not chunked when it is 0
+	CollectChunkSize int
+	// NativeTypes keeps the Go int and uint metric values sent to the
+	// plugin, and fails the calls with values gRPC cannot carry, for the
+	// shim of a native plugin
+	NativeTypes bool
+}
+
 // SecurityTLSEnabled generates security object for securing gRPC communication
 func SecurityTLSEnabled(certPath, keyPath string, secureSide SecureSide) GRPCSecurity {
 	return GRPCSecurity{
@@ -124,9 +153,9 @@ func SecurityTLSOff() GRPCSecurity {
 }
 
 // NewCollectorGrpcClient returns a collector gRPC Client.
-func NewCollectorGrpcClient(address string, timeout time.Duration, security GRPCSecurity) (PluginCollectorClient, error) {
+func NewCollectorGrpcClient(address string, timeout time.Duration, security GRPCSecurity, channel GRPCChannel) (PluginCollectorClient, error) {
 	ctx := context.Background()
-	p, err := newPluginGrpcClient(ctx, address, timeout, security, plugin.CollectorPluginType)
+	p, err := newPluginGrpcClient(ctx, address, timeout, security, channel, plugin.CollectorPluginType)
 	if err != nil {
 		return nil, err
 	}
@@ -134,9 +163,9 @@ func NewCollectorGrpcClient(address string, timeout time.Duration, security GRPC
 }
 
 // NewStreamCollectorGrpcClient returns a stream collector gRPC client
-func NewStreamCollectorGrpcClient(address string, timeout time.Duration, security GRPCSecurity) (PluginStreamCollectorClient, error) {
+func NewStreamCollectorGrpcClient(address string, timeout time.Duration, security GRPCSecurity, channel GRPCChannel) (PluginStreamCollectorClient, error) {
 	ctx := context.Background()
-	p, err := newPluginGrpcClient(ctx, address, timeout, security, plugin.StreamCollectorPluginType)
+	p, err := newPluginGrpcClient(ctx, address, timeout, security, channel, plugin.StreamCollectorPluginType)
 	if err != nil {
 		return nil, err
 	}
@@ -144,9 +173,9 @@ func NewStreamCollectorGrpcClient(address string, timeout time.Duration, securit
 }
 
 // NewProcessorGrpcClient returns a processor gRPC Client.
-func NewProcessorGrpcClient(address string, timeout time.Duration, security GRPCSecurity) (PluginProcessorClient, error) {
+func NewProcessorGrpcClient(address string, timeout time.Duration, security GRPCSecurity, channel GRPCChannel) (PluginProcessorClient, error) {
 	ctx := context.Background()
-	p, err := newPluginGrpcClient(ctx, address, timeout, security, plugin.ProcessorPluginType)
+	p, err := newPluginGrpcClient(ctx, address, timeout, security, channel, plugin.ProcessorPluginType)
 	if err != nil {
 		return nil, err
 	}
@@ -154,9 +183,9 @@ func NewProcessorGrpcClient(address string, timeout time.Duration, security GRPC
 }
 
 // NewPublisherGrpcClient returns a publisher gRPC Client.
-func NewPublisherGrpcClient(address string, timeout time.Duration, security GRPCSecurity) (PluginPublisherClient, error) {
+func NewPublisherGrpcClient(address string, timeout time.Duration, security GRPCSecurity, channel GRPCChannel) (PluginPublisherClient, error) {
 	ctx := context.Background()
-	p, err := newPluginGrpcClient(ctx, address, timeout, security, plugin.PublisherPluginType)
+	p, err := newPluginGrpcClient(ctx, address, timeout, security, channel, plugin.PublisherPluginType)
 	if err != nil {
 		return nil, err
 	}
@@ -254,18 +283,25 @@ func buildCredentials(security GRPCSecurity) (creds credentials.TransportCredent
 	return creds, nil
 }
 
-// newPluginGrpcClient returns a configured gRPC Client.
-func newPluginGrpcClient(ctx context.Context, address string, timeout time.Duration, security GRPCSecurity, typ plugin.PluginType) (interface{}, error) {
-	address, port, err := parseAddress(address)
+// NewStreamPublisherGrpcClient returns a streaming publisher gRPC client
+func NewStreamPublisherGrpcClient(address string, timeout time.Duration, security GRPCSecurity, channel GRPCChannel) (PluginStreamPublisherClient, error) {
+	ctx := context.Background()
+	p, err := newPluginGrpcClient(ctx, address, timeout, security, channel, plugin.StreamPublisherPluginType)
 	if err != nil {
 		return nil, err
 	}
+	return p.(PluginStreamPublisherClient), nil
+}
+
+// newPluginGrpcClient returns a configured gRPC Client.
+func newPluginGrpcClient(ctx context.Context, address string, timeout time.Duration, security GRPCSecurity, channel GRPCChannel, typ plugin.PluginType) (interface{}, error) {
 	var p *grpcClient
 	var creds credentials.TransportCredentials
+	var err error
 	if creds, err = buildCredentials(security); err != nil {
 		return nil, err
 	}
-	p, err = newGrpcClient(ctx, address, int(port), timeout, typ, creds)
+	p, err = newGrpcClient(ctx, address, timeout, typ, creds, channel)
 	if err != nil {
 		return nil, err
 	}
@@ -285,16 +321,34 @@ func parseAddress(address string) (string, int64, error) {
 	return address, port, nil
 }
 
-func newGrpcClient(ctx context.Context, addr string, port int, timeout time.Duration, typ plugin.PluginType, creds credentials.TransportCredentials) (*grpcClient, error) {
-	var conn *grpc.ClientConn
-	var err error
-	if conn, err = rpcutil.GetClientConnectionWithCreds(ctx, addr, port, creds); err != nil {
+// newGrpcClient dials the plugin listening on address, either host:port or
+// the path of a Unix socket prefixed by plugin.UnixSocketScheme.
+func newGrpcClient(ctx context.Context, address string, timeout time.Duration, typ plugin.PluginType, creds credentials.TransportCredentials, channel GRPCChannel) (*grpcClient, error) {
+	opts, err := rpcutil.ChannelDialOptions(channel.Compression, channel.MaxMessageSize)
+	if err != nil {
 		return nil, err
 	}
+	var conn *grpc.ClientConn
+	if strings.HasPrefix(address, plugin.UnixSocketScheme) {
+		path := strings.TrimPrefix(address, plugin.UnixSocketScheme)
+		if conn, err = rpcutil.GetUnixClientConnection(ctx, path, creds, opts...); err != nil {
+			return nil, err
+		}
+	} else {
+		addr, port, err := parseAddress(address)
+		if err != nil {
+			return nil, err
+		}
+		if conn, err = rpcutil.GetClientConnectionWithCreds(ctx, addr, int(port), creds, opts...); err != nil {
+			return nil, err
+		}
+	}
 	p := &grpcClient{
-		timeout: timeout,
-		conn:    conn,
-		context: ctx,
+		timeout:          timeout,
+		conn:             conn,
+		context:          ctx,
+		collectChunkSize: channel.CollectChunkSize,
+		nativeTypes:      channel.NativeTypes,
 	}
 
 	switch typ {
@@ -311,6 +365,10 @@ func newGrpcClient(ctx context.Context, addr string, port int, timeout time.Dura
 	case plugin.PublisherPluginType:
 		p.publisher = rpc.NewPublisherClient(conn)
 		p.plugin = p.publisher
+	case plugin.StreamPublisherPluginType:
+		p.streamPublisher = rpc.NewStreamPublisherClient(conn)
+		p.plugin = p.streamPublisher
+		p.publishStreams = map[string]*publishStream{}
 	default:
 		return nil, errors.New(fmt.Sprintf("Invalid plugin type provided %v", typ))
 	}
@@ -323,6 +381,16 @@ func getContext(timeout time.Duration) context.Context {
 	return ctxTimeout
 }
 
+// getCallContext bounds the caller's context by the client timeout so that a
+// call ends at whichever comes first: the plugin timeout, the caller's
+// deadline or the caller cancelling the call.
+func getCallContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
 func (g *grpcClient) Ping() error {
 	_, err := g.plugin.Ping(getContext(g.timeout), &rpc.Empty{})
 	if err != nil {
@@ -331,6 +399,19 @@ func (g *grpcClient) Ping() error {
 	return nil
 }
 
+// PingHealth pings the plugin like Ping, also failing when the plugin reports
+// itself unhealthy in the error of its reply.
+func (g *grpcClient) PingHealth() error {
+	reply, err := g.plugin.Ping(getContext(g.timeout), &rpc.Empty{})
+	if err != nil {
+		return err
+	}
+	if reply.Error != "" {
+		return errors.New(reply.Error)
+	}
+	return nil
+}
+
 func (g *grpcClient) SetKey() error {
 	// Added to conform to interface but not needed by grpc
 	return nil
@@ -360,12 +441,18 @@ func (g *grpcClient) Close() error {
 	return nil
 }
 
-func (g *grpcClient) Publish(metrics []core.Metric, config map[string]ctypes.ConfigValue) error {
+func (g *grpcClient) Publish(ctx context.Context, metrics []core.Metric, config map[string]ctypes.ConfigValue) error {
+	mts, err := g.newMetrics(metrics)
+	if err != nil {
+		return err
+	}
 	arg := &rpc.PubProcArg{
-		Metrics: NewMetrics(metrics),
+		Metrics: mts,
 		Config:  ToConfigMap(config),
 	}
-	reply, err := g.publisher.Publish(getContext(g.timeout), arg)
+	ctx, cancel := getCallContext(ctx, g.timeout)
+	defer cancel()
+	reply, err := g.publisher.Publish(ctx, arg)
 	if err != nil {
 		return err
 	}
@@ -375,12 +462,73 @@ func (g *grpcClient) Publish(metrics []core.Metric, config map[string]ctypes.Con
 	return nil
 }
 
-func (g *grpcClient) Process(metrics []core.Metric, config map[string]ctypes.ConfigValue) ([]core.Metric, error) {
+// StreamPublish sends a batch of metrics over the stream kept open to the
+// plugin for the task and waits for the plugin to acknowledge it.  The stream
+// is opened on the first batch of the task and reopened on the next batch if
+// it breaks.
+func (g *grpcClient) StreamPublish(ctx context.Context, taskID string, metrics []core.Metric, config map[string]ctypes.ConfigValue) error {
+	s, err := g.publishStream(taskID)
+	if err != nil {
+		return err
+	}
+	err = s.publish(ctx, metrics, config)
+	if s.broken() {
+		g.publishStreamsMutex.Lock()
+		if g.publishStreams[taskID] == s {
+			delete(g.publishStreams, taskID)
+		}
+		g.publishStreamsMutex.Unlock()
+	}
+	return err
+}
+
+// ClosePublishStream closes the stream kept open to the plugin for the task,
+// if any.
+func (g *grpcClient) ClosePublishStream(taskID string) error {
+	g.publishStreamsMutex.Lock()
+	s, ok := g.publishStreams[taskID]
+	delete(g.publishStreams, taskID)
+	g.publishStreamsMutex.Unlock()
+	if !ok {
+		return nil
+	}
+	return s.close()
+}
+
+// publishStream returns the stream to the plugin for the task opening it when
+// the task has none.
+func (g *grpcClient) publishStream(taskID string) (*publishStream, error) {
+	g.publishStreamsMutex.Lock()
+	defer g.publishStreamsMutex.Unlock()
+	if s, ok := g.publishStreams[taskID]; ok {
+		return s, nil
+	}
+	header := metadata.New(map[string]string{
+		"task-id": taskID,
+	})
+	ctx, cancel := context.WithCancel(metadata.NewContext(g.context, header))
+	stream, err := g.streamPublisher.StreamPublish(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	s := newPublishStream(stream, cancel, PublishStreamWindow)
+	g.publishStreams[taskID] = s
+	return s, nil
+}
+
+func (g *grpcClient) Process(ctx context.Context, metrics []core.Metric, config map[string]ctypes.ConfigValue) ([]core.Metric, error) {
+	pmts, err := g.newMetrics(metrics)
+	if err != nil {
+		return nil, err
+	}
 	arg := &rpc.PubProcArg{
-		Metrics: NewMetrics(metrics),
+		Metrics: pmts,
 		Config:  ToConfigMap(config),
 	}
-	reply, err := g.processor.Process(getContext(g.timeout), arg)
+	ctx, cancel := getCallContext(ctx, g.timeout)
+	defer cancel()
+	reply, err := g.processor.Process(ctx, arg)
 
 	if err != nil {
 		return nil, err
@@ -388,18 +536,30 @@ func (g *grpcClient) Process(metrics []core.Metric, config map[string]ctypes.Con
 	if reply.Error != "" {
 		return nil, errors.New(reply.Error)
 	}
-	mts := ToCoreMetrics(reply.Metrics)
+	mts, err := toValidCoreMetrics(reply.Metrics)
+	if err != nil {
+		return nil, err
+	}
 	for _, mt := range mts {
 		log.Debug(mt.Namespace())
 	}
 	return mts, nil
 }
 
-func (g *grpcClient) CollectMetrics(mts []core.Metric) ([]core.Metric, error) {
+func (g *grpcClient) CollectMetrics(ctx context.Context, mts []core.Metric) ([]core.Metric, error) {
+	rmts, err := g.newMetrics(mts)
+	if err != nil {
+		return nil, err
+	}
 	arg := &rpc.MetricsArg{
-		Metrics: NewMetrics(mts),
+		Metrics: rmts,
+	}
+	ctx, cancel := getCallContext(ctx, g.timeout)
+	defer cancel()
+	if g.collectChunkSize > 0 {
+		return g.collectMetricsChunked(ctx, arg)
 	}
-	reply, err := g.collector.CollectMetrics(getContext(g.timeout), arg)
+	reply, err := g.collector.CollectMetrics(ctx, arg)
 
 	if err != nil {
 		return nil, err
@@ -409,8 +569,37 @@ func (g *grpcClient) CollectMetrics(mts []core.Metric) ([]core.Metric, error) {
 		return nil, errors.New(reply.Error)
 	}
 
-	metrics := ToCoreMetrics(reply.Metrics)
-	return metrics, nil
+	return toValidCoreMetrics(reply.Metrics)
+}
+
+// collectMetricsChunked collects the metrics of arg over a server stream,
+// gathering the replies of the collector until it closes the stream.
+func (g *grpcClient) collectMetricsChunked(ctx context.Context, arg *rpc.MetricsArg) ([]core.Metric, error) {
+	stream, err := g.collector.CollectMetricsChunked(ctx, &rpc.CollectChunkedArg{
+		Metrics_Arg: arg,
+		ChunkSize:   int64(g.collectChunkSize),
+	})
+	if err != nil {
+		return nil, err
+	}
+	metrics := []core.Metric{}
+	for {
+		reply, err := stream.Recv()
+		if err == io.EOF {
+			return metrics, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if reply.Error != "" {
+			return nil, errors.New(reply.Error)
+		}
+		mts, err := toValidCoreMetrics(reply.Metrics)
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, mts...)
+	}
 }
 
 func (g *grpcClient) UpdateCollectedMetrics(mts []core.Metric) error {
@@ -462,10 +651,16 @@ func (g *grpcClient) UpdateMetricsBuffer(maxMetricsBuffer int64) error {
 	return nil
 }
 
-func (g *grpcClient) StreamMetrics(taskID string, mts []core.Metric) (chan []core.Metric, chan error, error) {
+// StreamMetrics opens a stream to the stream collector for the task.  The
+// stream is closed, and its metrics and errors no longer sent, once done is
+// closed.
+func (g *grpcClient) StreamMetrics(taskID string, mts []core.Metric, resume *StreamResume, done <-chan struct{}) (chan []core.Metric, chan error, error) {
 	arg := &rpc.CollectArg{
 		Metrics_Arg: &rpc.MetricsArg{Metrics: NewMetrics(mts)},
 	}
+	if resume != nil {
+		arg.ResumeToken = resume.Token()
+	}
 	if len(mts) == 0 {
 		return nil, nil, errors.New("No metrics requested to stream")
 	}
@@ -524,14 +719,16 @@ func (g *grpcClient) StreamMetrics(taskID string, mts []core.Metric) (chan []cor
 	header := metadata.New(map[string]string{
 		"task-id": taskID,
 	})
-	ctx := metadata.NewContext(g.context, header)
+	ctx, cancel := context.WithCancel(metadata.NewContext(g.context, header))
 
 	s, err := g.streamCollector.StreamMetrics(ctx)
 	if err != nil {
+		cancel()
 		return nil, nil, err
 	}
 	err = s.Send(arg)
 	if err != nil {
+		cancel()
 		return nil, nil, err
 	}
 	metricChan := make(chan []core.Metric)
@@ -539,41 +736,81 @@ func (g *grpcClient) StreamMetrics(taskID string, mts []core.Metric) (chan []cor
 	doneChan := make(chan struct{})
 	g.killChan = doneChan
 	g.stream = s
-	go g.handleInStream(metricChan, errChan)
+	go g.handleInStream(s, cancel, metricChan, errChan, resume, doneChan, done)
 	return metricChan, errChan, nil
 }
 
 func (g *grpcClient) handleInStream(
+	s rpc.StreamCollector_StreamMetricsClient,
+	cancel context.CancelFunc,
 	metricChan chan []core.Metric,
-	errChan chan error) {
+	errChan chan error,
+	resume *StreamResume,
+	killChan <-chan struct{},
+	done <-chan struct{}) {
+	sendErr := func(err error) bool {
+		select {
+		case errChan <- err:
+			return true
+		case <-done:
+			return false
+		}
+	}
 	go func() {
 		for {
-			in, err := g.stream.Recv()
+			in, err := s.Recv()
 			if err != nil {
+				select {
+				case <-done:
+					// the stream was closed by the caller, the
+					// connection to the plugin is still in use
+					return
+				default:
+				}
 				g.conn.Close()
 				if strings.Contains(err.Error(), "transport is closing") {
-					errChan <- errors.New("connection broken")
+					if !sendErr(errors.New("connection broken")) {
+						return
+					}
 				}
-				errChan <- err
-				break
+				sendErr(err)
+				return
 			}
 			if in.Metrics_Reply != nil {
-				mts := ToCoreMetrics(in.Metrics_Reply.Metrics)
+				if resume != nil && !resume.advance(in.Sequence, in.ResumeToken) {
+					// replayed after a reconnect but already received
+					continue
+				}
+				mts, err := toValidCoreMetrics(in.Metrics_Reply.Metrics)
+				if err != nil {
+					if !sendErr(err) {
+						return
+					}
+					continue
+				}
 				if len(mts) == 0 {
 					// skip empty metrics
 					continue
 				}
-				metricChan <- mts
+				select {
+				case metricChan <- mts:
+				case <-done:
+					return
+				}
 			} else if in.Error != nil {
-				e := errors.New(in.Error.Error)
-				errChan <- e
+				if !sendErr(errors.New(in.Error.Error)) {
+					return
+				}
 			}
 		}
 	}()
 
-	<-g.killChan
-	errChan <- errors.New("connection broken")
-
+	select {
+	case <-killChan:
+		sendErr(errors.New("connection broken"))
+	case <-done:
+	}
+	cancel()
 }
 
 func (g *grpcClient) GetMetricTypes(config plugin.ConfigType) ([]core.Metric, error) {
@@ -623,6 +860,7 @@ type metric struct {
 	tags               map[string]string
 	description        string
 	unit               string
+	kind               core.MetricKind
 }
 
 func (m *metric) Namespace() core.Namespace     { return m.namespace }
@@ -634,6 +872,19 @@ func (m *metric) LastAdvertisedTime() time.Time { return m.lastAdvertisedTime }
 func (m *metric) Timestamp() time.Time          { return m.timeStamp }
 func (m *metric) Description() string           { return m.description }
 func (m *metric) Unit() string                  { return m.unit }
+func (m *metric) Kind() core.MetricKind         { return m.kind }
+
+// toValidCoreMetrics converts the metrics returned by a plugin, failing on
+// the first metric whose data is not consistent
+func toValidCoreMetrics(mts []*rpc.Metric) ([]core.Metric, error) {
+	metrics := ToCoreMetrics(mts)
+	for _, m := range metrics {
+		if err := core.ValidateMetricData(m.Data()); err != nil {
+			return nil, fmt.Errorf("metric %s: %v", m.Namespace(), err)
+		}
+	}
+	return metrics, nil
+}
 
 func ToCoreMetrics(mts []*rpc.Metric) []core.Metric {
 	metrics := make([]core.Metric, len(mts))
@@ -661,6 +912,7 @@ func ToCoreMetric(mt *rpc.Metric) core.Metric {
 		config:             ConfigMapToConfig(mt.Config),
 		description:        mt.Description,
 		unit:               mt.Unit,
+		kind:               core.MetricKind(mt.Kind),
 	}
 
 	switch mt.Data.(type) {
@@ -682,10 +934,50 @@ func ToCoreMetric(mt *rpc.Metric) core.Metric {
 		ret.data = mt.GetUint32Data()
 	case *rpc.Metric_Uint64Data:
 		ret.data = mt.GetUint64Data()
+	case *rpc.Metric_HistogramData:
+		ret.data = toCoreHistogram(mt.GetHistogramData())
+	case *rpc.Metric_SummaryData:
+		ret.data = toCoreSummary(mt.GetSummaryData())
+	case *rpc.Metric_Float64MapData:
+		ret.data = mt.GetFloat64MapData().Values
+	case *rpc.Metric_StringMapData:
+		ret.data = mt.GetStringMapData().Values
+	case *rpc.Metric_IntData:
+		ret.data = int(mt.GetIntData())
+	case *rpc.Metric_UintData:
+		ret.data = uint(mt.GetUintData())
 	}
 	return ret
 }
 
+// toHistogram converts a core.Histogram to a Histogram protobuf message
+func toHistogram(h core.Histogram) *rpc.Histogram {
+	return &rpc.Histogram{Bounds: h.Bounds, Counts: h.Counts, Sum: h.Sum, Count: h.Count}
+}
+
+// toCoreHistogram converts a Histogram protobuf message to a core.Histogram
+func toCoreHistogram(h *rpc.Histogram) core.Histogram {
+	return core.Histogram{Bounds: h.GetBounds(), Counts: h.GetCounts(), Sum: h.GetSum(), Count: h.GetCount()}
+}
+
+// toSummary converts a core.Summary to a Summary protobuf message
+func toSummary(s core.Summary) *rpc.Summary {
+	quantiles := make([]*rpc.Quantile, len(s.Quantiles))
+	for i, q := range s.Quantiles {
+		quantiles[i] = &rpc.Quantile{Quantile: q.Quantile, Value: q.Value}
+	}
+	return &rpc.Summary{Quantiles: quantiles, Sum: s.Sum, Count: s.Count}
+}
+
+// toCoreSummary converts a Summary protobuf message to a core.Summary
+func toCoreSummary(s *rpc.Summary) core.Summary {
+	quantiles := make([]core.Quantile, len(s.GetQuantiles()))
+	for i, q := range s.GetQuantiles() {
+		quantiles[i] = core.Quantile{Quantile: q.GetQuantile(), Value: q.GetValue()}
+	}
+	return core.Summary{Quantiles: quantiles, Sum: s.GetSum(), Count: s.GetCount()}
+}
+
 func NewMetrics(ms []core.Metric) []*rpc.Metric {
 	metrics := make([]*rpc.Metric, len(ms))
 	for i, m := range ms {
@@ -694,7 +986,42 @@ func NewMetrics(ms []core.Metric) []*rpc.Metric {
 	return metrics
 }
 
+// ToNativeMetrics converts metrics like NewMetrics but keeps their Go int and
+// uint values, and fails on values of types gRPC cannot carry.  The metrics
+// of native plugins are converted with it on their way through the native
+// plugin shim, as they were not restricted to the types of gRPC.
+func ToNativeMetrics(ms []core.Metric) ([]*rpc.Metric, error) {
+	metrics := make([]*rpc.Metric, len(ms))
+	for i, m := range ms {
+		cm, err := toMetric(m, true)
+		if err != nil {
+			return nil, fmt.Errorf("metric %s: %v", m.Namespace(), err)
+		}
+		metrics[i] = cm
+	}
+	return metrics, nil
+}
+
+// newMetrics converts the metrics sent to the plugin.
+func (g *grpcClient) newMetrics(ms []core.Metric) ([]*rpc.Metric, error) {
+	if g.nativeTypes {
+		return ToNativeMetrics(ms)
+	}
+	return NewMetrics(ms), nil
+}
+
 func ToMetric(co core.Metric) *rpc.Metric {
+	cm, err := toMetric(co, false)
+	if err != nil {
+		log.Error(err.Error())
+	}
+	return cm
+}
+
+// toMetric converts a core.Metric to a Metric protobuf message, the data of
+// the metric is left out when its type is not supported.  With native the Go
+// int and uint values are kept in the int and uint data.
+func toMetric(co core.Metric, native bool) (*rpc.Metric, error) {
 	cm := &rpc.Metric{
 		Namespace: ToNamespace(co.Namespace()),
 		Version:   int64(co.Version()),
@@ -712,6 +1039,7 @@ func ToMetric(co core.Metric) *rpc.Metric {
 	if co.Config() != nil {
 		cm.Config = ConfigToConfigMap(co.Config())
 	}
+	cm.Kind = rpc.MetricKind(co.Kind())
 	switch t := co.Data().(type) {
 	case string:
 		cm.Data = &rpc.Metric_StringData{t}
@@ -722,7 +1050,16 @@ func ToMetric(co core.Metric) *rpc.Metric {
 	case int32:
 		cm.Data = &rpc.Metric_Int32Data{t}
 	case int:
-		cm.Data = &rpc.Metric_Int64Data{int64(t)}
+		if native {
+			cm.Data = &rpc.Metric_IntData{int64(t)}
+		} else {
+			cm.Data = &rpc.Metric_Int64Data{int64(t)}
+		}
+	case uint:
+		if !native {
+			return cm, fmt.Errorf("unsupported type: %T", t)
+		}
+		cm.Data = &rpc.Metric_UintData{uint64(t)}
 	case int64:
 		cm.Data = &rpc.Metric_Int64Data{t}
 	case uint32:
@@ -733,12 +1070,24 @@ func ToMetric(co core.Metric) *rpc.Metric {
 		cm.Data = &rpc.Metric_BytesData{t}
 	case bool:
 		cm.Data = &rpc.Metric_BoolData{t}
+	case core.Histogram:
+		cm.Data = &rpc.Metric_HistogramData{toHistogram(t)}
+	case *core.Histogram:
+		cm.Data = &rpc.Metric_HistogramData{toHistogram(*t)}
+	case core.Summary:
+		cm.Data = &rpc.Metric_SummaryData{toSummary(t)}
+	case *core.Summary:
+		cm.Data = &rpc.Metric_SummaryData{toSummary(*t)}
+	case map[string]float64:
+		cm.Data = &rpc.Metric_Float64MapData{&rpc.Float64Map{Values: t}}
+	case map[string]string:
+		cm.Data = &rpc.Metric_StringMapData{&rpc.StringMap{Values: t}}
 	case nil:
 		cm.Data = nil
 	default:
-		log.Error(fmt.Sprintf("unsupported type: %s", t))
+		return cm, fmt.Errorf("unsupported type: %T", t)
 	}
-	return cm
+	return cm, nil
 }
 
 func ToCoreNamespace(n []*rpc.NamespaceElement) core.Namespace {
@@ -25,6 +25,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/intelsdi-x/snap/control/plugin/rpc"
 	"github.com/intelsdi-x/snap/core"
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -47,6 +49,54 @@ func TestToCoreMetric(t *testing.T) {
 	})
 }
 
+func TestToCoreMetricStructuredData(t *testing.T) {
+	Convey("Structured data and the kind of a metric are converted", t, func() {
+		tc := []interface{}{
+			core.Histogram{Bounds: []float64{1, 5}, Counts: []uint64{2, 3, 1}, Sum: 17.5, Count: 6},
+			core.Summary{Quantiles: []core.Quantile{{Quantile: 0.5, Value: 2}, {Quantile: 0.99, Value: 9.5}}, Sum: 17.5, Count: 6},
+			map[string]float64{"a": 1.5, "b": -2},
+			map[string]string{"a": "b"},
+		}
+		for _, data := range tc {
+			m := &metric{
+				namespace: core.NewNamespace("a", "b", "c"),
+				version:   1,
+				data:      data,
+				kind:      core.GaugeMetricKind,
+			}
+			b, err := proto.Marshal(ToMetric(m))
+			So(err, ShouldBeNil)
+			mt := &rpc.Metric{}
+			So(proto.Unmarshal(b, mt), ShouldBeNil)
+			So(mt.Kind, ShouldEqual, rpc.MetricKind_GAUGE)
+			cmt := ToCoreMetric(mt)
+			So(cmt.Data(), ShouldResemble, data)
+			So(cmt.Kind(), ShouldEqual, core.GaugeMetricKind)
+		}
+	})
+}
+
+func TestToNativeMetrics(t *testing.T) {
+	Convey("The metrics of native plugins keep the Go types of their values", t, func() {
+		for _, data := range []interface{}{int(-3), uint(3), int64(-3), uint64(3), 1.5, "a", nil} {
+			m := &metric{namespace: core.NewNamespace("a", "b", "c"), data: data}
+			mts, err := ToNativeMetrics([]core.Metric{m})
+			So(err, ShouldBeNil)
+			b, err := proto.Marshal(mts[0])
+			So(err, ShouldBeNil)
+			mt := &rpc.Metric{}
+			So(proto.Unmarshal(b, mt), ShouldBeNil)
+			So(ToCoreMetric(mt).Data(), ShouldEqual, data)
+		}
+		Convey("and fail when gRPC cannot carry them", func() {
+			m := &metric{namespace: core.NewNamespace("a", "b", "c"), data: int16(3)}
+			_, err := ToNativeMetrics([]core.Metric{m})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "metric /a/b/c: unsupported type: int16")
+		})
+	})
+}
+
 func testCases() []*metric {
 	now := time.Now()
 	tc := []*metric{
@@ -0,0 +1,190 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+
+	"github.com/intelsdi-x/snap/control/plugin/rpc"
+	"github.com/intelsdi-x/snap/core"
+	"github.com/intelsdi-x/snap/core/ctypes"
+)
+
+// PublishStreamWindow is the number of batches sent over a publisher stream
+// which may wait on their acknowledgement at the same time.  Sending a batch
+// blocks while the window is full.
+var PublishStreamWindow = 16
+
+var (
+	// ErrPublishStreamClosed is returned for the batches of a publisher stream
+	// closed before the plugin acknowledged them.
+	ErrPublishStreamClosed = errors.New("publisher stream closed")
+)
+
+// publishStream is a stream to a streaming publisher over which the batches
+// of a task are sent.  Each batch carries a sequence number the plugin echoes
+// back when it acknowledges the batch.
+type publishStream struct {
+	sync.Mutex
+	stream rpc.StreamPublisher_StreamPublishClient
+	cancel context.CancelFunc
+	// window holds a token for each batch waiting on its acknowledgement
+	window   chan struct{}
+	sequence int64
+	pending  map[int64]chan error
+	// config last sent over the stream
+	config map[string]ctypes.ConfigValue
+	// err is the reason the stream broke, done is closed when it does
+	err  error
+	done chan struct{}
+}
+
+func newPublishStream(stream rpc.StreamPublisher_StreamPublishClient, cancel context.CancelFunc, window int) *publishStream {
+	if window < 1 {
+		window = 1
+	}
+	s := &publishStream{
+		stream:  stream,
+		cancel:  cancel,
+		window:  make(chan struct{}, window),
+		pending: map[int64]chan error{},
+		done:    make(chan struct{}),
+	}
+	go s.receive()
+	return s
+}
+
+// publish sends a batch of metrics and waits for the plugin to acknowledge
+// it, for ctx to end or for the stream to break.  The config is only sent
+// when it differs from the one the plugin last received.
+func (s *publishStream) publish(ctx context.Context, metrics []core.Metric, config map[string]ctypes.ConfigValue) error {
+	select {
+	case s.window <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.done:
+		return s.err
+	}
+
+	s.Lock()
+	if s.err != nil {
+		s.Unlock()
+		<-s.window
+		return s.err
+	}
+	s.sequence++
+	arg := &rpc.PublishArg{
+		Sequence: s.sequence,
+		Metrics:  NewMetrics(metrics),
+	}
+	if s.sequence == 1 || !reflect.DeepEqual(config, s.config) {
+		arg.Config = ToConfigMap(config)
+		s.config = config
+	}
+	ack := make(chan error, 1)
+	s.pending[arg.Sequence] = ack
+	if err := s.stream.Send(arg); err != nil {
+		s.Unlock()
+		s.fail(err)
+		return err
+	}
+	s.Unlock()
+
+	select {
+	case err := <-ack:
+		return err
+	case <-ctx.Done():
+		// the window is only freed once the plugin acknowledges the batch
+		return ctx.Err()
+	case <-s.done:
+		return s.err
+	}
+}
+
+// receive dispatches the acknowledgements sent by the plugin to the batches
+// waiting on them until the stream breaks.
+func (s *publishStream) receive() {
+	for {
+		reply, err := s.stream.Recv()
+		if err != nil {
+			s.fail(err)
+			return
+		}
+		s.Lock()
+		ack, ok := s.pending[reply.Sequence]
+		delete(s.pending, reply.Sequence)
+		s.Unlock()
+		if !ok {
+			log.WithFields(log.Fields{
+				"_block":   "publish-stream",
+				"sequence": reply.Sequence,
+			}).Warn("acknowledgement received for an unknown batch")
+			continue
+		}
+		<-s.window
+		if reply.Error != "" {
+			ack <- errors.New(reply.Error)
+		} else {
+			ack <- nil
+		}
+	}
+}
+
+// fail marks the stream broken, failing the batches waiting on their
+// acknowledgement with err.
+func (s *publishStream) fail(err error) {
+	s.Lock()
+	defer s.Unlock()
+	if s.err != nil {
+		return
+	}
+	s.err = err
+	close(s.done)
+	for seq, ack := range s.pending {
+		ack <- err
+		delete(s.pending, seq)
+	}
+	s.cancel()
+}
+
+// broken returns true once the stream can no longer be used.
+func (s *publishStream) broken() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// close ends the stream, the batches not acknowledged yet fail with
+// ErrPublishStreamClosed.
+func (s *publishStream) close() error {
+	s.Lock()
+	err := s.stream.CloseSend()
+	s.Unlock()
+	s.fail(ErrPublishStreamClosed)
+	return err
+}
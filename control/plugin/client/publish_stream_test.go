@@ -0,0 +1,161 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	"github.com/intelsdi-x/snap/control/plugin/rpc"
+	"github.com/intelsdi-x/snap/core"
+	"github.com/intelsdi-x/snap/core/ctypes"
+)
+
+// mockPublishStream is the client side of a publisher stream, the batches
+// sent are available on sent and acknowledgements are queued on replies.
+type mockPublishStream struct {
+	grpc.ClientStream
+	sent    chan *rpc.PublishArg
+	acked   chan *rpc.PublishArg
+	replies chan *rpc.PublishReply
+	closed  chan struct{}
+}
+
+func newMockPublishStream() *mockPublishStream {
+	return &mockPublishStream{
+		sent:    make(chan *rpc.PublishArg, 10),
+		acked:   make(chan *rpc.PublishArg, 10),
+		replies: make(chan *rpc.PublishReply, 10),
+		closed:  make(chan struct{}),
+	}
+}
+
+func (m *mockPublishStream) Send(arg *rpc.PublishArg) error {
+	m.sent <- arg
+	return nil
+}
+
+func (m *mockPublishStream) Recv() (*rpc.PublishReply, error) {
+	select {
+	case reply := <-m.replies:
+		return reply, nil
+	case <-m.closed:
+		return nil, io.EOF
+	}
+}
+
+func (m *mockPublishStream) CloseSend() error {
+	return nil
+}
+
+// ackAll acknowledges every batch sent, failing those with a sequence in
+// failed.
+func (m *mockPublishStream) ackAll(failed ...int64) {
+	go func() {
+		for arg := range m.sent {
+			reply := &rpc.PublishReply{Sequence: arg.Sequence}
+			for _, f := range failed {
+				if f == arg.Sequence {
+					reply.Error = "publish failed"
+				}
+			}
+			m.replies <- reply
+			m.acked <- arg
+		}
+	}()
+}
+
+func TestPublishStream(t *testing.T) {
+	metrics := []core.Metric{}
+	config := map[string]ctypes.ConfigValue{"file": ctypes.ConfigValueStr{Value: "/tmp/out"}}
+	Convey("Given a publisher stream", t, func() {
+		ms := newMockPublishStream()
+		cancelled := false
+		s := newPublishStream(ms, func() { cancelled = true }, 1)
+
+		Convey("batches are acknowledged by their sequence number", func() {
+			ms.ackAll(2)
+			So(s.publish(context.Background(), metrics, config), ShouldBeNil)
+			err := s.publish(context.Background(), metrics, config)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "publish failed")
+			So(s.publish(context.Background(), metrics, config), ShouldBeNil)
+			So(s.broken(), ShouldBeFalse)
+		})
+		Convey("the config is only sent when it changes", func() {
+			ms.ackAll()
+			changed := map[string]ctypes.ConfigValue{"file": ctypes.ConfigValueStr{Value: "/tmp/other"}}
+			So(s.publish(context.Background(), metrics, config), ShouldBeNil)
+			So(s.publish(context.Background(), metrics, config), ShouldBeNil)
+			So(s.publish(context.Background(), metrics, changed), ShouldBeNil)
+
+			first, second, third := <-ms.acked, <-ms.acked, <-ms.acked
+			So(first.Sequence, ShouldEqual, 1)
+			So(first.Config, ShouldNotBeNil)
+			So(second.Sequence, ShouldEqual, 2)
+			So(second.Config, ShouldBeNil)
+			So(third.Sequence, ShouldEqual, 3)
+			So(third.Config, ShouldNotBeNil)
+		})
+		Convey("a batch waits for room in the window", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			So(s.publish(ctx, metrics, config), ShouldResemble, context.DeadlineExceeded)
+			ctx2, cancel2 := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel2()
+			So(s.publish(ctx2, metrics, config), ShouldResemble, context.DeadlineExceeded)
+			So(len(ms.sent), ShouldEqual, 1)
+
+			<-ms.sent
+			ms.replies <- &rpc.PublishReply{Sequence: 1}
+			ms.ackAll()
+			So(s.publish(context.Background(), metrics, config), ShouldBeNil)
+		})
+		Convey("closing the stream fails the batches not acknowledged", func() {
+			errc := make(chan error)
+			go func() { errc <- s.publish(context.Background(), metrics, config) }()
+			<-ms.sent
+			So(s.close(), ShouldBeNil)
+			So(<-errc, ShouldEqual, ErrPublishStreamClosed)
+			So(s.broken(), ShouldBeTrue)
+			So(cancelled, ShouldBeTrue)
+			So(s.publish(context.Background(), metrics, config), ShouldEqual, ErrPublishStreamClosed)
+		})
+		Convey("a broken stream fails the batches waiting on it", func() {
+			errc := make(chan error)
+			go func() { errc <- s.publish(context.Background(), metrics, config) }()
+			<-ms.sent
+			close(ms.closed)
+			So(<-errc, ShouldEqual, io.EOF)
+			So(s.broken(), ShouldBeTrue)
+		})
+		Reset(func() {
+			s.fail(errors.New("test done"))
+		})
+	})
+}
@@ -0,0 +1,56 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import "sync"
+
+// StreamResume is the position reached in the stream of a streaming
+// collector.  It outlives the stream so a stream set up again after its
+// connection broke picks up where the previous one stopped.
+type StreamResume struct {
+	sync.Mutex
+	sequence int64
+	token    string
+}
+
+// Token returns the resume token of the last reply received.
+func (r *StreamResume) Token() string {
+	r.Lock()
+	defer r.Unlock()
+	return r.token
+}
+
+// advance records a reply received over the stream.  It returns false for a
+// reply which was already received before the stream was set up again.  A
+// plugin which doesn't resume starts numbering its replies over from 1.
+func (r *StreamResume) advance(sequence int64, token string) bool {
+	r.Lock()
+	defer r.Unlock()
+	if sequence != 0 {
+		if sequence <= r.sequence && sequence != 1 {
+			return false
+		}
+		r.sequence = sequence
+	}
+	if token != "" {
+		r.token = token
+	}
+	return true
+}
@@ -0,0 +1,53 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestStreamResume(t *testing.T) {
+	Convey("Given the position reached in a stream", t, func() {
+		r := &StreamResume{}
+		So(r.advance(1, "a"), ShouldBeTrue)
+		So(r.advance(2, "b"), ShouldBeTrue)
+		So(r.Token(), ShouldEqual, "b")
+
+		Convey("replies replayed after a reconnect are dropped", func() {
+			So(r.advance(2, "b"), ShouldBeFalse)
+			So(r.advance(3, "c"), ShouldBeTrue)
+			So(r.Token(), ShouldEqual, "c")
+		})
+		Convey("a plugin numbering its replies over is followed", func() {
+			So(r.advance(1, "x"), ShouldBeTrue)
+			So(r.advance(2, "y"), ShouldBeTrue)
+			So(r.Token(), ShouldEqual, "y")
+		})
+		Convey("replies without a sequence are never dropped", func() {
+			So(r.advance(0, ""), ShouldBeTrue)
+			So(r.advance(0, ""), ShouldBeTrue)
+			So(r.Token(), ShouldEqual, "b")
+		})
+	})
+}
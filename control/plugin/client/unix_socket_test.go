@@ -0,0 +1,69 @@
+// +build medium
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	"github.com/intelsdi-x/snap/control/plugin"
+	"github.com/intelsdi-x/snap/control/plugin/rpc"
+)
+
+// mockCollectorServer only answers pings.
+type mockCollectorServer struct {
+	rpc.CollectorServer
+}
+
+func (m *mockCollectorServer) Ping(ctx context.Context, in *rpc.Empty) (*rpc.ErrReply, error) {
+	return &rpc.ErrReply{}, nil
+}
+
+func TestUnixSocketClient(t *testing.T) {
+	Convey("Given a collector listening on a Unix socket", t, func() {
+		dir, err := ioutil.TempDir("", "snap-sockets-")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "1.sock")
+		lis, err := net.Listen("unix", path)
+		So(err, ShouldBeNil)
+		server := grpc.NewServer()
+		rpc.RegisterCollectorServer(server, &mockCollectorServer{})
+		go server.Serve(lis)
+		defer server.Stop()
+
+		Convey("the client reaches it through the socket", func() {
+			c, err := NewCollectorGrpcClient(plugin.UnixSocketScheme+path, time.Second, SecurityTLSOff(), GRPCChannel{})
+			So(err, ShouldBeNil)
+			So(c.Ping(), ShouldBeNil)
+			So(c.Close(), ShouldBeNil)
+		})
+	})
+}
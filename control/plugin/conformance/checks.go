@@ -0,0 +1,440 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/intelsdi-x/snap/control/plugin"
+	"github.com/intelsdi-x/snap/control/plugin/client"
+	"github.com/intelsdi-x/snap/control/plugin/cpolicy"
+	"github.com/intelsdi-x/snap/control/plugin/nativeshim"
+	"github.com/intelsdi-x/snap/core"
+	"github.com/intelsdi-x/snap/core/cdata"
+	"github.com/intelsdi-x/snap/core/ctypes"
+	"github.com/intelsdi-x/snap/pkg/rpcutil"
+)
+
+// taskID identifies the streams opened by the checks.
+const taskID = "conformance"
+
+// connect creates the client of the plugin, a plugin speaking the deprecated
+// native RPC protocol is reached through the compatibility shim.
+func (t *tester) connect() (string, error) {
+	address := t.resp.ListenAddress
+	rpcType := t.resp.Meta.RPCType
+	msg := "connected to " + address
+	if t.resp.Meta.Supports(plugin.CapabilityCompression) {
+		t.channel.Compression = rpcutil.NegotiateCompression([]string{rpcutil.CompressionGzip}, t.resp.Meta.Compression)
+	}
+	if rpcType == plugin.NativeRPC {
+		// the directory is created with 0700 permissions
+		dir, err := ioutil.TempDir("", "snap-plugin-sockets-")
+		if err != nil {
+			return "", err
+		}
+		t.socketDir = dir
+		shim, err := nativeshim.Start(t.resp, t.opts.Timeout, filepath.Join(dir, "shim.sock"))
+		if err != nil {
+			return "", err
+		}
+		t.shim = shim
+		address = shim.Address()
+		rpcType = plugin.GRPC
+		t.channel.NativeTypes = true
+		msg += " through the native RPC shim"
+	}
+	c, err := newClient(t.resp.Type, rpcType, address, t.opts.Timeout, t.channel)
+	if err != nil {
+		return "", err
+	}
+	t.client = c
+	t.address = address
+	t.rpcType = rpcType
+	return msg, nil
+}
+
+// newClient returns the client of a plugin of type typ listening on address.
+func newClient(typ plugin.PluginType, rpcType plugin.RPCType, address string, timeout time.Duration, channel client.GRPCChannel) (client.PluginClient, error) {
+	security := client.SecurityTLSOff()
+	switch {
+	case typ == plugin.CollectorPluginType && rpcType == plugin.GRPC:
+		return client.NewCollectorGrpcClient(address, timeout, security, channel)
+	case typ == plugin.ProcessorPluginType && rpcType == plugin.GRPC:
+		return client.NewProcessorGrpcClient(address, timeout, security, channel)
+	case typ == plugin.PublisherPluginType && rpcType == plugin.GRPC:
+		return client.NewPublisherGrpcClient(address, timeout, security, channel)
+	case typ == plugin.StreamCollectorPluginType && rpcType == plugin.STREAMGRPC:
+		return client.NewStreamCollectorGrpcClient(address, timeout, security, channel)
+	case typ == plugin.StreamPublisherPluginType && rpcType == plugin.STREAMGRPC:
+		return client.NewStreamPublisherGrpcClient(address, timeout, security, channel)
+	}
+	return nil, fmt.Errorf("RPC type %d is not supported by %s plugins", rpcType, typ)
+}
+
+func (t *tester) ping() (string, error) {
+	return "", t.client.Ping()
+}
+
+// configPolicy gets the config policy of the plugin and the config values of
+// each of its nodes.
+func (t *tester) configPolicy() (string, error) {
+	policy, err := t.client.GetConfigPolicy()
+	if err != nil {
+		return "", err
+	}
+	t.policy = policy
+	t.config = map[string]ctypes.ConfigValue{}
+	for k, v := range t.opts.Config {
+		t.config[k] = v
+	}
+	rules := 0
+	generated := []string{}
+	for _, node := range policy.GetAll() {
+		values, gen, err := configValues(node.ConfigPolicyNode, t.opts.Config)
+		if err != nil {
+			return "", fmt.Errorf("config of /%s: %v", strings.Join(node.Key, "/"), err)
+		}
+		for k, v := range values {
+			t.config[k] = v
+		}
+		rules += len(node.RulesAsTable())
+		generated = append(generated, gen...)
+	}
+	msg := fmt.Sprintf("%d rules", rules)
+	if len(generated) > 0 {
+		sort.Strings(generated)
+		msg += ", generated values for " + strings.Join(generated, ", ")
+	}
+	return msg, nil
+}
+
+// configValues returns the config values processed by the policy node,
+// given values come first, then the defaults of the node and values
+// generated for the remaining required rules.  The names of the rules values
+// were generated for are returned as well.
+func configValues(node *cpolicy.ConfigPolicyNode, given map[string]ctypes.ConfigValue) (map[string]ctypes.ConfigValue, []string, error) {
+	values := map[string]ctypes.ConfigValue{}
+	for k, v := range given {
+		values[k] = v
+	}
+	// defaults are added before processing so that they are validated
+	for k, v := range node.Defaults() {
+		if _, ok := values[k]; !ok {
+			values[k] = v
+		}
+	}
+	generated := []string{}
+	for _, rule := range node.RulesAsTable() {
+		if _, ok := values[rule.Name]; ok || !rule.Required {
+			continue
+		}
+		values[rule.Name] = generateValue(rule)
+		generated = append(generated, rule.Name)
+	}
+	processed, errs := node.Process(values)
+	if errs != nil && errs.HasErrors() {
+		msgs := []string{}
+		for _, e := range errs.Errors() {
+			msgs = append(msgs, e.Error())
+		}
+		return nil, nil, errors.New(strings.Join(msgs, ", "))
+	}
+	return *processed, generated, nil
+}
+
+// generateValue returns a value satisfying the rule.
+func generateValue(rule cpolicy.RuleTable) ctypes.ConfigValue {
+	if v, ok := rule.Minimum.(ctypes.ConfigValue); ok {
+		return v
+	}
+	if v, ok := rule.Maximum.(ctypes.ConfigValue); ok {
+		return v
+	}
+	switch rule.Type {
+	case cpolicy.IntegerType:
+		return ctypes.ConfigValueInt{Value: 1}
+	case cpolicy.FloatType:
+		return ctypes.ConfigValueFloat{Value: 1}
+	case cpolicy.BoolType:
+		return ctypes.ConfigValueBool{Value: false}
+	}
+	return ctypes.ConfigValueStr{Value: "snap-conformance"}
+}
+
+// metricTypes gets the metric types the collector advertises and checks
+// their namespaces.
+func (t *tester) metricTypes() (string, error) {
+	c, ok := t.client.(interface {
+		GetMetricTypes(plugin.ConfigType) ([]core.Metric, error)
+	})
+	if !ok {
+		return "", fmt.Errorf("%s plugins do not advertise metric types", t.resp.Type)
+	}
+	mts, err := c.GetMetricTypes(plugin.ConfigType{ConfigDataNode: cdata.FromTable(t.config)})
+	if err != nil {
+		return "", err
+	}
+	if len(mts) == 0 {
+		return "", errors.New("no metric types advertised")
+	}
+	seen := map[string]bool{}
+	for _, mt := range mts {
+		ns := mt.Namespace()
+		if len(ns) == 0 {
+			return "", errors.New("metric type with an empty namespace advertised")
+		}
+		if err := ns.Validate(); err != nil {
+			return "", err
+		}
+		if seen[ns.String()] {
+			return "", fmt.Errorf("metric type %s advertised twice", ns)
+		}
+		seen[ns.String()] = true
+	}
+	t.types = mts
+	return fmt.Sprintf("%d metric types", len(mts)), nil
+}
+
+// requestedMetrics returns a request for each advertised metric type along
+// with its config.
+func (t *tester) requestedMetrics() ([]core.Metric, error) {
+	mts := make([]core.Metric, len(t.types))
+	for i, mt := range t.types {
+		ns := mt.Namespace()
+		values, _, err := configValues(t.policy.Get(ns.Strings()), t.opts.Config)
+		if err != nil {
+			return nil, fmt.Errorf("config of %s: %v", ns, err)
+		}
+		mts[i] = plugin.MetricType{
+			Namespace_: ns,
+			Version_:   mt.Version(),
+			Config_:    cdata.FromTable(values),
+		}
+	}
+	return mts, nil
+}
+
+func (t *tester) collect() (string, error) {
+	return t.collectWith(t.client.(client.PluginCollectorClient))
+}
+
+// collectChunked collects over a channel asking for a single metric per
+// reply.
+func (t *tester) collectChunked() (string, error) {
+	channel := t.channel
+	channel.CollectChunkSize = 1
+	c, err := newClient(t.resp.Type, t.rpcType, t.address, t.opts.Timeout, channel)
+	if err != nil {
+		return "", err
+	}
+	defer c.Close()
+	return t.collectWith(c.(client.PluginCollectorClient))
+}
+
+func (t *tester) collectWith(c client.PluginCollectorClient) (string, error) {
+	requested, err := t.requestedMetrics()
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), t.opts.Timeout)
+	defer cancel()
+	mts, err := c.CollectMetrics(ctx, requested)
+	if err != nil {
+		return "", err
+	}
+	return t.checkCollected(mts)
+}
+
+func (t *tester) streamMetrics() (string, error) {
+	requested, err := t.requestedMetrics()
+	if err != nil {
+		return "", err
+	}
+	done := make(chan struct{})
+	defer close(done)
+	metrics, errs, err := t.client.(client.PluginStreamCollectorClient).StreamMetrics(taskID, requested, nil, done)
+	if err != nil {
+		return "", err
+	}
+	select {
+	case mts := <-metrics:
+		return t.checkCollected(mts)
+	case err := <-errs:
+		return "", err
+	case <-time.After(t.opts.Timeout):
+		return "", fmt.Errorf("no metrics streamed within %s", t.opts.Timeout)
+	}
+}
+
+// checkCollected checks that collected metrics are of an advertised type
+// and carry consistent data.
+func (t *tester) checkCollected(mts []core.Metric) (string, error) {
+	if len(mts) == 0 {
+		return "", errors.New("no metrics collected")
+	}
+	for _, m := range mts {
+		if !t.advertised(m.Namespace()) {
+			return "", fmt.Errorf("metric %s is not of an advertised type", m.Namespace())
+		}
+		if m.Data() == nil {
+			return "", fmt.Errorf("metric %s has no data", m.Namespace())
+		}
+		if err := core.ValidateMetricData(m.Data()); err != nil {
+			return "", fmt.Errorf("metric %s: %v", m.Namespace(), err)
+		}
+	}
+	return fmt.Sprintf("%d metrics collected", len(mts)), nil
+}
+
+// advertised returns true when ns is the namespace of an advertised metric
+// type, with any value in place of its dynamic elements.
+func (t *tester) advertised(ns core.Namespace) bool {
+	for _, mt := range t.types {
+		if matches(ns, mt.Namespace()) {
+			return true
+		}
+	}
+	return false
+}
+
+func matches(ns, advertised core.Namespace) bool {
+	if len(ns) != len(advertised) {
+		return false
+	}
+	for i, e := range advertised {
+		if e.IsDynamic() {
+			continue
+		}
+		if ns[i].Value != e.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// generatedMetrics returns metrics with data of each of the basic types to
+// feed processors and publishers with.
+func generatedMetrics() []core.Metric {
+	now := time.Now()
+	data := map[string]interface{}{
+		"int":    int64(42),
+		"float":  4.2,
+		"string": "snap",
+		"bool":   true,
+	}
+	names := []string{"bool", "float", "int", "string"}
+	mts := make([]core.Metric, len(names))
+	for i, name := range names {
+		mts[i] = plugin.MetricType{
+			Namespace_: core.NewNamespace("snap", "conformance", name),
+			Version_:   1,
+			Data_:      data[name],
+			Timestamp_: now,
+			Tags_:      map[string]string{"source": "conformance"},
+		}
+	}
+	return mts
+}
+
+// pluginConfig returns the config values of a processor or publisher, the
+// values of the root node of its policy.
+func (t *tester) pluginConfig() (map[string]ctypes.ConfigValue, error) {
+	values, _, err := configValues(t.policy.Get([]string{""}), t.opts.Config)
+	return values, err
+}
+
+func (t *tester) process() (string, error) {
+	config, err := t.pluginConfig()
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), t.opts.Timeout)
+	defer cancel()
+	mts := generatedMetrics()
+	processed, err := t.client.(client.PluginProcessorClient).Process(ctx, mts, config)
+	if err != nil {
+		return "", err
+	}
+	for _, m := range processed {
+		if len(m.Namespace()) == 0 {
+			return "", errors.New("metric with an empty namespace returned")
+		}
+		if err := core.ValidateMetricData(m.Data()); err != nil {
+			return "", fmt.Errorf("metric %s: %v", m.Namespace(), err)
+		}
+	}
+	return fmt.Sprintf("%d metrics processed into %d", len(mts), len(processed)), nil
+}
+
+func (t *tester) publish() (string, error) {
+	config, err := t.pluginConfig()
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), t.opts.Timeout)
+	defer cancel()
+	mts := generatedMetrics()
+	if err := t.client.(client.PluginPublisherClient).Publish(ctx, mts, config); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d metrics published", len(mts)), nil
+}
+
+func (t *tester) streamPublish() (string, error) {
+	config, err := t.pluginConfig()
+	if err != nil {
+		return "", err
+	}
+	c := t.client.(client.PluginStreamPublisherClient)
+	ctx, cancel := context.WithTimeout(context.Background(), t.opts.Timeout)
+	defer cancel()
+	mts := generatedMetrics()
+	if err := c.StreamPublish(ctx, taskID, mts, config); err != nil {
+		return "", err
+	}
+	if err := c.ClosePublishStream(taskID); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d metrics published", len(mts)), nil
+}
+
+// kill asks the plugin to stop and waits until it does not answer anymore.
+func (t *tester) kill() (string, error) {
+	if err := t.client.Kill("conformance checks done"); err != nil {
+		return "", err
+	}
+	deadline := time.Now().Add(t.opts.Timeout)
+	for time.Now().Before(deadline) {
+		if t.client.Ping() != nil {
+			return "", nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return "", fmt.Errorf("plugin still answers %s after being killed", t.opts.Timeout)
+}
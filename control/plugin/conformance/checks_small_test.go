@@ -0,0 +1,95 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/intelsdi-x/snap/control/plugin/cpolicy"
+	"github.com/intelsdi-x/snap/core"
+	"github.com/intelsdi-x/snap/core/ctypes"
+)
+
+func TestConfigValues(t *testing.T) {
+	Convey("Given a policy node", t, func() {
+		node := cpolicy.NewPolicyNode()
+		name, _ := cpolicy.NewStringRule("name", false, "bob")
+		password, _ := cpolicy.NewStringRule("password", true)
+		port, _ := cpolicy.NewIntegerRule("port", true)
+		port.SetMinimum(1024)
+		ratio, _ := cpolicy.NewFloatRule("ratio", true)
+		debug, _ := cpolicy.NewBoolRule("debug", true)
+		node.Add(name, password, port, ratio, debug)
+
+		Convey("values are generated for required rules without a value", func() {
+			values, generated, err := configValues(node, nil)
+			So(err, ShouldBeNil)
+			So(generated, ShouldHaveLength, 4)
+			So(values["name"], ShouldResemble, ctypes.ConfigValueStr{Value: "bob"})
+			So(values["password"], ShouldResemble, ctypes.ConfigValueStr{Value: "snap-conformance"})
+			So(values["port"], ShouldResemble, ctypes.ConfigValueInt{Value: 1024})
+			So(values["ratio"], ShouldResemble, ctypes.ConfigValueFloat{Value: 1})
+			So(values["debug"], ShouldResemble, ctypes.ConfigValueBool{Value: false})
+		})
+		Convey("given values are kept", func() {
+			values, generated, err := configValues(node, map[string]ctypes.ConfigValue{
+				"password": ctypes.ConfigValueStr{Value: "secret"},
+			})
+			So(err, ShouldBeNil)
+			So(generated, ShouldNotContain, "password")
+			So(values["password"], ShouldResemble, ctypes.ConfigValueStr{Value: "secret"})
+		})
+		Convey("given values are validated", func() {
+			_, _, err := configValues(node, map[string]ctypes.ConfigValue{
+				"port": ctypes.ConfigValueInt{Value: 80},
+			})
+			So(err, ShouldNotBeNil)
+		})
+		Convey("defaults are validated", func() {
+			timeout, _ := cpolicy.NewIntegerRule("timeout", false, 1)
+			timeout.SetMinimum(5)
+			node.Add(timeout)
+			_, _, err := configValues(node, nil)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestMatches(t *testing.T) {
+	Convey("Given an advertised namespace with a dynamic element", t, func() {
+		advertised := core.NewNamespace("intel", "mock").
+			AddDynamicElement("host", "name of the host").
+			AddStaticElement("baz")
+
+		Convey("a namespace with any value for the dynamic element matches", func() {
+			So(matches(core.NewNamespace("intel", "mock", "host0", "baz"), advertised), ShouldBeTrue)
+		})
+		Convey("a namespace with another static element does not match", func() {
+			So(matches(core.NewNamespace("intel", "mock", "host0", "bar"), advertised), ShouldBeFalse)
+		})
+		Convey("a namespace of another length does not match", func() {
+			So(matches(core.NewNamespace("intel", "mock", "host0"), advertised), ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,265 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package conformance checks that a plugin binary behaves the way snapteld
+// expects without loading it into snapteld.  The plugin is started like
+// snapteld starts it and every call of its gRPC services is made with
+// generated inputs, the replies are checked against the metric types and the
+// config policy the plugin advertises.
+package conformance
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/intelsdi-x/snap/control/plugin"
+	"github.com/intelsdi-x/snap/control/plugin/client"
+	"github.com/intelsdi-x/snap/control/plugin/cpolicy"
+	"github.com/intelsdi-x/snap/control/plugin/nativeshim"
+	"github.com/intelsdi-x/snap/core"
+	"github.com/intelsdi-x/snap/core/ctypes"
+)
+
+// DefaultTimeout bounds the start of a plugin and each call made to it when
+// Options.Timeout is not set.
+const DefaultTimeout = 10 * time.Second
+
+var conformanceLogger = log.WithFields(log.Fields{
+	"_module": "plugin-conformance",
+})
+
+// Status is the outcome of a check.
+type Status int
+
+const (
+	// Passed means the plugin behaved as expected.
+	Passed Status = iota
+	// Failed means the plugin did not behave as expected.
+	Failed
+	// Skipped means the check does not apply to the plugin or could not be
+	// made because an earlier check failed.
+	Skipped
+)
+
+var statuses = [...]string{
+	Passed:  "passed",
+	Failed:  "failed",
+	Skipped: "skipped",
+}
+
+func (s Status) String() string {
+	return statuses[s]
+}
+
+// Result is the outcome of one check of a plugin.
+type Result struct {
+	Check   string
+	Status  Status
+	Message string
+}
+
+// Report holds the results of the checks of a plugin in the order they were
+// made.
+type Report struct {
+	Path    string
+	Name    string
+	Version int
+	Type    plugin.PluginType
+	Results []Result
+}
+
+// Passed returns true when no check failed.
+func (r *Report) Passed() bool {
+	for _, res := range r.Results {
+		if res.Status == Failed {
+			return false
+		}
+	}
+	return true
+}
+
+// Options tune a conformance run.
+type Options struct {
+	// Config holds config values passed to the plugin.  Values for the rules
+	// of its config policy which are required but have neither a value here
+	// nor a default are generated.
+	Config map[string]ctypes.ConfigValue
+	// Timeout bounds the start of the plugin and each call made to it.
+	Timeout time.Duration
+}
+
+// Run starts the plugin at path, checks it and kills it.  The returned report
+// holds a result for every check, a check which cannot be made since an
+// earlier one failed is skipped.
+func Run(path string, opts Options) *Report {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	t := &tester{
+		opts:   opts,
+		report: &Report{Path: path},
+	}
+	defer t.stop()
+
+	if !t.check("handshake", t.handshake) || !t.check("connect", t.connect) {
+		return t.report
+	}
+	healthy := t.check("ping", t.ping)
+	policy := t.check("config policy", t.configPolicy)
+	switch t.resp.Type {
+	case plugin.CollectorPluginType:
+		if t.checkAfter("metric types", policy, t.metricTypes) {
+			t.checkAfter("collect", true, t.collect)
+			t.checkAfter("chunked collect", t.resp.Meta.Supports(plugin.CapabilityChunkedCollect), t.collectChunked)
+		} else {
+			t.skip("collect", "chunked collect")
+		}
+	case plugin.StreamCollectorPluginType:
+		if t.checkAfter("metric types", policy, t.metricTypes) {
+			t.checkAfter("stream metrics", true, t.streamMetrics)
+		} else {
+			t.skip("stream metrics")
+		}
+	case plugin.ProcessorPluginType:
+		t.checkAfter("process", policy, t.process)
+	case plugin.PublisherPluginType:
+		t.checkAfter("publish", policy, t.publish)
+	case plugin.StreamPublisherPluginType:
+		t.checkAfter("stream publish", policy, t.streamPublish)
+	}
+	t.checkAfter("health", healthy, t.ping)
+	t.check("kill", t.kill)
+	return t.report
+}
+
+// tester holds the state of a conformance run.
+type tester struct {
+	opts   Options
+	report *Report
+
+	ep   *plugin.ExecutablePlugin
+	resp plugin.Response
+	shim *nativeshim.Shim
+	// directory of the Unix socket the shim serves on
+	socketDir string
+	client    client.PluginClient
+	// address, RPC type and channel the client was created with
+	address string
+	rpcType plugin.RPCType
+	channel client.GRPCChannel
+
+	policy *cpolicy.ConfigPolicy
+	// config of the plugin, the values of all the nodes of its policy
+	config map[string]ctypes.ConfigValue
+	types  []core.Metric
+}
+
+// check makes the check named name and records its outcome, the message
+// returned by f is recorded for a passed check.
+func (t *tester) check(name string, f func() (string, error)) bool {
+	msg, err := f()
+	if err != nil {
+		t.record(name, Failed, err.Error())
+		return false
+	}
+	t.record(name, Passed, msg)
+	return true
+}
+
+// checkAfter makes the check named name if ok is true and skips it otherwise.
+func (t *tester) checkAfter(name string, ok bool, f func() (string, error)) bool {
+	if !ok {
+		t.skip(name)
+		return false
+	}
+	return t.check(name, f)
+}
+
+func (t *tester) skip(names ...string) {
+	for _, name := range names {
+		t.record(name, Skipped, "")
+	}
+}
+
+func (t *tester) record(name string, status Status, msg string) {
+	conformanceLogger.WithFields(log.Fields{
+		"_block":  "check",
+		"plugin":  filepath.Base(t.report.Path),
+		"check":   name,
+		"status":  status.String(),
+		"message": msg,
+	}).Debug("plugin checked")
+	t.report.Results = append(t.report.Results, Result{Check: name, Status: status, Message: msg})
+}
+
+// handshake starts the plugin and checks its response.
+func (t *tester) handshake() (string, error) {
+	ep, err := plugin.NewExecutablePlugin(plugin.NewArg(int(log.GetLevel()), false), t.report.Path)
+	if err != nil {
+		return "", err
+	}
+	ep.SetName(filepath.Base(t.report.Path))
+	resp, err := ep.Run(t.opts.Timeout)
+	if err != nil {
+		return "", err
+	}
+	t.ep = ep
+	t.resp = resp
+	t.report.Name = resp.Meta.Name
+	t.report.Version = resp.Meta.Version
+	t.report.Type = resp.Type
+
+	if resp.State != plugin.PluginSuccess {
+		return "", fmt.Errorf("plugin failed to start: %s", resp.ErrorMessage)
+	}
+	if resp.Meta.Name == "" {
+		return "", fmt.Errorf("plugin has no name")
+	}
+	if resp.Meta.Version < 1 {
+		return "", fmt.Errorf("plugin version %d is not greater than zero", resp.Meta.Version)
+	}
+	if resp.Type < plugin.CollectorPluginType || resp.Type > plugin.StreamPublisherPluginType {
+		return "", fmt.Errorf("unknown plugin type %d", resp.Type)
+	}
+	if resp.ListenAddress == "" {
+		return "", fmt.Errorf("plugin did not tell the address it listens on")
+	}
+	return fmt.Sprintf("%s %s version %d, protocol version %d", resp.Type, resp.Meta.Name, resp.Meta.Version, resp.Meta.ProtocolVersion), nil
+}
+
+// stop kills the plugin unless it exited already and releases the
+// connection to it.
+func (t *tester) stop() {
+	if t.client != nil {
+		t.client.Close()
+	}
+	if t.shim != nil {
+		t.shim.Stop()
+	}
+	if t.socketDir != "" {
+		os.RemoveAll(t.socketDir)
+	}
+	if t.ep != nil {
+		t.ep.Kill()
+	}
+}
@@ -0,0 +1,99 @@
+// +build legacy
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/intelsdi-x/snap/control/fixtures"
+	"github.com/intelsdi-x/snap/core/ctypes"
+	"github.com/intelsdi-x/snap/plugin/helper"
+)
+
+func statusOf(r *Report, check string) Status {
+	for _, res := range r.Results {
+		if res.Check == check {
+			return res.Status
+		}
+	}
+	return -1
+}
+
+func TestRun(t *testing.T) {
+	opts := Options{Timeout: 5 * time.Second}
+
+	Convey("Checking the mock collector", t, func() {
+		r := Run(fixtures.PluginPathMock1, opts)
+		So(r.Results, ShouldNotBeEmpty)
+		for _, res := range r.Results {
+			if res.Check == "chunked collect" {
+				So(res.Status, ShouldEqual, Skipped)
+			} else {
+				So(res.Status, ShouldEqual, Passed)
+			}
+		}
+		So(r.Passed(), ShouldBeTrue)
+		So(r.Name, ShouldEqual, "mock")
+		So(r.Version, ShouldEqual, 1)
+	})
+	Convey("Checking the passthru processor", t, func() {
+		r := Run(helper.PluginFilePath("snap-plugin-processor-passthru"), opts)
+		So(r.Passed(), ShouldBeTrue)
+		So(statusOf(r, "process"), ShouldEqual, Passed)
+		So(statusOf(r, "kill"), ShouldEqual, Passed)
+	})
+	Convey("Checking the mock file publisher", t, func() {
+		dir, err := ioutil.TempDir("", "snap-conformance-")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		file := filepath.Join(dir, "published")
+		r := Run(helper.PluginFilePath("snap-plugin-publisher-mock-file"), Options{
+			Timeout: opts.Timeout,
+			Config:  map[string]ctypes.ConfigValue{"file": ctypes.ConfigValueStr{Value: file}},
+		})
+		So(r.Passed(), ShouldBeTrue)
+		So(statusOf(r, "publish"), ShouldEqual, Passed)
+		_, err = os.Stat(file)
+		So(err, ShouldBeNil)
+	})
+	Convey("Checking a collector panicking on collect", t, func() {
+		r := Run(fixtures.PluginPathMock2, Options{
+			Timeout: opts.Timeout,
+			Config:  map[string]ctypes.ConfigValue{"panic": ctypes.ConfigValueBool{Value: true}},
+		})
+		So(r.Passed(), ShouldBeFalse)
+		So(statusOf(r, "metric types"), ShouldEqual, Passed)
+		So(statusOf(r, "collect"), ShouldEqual, Failed)
+	})
+	Convey("Checking a file which is not a plugin", t, func() {
+		r := Run(filepath.Join(helper.PluginPath(), "not-a-plugin"), opts)
+		So(r.Passed(), ShouldBeFalse)
+		So(r.Results, ShouldHaveLength, 1)
+		So(statusOf(r, "handshake"), ShouldEqual, Failed)
+	})
+}
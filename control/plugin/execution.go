@@ -39,6 +39,9 @@ type ExecutablePlugin struct {
 	cmd    command
 	stdout io.Reader
 	stderr io.Reader
+	// the Unix socket the plugin was given to listen on, see
+	// Arg.ListenSocket
+	listenSocket string
 }
 
 // An interface for the interactions ExecutablePlugin has with an exec.Cmd
@@ -95,9 +98,10 @@ func NewExecutablePlugin(a Arg, commands ...string) (*ExecutablePlugin, error) {
 		return nil, err
 	}
 	return &ExecutablePlugin{
-		cmd:    &commandWrapper{cmd},
-		stdout: stdout,
-		stderr: stderr,
+		cmd:          &commandWrapper{cmd},
+		stdout:       stdout,
+		stderr:       stderr,
+		listenSocket: a.ListenSocket,
 	}, nil
 }
 
@@ -168,6 +172,12 @@ func (e *ExecutablePlugin) Run(timeout time.Duration) (Response, error) {
 		// We timed out waiting for the plugin's response.  Set err.
 		err = fmt.Errorf("timed out waiting for plugin %s", path.Base(e.cmd.Path()))
 	}
+	if err == nil && strings.HasPrefix(resp.ListenAddress, UnixSocketScheme) {
+		// the plugin may only listen on the socket it was given
+		if socket := strings.TrimPrefix(resp.ListenAddress, UnixSocketScheme); socket != e.listenSocket {
+			err = fmt.Errorf("plugin %s listens on Unix socket %s instead of the one it was given", path.Base(e.cmd.Path()), socket)
+		}
+	}
 	if err != nil {
 		execLogger.WithFields(log.Fields{
 			"received_response": string(respBytes),
@@ -189,6 +199,12 @@ func (e *ExecutablePlugin) Run(timeout time.Duration) (Response, error) {
 	return resp, err
 }
 
+// ListenSocket returns the path of the Unix socket the plugin was given to
+// listen on, empty when it was given none.
+func (e *ExecutablePlugin) ListenSocket() string {
+	return e.listenSocket
+}
+
 func (e *ExecutablePlugin) SetName(name string) {
 	e.name = name
 }
@@ -74,6 +74,23 @@ func TestExecutablePlugin(t *testing.T) {
 			_, err := e.Run(time.Millisecond * 100)
 			So(err, ShouldNotBeNil)
 		})
+		Convey("accepts a plugin listening on the Unix socket it was given", func() {
+			e := setupMockExec([]byte(`{"ListenAddress": "unix:///tmp/snap/1.sock"}`), false)
+			e.listenSocket = "/tmp/snap/1.sock"
+			_, err := e.Run(time.Millisecond * 100)
+			So(err, ShouldBeNil)
+		})
+		Convey("returns an error if the plugin listens on another Unix socket", func() {
+			e := setupMockExec([]byte(`{"ListenAddress": "unix:///tmp/other.sock"}`), false)
+			e.listenSocket = "/tmp/snap/1.sock"
+			_, err := e.Run(time.Millisecond * 100)
+			So(err, ShouldNotBeNil)
+		})
+		Convey("returns an error if the plugin listens on a Unix socket it was not given", func() {
+			e := setupMockExec([]byte(`{"ListenAddress": "unix:///tmp/other.sock"}`), false)
+			_, err := e.Run(time.Millisecond * 100)
+			So(err, ShouldNotBeNil)
+		})
 		Convey("returns an error if the timeout expires", func() {
 			e := setupMockExec([]byte(`{"Token": "a token"}`), true)
 			_, err := e.Run(time.Millisecond * 100)
@@ -93,6 +93,9 @@ type MetricType struct {
 
 	Data_ interface{} `json:"data"`
 
+	// Kind tells how the value of the metric changes between collections.
+	Kind_ core.MetricKind `json:"kind,omitempty"`
+
 	// Tags are key value pairs that can be added by the framework or any
 	// plugin along the collect -> process -> publish pipeline.
 	Tags_ map[string]string `json:"tags"`
@@ -145,6 +148,11 @@ func (p MetricType) Data() interface{} {
 	return p.Data_
 }
 
+// returns the kind of the metric
+func (p MetricType) Kind() core.MetricKind {
+	return p.Kind_
+}
+
 // returns the description of the metric
 func (p MetricType) Description() string {
 	return p.Description_
@@ -158,3 +166,79 @@ func (p MetricType) Unit() string {
 func (p *MetricType) AddData(data interface{}) {
 	p.Data_ = data
 }
+
+// These are the types of the structured data of a metric, they are added to
+// the JSON encoding of the metric to decode the data back to the same type
+const (
+	histogramDataType  = "histogram"
+	summaryDataType    = "summary"
+	float64MapDataType = "float64_map"
+	stringMapDataType  = "string_map"
+)
+
+// jsonMetricType is a MetricType without its JSON methods
+type jsonMetricType MetricType
+
+// MarshalJSON adds the type of the structured data to the metric
+func (p MetricType) MarshalJSON() ([]byte, error) {
+	var dataType string
+	switch p.Data_.(type) {
+	case core.Histogram, *core.Histogram:
+		dataType = histogramDataType
+	case core.Summary, *core.Summary:
+		dataType = summaryDataType
+	case map[string]float64:
+		dataType = float64MapDataType
+	case map[string]string:
+		dataType = stringMapDataType
+	}
+	return json.Marshal(struct {
+		jsonMetricType
+		DataType string `json:"data_type,omitempty"`
+	}{jsonMetricType(p), dataType})
+}
+
+// UnmarshalJSON decodes the structured data of the metric to its type
+func (p *MetricType) UnmarshalJSON(data []byte) error {
+	m := struct {
+		*jsonMetricType
+		DataType string          `json:"data_type"`
+		Data     json.RawMessage `json:"data"`
+	}{jsonMetricType: (*jsonMetricType)(p)}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	p.Data_ = nil
+	if len(m.Data) == 0 {
+		return nil
+	}
+	switch m.DataType {
+	case histogramDataType:
+		h := core.Histogram{}
+		if err := json.Unmarshal(m.Data, &h); err != nil {
+			return err
+		}
+		p.Data_ = h
+	case summaryDataType:
+		s := core.Summary{}
+		if err := json.Unmarshal(m.Data, &s); err != nil {
+			return err
+		}
+		p.Data_ = s
+	case float64MapDataType:
+		fm := map[string]float64{}
+		if err := json.Unmarshal(m.Data, &fm); err != nil {
+			return err
+		}
+		p.Data_ = fm
+	case stringMapDataType:
+		sm := map[string]string{}
+		if err := json.Unmarshal(m.Data, &sm); err != nil {
+			return err
+		}
+		p.Data_ = sm
+	default:
+		return json.Unmarshal(m.Data, &p.Data_)
+	}
+	return nil
+}
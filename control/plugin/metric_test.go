@@ -139,6 +139,31 @@ func TestMetric(t *testing.T) {
 		})
 	})
 
+	Convey("structured data is kept by snap.gob and snap.json", t, func() {
+		h := core.Histogram{Bounds: []float64{1, 5}, Counts: []uint64{2, 3, 1}, Sum: 17.5, Count: 6}
+		sm := core.Summary{Quantiles: []core.Quantile{{Quantile: 0.5, Value: 2}, {Quantile: 0.99, Value: 9.5}}, Sum: 17.5, Count: 6}
+		m := []MetricType{
+			*NewMetricType(core.NewNamespace("foo", "histogram"), time.Now(), nil, "", h),
+			*NewMetricType(core.NewNamespace("foo", "summary"), time.Now(), nil, "", sm),
+			*NewMetricType(core.NewNamespace("foo", "floats"), time.Now(), nil, "", map[string]float64{"a": 1.5}),
+			*NewMetricType(core.NewNamespace("foo", "strings"), time.Now(), nil, "", map[string]string{"a": "b"}),
+		}
+		m[0].Kind_ = core.CounterMetricKind
+		for _, contentType := range []string{"snap.gob", "snap.json"} {
+			a, c, e := MarshalMetricTypes(contentType, m)
+			So(e, ShouldBeNil)
+			So(c, ShouldEqual, contentType)
+			mts, e := UnmarshallMetricTypes(c, a)
+			So(e, ShouldBeNil)
+			So(mts[0].Data(), ShouldResemble, h)
+			So(mts[0].Kind(), ShouldEqual, core.CounterMetricKind)
+			So(mts[1].Data(), ShouldResemble, sm)
+			So(mts[1].Kind(), ShouldEqual, core.UnknownMetricKind)
+			So(mts[2].Data(), ShouldResemble, map[string]float64{"a": 1.5})
+			So(mts[3].Data(), ShouldResemble, map[string]string{"a": "b"})
+		}
+	})
+
 	Convey("error on unmarshall using bad content type", t, func() {
 		m := []MetricType{
 			*NewMetricType(core.NewNamespace("foo", "bar"), time.Now(), nil, "", 1),
@@ -17,7 +17,7 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package client
+package nativeshim
 
 import (
 	"bytes"
@@ -28,9 +28,9 @@ import (
 	"net"
 	"net/rpc"
 	"time"
-	"unicode"
 
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
 
 	"github.com/intelsdi-x/snap/control/plugin"
 	"github.com/intelsdi-x/snap/control/plugin/cpolicy"
@@ -41,39 +41,28 @@ import (
 	"github.com/intelsdi-x/snap/core/ctypes"
 )
 
-// CallsRPC provides an interface for RPC clients
-type CallsRPC interface {
+// callsRPC provides an interface for RPC clients
+type callsRPC interface {
 	Call(methd string, args interface{}, reply interface{}) error
+	Close() error
 }
 
 // Native clients use golang net/rpc for communication to a native rpc server.
-type PluginNativeClient struct {
-	connection CallsRPC
+type nativeClient struct {
+	connection callsRPC
 	pluginType plugin.PluginType
 	encoder    encoding.Encoder
 	encrypter  *encrypter.Encrypter
 	timeout    time.Duration
 }
 
-func NewCollectorNativeClient(address string, timeout time.Duration, pub *rsa.PublicKey, secure bool) (PluginCollectorClient, error) {
-	return newNativeClient(address, timeout, plugin.CollectorPluginType, pub, secure)
-}
-
-func NewPublisherNativeClient(address string, timeout time.Duration, pub *rsa.PublicKey, secure bool) (PluginPublisherClient, error) {
-	return newNativeClient(address, timeout, plugin.PublisherPluginType, pub, secure)
-}
-
-func NewProcessorNativeClient(address string, timeout time.Duration, pub *rsa.PublicKey, secure bool) (PluginProcessorClient, error) {
-	return newNativeClient(address, timeout, plugin.ProcessorPluginType, pub, secure)
-}
-
-func (p *PluginNativeClient) Ping() error {
+func (p *nativeClient) Ping() error {
 	var reply []byte
 	err := p.connection.Call("SessionState.Ping", []byte{}, &reply)
 	return err
 }
 
-func (p *PluginNativeClient) SetKey() error {
+func (p *nativeClient) SetKey() error {
 	out, err := p.encrypter.EncryptKey()
 	if err != nil {
 		return err
@@ -83,7 +72,7 @@ func (p *PluginNativeClient) SetKey() error {
 	}, &[]byte{})
 }
 
-func (p *PluginNativeClient) Kill(reason string) error {
+func (p *nativeClient) Kill(reason string) error {
 	args := plugin.KillArgs{Reason: reason}
 	out, err := p.encoder.Encode(args)
 	if err != nil {
@@ -95,9 +84,8 @@ func (p *PluginNativeClient) Kill(reason string) error {
 	return err
 }
 
-func (p *PluginNativeClient) Close() error {
-	// Added to conform to interface, but not needed by native
-	return nil
+func (p *nativeClient) Close() error {
+	return p.connection.Close()
 }
 
 // Used to catch zero values for times and overwrite with current time
@@ -123,6 +111,7 @@ func encodeMetrics(metrics []core.Metric) []byte {
 			Unit_:               m.Unit(),
 			Description_:        m.Description(),
 			Data_:               m.Data(),
+			Kind_:               m.Kind(),
 		}
 	}
 	var buf bytes.Buffer
@@ -146,7 +135,7 @@ func decodeMetrics(bts []byte) ([]core.Metric, error) {
 	return cmetrics, nil
 }
 
-func enforceTimeout(p *PluginNativeClient, dl time.Duration, done chan int) {
+func enforceTimeout(p *nativeClient, dl time.Duration, done chan int) {
 	select {
 	case <-time.After(dl):
 		p.Kill("Passed deadline")
@@ -155,7 +144,32 @@ func enforceTimeout(p *PluginNativeClient, dl time.Duration, done chan int) {
 	}
 }
 
-func (p *PluginNativeClient) Publish(metrics []core.Metric, config map[string]ctypes.ConfigValue) error {
+// call invokes method on the plugin and returns the context's error as soon as
+// ctx is cancelled or passes its deadline, without waiting on the plugin any
+// longer. A plugin which does not reply within the client timeout is killed.
+func (p *nativeClient) call(ctx context.Context, method string, args interface{}, reply interface{}) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	done := make(chan int)
+	go enforceTimeout(p, p.timeout, done)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- p.connection.Call(method, args, reply)
+		close(done)
+	}()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *nativeClient) Publish(ctx context.Context, metrics []core.Metric, config map[string]ctypes.ConfigValue) error {
 
 	args := plugin.PublishArgs{
 		ContentType: plugin.SnapGOBContentType,
@@ -168,14 +182,10 @@ func (p *PluginNativeClient) Publish(metrics []core.Metric, config map[string]ct
 		return err
 	}
 	var reply []byte
-	done := make(chan int)
-	go enforceTimeout(p, p.timeout, done)
-	err = p.connection.Call("Publisher.Publish", out, &reply)
-	close(done)
-	return err
+	return p.call(ctx, "Publisher.Publish", out, &reply)
 }
 
-func (p *PluginNativeClient) Process(metrics []core.Metric, config map[string]ctypes.ConfigValue) ([]core.Metric, error) {
+func (p *nativeClient) Process(ctx context.Context, metrics []core.Metric, config map[string]ctypes.ConfigValue) ([]core.Metric, error) {
 
 	args := plugin.ProcessorArgs{
 		ContentType: plugin.SnapGOBContentType,
@@ -189,10 +199,7 @@ func (p *PluginNativeClient) Process(metrics []core.Metric, config map[string]ct
 	}
 
 	var reply []byte
-	done := make(chan int)
-	go enforceTimeout(p, p.timeout, done)
-	err = p.connection.Call("Processor.Process", out, &reply)
-	close(done)
+	err = p.call(ctx, "Processor.Process", out, &reply)
 	if err != nil {
 		return nil, err
 	}
@@ -210,7 +217,7 @@ func (p *PluginNativeClient) Process(metrics []core.Metric, config map[string]ct
 
 }
 
-func (p *PluginNativeClient) CollectMetrics(mts []core.Metric) ([]core.Metric, error) {
+func (p *nativeClient) CollectMetrics(ctx context.Context, mts []core.Metric) ([]core.Metric, error) {
 	// Convert core.MetricType slice into plugin.nMetricType slice as we have
 	// to send structs over RPC
 	var results []core.Metric
@@ -227,6 +234,7 @@ func (p *PluginNativeClient) CollectMetrics(mts []core.Metric) ([]core.Metric, e
 			Tags_:               mt.Tags(),
 			Config_:             mt.Config(),
 			Unit_:               mt.Unit(),
+			Kind_:               mt.Kind(),
 		}
 	}
 
@@ -237,10 +245,7 @@ func (p *PluginNativeClient) CollectMetrics(mts []core.Metric) ([]core.Metric, e
 	}
 
 	var reply []byte
-	done := make(chan int)
-	go enforceTimeout(p, p.timeout, done)
-	err = p.connection.Call("Collector.CollectMetrics", out, &reply)
-	close(done)
+	err = p.call(ctx, "Collector.CollectMetrics", out, &reply)
 	if err != nil {
 		return nil, err
 	}
@@ -261,7 +266,7 @@ func (p *PluginNativeClient) CollectMetrics(mts []core.Metric) ([]core.Metric, e
 	return results, nil
 }
 
-func (p *PluginNativeClient) GetMetricTypes(config plugin.ConfigType) ([]core.Metric, error) {
+func (p *nativeClient) GetMetricTypes(config plugin.ConfigType) ([]core.Metric, error) {
 	var reply []byte
 
 	args := plugin.GetMetricTypesArgs{PluginConfig: config}
@@ -292,7 +297,7 @@ func (p *PluginNativeClient) GetMetricTypes(config plugin.ConfigType) ([]core.Me
 	return retMetricTypes, nil
 }
 
-func (p *PluginNativeClient) GetConfigPolicy() (*cpolicy.ConfigPolicy, error) {
+func (p *nativeClient) GetConfigPolicy() (*cpolicy.ConfigPolicy, error) {
 	var reply []byte
 	err := p.connection.Call("SessionState.GetConfigPolicy", []byte{}, &reply)
 	if err != nil {
@@ -308,13 +313,7 @@ func (p *PluginNativeClient) GetConfigPolicy() (*cpolicy.ConfigPolicy, error) {
 	return r.Policy, nil
 }
 
-// GetType returns the string type of the plugin
-// Note: the first letter of the type will be capitalized.
-func (p *PluginNativeClient) GetType() string {
-	return upcaseInitial(p.pluginType.String())
-}
-
-func newNativeClient(address string, timeout time.Duration, t plugin.PluginType, pub *rsa.PublicKey, secure bool) (*PluginNativeClient, error) {
+func newNativeClient(address string, timeout time.Duration, t plugin.PluginType, pub *rsa.PublicKey, secure bool) (*nativeClient, error) {
 	// Attempt to dial address error on timeout or problem
 	conn, err := net.DialTimeout("tcp", address, timeout)
 	// Return nil RPCClient and err if encoutered
@@ -322,7 +321,7 @@ func newNativeClient(address string, timeout time.Duration, t plugin.PluginType,
 		return nil, err
 	}
 	r := rpc.NewClient(conn)
-	p := &PluginNativeClient{
+	p := &nativeClient{
 		connection: r,
 		pluginType: t,
 		timeout:    timeout,
@@ -357,10 +356,3 @@ func init() {
 	gob.RegisterName("conf_policy_float", &cpolicy.FloatRule{})
 	gob.RegisterName("conf_policy_bool", &cpolicy.BoolRule{})
 }
-
-func upcaseInitial(str string) string {
-	for i, v := range str {
-		return string(unicode.ToUpper(v)) + str[i+1:]
-	}
-	return ""
-}
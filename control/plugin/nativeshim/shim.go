@@ -0,0 +1,263 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package nativeshim serves the legacy plugins speaking the deprecated native
+// RPC protocol over gRPC, so snap only speaks gRPC to its plugins.  The native
+// protocol is no longer used anywhere else in snap.
+package nativeshim
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/intelsdi-x/snap/control/plugin"
+	"github.com/intelsdi-x/snap/control/plugin/client"
+	"github.com/intelsdi-x/snap/control/plugin/rpc"
+	"github.com/intelsdi-x/snap/core"
+	"github.com/intelsdi-x/snap/core/cdata"
+)
+
+var shimLogger = log.WithField("_module", "native-shim")
+
+// Shim is a gRPC server on a Unix socket forwarding the calls it receives
+// to a native plugin.
+type Shim struct {
+	native   *nativeClient
+	secure   bool
+	server   *grpc.Server
+	listener net.Listener
+	stopOnce sync.Once
+
+	// the session key of a secure plugin is set before the first call
+	// needing it
+	keyMutex sync.Mutex
+	keySet   bool
+}
+
+// Start connects to the native plugin which answered resp and serves it over
+// gRPC on the Unix socket at socketPath until the plugin is killed through the
+// shim or Stop is called.  The socket should be in a directory only snapteld's
+// user may access, as the shim serves without TLS.
+func Start(resp plugin.Response, timeout time.Duration, socketPath string) (*Shim, error) {
+	switch resp.Type {
+	case plugin.CollectorPluginType, plugin.ProcessorPluginType, plugin.PublisherPluginType:
+	default:
+		return nil, fmt.Errorf("native RPC is not supported by plugins of the type: %v", resp.Type)
+	}
+	native, err := newNativeClient(resp.ListenAddress, timeout, resp.Type, resp.PublicKey, !resp.Meta.Unsecure)
+	if err != nil {
+		return nil, err
+	}
+	return serve(native, !resp.Meta.Unsecure, socketPath)
+}
+
+func serve(native *nativeClient, secure bool, socketPath string) (*Shim, error) {
+	// the socket file is removed when the listener is closed
+	lis, err := net.Listen("unix", socketPath)
+	if err != nil {
+		native.Close()
+		return nil, err
+	}
+	s := &Shim{
+		native:   native,
+		secure:   secure,
+		server:   grpc.NewServer(),
+		listener: lis,
+	}
+	base := shimServer{s}
+	switch native.pluginType {
+	case plugin.CollectorPluginType:
+		rpc.RegisterCollectorServer(s.server, collectorServer{base})
+	case plugin.ProcessorPluginType:
+		rpc.RegisterProcessorServer(s.server, processorServer{base})
+	case plugin.PublisherPluginType:
+		rpc.RegisterPublisherServer(s.server, publisherServer{base})
+	}
+	go func() {
+		if err := s.server.Serve(lis); err != nil {
+			shimLogger.WithFields(log.Fields{
+				"_block":  "serve",
+				"address": s.Address(),
+			}).Debug(err)
+		}
+	}()
+	return s, nil
+}
+
+// Address returns the address the shim serves the plugin on, in the form a
+// plugin gives the address of its Unix socket.
+func (s *Shim) Address() string {
+	return plugin.UnixSocketScheme + s.listener.Addr().String()
+}
+
+// Stop stops serving the plugin and closes the connection to it, the plugin
+// itself is left running.
+func (s *Shim) Stop() {
+	s.stop(s.server.Stop)
+}
+
+func (s *Shim) stop(stopServer func()) {
+	s.stopOnce.Do(func() {
+		stopServer()
+		s.native.Close()
+	})
+}
+
+// setKey sends the session key to a secure plugin unless it was already.
+func (s *Shim) setKey() error {
+	if !s.secure {
+		return nil
+	}
+	s.keyMutex.Lock()
+	defer s.keyMutex.Unlock()
+	if s.keySet {
+		return nil
+	}
+	if err := s.native.SetKey(); err != nil {
+		return err
+	}
+	s.keySet = true
+	return nil
+}
+
+// shimServer implements the methods shared by the gRPC services of all the
+// plugin types.
+type shimServer struct {
+	shim *Shim
+}
+
+// Ping fails the call itself when the plugin cannot be reached so that health
+// checks, which only look at the call error, notice a dead plugin.
+func (s shimServer) Ping(ctx context.Context, arg *rpc.Empty) (*rpc.ErrReply, error) {
+	if err := s.shim.native.Ping(); err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return &rpc.ErrReply{}, nil
+}
+
+// Kill kills the plugin and stops the shim once the reply is sent.
+func (s shimServer) Kill(ctx context.Context, arg *rpc.KillArg) (*rpc.ErrReply, error) {
+	if err := s.shim.setKey(); err != nil {
+		return &rpc.ErrReply{Error: err.Error()}, nil
+	}
+	err := s.shim.native.Kill(arg.Reason)
+	go s.shim.stop(s.shim.server.GracefulStop)
+	if err != nil {
+		return &rpc.ErrReply{Error: err.Error()}, nil
+	}
+	return &rpc.ErrReply{}, nil
+}
+
+func (s shimServer) GetConfigPolicy(ctx context.Context, arg *rpc.Empty) (*rpc.GetConfigPolicyReply, error) {
+	if err := s.shim.setKey(); err != nil {
+		return &rpc.GetConfigPolicyReply{Error: err.Error()}, nil
+	}
+	policy, err := s.shim.native.GetConfigPolicy()
+	if err != nil {
+		return &rpc.GetConfigPolicyReply{Error: err.Error()}, nil
+	}
+	return rpc.NewGetConfigPolicyReply(policy)
+}
+
+// metricsReply returns the reply carrying the metrics of the plugin, their
+// values keep their Go types or the reply fails when gRPC cannot carry one.
+func metricsReply(mts []core.Metric) *rpc.MetricsReply {
+	rmts, err := client.ToNativeMetrics(mts)
+	if err != nil {
+		return &rpc.MetricsReply{Error: err.Error()}
+	}
+	return &rpc.MetricsReply{Metrics: rmts}
+}
+
+type collectorServer struct {
+	shimServer
+}
+
+func (s collectorServer) CollectMetrics(ctx context.Context, arg *rpc.MetricsArg) (*rpc.MetricsReply, error) {
+	if err := s.shim.setKey(); err != nil {
+		return &rpc.MetricsReply{Error: err.Error()}, nil
+	}
+	mts, err := s.shim.native.CollectMetrics(ctx, client.ToCoreMetrics(arg.Metrics))
+	if err != nil {
+		return &rpc.MetricsReply{Error: err.Error()}, nil
+	}
+	return metricsReply(mts), nil
+}
+
+// CollectMetricsChunked is not supported, native plugins do not advertise
+// the chunked collect capability.
+func (s collectorServer) CollectMetricsChunked(arg *rpc.CollectChunkedArg, stream rpc.Collector_CollectMetricsChunkedServer) error {
+	return status.Error(codes.Unimplemented, "chunked collection is not supported by native plugins")
+}
+
+func (s collectorServer) GetMetricTypes(ctx context.Context, arg *rpc.GetMetricTypesArg) (*rpc.MetricsReply, error) {
+	if err := s.shim.setKey(); err != nil {
+		return &rpc.MetricsReply{Error: err.Error()}, nil
+	}
+	mts, err := s.shim.native.GetMetricTypes(plugin.ConfigType{ConfigDataNode: toConfig(arg.Config)})
+	if err != nil {
+		return &rpc.MetricsReply{Error: err.Error()}, nil
+	}
+	return metricsReply(mts), nil
+}
+
+type processorServer struct {
+	shimServer
+}
+
+func (s processorServer) Process(ctx context.Context, arg *rpc.PubProcArg) (*rpc.MetricsReply, error) {
+	if err := s.shim.setKey(); err != nil {
+		return &rpc.MetricsReply{Error: err.Error()}, nil
+	}
+	mts, err := s.shim.native.Process(ctx, client.ToCoreMetrics(arg.Metrics), toConfig(arg.Config).Table())
+	if err != nil {
+		return &rpc.MetricsReply{Error: err.Error()}, nil
+	}
+	return metricsReply(mts), nil
+}
+
+type publisherServer struct {
+	shimServer
+}
+
+func (s publisherServer) Publish(ctx context.Context, arg *rpc.PubProcArg) (*rpc.ErrReply, error) {
+	if err := s.shim.setKey(); err != nil {
+		return &rpc.ErrReply{Error: err.Error()}, nil
+	}
+	if err := s.shim.native.Publish(ctx, client.ToCoreMetrics(arg.Metrics), toConfig(arg.Config).Table()); err != nil {
+		return &rpc.ErrReply{Error: err.Error()}, nil
+	}
+	return &rpc.ErrReply{}, nil
+}
+
+// toConfig returns the config of a call, empty when none was sent.
+func toConfig(cfg *rpc.ConfigMap) *cdata.ConfigDataNode {
+	if cfg == nil {
+		return cdata.NewNode()
+	}
+	return client.ConfigMapToConfig(cfg)
+}
@@ -0,0 +1,148 @@
+// +build medium
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nativeshim
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/rpc"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/net/context"
+
+	"github.com/intelsdi-x/snap/control/plugin"
+	"github.com/intelsdi-x/snap/control/plugin/client"
+	"github.com/intelsdi-x/snap/control/plugin/cpolicy"
+	"github.com/intelsdi-x/snap/control/plugin/encoding"
+	"github.com/intelsdi-x/snap/core"
+	"github.com/intelsdi-x/snap/core/ctypes"
+)
+
+// mockNativeRPC answers the calls of the shim the way a native collector
+// would until it is marked dead.
+type mockNativeRPC struct {
+	encoder encoding.Encoder
+	dead    bool
+	closed  bool
+	// value of the collected metrics
+	data interface{}
+}
+
+func (m *mockNativeRPC) Call(method string, args interface{}, reply interface{}) error {
+	if m.dead {
+		return rpc.ErrShutdown
+	}
+	switch method {
+	case "SessionState.Ping":
+		return nil
+	case "SessionState.GetConfigPolicy":
+		policy := cpolicy.New()
+		node := cpolicy.NewPolicyNode()
+		rule, _ := cpolicy.NewStringRule("name", false, "bob")
+		node.Add(rule)
+		policy.Add([]string{"intel", "mock"}, node)
+		out, err := m.encoder.Encode(plugin.GetConfigPolicyReply{Policy: policy})
+		if err != nil {
+			return err
+		}
+		*reply.(*[]byte) = out
+		return nil
+	case "Collector.CollectMetrics":
+		out, err := m.encoder.Encode(plugin.CollectMetricsReply{PluginMetrics: []plugin.MetricType{{
+			Namespace_: core.NewNamespace("intel", "mock", "foo"),
+			Data_:      m.data,
+		}}})
+		if err != nil {
+			return err
+		}
+		*reply.(*[]byte) = out
+		return nil
+	}
+	return errors.New("unexpected call " + method)
+}
+
+func (m *mockNativeRPC) Close() error {
+	m.closed = true
+	return nil
+}
+
+func TestShim(t *testing.T) {
+	Convey("Given a shim serving a native collector", t, func() {
+		dir, err := ioutil.TempDir("", "snap-sockets-")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		native := &mockNativeRPC{encoder: encoding.NewGobEncoder()}
+		shim, err := serve(&nativeClient{
+			connection: native,
+			pluginType: plugin.CollectorPluginType,
+			encoder:    encoding.NewGobEncoder(),
+			timeout:    time.Second,
+		}, false, filepath.Join(dir, "1.sock"))
+		So(err, ShouldBeNil)
+		defer shim.Stop()
+
+		c, err := client.NewCollectorGrpcClient(shim.Address(), time.Second, client.SecurityTLSOff(), client.GRPCChannel{})
+		So(err, ShouldBeNil)
+		defer c.Close()
+
+		Convey("the plugin can be pinged", func() {
+			So(c.Ping(), ShouldBeNil)
+		})
+		Convey("the config policy keeps the defaults of the plugin", func() {
+			policy, err := c.GetConfigPolicy()
+			So(err, ShouldBeNil)
+			node := policy.Get([]string{"intel", "mock"})
+			So(node, ShouldNotBeNil)
+			rules := node.RulesAsTable()
+			So(rules, ShouldHaveLength, 1)
+			So(rules[0].Default, ShouldResemble, ctypes.ConfigValueStr{Value: "bob"})
+		})
+		Convey("the collected values keep their Go types", func() {
+			requested := []core.Metric{plugin.MetricType{Namespace_: core.NewNamespace("intel", "mock", "foo")}}
+			for _, data := range []interface{}{int(42), uint(42), int64(42), "42"} {
+				native.data = data
+				mts, err := c.CollectMetrics(context.Background(), requested)
+				So(err, ShouldBeNil)
+				So(mts, ShouldHaveLength, 1)
+				So(mts[0].Data(), ShouldEqual, data)
+			}
+		})
+		Convey("a collected value gRPC cannot carry fails the collection", func() {
+			native.data = int8(42)
+			_, err := c.CollectMetrics(context.Background(), []core.Metric{plugin.MetricType{Namespace_: core.NewNamespace("intel", "mock", "foo")}})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "unsupported type: int8")
+		})
+		Convey("a dead plugin fails the ping", func() {
+			native.dead = true
+			So(c.Ping(), ShouldNotBeNil)
+		})
+		Convey("stopping the shim closes the connection to the plugin", func() {
+			shim.Stop()
+			So(native.closed, ShouldBeTrue)
+		})
+	})
+}
@@ -27,6 +27,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/intelsdi-x/snap/control/plugin/cpolicy"
+	"github.com/intelsdi-x/snap/core"
 )
 
 // Plugin type
@@ -42,6 +43,7 @@ const (
 	ProcessorPluginType
 	PublisherPluginType
 	StreamCollectorPluginType
+	StreamPublisherPluginType
 )
 
 type RoutingStrategyType int
@@ -61,6 +63,10 @@ const (
 	// Using this strategy enables a running database plugin that has the same connection info between
 	// two tasks to be shared.
 	ConfigRouting
+	// ConsistentHashRouting is routing to plugins using a hash ring over the running instances.
+	// Using this strategy requests with the same key (config, task or tag) are sent to the same
+	// instance while the pool grows and shrinks with minimal re-assignment.
+	ConsistentHashRouting
 )
 
 // Plugin response states
@@ -80,6 +86,64 @@ const (
 	STREAMGRPC RPCType = 3
 )
 
+// ProtocolVersion is the version of the plugin protocol spoken by snapteld,
+// sent to the plugins in Arg.  Plugins reporting version 0 in their meta
+// predate the negotiation and are assumed to support no Capability.
+const ProtocolVersion = 1
+
+// Capability is an optional feature of the plugin protocol
+type Capability string
+
+const (
+	// CapabilityCompression - the plugin accepts compressed calls, see
+	// PluginMeta.Compression
+	CapabilityCompression Capability = "compression"
+	// CapabilityChunkedCollect - the collector serves CollectMetricsChunked
+	CapabilityChunkedCollect Capability = "chunked-collect"
+	// CapabilityHistograms - the plugin decodes histogram, summary and map
+	// metric values
+	CapabilityHistograms Capability = "histograms"
+	// CapabilityCancellation - the plugin gives up on calls once their
+	// context is cancelled
+	CapabilityCancellation Capability = "cancellation"
+	// CapabilityHealthDetail - the plugin reports an unhealthy state in the
+	// error of its reply to Ping
+	CapabilityHealthDetail Capability = "health-detail"
+)
+
+// capabilityVersions holds the version of the plugin protocol which
+// introduced each Capability.
+var capabilityVersions = map[Capability]int{
+	CapabilityCompression:    1,
+	CapabilityChunkedCollect: 1,
+	CapabilityHistograms:     1,
+	CapabilityCancellation:   1,
+	CapabilityHealthDetail:   1,
+}
+
+// NegotiateProtocolVersion returns the version of the plugin protocol spoken
+// with a plugin speaking version v, the older of v and ProtocolVersion.
+func NegotiateProtocolVersion(v int) int {
+	if v > ProtocolVersion {
+		return ProtocolVersion
+	}
+	return v
+}
+
+// HasCapability returns true when the capabilities listed by a plugin
+// include c and c is part of the protocol version v negotiated with it.
+func HasCapability(v int, capabilities []Capability, c Capability) bool {
+	if since, ok := capabilityVersions[c]; !ok || v < since {
+		return false
+	}
+	for _, capability := range capabilities {
+		if capability == c {
+			return true
+		}
+	}
+	return false
+}
+
 var (
 	// Timeout settings
 	// How much time must elapse before a lack of Ping results in a timeout
@@ -92,12 +156,14 @@ var (
 		"processor",
 		"publisher",
 		"streaming-collector",
+		"streaming-publisher",
 	}
 
 	routingStrategyTypes = [...]string{
 		"least-recently-used",
 		"sticky",
 		"config",
+		"consistent-hash",
 	}
 )
 
@@ -134,6 +200,25 @@ type PluginMeta struct {
 	RoutingStrategy RoutingStrategyType
 	// TLSEnabled identifies status of plugin security
 	TLSEnabled bool
+	// Metadata describes the plugin to users, a plugin requiring a newer
+	// snapteld, another platform or unset environment variables is not loaded.
+	Metadata *core.PluginMetadata
+	// Compression lists the compressors, e.g. "gzip", a plugin with the
+	// compression capability accepts on its gRPC channel.  Snap uses the first
+	// of its preferred compressors the plugin accepts.
+	Compression []string
+	// ProtocolVersion is the version of the plugin protocol the plugin speaks
+	ProtocolVersion int
+	// Capabilities lists the optional features of the protocol the plugin
+	// supports
+	Capabilities []Capability
+}
+
+// Supports returns true when the plugin supports the capability c in the
+// protocol version negotiated with it, none is supported by plugins predating
+// the negotiation.
+func (m PluginMeta) Supports(c Capability) bool {
+	return HasCapability(NegotiateProtocolVersion(m.ProtocolVersion), m.Capabilities, c)
 }
 
 // Arg contains arguments passed to startup of Plugin
@@ -154,6 +239,13 @@ type Arg struct {
 	KeyPath     string `json:"KeyPath"`
 	CACertPaths string `json:"RootCertPaths"`
 	TLSEnabled  bool   `json:"TLSEnabled"`
+
+	// ListenSocket is the path of the Unix socket a gRPC plugin may listen
+	// on instead of a TCP port, see Response.ListenAddress.
+	ListenSocket string `json:"ListenSocket,omitempty"`
+
+	// ProtocolVersion is the version of the plugin protocol of snapteld
+	ProtocolVersion int `json:"ProtocolVersion"`
 }
 
 // SetCertPath sets path to TLS certificate in plugin arguments
@@ -180,18 +272,31 @@ func (a Arg) SetCACertPaths(caCertPaths string) Arg {
 	return a
 }
 
+// SetListenSocket sets the path of the Unix socket the plugin may listen on
+func (a Arg) SetListenSocket(path string) Arg {
+	a.ListenSocket = path
+	return a
+}
+
 // NewArg returns new plugin arguments structure
 func NewArg(logLevel int, pprof bool) Arg {
 	return Arg{
 		LogLevel:            log.Level(logLevel),
 		PingTimeoutDuration: PingTimeoutDurationDefault,
 		Pprof:               pprof,
+		ProtocolVersion:     ProtocolVersion,
 	}
 }
 
+// UnixSocketScheme prefixes the path of the Unix socket a plugin listens on in
+// Response.ListenAddress.
+const UnixSocketScheme = "unix://"
+
 // Response from started plugin
 type Response struct {
-	Meta          PluginMeta
+	Meta PluginMeta
+	// ListenAddress is host:port, or UnixSocketScheme followed by
+	// Arg.ListenSocket when the plugin listens on the Unix socket.
 	ListenAddress string
 	PprofAddress  string
 	Token         string
@@ -33,6 +33,8 @@ import (
 	"time"
 
 	log "github.com/sirupsen/logrus"
+
+	"github.com/intelsdi-x/snap/core"
 )
 
 var (
@@ -77,6 +79,13 @@ func CacheTTL(t time.Duration) metaOp {
 	}
 }
 
+// Metadata is an option that can be be provided to the func NewPluginMeta.
+func Metadata(md core.PluginMetadata) metaOp {
+	return func(m *PluginMeta) {
+		m.Metadata = &md
+	}
+}
+
 // NewPluginMeta constructs and returns a PluginMeta struct
 func NewPluginMeta(name string, version int, pluginType PluginType, acceptContentTypes, returnContentTypes []string, opts ...metaOp) *PluginMeta {
 	// An empty accepted content type default to "snap.*"
@@ -63,6 +63,33 @@ func TestArg(t *testing.T) {
 	Convey("NewArg", t, func() {
 		arg := NewArg(int(log.InfoLevel), false)
 		So(arg, ShouldNotBeNil)
+		So(arg.ProtocolVersion, ShouldEqual, ProtocolVersion)
+	})
+}
+
+func TestPluginMetaSupports(t *testing.T) {
+	Convey("Given the meta of a plugin listing its capabilities", t, func() {
+		meta := PluginMeta{
+			ProtocolVersion: 1,
+			Capabilities:    []Capability{CapabilityHistograms},
+		}
+		Convey("it supports the capabilities listed", func() {
+			So(meta.Supports(CapabilityHistograms), ShouldBeTrue)
+			So(meta.Supports(CapabilityChunkedCollect), ShouldBeFalse)
+		})
+		Convey("a plugin predating the negotiation supports none", func() {
+			meta.ProtocolVersion = 0
+			So(meta.Supports(CapabilityHistograms), ShouldBeFalse)
+		})
+		Convey("a capability unknown to snapteld is not supported", func() {
+			meta.Capabilities = append(meta.Capabilities, Capability("teleport"))
+			So(meta.Supports(Capability("teleport")), ShouldBeFalse)
+		})
+		Convey("a plugin speaking a newer protocol is spoken to in the protocol of snapteld", func() {
+			meta.ProtocolVersion = ProtocolVersion + 1
+			So(NegotiateProtocolVersion(meta.ProtocolVersion), ShouldEqual, ProtocolVersion)
+			So(meta.Supports(CapabilityHistograms), ShouldBeTrue)
+		})
 	})
 }
 
@@ -0,0 +1,57 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rpc
+
+import (
+	"golang.org/x/net/context"
+)
+
+// CollectChunked serves a CollectMetricsChunked call with the CollectMetrics
+// of a collector plugin.  The collection is sent in replies of at most the
+// chunk size asked for, or in a single reply when the chunk size is not above
+// 0, and an error of the collection is sent in a reply of its own.  A plugin
+// advertising the chunked-collect capability can implement the call with:
+//
+//	func (c *collector) CollectMetricsChunked(arg *rpc.CollectChunkedArg, stream rpc.Collector_CollectMetricsChunkedServer) error {
+//		return rpc.CollectChunked(arg, stream, c.CollectMetrics)
+//	}
+func CollectChunked(arg *CollectChunkedArg, stream Collector_CollectMetricsChunkedServer, collect func(context.Context, *MetricsArg) (*MetricsReply, error)) error {
+	reply, err := collect(stream.Context(), arg.Metrics_Arg)
+	if err != nil {
+		return err
+	}
+	if reply.Error != "" {
+		return stream.Send(&MetricsReply{Error: reply.Error})
+	}
+	mts := reply.Metrics
+	for {
+		n := len(mts)
+		if arg.ChunkSize > 0 && int64(n) > arg.ChunkSize {
+			n = int(arg.ChunkSize)
+		}
+		if err := stream.Send(&MetricsReply{Metrics: mts[:n]}); err != nil {
+			return err
+		}
+		mts = mts[n:]
+		if len(mts) == 0 {
+			return nil
+		}
+	}
+}
@@ -35,6 +35,7 @@ func NewGetConfigPolicyReply(policy *cpolicy.ConfigPolicy) (*GetConfigPolicyRepl
 				}
 				if rule.Default != nil {
 					r.Default = rule.Default.(ctypes.ConfigValueBool).Value
+					r.HasDefault = true
 				}
 				if ret.BoolPolicy[key] == nil {
 					ret.BoolPolicy[key] = &BoolPolicy{
@@ -49,6 +50,7 @@ func NewGetConfigPolicyReply(policy *cpolicy.ConfigPolicy) (*GetConfigPolicyRepl
 				}
 				if rule.Default != nil {
 					r.Default = rule.Default.(ctypes.ConfigValueStr).Value
+					r.HasDefault = true
 				}
 				if ret.StringPolicy[key] == nil {
 					ret.StringPolicy[key] = &StringPolicy{
@@ -63,12 +65,15 @@ func NewGetConfigPolicyReply(policy *cpolicy.ConfigPolicy) (*GetConfigPolicyRepl
 				}
 				if rule.Default != nil {
 					r.Default = int64(rule.Default.(ctypes.ConfigValueInt).Value)
+					r.HasDefault = true
 				}
 				if rule.Maximum != nil {
 					r.Maximum = int64(rule.Maximum.(ctypes.ConfigValueInt).Value)
+					r.HasMax = true
 				}
 				if rule.Minimum != nil {
 					r.Minimum = int64(rule.Minimum.(ctypes.ConfigValueInt).Value)
+					r.HasMin = true
 				}
 				if ret.IntegerPolicy[key] == nil {
 					ret.IntegerPolicy[key] = &IntegerPolicy{
@@ -83,12 +88,15 @@ func NewGetConfigPolicyReply(policy *cpolicy.ConfigPolicy) (*GetConfigPolicyRepl
 				}
 				if rule.Default != nil {
 					r.Default = rule.Default.(ctypes.ConfigValueFloat).Value
+					r.HasDefault = true
 				}
 				if rule.Maximum != nil {
 					r.Maximum = rule.Maximum.(ctypes.ConfigValueFloat).Value
+					r.HasMax = true
 				}
 				if rule.Minimum != nil {
 					r.Minimum = rule.Minimum.(ctypes.ConfigValueFloat).Value
+					r.HasMin = true
 				}
 				if ret.FloatPolicy[key] == nil {
 					ret.FloatPolicy[key] = &FloatPolicy{
@@ -49,6 +49,14 @@ It has these top-level messages:
 	MetricsArg
 	MetricsReply
 	GetMetricTypesArg
+	Histogram
+	Quantile
+	Summary
+	Float64Map
+	StringMap
+	PublishArg
+	PublishReply
+	CollectChunkedArg
 */
 package rpc
 
@@ -72,6 +80,33 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+// MetricKind tells how the value of a metric changes between collections
+type MetricKind int32
+
+const (
+	MetricKind_UNKNOWN MetricKind = 0
+	// the value only increases, until it is reset
+	MetricKind_COUNTER MetricKind = 1
+	// the value can go up and down
+	MetricKind_GAUGE MetricKind = 2
+)
+
+var MetricKind_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "COUNTER",
+	2: "GAUGE",
+}
+var MetricKind_value = map[string]int32{
+	"UNKNOWN": 0,
+	"COUNTER": 1,
+	"GAUGE":   2,
+}
+
+func (x MetricKind) String() string {
+	return proto.EnumName(MetricKind_name, int32(x))
+}
+func (MetricKind) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
 // Request that can be passed a stream collector
 type CollectArg struct {
 	// Request these metrics to be collected on the plugins schedule
@@ -88,6 +123,9 @@ type CollectArg struct {
 	MaxMetricsBuffer int64 `protobuf:"varint,3,opt,name=MaxMetricsBuffer" json:"MaxMetricsBuffer,omitempty"`
 	// Blob of domain specific info
 	Other []byte `protobuf:"bytes,4,opt,name=Other,proto3" json:"Other,omitempty"`
+	// Token of the last reply received before the stream was reconnected.
+	// The plugin should replay what it sent after that reply.
+	ResumeToken string `protobuf:"bytes,5,opt,name=ResumeToken" json:"ResumeToken,omitempty"`
 }
 
 func (m *CollectArg) Reset()                    { *m = CollectArg{} }
@@ -123,11 +161,23 @@ func (m *CollectArg) GetOther() []byte {
 	return nil
 }
 
+func (m *CollectArg) GetResumeToken() string {
+	if m != nil {
+		return m.ResumeToken
+	}
+	return ""
+}
+
 // Replies that can be sent from a stream collector
 type CollectReply struct {
 	// Reply with metrics
 	Metrics_Reply *MetricsReply `protobuf:"bytes,1,opt,name=Metrics_Reply,json=MetricsReply" json:"Metrics_Reply,omitempty"`
 	Error         *ErrReply     `protobuf:"bytes,2,opt,name=Error" json:"Error,omitempty"`
+	// Increasing number of the reply, replies replayed after a reconnect
+	// which were already received are dropped.  0 when not used.
+	Sequence int64 `protobuf:"varint,3,opt,name=Sequence" json:"Sequence,omitempty"`
+	// Opaque token the plugin resumes the stream from after a reconnect
+	ResumeToken string `protobuf:"bytes,4,opt,name=ResumeToken" json:"ResumeToken,omitempty"`
 }
 
 func (m *CollectReply) Reset()                    { *m = CollectReply{} }
@@ -149,6 +199,20 @@ func (m *CollectReply) GetError() *ErrReply {
 	return nil
 }
 
+func (m *CollectReply) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *CollectReply) GetResumeToken() string {
+	if m != nil {
+		return m.ResumeToken
+	}
+	return ""
+}
+
 type Empty struct {
 }
 
@@ -273,7 +337,14 @@ type Metric struct {
 	//	*Metric_BoolData
 	//	*Metric_Uint32Data
 	//	*Metric_Uint64Data
+	//	*Metric_HistogramData
+	//	*Metric_SummaryData
+	//	*Metric_Float64MapData
+	//	*Metric_StringMapData
+	//	*Metric_IntData
+	//	*Metric_UintData
 	Data isMetric_Data `protobuf_oneof:"data"`
+	Kind MetricKind    `protobuf:"varint,22,opt,name=Kind,enum=rpc.MetricKind" json:"Kind,omitempty"`
 }
 
 func (m *Metric) Reset()                    { *m = Metric{} }
@@ -312,16 +383,40 @@ type Metric_Uint32Data struct {
 type Metric_Uint64Data struct {
 	Uint64Data uint64 `protobuf:"varint,17,opt,name=uint64_data,json=uint64Data,oneof"`
 }
+type Metric_HistogramData struct {
+	HistogramData *Histogram `protobuf:"bytes,18,opt,name=histogram_data,json=histogramData,oneof"`
+}
+type Metric_SummaryData struct {
+	SummaryData *Summary `protobuf:"bytes,19,opt,name=summary_data,json=summaryData,oneof"`
+}
+type Metric_Float64MapData struct {
+	Float64MapData *Float64Map `protobuf:"bytes,20,opt,name=float64_map_data,json=float64MapData,oneof"`
+}
+type Metric_StringMapData struct {
+	StringMapData *StringMap `protobuf:"bytes,21,opt,name=string_map_data,json=stringMapData,oneof"`
+}
+type Metric_IntData struct {
+	IntData int64 `protobuf:"varint,23,opt,name=int_data,json=intData,oneof"`
+}
+type Metric_UintData struct {
+	UintData uint64 `protobuf:"varint,24,opt,name=uint_data,json=uintData,oneof"`
+}
 
-func (*Metric_StringData) isMetric_Data()  {}
-func (*Metric_Float32Data) isMetric_Data() {}
-func (*Metric_Float64Data) isMetric_Data() {}
-func (*Metric_Int32Data) isMetric_Data()   {}
-func (*Metric_Int64Data) isMetric_Data()   {}
-func (*Metric_BytesData) isMetric_Data()   {}
-func (*Metric_BoolData) isMetric_Data()    {}
-func (*Metric_Uint32Data) isMetric_Data()  {}
-func (*Metric_Uint64Data) isMetric_Data()  {}
+func (*Metric_StringData) isMetric_Data()     {}
+func (*Metric_Float32Data) isMetric_Data()    {}
+func (*Metric_Float64Data) isMetric_Data()    {}
+func (*Metric_Int32Data) isMetric_Data()      {}
+func (*Metric_Int64Data) isMetric_Data()      {}
+func (*Metric_BytesData) isMetric_Data()      {}
+func (*Metric_BoolData) isMetric_Data()       {}
+func (*Metric_Uint32Data) isMetric_Data()     {}
+func (*Metric_Uint64Data) isMetric_Data()     {}
+func (*Metric_HistogramData) isMetric_Data()  {}
+func (*Metric_SummaryData) isMetric_Data()    {}
+func (*Metric_Float64MapData) isMetric_Data() {}
+func (*Metric_StringMapData) isMetric_Data()  {}
+func (*Metric_IntData) isMetric_Data()        {}
+func (*Metric_UintData) isMetric_Data()       {}
 
 func (m *Metric) GetData() isMetric_Data {
 	if m != nil {
@@ -449,6 +544,55 @@ func (m *Metric) GetUint64Data() uint64 {
 	return 0
 }
 
+func (m *Metric) GetHistogramData() *Histogram {
+	if x, ok := m.GetData().(*Metric_HistogramData); ok {
+		return x.HistogramData
+	}
+	return nil
+}
+
+func (m *Metric) GetSummaryData() *Summary {
+	if x, ok := m.GetData().(*Metric_SummaryData); ok {
+		return x.SummaryData
+	}
+	return nil
+}
+
+func (m *Metric) GetFloat64MapData() *Float64Map {
+	if x, ok := m.GetData().(*Metric_Float64MapData); ok {
+		return x.Float64MapData
+	}
+	return nil
+}
+
+func (m *Metric) GetStringMapData() *StringMap {
+	if x, ok := m.GetData().(*Metric_StringMapData); ok {
+		return x.StringMapData
+	}
+	return nil
+}
+
+func (m *Metric) GetIntData() int64 {
+	if x, ok := m.GetData().(*Metric_IntData); ok {
+		return x.IntData
+	}
+	return 0
+}
+
+func (m *Metric) GetUintData() uint64 {
+	if x, ok := m.GetData().(*Metric_UintData); ok {
+		return x.UintData
+	}
+	return 0
+}
+
+func (m *Metric) GetKind() MetricKind {
+	if m != nil {
+		return m.Kind
+	}
+	return MetricKind_UNKNOWN
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*Metric) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _Metric_OneofMarshaler, _Metric_OneofUnmarshaler, _Metric_OneofSizer, []interface{}{
@@ -461,6 +605,12 @@ func (*Metric) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error,
 		(*Metric_BoolData)(nil),
 		(*Metric_Uint32Data)(nil),
 		(*Metric_Uint64Data)(nil),
+		(*Metric_HistogramData)(nil),
+		(*Metric_SummaryData)(nil),
+		(*Metric_Float64MapData)(nil),
+		(*Metric_StringMapData)(nil),
+		(*Metric_IntData)(nil),
+		(*Metric_UintData)(nil),
 	}
 }
 
@@ -499,6 +649,32 @@ func _Metric_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
 	case *Metric_Uint64Data:
 		b.EncodeVarint(17<<3 | proto.WireVarint)
 		b.EncodeVarint(uint64(x.Uint64Data))
+	case *Metric_HistogramData:
+		b.EncodeVarint(18<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.HistogramData); err != nil {
+			return err
+		}
+	case *Metric_SummaryData:
+		b.EncodeVarint(19<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.SummaryData); err != nil {
+			return err
+		}
+	case *Metric_Float64MapData:
+		b.EncodeVarint(20<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Float64MapData); err != nil {
+			return err
+		}
+	case *Metric_StringMapData:
+		b.EncodeVarint(21<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.StringMapData); err != nil {
+			return err
+		}
+	case *Metric_IntData:
+		b.EncodeVarint(23<<3 | proto.WireVarint)
+		b.EncodeVarint(uint64(x.IntData))
+	case *Metric_UintData:
+		b.EncodeVarint(24<<3 | proto.WireVarint)
+		b.EncodeVarint(uint64(x.UintData))
 	case nil:
 	default:
 		return fmt.Errorf("Metric.Data has unexpected type %T", x)
@@ -572,6 +748,52 @@ func _Metric_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer)
 		x, err := b.DecodeVarint()
 		m.Data = &Metric_Uint64Data{x}
 		return true, err
+	case 18: // data.histogram_data
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(Histogram)
+		err := b.DecodeMessage(msg)
+		m.Data = &Metric_HistogramData{msg}
+		return true, err
+	case 19: // data.summary_data
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(Summary)
+		err := b.DecodeMessage(msg)
+		m.Data = &Metric_SummaryData{msg}
+		return true, err
+	case 20: // data.float64_map_data
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(Float64Map)
+		err := b.DecodeMessage(msg)
+		m.Data = &Metric_Float64MapData{msg}
+		return true, err
+	case 21: // data.string_map_data
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(StringMap)
+		err := b.DecodeMessage(msg)
+		m.Data = &Metric_StringMapData{msg}
+		return true, err
+	case 23: // data.int_data
+		if wire != proto.WireVarint {
+			return true, proto.ErrInternalBadWireType
+		}
+		x, err := b.DecodeVarint()
+		m.Data = &Metric_IntData{int64(x)}
+		return true, err
+	case 24: // data.uint_data
+		if wire != proto.WireVarint {
+			return true, proto.ErrInternalBadWireType
+		}
+		x, err := b.DecodeVarint()
+		m.Data = &Metric_UintData{x}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -610,6 +832,32 @@ func _Metric_OneofSizer(msg proto.Message) (n int) {
 	case *Metric_Uint64Data:
 		n += proto.SizeVarint(17<<3 | proto.WireVarint)
 		n += proto.SizeVarint(uint64(x.Uint64Data))
+	case *Metric_HistogramData:
+		s := proto.Size(x.HistogramData)
+		n += proto.SizeVarint(18<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Metric_SummaryData:
+		s := proto.Size(x.SummaryData)
+		n += proto.SizeVarint(19<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Metric_Float64MapData:
+		s := proto.Size(x.Float64MapData)
+		n += proto.SizeVarint(20<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Metric_StringMapData:
+		s := proto.Size(x.StringMapData)
+		n += proto.SizeVarint(21<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Metric_IntData:
+		n += proto.SizeVarint(23<<3 | proto.WireVarint)
+		n += proto.SizeVarint(uint64(x.IntData))
+	case *Metric_UintData:
+		n += proto.SizeVarint(24<<3 | proto.WireVarint)
+		n += proto.SizeVarint(uint64(x.UintData))
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -1066,6 +1314,227 @@ func (m *GetMetricTypesArg) GetConfig() *ConfigMap {
 	return nil
 }
 
+// Histogram is the distribution of the observed values into buckets, counts
+// has one more element than bounds for the values above the last bound
+type Histogram struct {
+	Bounds []float64 `protobuf:"fixed64,1,rep,packed,name=bounds" json:"bounds,omitempty"`
+	Counts []uint64  `protobuf:"varint,2,rep,packed,name=counts" json:"counts,omitempty"`
+	Sum    float64   `protobuf:"fixed64,3,opt,name=sum" json:"sum,omitempty"`
+	Count  uint64    `protobuf:"varint,4,opt,name=count" json:"count,omitempty"`
+}
+
+func (m *Histogram) Reset()                    { *m = Histogram{} }
+func (m *Histogram) String() string            { return proto.CompactTextString(m) }
+func (*Histogram) ProtoMessage()               {}
+func (*Histogram) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+func (m *Histogram) GetBounds() []float64 {
+	if m != nil {
+		return m.Bounds
+	}
+	return nil
+}
+
+func (m *Histogram) GetCounts() []uint64 {
+	if m != nil {
+		return m.Counts
+	}
+	return nil
+}
+
+func (m *Histogram) GetSum() float64 {
+	if m != nil {
+		return m.Sum
+	}
+	return 0
+}
+
+func (m *Histogram) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+type Quantile struct {
+	Quantile float64 `protobuf:"fixed64,1,opt,name=quantile" json:"quantile,omitempty"`
+	Value    float64 `protobuf:"fixed64,2,opt,name=value" json:"value,omitempty"`
+}
+
+func (m *Quantile) Reset()                    { *m = Quantile{} }
+func (m *Quantile) String() string            { return proto.CompactTextString(m) }
+func (*Quantile) ProtoMessage()               {}
+func (*Quantile) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+func (m *Quantile) GetQuantile() float64 {
+	if m != nil {
+		return m.Quantile
+	}
+	return 0
+}
+
+func (m *Quantile) GetValue() float64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+// Summary is the distribution of the observed values as quantiles
+type Summary struct {
+	Quantiles []*Quantile `protobuf:"bytes,1,rep,name=quantiles" json:"quantiles,omitempty"`
+	Sum       float64     `protobuf:"fixed64,2,opt,name=sum" json:"sum,omitempty"`
+	Count     uint64      `protobuf:"varint,3,opt,name=count" json:"count,omitempty"`
+}
+
+func (m *Summary) Reset()                    { *m = Summary{} }
+func (m *Summary) String() string            { return proto.CompactTextString(m) }
+func (*Summary) ProtoMessage()               {}
+func (*Summary) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+
+func (m *Summary) GetQuantiles() []*Quantile {
+	if m != nil {
+		return m.Quantiles
+	}
+	return nil
+}
+
+func (m *Summary) GetSum() float64 {
+	if m != nil {
+		return m.Sum
+	}
+	return 0
+}
+
+func (m *Summary) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+type Float64Map struct {
+	Values map[string]float64 `protobuf:"bytes,1,rep,name=values" json:"values,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+}
+
+func (m *Float64Map) Reset()                    { *m = Float64Map{} }
+func (m *Float64Map) String() string            { return proto.CompactTextString(m) }
+func (*Float64Map) ProtoMessage()               {}
+func (*Float64Map) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+
+func (m *Float64Map) GetValues() map[string]float64 {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+type StringMap struct {
+	Values map[string]string `protobuf:"bytes,1,rep,name=values" json:"values,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+}
+
+func (m *StringMap) Reset()                    { *m = StringMap{} }
+func (m *StringMap) String() string            { return proto.CompactTextString(m) }
+func (*StringMap) ProtoMessage()               {}
+func (*StringMap) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+
+func (m *StringMap) GetValues() map[string]string {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+// Batch of metrics sent over the stream of a streaming publisher
+type PublishArg struct {
+	// Sequence number of the batch on the stream, echoed back by the
+	// plugin in the PublishReply acknowledging the batch
+	Sequence int64     `protobuf:"varint,1,opt,name=Sequence" json:"Sequence,omitempty"`
+	Metrics  []*Metric `protobuf:"bytes,2,rep,name=Metrics" json:"Metrics,omitempty"`
+	// Config of the stream, only sent with the first batch and whenever
+	// it changes. Plugins keep using the last config they received.
+	Config *ConfigMap `protobuf:"bytes,3,opt,name=Config" json:"Config,omitempty"`
+}
+
+func (m *PublishArg) Reset()                    { *m = PublishArg{} }
+func (m *PublishArg) String() string            { return proto.CompactTextString(m) }
+func (*PublishArg) ProtoMessage()               {}
+func (*PublishArg) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+
+func (m *PublishArg) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *PublishArg) GetMetrics() []*Metric {
+	if m != nil {
+		return m.Metrics
+	}
+	return nil
+}
+
+func (m *PublishArg) GetConfig() *ConfigMap {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+// Acknowledgement of a batch sent by a streaming publisher
+type PublishReply struct {
+	// Sequence number of the acknowledged batch
+	Sequence int64 `protobuf:"varint,1,opt,name=Sequence" json:"Sequence,omitempty"`
+	// Error publishing the batch, empty on success
+	Error string `protobuf:"bytes,2,opt,name=Error" json:"Error,omitempty"`
+}
+
+func (m *PublishReply) Reset()                    { *m = PublishReply{} }
+func (m *PublishReply) String() string            { return proto.CompactTextString(m) }
+func (*PublishReply) ProtoMessage()               {}
+func (*PublishReply) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+
+func (m *PublishReply) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *PublishReply) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+// Request collecting metrics in chunks
+type CollectChunkedArg struct {
+	Metrics_Arg *MetricsArg `protobuf:"bytes,1,opt,name=Metrics_Arg,json=MetricsArg" json:"Metrics_Arg,omitempty"`
+	// Maximum number of metrics in a chunk
+	ChunkSize int64 `protobuf:"varint,2,opt,name=ChunkSize" json:"ChunkSize,omitempty"`
+}
+
+func (m *CollectChunkedArg) Reset()                    { *m = CollectChunkedArg{} }
+func (m *CollectChunkedArg) String() string            { return proto.CompactTextString(m) }
+func (*CollectChunkedArg) ProtoMessage()               {}
+func (*CollectChunkedArg) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+
+func (m *CollectChunkedArg) GetMetrics_Arg() *MetricsArg {
+	if m != nil {
+		return m.Metrics_Arg
+	}
+	return nil
+}
+
+func (m *CollectChunkedArg) GetChunkSize() int64 {
+	if m != nil {
+		return m.ChunkSize
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*CollectArg)(nil), "rpc.CollectArg")
 	proto.RegisterType((*CollectReply)(nil), "rpc.CollectReply")
@@ -1089,6 +1558,15 @@ func init() {
 	proto.RegisterType((*MetricsArg)(nil), "rpc.MetricsArg")
 	proto.RegisterType((*MetricsReply)(nil), "rpc.MetricsReply")
 	proto.RegisterType((*GetMetricTypesArg)(nil), "rpc.GetMetricTypesArg")
+	proto.RegisterType((*Histogram)(nil), "rpc.Histogram")
+	proto.RegisterType((*Quantile)(nil), "rpc.Quantile")
+	proto.RegisterType((*Summary)(nil), "rpc.Summary")
+	proto.RegisterType((*Float64Map)(nil), "rpc.Float64Map")
+	proto.RegisterType((*StringMap)(nil), "rpc.StringMap")
+	proto.RegisterType((*PublishArg)(nil), "rpc.PublishArg")
+	proto.RegisterType((*PublishReply)(nil), "rpc.PublishReply")
+	proto.RegisterType((*CollectChunkedArg)(nil), "rpc.CollectChunkedArg")
+	proto.RegisterEnum("rpc.MetricKind", MetricKind_name, MetricKind_value)
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1103,6 +1581,9 @@ const _ = grpc.SupportPackageIsVersion4
 
 type CollectorClient interface {
 	CollectMetrics(ctx context.Context, in *MetricsArg, opts ...grpc.CallOption) (*MetricsReply, error)
+	// Collects the metrics in chunks, only called on plugins advertising
+	// chunked collection in their meta
+	CollectMetricsChunked(ctx context.Context, in *CollectChunkedArg, opts ...grpc.CallOption) (Collector_CollectMetricsChunkedClient, error)
 	GetMetricTypes(ctx context.Context, in *GetMetricTypesArg, opts ...grpc.CallOption) (*MetricsReply, error)
 	Ping(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ErrReply, error)
 	Kill(ctx context.Context, in *KillArg, opts ...grpc.CallOption) (*ErrReply, error)
@@ -1126,6 +1607,38 @@ func (c *collectorClient) CollectMetrics(ctx context.Context, in *MetricsArg, op
 	return out, nil
 }
 
+func (c *collectorClient) CollectMetricsChunked(ctx context.Context, in *CollectChunkedArg, opts ...grpc.CallOption) (Collector_CollectMetricsChunkedClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Collector_serviceDesc.Streams[0], c.cc, "/rpc.Collector/CollectMetricsChunked", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &collectorCollectMetricsChunkedClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Collector_CollectMetricsChunkedClient interface {
+	Recv() (*MetricsReply, error)
+	grpc.ClientStream
+}
+
+type collectorCollectMetricsChunkedClient struct {
+	grpc.ClientStream
+}
+
+func (x *collectorCollectMetricsChunkedClient) Recv() (*MetricsReply, error) {
+	m := new(MetricsReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *collectorClient) GetMetricTypes(ctx context.Context, in *GetMetricTypesArg, opts ...grpc.CallOption) (*MetricsReply, error) {
 	out := new(MetricsReply)
 	err := grpc.Invoke(ctx, "/rpc.Collector/GetMetricTypes", in, out, c.cc, opts...)
@@ -1166,6 +1679,9 @@ func (c *collectorClient) GetConfigPolicy(ctx context.Context, in *Empty, opts .
 
 type CollectorServer interface {
 	CollectMetrics(context.Context, *MetricsArg) (*MetricsReply, error)
+	// Collects the metrics in chunks, only called on plugins advertising
+	// chunked collection in their meta
+	CollectMetricsChunked(*CollectChunkedArg, Collector_CollectMetricsChunkedServer) error
 	GetMetricTypes(context.Context, *GetMetricTypesArg) (*MetricsReply, error)
 	Ping(context.Context, *Empty) (*ErrReply, error)
 	Kill(context.Context, *KillArg) (*ErrReply, error)
@@ -1194,6 +1710,27 @@ func _Collector_CollectMetrics_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Collector_CollectMetricsChunked_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CollectChunkedArg)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CollectorServer).CollectMetricsChunked(m, &collectorCollectMetricsChunkedServer{stream})
+}
+
+type Collector_CollectMetricsChunkedServer interface {
+	Send(*MetricsReply) error
+	grpc.ServerStream
+}
+
+type collectorCollectMetricsChunkedServer struct {
+	grpc.ServerStream
+}
+
+func (x *collectorCollectMetricsChunkedServer) Send(m *MetricsReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Collector_GetMetricTypes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetMetricTypesArg)
 	if err := dec(in); err != nil {
@@ -1291,7 +1828,13 @@ var _Collector_serviceDesc = grpc.ServiceDesc{
 			Handler:    _Collector_GetConfigPolicy_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "CollectMetricsChunked",
+			Handler:       _Collector_CollectMetricsChunked_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "github.com/intelsdi-x/snap/control/plugin/rpc/plugin.proto",
 }
 
@@ -1850,105 +2393,328 @@ var _StreamCollector_serviceDesc = grpc.ServiceDesc{
 	Metadata: "github.com/intelsdi-x/snap/control/plugin/rpc/plugin.proto",
 }
 
+// Client API for StreamPublisher service
+
+type StreamPublisherClient interface {
+	StreamPublish(ctx context.Context, opts ...grpc.CallOption) (StreamPublisher_StreamPublishClient, error)
+	Ping(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ErrReply, error)
+	Kill(ctx context.Context, in *KillArg, opts ...grpc.CallOption) (*ErrReply, error)
+	GetConfigPolicy(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GetConfigPolicyReply, error)
+}
+
+type streamPublisherClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewStreamPublisherClient(cc *grpc.ClientConn) StreamPublisherClient {
+	return &streamPublisherClient{cc}
+}
+
+func (c *streamPublisherClient) StreamPublish(ctx context.Context, opts ...grpc.CallOption) (StreamPublisher_StreamPublishClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_StreamPublisher_serviceDesc.Streams[0], c.cc, "/rpc.StreamPublisher/StreamPublish", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &streamPublisherStreamPublishClient{stream}
+	return x, nil
+}
+
+type StreamPublisher_StreamPublishClient interface {
+	Send(*PublishArg) error
+	Recv() (*PublishReply, error)
+	grpc.ClientStream
+}
+
+type streamPublisherStreamPublishClient struct {
+	grpc.ClientStream
+}
+
+func (x *streamPublisherStreamPublishClient) Send(m *PublishArg) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *streamPublisherStreamPublishClient) Recv() (*PublishReply, error) {
+	m := new(PublishReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *streamPublisherClient) Ping(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ErrReply, error) {
+	out := new(ErrReply)
+	err := grpc.Invoke(ctx, "/rpc.StreamPublisher/Ping", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *streamPublisherClient) Kill(ctx context.Context, in *KillArg, opts ...grpc.CallOption) (*ErrReply, error) {
+	out := new(ErrReply)
+	err := grpc.Invoke(ctx, "/rpc.StreamPublisher/Kill", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *streamPublisherClient) GetConfigPolicy(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GetConfigPolicyReply, error) {
+	out := new(GetConfigPolicyReply)
+	err := grpc.Invoke(ctx, "/rpc.StreamPublisher/GetConfigPolicy", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for StreamPublisher service
+
+type StreamPublisherServer interface {
+	StreamPublish(StreamPublisher_StreamPublishServer) error
+	Ping(context.Context, *Empty) (*ErrReply, error)
+	Kill(context.Context, *KillArg) (*ErrReply, error)
+	GetConfigPolicy(context.Context, *Empty) (*GetConfigPolicyReply, error)
+}
+
+func RegisterStreamPublisherServer(s *grpc.Server, srv StreamPublisherServer) {
+	s.RegisterService(&_StreamPublisher_serviceDesc, srv)
+}
+
+func _StreamPublisher_StreamPublish_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(StreamPublisherServer).StreamPublish(&streamPublisherStreamPublishServer{stream})
+}
+
+type StreamPublisher_StreamPublishServer interface {
+	Send(*PublishReply) error
+	Recv() (*PublishArg, error)
+	grpc.ServerStream
+}
+
+type streamPublisherStreamPublishServer struct {
+	grpc.ServerStream
+}
+
+func (x *streamPublisherStreamPublishServer) Send(m *PublishReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *streamPublisherStreamPublishServer) Recv() (*PublishArg, error) {
+	m := new(PublishArg)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _StreamPublisher_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StreamPublisherServer).Ping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.StreamPublisher/Ping",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StreamPublisherServer).Ping(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StreamPublisher_Kill_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KillArg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StreamPublisherServer).Kill(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.StreamPublisher/Kill",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StreamPublisherServer).Kill(ctx, req.(*KillArg))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StreamPublisher_GetConfigPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StreamPublisherServer).GetConfigPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.StreamPublisher/GetConfigPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StreamPublisherServer).GetConfigPolicy(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _StreamPublisher_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "rpc.StreamPublisher",
+	HandlerType: (*StreamPublisherServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Ping",
+			Handler:    _StreamPublisher_Ping_Handler,
+		},
+		{
+			MethodName: "Kill",
+			Handler:    _StreamPublisher_Kill_Handler,
+		},
+		{
+			MethodName: "GetConfigPolicy",
+			Handler:    _StreamPublisher_GetConfigPolicy_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamPublish",
+			Handler:       _StreamPublisher_StreamPublish_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "github.com/intelsdi-x/snap/control/plugin/rpc/plugin.proto",
+}
+
 func init() {
 	proto.RegisterFile("github.com/intelsdi-x/snap/control/plugin/rpc/plugin.proto", fileDescriptor0)
 }
 
 var fileDescriptor0 = []byte{
-	// 1505 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xdc, 0x58, 0xcb, 0x6e, 0xdb, 0x46,
-	0x17, 0x16, 0x4d, 0xdd, 0x78, 0x28, 0xf9, 0x32, 0xc8, 0x9f, 0x5f, 0x55, 0x12, 0x44, 0xa1, 0xeb,
-	0x44, 0xb9, 0x54, 0x4e, 0xe5, 0xd4, 0x4d, 0x9c, 0x76, 0xe1, 0xc4, 0x6e, 0x9c, 0xa4, 0x4a, 0x0d,
-	0xc6, 0xcd, 0xa6, 0x40, 0x83, 0x11, 0x3d, 0x96, 0x89, 0x50, 0x24, 0x3b, 0x24, 0x03, 0xeb, 0x15,
-	0xba, 0xed, 0xaa, 0x40, 0x81, 0x02, 0x7d, 0x82, 0xae, 0xbb, 0xea, 0xa2, 0x8b, 0xa2, 0x2f, 0xd1,
-	0x57, 0x29, 0xe6, 0x42, 0x71, 0xa8, 0x4b, 0x6c, 0x2f, 0x0a, 0x04, 0xdd, 0x71, 0xce, 0xf9, 0xce,
-	0xa7, 0x73, 0xbe, 0x73, 0x66, 0xc8, 0x11, 0x6c, 0x0d, 0xdc, 0xf8, 0x38, 0xe9, 0x77, 0x9c, 0x60,
-	0xb8, 0xee, 0xfa, 0x31, 0xf1, 0xa2, 0x43, 0xf7, 0xa3, 0x93, 0xf5, 0xc8, 0xc7, 0xe1, 0xba, 0x13,
-	0xf8, 0x31, 0x0d, 0xbc, 0xf5, 0xd0, 0x4b, 0x06, 0xae, 0xbf, 0x4e, 0x43, 0x47, 0x3e, 0x76, 0x42,
-	0x1a, 0xc4, 0x01, 0xd2, 0x69, 0xe8, 0x58, 0xbf, 0x6a, 0x00, 0x8f, 0x03, 0xcf, 0x23, 0x4e, 0xbc,
-	0x4d, 0x07, 0xe8, 0x2e, 0x98, 0x3d, 0x12, 0x53, 0xd7, 0x89, 0x5e, 0x6f, 0xd3, 0x41, 0x43, 0x6b,
-	0x69, 0x6d, 0xb3, 0xbb, 0xd4, 0xa1, 0xa1, 0xd3, 0x91, 0xf6, 0x6d, 0x3a, 0xb0, 0x21, 0x7b, 0x46,
-	0x1d, 0x40, 0x3d, 0x7c, 0x22, 0x29, 0x76, 0x12, 0x8a, 0x63, 0x37, 0xf0, 0x1b, 0x0b, 0x2d, 0xad,
-	0xad, 0xdb, 0x33, 0x3c, 0xe8, 0x16, 0x2c, 0xf7, 0xf0, 0x89, 0x24, 0x78, 0x94, 0x1c, 0x1d, 0x11,
-	0xda, 0xd0, 0x39, 0x7a, 0xca, 0x8e, 0x2e, 0x40, 0xe9, 0xab, 0xf8, 0x98, 0xd0, 0x46, 0xb1, 0xa5,
-	0xb5, 0x6b, 0xb6, 0x58, 0x58, 0x6f, 0xa0, 0x26, 0x49, 0x6d, 0x12, 0x7a, 0x23, 0xb4, 0x09, 0xf5,
-	0x34, 0x67, 0x6e, 0x90, 0x59, 0xaf, 0xa8, 0x59, 0x73, 0x87, 0x5d, 0x53, 0x57, 0x68, 0x15, 0x4a,
-	0xbb, 0x94, 0x06, 0x94, 0x27, 0x6b, 0x76, 0xeb, 0x1c, 0xbf, 0x4b, 0xa9, 0xc0, 0x0a, 0x9f, 0x55,
-	0x81, 0xd2, 0xee, 0x30, 0x8c, 0x47, 0x56, 0x0b, 0xaa, 0xa9, 0x8f, 0xe5, 0x45, 0x78, 0x24, 0xfb,
-	0x25, 0xc3, 0x16, 0x0b, 0xeb, 0x0e, 0x14, 0x0f, 0xdc, 0x21, 0x41, 0xcb, 0xa0, 0x47, 0xc4, 0xe1,
-	0x3e, 0xdd, 0x66, 0x8f, 0x08, 0x41, 0xd1, 0x67, 0x26, 0xa1, 0x0a, 0x7f, 0xb6, 0xbe, 0x85, 0xe5,
-	0x17, 0x78, 0x48, 0xa2, 0x10, 0x3b, 0x64, 0xd7, 0x23, 0x43, 0xe2, 0xc7, 0x8c, 0xf7, 0x15, 0xf6,
-	0x12, 0x92, 0xf2, 0xf2, 0x05, 0x6a, 0x81, 0xb9, 0x43, 0x22, 0x87, 0xba, 0xe1, 0x58, 0x5a, 0xc3,
-	0x56, 0x4d, 0x8c, 0x9f, 0x71, 0x71, 0x1d, 0x0d, 0x9b, 0x3f, 0x5b, 0xdf, 0x00, 0xec, 0x27, 0xfd,
-	0x7d, 0x1a, 0x38, 0xac, 0x4b, 0x6b, 0x50, 0x91, 0xb5, 0x37, 0xb4, 0x96, 0xde, 0x36, 0xbb, 0xa6,
-	0xa2, 0x8e, 0x9d, 0xfa, 0xd0, 0x75, 0x28, 0x3f, 0x0e, 0xfc, 0x23, 0x77, 0x20, 0x35, 0x59, 0xe4,
-	0x28, 0x61, 0xea, 0xe1, 0xd0, 0x96, 0x5e, 0xeb, 0xb7, 0x12, 0x94, 0x45, 0x0c, 0xda, 0x00, 0x63,
-	0x5c, 0x87, 0xe4, 0xfe, 0x1f, 0x8f, 0x9a, 0xac, 0xce, 0xce, 0x70, 0xa8, 0x01, 0x95, 0x57, 0x84,
-	0x46, 0xd9, 0xa4, 0xa4, 0x4b, 0x25, 0x03, 0xfd, 0x5d, 0x19, 0xa0, 0x07, 0x80, 0xbe, 0xc4, 0x51,
-	0xbc, 0x7d, 0xf8, 0x96, 0xd0, 0xd8, 0x8d, 0xc8, 0x21, 0x93, 0x9e, 0xcf, 0x89, 0xd9, 0x35, 0x78,
-	0x0c, 0x33, 0xd8, 0x33, 0x40, 0xe8, 0x26, 0x14, 0x0f, 0xf0, 0x20, 0x6a, 0x94, 0x94, 0x64, 0x45,
-	0x31, 0x1d, 0x66, 0xdf, 0xf5, 0x63, 0x3a, 0xb2, 0x39, 0x04, 0xdd, 0x00, 0x83, 0x85, 0x44, 0x31,
-	0x1e, 0x86, 0x8d, 0xf2, 0x24, 0x79, 0xe6, 0x63, 0x1d, 0xf8, 0xda, 0x77, 0xe3, 0x46, 0x45, 0x74,
-	0x80, 0x3d, 0x4f, 0xf6, 0xad, 0x3a, 0xdd, 0xb7, 0x6b, 0x60, 0x46, 0x31, 0x75, 0xfd, 0xc1, 0xeb,
-	0x43, 0x1c, 0xe3, 0x86, 0xc1, 0x10, 0x7b, 0x05, 0x1b, 0x84, 0x71, 0x07, 0xc7, 0x18, 0xad, 0x42,
-	0xed, 0xc8, 0x0b, 0x70, 0xbc, 0xd1, 0x15, 0x18, 0x68, 0x69, 0xed, 0x85, 0xbd, 0x82, 0x6d, 0x4a,
-	0x6b, 0x0e, 0xb4, 0x79, 0x4f, 0x80, 0xcc, 0x96, 0xd6, 0xd6, 0xc6, 0xa0, 0xcd, 0x7b, 0x1c, 0x74,
-	0x15, 0xc0, 0xf5, 0xc7, 0x3c, 0xb5, 0x96, 0xd6, 0x2e, 0xed, 0x15, 0x6c, 0x83, 0xdb, 0x14, 0x40,
-	0xca, 0x51, 0x67, 0x7d, 0x91, 0x80, 0x8c, 0xa1, 0x3f, 0x8a, 0x49, 0x24, 0x00, 0x8b, 0x6c, 0x4f,
-	0x32, 0x00, 0xb7, 0x71, 0xc0, 0x15, 0x30, 0xfa, 0x41, 0xe0, 0x09, 0xff, 0x52, 0x4b, 0x6b, 0x57,
-	0xf7, 0x0a, 0x76, 0x95, 0x99, 0xb8, 0xfb, 0x1a, 0x98, 0x89, 0x92, 0xc2, 0x72, 0x4b, 0x6b, 0xd7,
-	0x59, 0xb9, 0x49, 0x96, 0x83, 0x84, 0xa4, 0x49, 0xac, 0xb4, 0xb4, 0x76, 0x31, 0x85, 0x88, 0x2c,
-	0x9a, 0x9f, 0x82, 0x31, 0x6e, 0x13, 0xdb, 0x6b, 0x6f, 0xc8, 0x48, 0xee, 0x17, 0xf6, 0xc8, 0xf6,
-	0xd0, 0x5b, 0xbe, 0x87, 0xc4, 0x3e, 0x11, 0x8b, 0xad, 0x85, 0xfb, 0xda, 0xa3, 0x32, 0x14, 0x19,
-	0xa9, 0xf5, 0xb7, 0x0e, 0xc6, 0x78, 0xa0, 0x50, 0x17, 0xca, 0x4f, 0xfd, 0xb8, 0x87, 0x43, 0x39,
-	0xbc, 0xcd, 0xfc, 0xc0, 0x75, 0x84, 0x53, 0x0c, 0x85, 0x44, 0xa2, 0x87, 0x60, 0xbc, 0xe4, 0x2d,
-	0x62, 0x61, 0x0b, 0x3c, 0xec, 0xca, 0x44, 0xd8, 0xd8, 0x2f, 0x22, 0x33, 0x3c, 0xba, 0x0f, 0xd5,
-	0x2f, 0x58, 0x5b, 0x58, 0xac, 0xce, 0x63, 0x2f, 0x4f, 0xc4, 0xa6, 0x6e, 0x11, 0x3a, 0x46, 0xa3,
-	0x4f, 0xa0, 0xf2, 0x28, 0x08, 0x3c, 0x16, 0x58, 0xe4, 0x81, 0x97, 0x26, 0x02, 0xa5, 0x57, 0xc4,
-	0xa5, 0xd8, 0xe6, 0x03, 0x30, 0x95, 0x22, 0x4e, 0x93, 0x4c, 0x57, 0x24, 0x6b, 0x7e, 0x06, 0x8b,
-	0xf9, 0x42, 0xce, 0x23, 0x78, 0xf3, 0x21, 0xd4, 0x73, 0xa5, 0x9c, 0x16, 0xac, 0xa9, 0xc1, 0x5b,
-	0x50, 0x53, 0xcb, 0x39, 0x2d, 0xb6, 0xaa, 0xc4, 0x5a, 0xd7, 0xa0, 0xf2, 0xdc, 0xf5, 0x3c, 0x76,
-	0xf0, 0x5d, 0x84, 0xb2, 0x4d, 0x70, 0x14, 0xf8, 0x32, 0x52, 0xae, 0xd8, 0x09, 0x76, 0xe1, 0x09,
-	0x89, 0x85, 0x76, 0xfb, 0x81, 0xe7, 0x3a, 0xa3, 0x77, 0x9c, 0xed, 0xe8, 0x19, 0x98, 0x7c, 0xb2,
-	0x43, 0x8e, 0x94, 0x3d, 0xbf, 0xc9, 0xe5, 0x9f, 0xc5, 0xc2, 0x3b, 0x21, 0xd6, 0xa2, 0x19, 0xd0,
-	0x1f, 0x1b, 0x50, 0x4f, 0xee, 0xd6, 0x94, 0x4c, 0x0c, 0xc1, 0xad, 0xf9, 0x64, 0x5c, 0x44, 0x95,
-	0x4d, 0xec, 0x6b, 0x49, 0xf7, 0x12, 0x16, 0xd9, 0x9b, 0x7f, 0x40, 0x68, 0x4a, 0x28, 0x86, 0xe3,
-	0xce, 0x7c, 0xc2, 0xa7, 0x02, 0xaf, 0x52, 0xd6, 0x5d, 0xd5, 0x86, 0xf6, 0xa1, 0x2e, 0x4f, 0x26,
-	0xc9, 0x29, 0x0e, 0xcb, 0xdb, 0xf3, 0x39, 0xc5, 0x9c, 0xa8, 0x94, 0xb5, 0x48, 0x31, 0x35, 0x5f,
-	0xc0, 0xd2, 0x84, 0x28, 0x33, 0x5a, 0xba, 0xa6, 0xb6, 0x34, 0xfd, 0xf0, 0xc8, 0xc2, 0xd4, 0xf9,
-	0xd8, 0x87, 0xe5, 0x49, 0x5d, 0x66, 0x10, 0x5e, 0xcf, 0x13, 0x2e, 0x73, 0x42, 0x25, 0x4e, 0x65,
-	0x3c, 0x00, 0x34, 0x2d, 0xcc, 0x0c, 0xce, 0x76, 0x9e, 0x13, 0x71, 0xce, 0x5c, 0xa4, 0xca, 0x6a,
-	0xc3, 0xca, 0x94, 0x34, 0x33, 0x48, 0x6f, 0xe4, 0x49, 0xc5, 0xc7, 0x8b, 0x1a, 0xa8, 0xce, 0x37,
-	0x86, 0x2a, 0x13, 0xc5, 0x4e, 0x3c, 0x82, 0x9a, 0x50, 0xa5, 0xe4, 0xbb, 0xc4, 0xa5, 0xe4, 0x90,
-	0xf3, 0x55, 0xed, 0xf1, 0x9a, 0xbd, 0x66, 0x0f, 0xc9, 0x11, 0x4e, 0xbc, 0x58, 0xee, 0x91, 0x74,
-	0x89, 0xae, 0x82, 0x79, 0x8c, 0xa3, 0xd7, 0xa9, 0x57, 0xe7, 0x5e, 0x38, 0xc6, 0xd1, 0x8e, 0xb0,
-	0x58, 0x3f, 0x6a, 0x00, 0x99, 0xf0, 0xe8, 0x2e, 0x94, 0x68, 0xe2, 0x91, 0x28, 0x77, 0x48, 0x66,
-	0xfe, 0x0e, 0x4b, 0x45, 0xbe, 0x39, 0x05, 0x30, 0x2d, 0x91, 0xed, 0x14, 0x51, 0x62, 0xf3, 0x09,
-	0x40, 0x06, 0x9b, 0x21, 0xc1, 0x6a, 0x5e, 0x82, 0xfa, 0xf8, 0x37, 0x58, 0x94, 0x5a, 0xfe, 0x9f,
-	0x1a, 0x18, 0xbc, 0x87, 0x67, 0x11, 0x60, 0xe8, 0xfa, 0xee, 0x30, 0x19, 0xca, 0x03, 0x26, 0x5d,
-	0x72, 0x0f, 0x3e, 0xe1, 0x1e, 0x5d, 0x7a, 0xc4, 0x52, 0x15, 0xad, 0x28, 0x3c, 0x73, 0x44, 0x2b,
-	0x4d, 0x8a, 0x86, 0xfe, 0x0f, 0x15, 0x06, 0x18, 0xba, 0x3e, 0xff, 0x58, 0xa8, 0xda, 0xe5, 0x63,
-	0x1c, 0xf5, 0x5c, 0x7f, 0xec, 0xc0, 0x27, 0xfc, 0x0b, 0x41, 0x3a, 0xf0, 0x89, 0xf5, 0x93, 0x06,
-	0xa6, 0x32, 0x8e, 0xe8, 0xe3, 0xbc, 0xce, 0x97, 0x26, 0xe7, 0xf5, 0x4c, 0x42, 0xef, 0x9d, 0x22,
-	0xf4, 0x87, 0x79, 0xa1, 0x17, 0xb3, 0x1f, 0x99, 0x54, 0xfa, 0x2f, 0x8d, 0xbf, 0x3b, 0xd8, 0x64,
-	0x9f, 0x57, 0x6b, 0x7d, 0xae, 0xd6, 0xfa, 0x5c, 0xad, 0xf5, 0x7f, 0x55, 0xeb, 0x5f, 0x34, 0xa8,
-	0xe7, 0xb6, 0x29, 0xda, 0xc8, 0xab, 0x7d, 0x65, 0x7a, 0x27, 0x9f, 0x49, 0xef, 0x67, 0xa7, 0xe8,
-	0x3d, 0xf3, 0x10, 0x52, 0x64, 0x55, 0x15, 0x77, 0x00, 0xc4, 0xae, 0x3f, 0xef, 0xe6, 0x36, 0xce,
-	0xb1, 0xb9, 0x7f, 0xd6, 0xa0, 0xa6, 0x9e, 0x2d, 0xa8, 0x9b, 0x17, 0xe2, 0xf2, 0xd4, 0xe9, 0x73,
-	0x26, 0x1d, 0x9e, 0x9e, 0xa2, 0xc3, 0xcc, 0xd3, 0x3d, 0xab, 0x56, 0x95, 0x61, 0x03, 0xd4, 0x3b,
-	0xe6, 0x1a, 0x54, 0x86, 0xef, 0xb8, 0xbd, 0x48, 0x9f, 0xf5, 0x1c, 0xf2, 0x17, 0xbc, 0xb3, 0x85,
-	0x65, 0x6f, 0xfc, 0x05, 0xf5, 0x36, 0xf7, 0x10, 0x56, 0x9e, 0x90, 0x58, 0x60, 0x0f, 0x46, 0x21,
-	0xe1, 0x89, 0x5c, 0x87, 0xb2, 0x23, 0x6e, 0x27, 0xda, 0xec, 0xdb, 0x89, 0xf0, 0x76, 0xbf, 0x5f,
-	0x60, 0x9f, 0x98, 0xfc, 0x8e, 0x1a, 0x50, 0xb4, 0x09, 0x8b, 0x72, 0x91, 0xde, 0xb3, 0x26, 0x6f,
-	0xd4, 0xcd, 0xe9, 0xcb, 0xaa, 0x55, 0x40, 0x9f, 0xc3, 0x62, 0x3e, 0x05, 0x74, 0x31, 0x7d, 0xff,
-	0xe6, 0xf3, 0x9a, 0x1d, 0xbe, 0x0a, 0xc5, 0x7d, 0xd7, 0x1f, 0x20, 0x10, 0x17, 0x5b, 0x76, 0x8b,
-	0x6d, 0xe6, 0x2f, 0xb9, 0x56, 0x01, 0xad, 0x41, 0x91, 0x7d, 0x2a, 0xa1, 0x1a, 0x77, 0xc8, 0xaf,
-	0xa6, 0x69, 0xd8, 0x16, 0x2c, 0x4d, 0xbc, 0xf5, 0x73, 0xb4, 0x1f, 0xcc, 0xfd, 0x2e, 0xb0, 0x0a,
-	0xdd, 0x3f, 0x34, 0x30, 0xd8, 0x3d, 0x94, 0x44, 0x51, 0x40, 0xd1, 0x3a, 0x54, 0xe4, 0x42, 0xaa,
-	0x90, 0xdd, 0x52, 0xdf, 0xef, 0x32, 0x7e, 0x67, 0x65, 0x24, 0x7d, 0xcf, 0x8d, 0x8e, 0x09, 0x45,
-	0xb7, 0xa1, 0x22, 0x17, 0xd3, 0x65, 0x4c, 0xfd, 0xec, 0xfb, 0x52, 0xc2, 0x0f, 0x0b, 0xb0, 0xf4,
-	0x32, 0xa6, 0x04, 0x0f, 0xb3, 0xe1, 0x7c, 0x00, 0x75, 0x61, 0xca, 0xcf, 0x66, 0xf6, 0x9f, 0x90,
-	0xec, 0x8a, 0xfa, 0x97, 0x8b, 0x55, 0x68, 0x6b, 0x77, 0xb5, 0xff, 0xc8, 0x7c, 0xf6, 0xcb, 0xfc,
-	0xef, 0xb0, 0x8d, 0x7f, 0x02, 0x00, 0x00, 0xff, 0xff, 0x65, 0x21, 0x51, 0x00, 0x4c, 0x13, 0x00,
-	0x00,
+	// 1950 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xdc, 0x59, 0x5b, 0x6f, 0x1b, 0xc7,
+	0x15, 0xe6, 0xf0, 0xbe, 0x67, 0x49, 0x8a, 0x9a, 0x3a, 0x0e, 0x4b, 0xdb, 0x08, 0xbd, 0xaa, 0x1d,
+	0xc6, 0x4e, 0x29, 0x9b, 0x4a, 0x1d, 0x5f, 0x52, 0xa0, 0xb2, 0xad, 0x48, 0x8e, 0x2b, 0x59, 0x5d,
+	0xc9, 0xe9, 0x43, 0x81, 0x18, 0xab, 0xe5, 0x88, 0x5a, 0x78, 0x6f, 0xde, 0x4b, 0x2a, 0x05, 0xfd,
+	0x17, 0x7d, 0x2a, 0x50, 0xa0, 0x40, 0xdf, 0xfa, 0x17, 0xfa, 0xd4, 0x87, 0x3e, 0x14, 0x7d, 0x0c,
+	0xfa, 0xde, 0x7f, 0x52, 0x14, 0x73, 0xdb, 0x9d, 0x25, 0xa9, 0x5b, 0xd1, 0x02, 0x46, 0xdf, 0xf6,
+	0xdc, 0xbe, 0x39, 0xe7, 0x9b, 0x99, 0xc3, 0x99, 0x21, 0x3c, 0x9e, 0x3a, 0xc9, 0x51, 0x7a, 0x30,
+	0xb2, 0x03, 0x6f, 0xd5, 0xf1, 0x13, 0xe2, 0xc6, 0x13, 0xe7, 0xc7, 0xc7, 0xab, 0xb1, 0x6f, 0x85,
+	0xab, 0x76, 0xe0, 0x27, 0x51, 0xe0, 0xae, 0x86, 0x6e, 0x3a, 0x75, 0xfc, 0xd5, 0x28, 0xb4, 0xc5,
+	0xe7, 0x28, 0x8c, 0x82, 0x24, 0xc0, 0x95, 0x28, 0xb4, 0x8d, 0xef, 0x11, 0xc0, 0xb3, 0xc0, 0x75,
+	0x89, 0x9d, 0xac, 0x47, 0x53, 0x7c, 0x0f, 0xf4, 0x6d, 0x92, 0x44, 0x8e, 0x1d, 0xbf, 0x59, 0x8f,
+	0xa6, 0x3d, 0x34, 0x40, 0x43, 0x7d, 0xbc, 0x34, 0x8a, 0x42, 0x7b, 0x24, 0xf4, 0xeb, 0xd1, 0xd4,
+	0x84, 0xfc, 0x1b, 0x8f, 0x00, 0x6f, 0x5b, 0xc7, 0x02, 0xe2, 0x79, 0x1a, 0x59, 0x89, 0x13, 0xf8,
+	0xbd, 0xf2, 0x00, 0x0d, 0x2b, 0xe6, 0x02, 0x0b, 0xbe, 0x03, 0xdd, 0x6d, 0xeb, 0x58, 0x00, 0x3c,
+	0x4d, 0x0f, 0x0f, 0x49, 0xd4, 0xab, 0x30, 0xef, 0x39, 0x3d, 0xbe, 0x02, 0xb5, 0x57, 0xc9, 0x11,
+	0x89, 0x7a, 0xd5, 0x01, 0x1a, 0xb6, 0x4c, 0x2e, 0xe0, 0x01, 0xe8, 0x26, 0x89, 0x53, 0x8f, 0xec,
+	0x07, 0x6f, 0x89, 0xdf, 0xab, 0x0d, 0xd0, 0x50, 0x33, 0x55, 0x95, 0xf1, 0x27, 0x04, 0x2d, 0x31,
+	0xae, 0x49, 0x42, 0xf7, 0x04, 0x3f, 0x80, 0xb6, 0x2c, 0x8b, 0x29, 0x44, 0x61, 0xcb, 0x6a, 0x61,
+	0xcc, 0x60, 0xb6, 0x54, 0x09, 0xaf, 0x40, 0x6d, 0x23, 0x8a, 0x82, 0x88, 0xd5, 0xa3, 0x8f, 0xdb,
+	0xcc, 0x7f, 0x23, 0x8a, 0xb8, 0x2f, 0xb7, 0xe1, 0x3e, 0x34, 0xf7, 0xc8, 0xbb, 0x94, 0xf8, 0x36,
+	0x11, 0x95, 0x64, 0xf2, 0x6c, 0xae, 0xd5, 0xf9, 0x5c, 0x1b, 0x50, 0xdb, 0xf0, 0xc2, 0xe4, 0xc4,
+	0x18, 0x40, 0x53, 0x22, 0xd3, 0xc2, 0x09, 0x1b, 0x17, 0xb1, 0x00, 0x2e, 0x18, 0x9f, 0x42, 0x75,
+	0xdf, 0xf1, 0x08, 0xee, 0x42, 0x25, 0x26, 0x36, 0xb3, 0x55, 0x4c, 0xfa, 0x89, 0x31, 0x54, 0x7d,
+	0xaa, 0xe2, 0xb4, 0xb3, 0x6f, 0xe3, 0x1b, 0xe8, 0xee, 0x58, 0x1e, 0x89, 0x43, 0xcb, 0x26, 0x1b,
+	0x2e, 0xf1, 0x88, 0x9f, 0x50, 0xdc, 0xaf, 0x2d, 0x37, 0x25, 0x12, 0x97, 0x09, 0x34, 0xc9, 0xe7,
+	0x24, 0xb6, 0x23, 0x27, 0xcc, 0xe6, 0x4e, 0x33, 0x55, 0x15, 0xc5, 0xa7, 0x58, 0xac, 0x3c, 0xcd,
+	0x64, 0xdf, 0xc6, 0xaf, 0x00, 0x76, 0xd3, 0x83, 0xdd, 0x28, 0xb0, 0xe9, 0x32, 0xb8, 0x05, 0x0d,
+	0xc1, 0x5c, 0x0f, 0x0d, 0x2a, 0x43, 0x7d, 0xac, 0x2b, 0xdc, 0x9a, 0xd2, 0x86, 0x6f, 0x43, 0xfd,
+	0x59, 0xe0, 0x1f, 0x3a, 0x53, 0xc1, 0x68, 0x87, 0x79, 0x71, 0xd5, 0xb6, 0x15, 0x9a, 0xc2, 0x6a,
+	0xfc, 0xab, 0x01, 0x75, 0x1e, 0x83, 0xd7, 0x40, 0xcb, 0xea, 0x10, 0xd8, 0x1f, 0xb0, 0xa8, 0xd9,
+	0xea, 0xcc, 0xdc, 0x0f, 0xf7, 0xa0, 0xf1, 0x35, 0x89, 0xe2, 0x7c, 0x29, 0x4a, 0x51, 0xc9, 0xa0,
+	0x72, 0x56, 0x06, 0xf8, 0x11, 0xe0, 0x9f, 0x5b, 0x71, 0xb2, 0x3e, 0xf9, 0x96, 0x44, 0x89, 0x13,
+	0x93, 0x09, 0xa5, 0x9e, 0x4d, 0xa0, 0x3e, 0xd6, 0x58, 0x0c, 0x55, 0x98, 0x0b, 0x9c, 0xf0, 0x27,
+	0x50, 0xdd, 0xb7, 0xa6, 0x71, 0xaf, 0xa6, 0x24, 0xcb, 0x8b, 0x19, 0x51, 0xfd, 0x86, 0x9f, 0x44,
+	0x27, 0x26, 0x73, 0xc1, 0x1f, 0x83, 0x46, 0x43, 0xe2, 0xc4, 0xf2, 0xc2, 0x5e, 0x7d, 0x16, 0x3c,
+	0xb7, 0xd1, 0x19, 0x78, 0xed, 0x3b, 0x49, 0xaf, 0xc1, 0x67, 0x80, 0x7e, 0xcf, 0xce, 0x5b, 0x73,
+	0x7e, 0xde, 0x6e, 0x82, 0x1e, 0x27, 0x91, 0xe3, 0x4f, 0xdf, 0x4c, 0xac, 0xc4, 0xea, 0x69, 0xd4,
+	0x63, 0xab, 0x64, 0x02, 0x57, 0x3e, 0xb7, 0x12, 0x0b, 0xaf, 0x40, 0xeb, 0xd0, 0x0d, 0xac, 0x64,
+	0x6d, 0xcc, 0x7d, 0x60, 0x80, 0x86, 0xe5, 0xad, 0x92, 0xa9, 0x0b, 0x6d, 0xc1, 0xe9, 0xc1, 0x67,
+	0xdc, 0x49, 0x1f, 0xa0, 0x21, 0xca, 0x9c, 0x1e, 0x7c, 0xc6, 0x9c, 0x3e, 0x02, 0x70, 0xfc, 0x0c,
+	0xa7, 0x35, 0x40, 0xc3, 0xda, 0x56, 0xc9, 0xd4, 0x98, 0x4e, 0x71, 0x90, 0x18, 0x6d, 0x3a, 0x2f,
+	0xc2, 0x21, 0x47, 0x38, 0x38, 0x49, 0x48, 0xcc, 0x1d, 0x3a, 0x74, 0xd3, 0x53, 0x07, 0xa6, 0x63,
+	0x0e, 0x37, 0x40, 0x3b, 0x08, 0x02, 0x97, 0xdb, 0x97, 0x06, 0x68, 0xd8, 0xdc, 0x2a, 0x99, 0x4d,
+	0xaa, 0x62, 0xe6, 0x9b, 0xa0, 0xa7, 0x4a, 0x0a, 0xdd, 0x01, 0x1a, 0xb6, 0x69, 0xb9, 0x69, 0x9e,
+	0x83, 0x70, 0x91, 0x49, 0x2c, 0x0f, 0xd0, 0xb0, 0x2a, 0x5d, 0x44, 0x16, 0x9f, 0x43, 0xe7, 0xc8,
+	0x89, 0x93, 0x60, 0x1a, 0x59, 0x1e, 0xf7, 0xc2, 0xca, 0x4a, 0xd9, 0x92, 0xa6, 0xad, 0x92, 0xd9,
+	0xce, 0xfc, 0x58, 0xe0, 0x7d, 0x68, 0xc5, 0xa9, 0xe7, 0x59, 0xd1, 0x09, 0x0f, 0xfb, 0x01, 0x0b,
+	0x6b, 0xb1, 0xb0, 0x3d, 0x6e, 0xa0, 0x9c, 0x09, 0x1f, 0x16, 0xf2, 0x04, 0xba, 0x92, 0x58, 0xcf,
+	0x0a, 0x79, 0xd8, 0x15, 0xa5, 0xe9, 0x7e, 0xc9, 0x8d, 0xdb, 0x56, 0xb8, 0x55, 0x32, 0x3b, 0x87,
+	0x99, 0xc4, 0x82, 0x1f, 0xc2, 0x92, 0x98, 0xdd, 0x2c, 0xf6, 0x03, 0x25, 0xd3, 0x3d, 0x66, 0xe3,
+	0xa1, 0xed, 0x58, 0x0a, 0x2c, 0xf2, 0x1a, 0x34, 0x1d, 0x3f, 0xe1, 0x21, 0x1f, 0x8a, 0x79, 0x68,
+	0x38, 0x7e, 0x22, 0x49, 0x4e, 0x33, 0x6b, 0x4f, 0x10, 0xd4, 0x4c, 0xa5, 0x79, 0x05, 0xaa, 0x2f,
+	0x1d, 0x7f, 0xd2, 0xbb, 0x3a, 0x40, 0xc3, 0x4e, 0xe1, 0xb7, 0x81, 0xaa, 0x4d, 0x66, 0xec, 0x7f,
+	0x0e, 0x5a, 0xb6, 0xd4, 0x69, 0xbf, 0x7a, 0x4b, 0x4e, 0x44, 0xcf, 0xa1, 0x9f, 0xb4, 0x0f, 0x7d,
+	0xcb, 0xfa, 0x10, 0xef, 0x35, 0x5c, 0x78, 0x5c, 0x7e, 0x88, 0x9e, 0xd6, 0xa1, 0x4a, 0xc7, 0x35,
+	0xfe, 0x59, 0x01, 0x2d, 0xdb, 0x94, 0x78, 0x0c, 0xf5, 0x17, 0x7e, 0xb2, 0x6d, 0x85, 0xa2, 0x01,
+	0xf4, 0x8b, 0x9b, 0x76, 0xc4, 0x8d, 0x7c, 0x63, 0x09, 0x4f, 0xfc, 0x04, 0xb4, 0x8c, 0x81, 0x5e,
+	0x99, 0x85, 0xdd, 0x98, 0x09, 0xcb, 0xec, 0x3c, 0x32, 0xf7, 0xc7, 0x0f, 0xa1, 0xc9, 0xa8, 0xa7,
+	0xb1, 0x15, 0x16, 0x7b, 0x7d, 0x26, 0x56, 0x9a, 0x79, 0x68, 0xe6, 0x8d, 0x7f, 0x02, 0x8d, 0xa7,
+	0x41, 0xe0, 0xd2, 0xc0, 0x2a, 0x0b, 0xbc, 0x36, 0x13, 0x28, 0xac, 0x3c, 0x4e, 0xfa, 0xf6, 0x1f,
+	0x81, 0xae, 0x14, 0x71, 0x1e, 0x65, 0x15, 0x85, 0xb2, 0xfe, 0x17, 0xd0, 0x29, 0x16, 0x72, 0x19,
+	0xc2, 0xfb, 0x4f, 0xa0, 0x5d, 0x28, 0xe5, 0xbc, 0x60, 0xa4, 0x06, 0x3f, 0x86, 0x96, 0x5a, 0xce,
+	0x79, 0xb1, 0x4d, 0x25, 0xd6, 0xb8, 0x09, 0x8d, 0x97, 0x8e, 0xeb, 0xd2, 0x1f, 0x8f, 0xab, 0x50,
+	0x37, 0x89, 0x15, 0x07, 0xbe, 0x88, 0x14, 0x92, 0xf1, 0xe7, 0x1a, 0x5c, 0xd9, 0x24, 0x09, 0xe7,
+	0x6e, 0x37, 0x70, 0x1d, 0xfb, 0xe4, 0x8c, 0xdf, 0x47, 0xfc, 0x15, 0xe8, 0xac, 0x3b, 0x84, 0xcc,
+	0x53, 0xcc, 0xf9, 0x27, 0x8c, 0xfe, 0x45, 0x28, 0x6c, 0x26, 0xb8, 0xcc, 0x27, 0x03, 0x0e, 0x32,
+	0x05, 0xde, 0x16, 0x1d, 0x4f, 0x82, 0xf1, 0x45, 0x70, 0xe7, 0x74, 0x30, 0x46, 0xa2, 0x8a, 0xa6,
+	0x1f, 0xe6, 0x1a, 0xbc, 0x07, 0x1d, 0x7a, 0x3c, 0x9b, 0x92, 0x48, 0x02, 0xf2, 0xc5, 0xf1, 0xe9,
+	0xe9, 0x80, 0x2f, 0xb8, 0xbf, 0x0a, 0xd9, 0x76, 0x54, 0x1d, 0xde, 0x05, 0xb1, 0xad, 0x25, 0x26,
+	0xff, 0xc1, 0xb9, 0x7b, 0x3a, 0x26, 0x5f, 0x27, 0x2a, 0x64, 0x2b, 0x56, 0x54, 0xfd, 0x1d, 0x58,
+	0x9a, 0x21, 0x65, 0xc1, 0x94, 0xde, 0x52, 0xa7, 0x54, 0x36, 0xaa, 0x3c, 0x4c, 0x5d, 0x1f, 0xbb,
+	0xd0, 0x9d, 0xe5, 0x65, 0x01, 0xe0, 0xed, 0x22, 0x60, 0x37, 0xef, 0x7c, 0xf3, 0x88, 0xfb, 0x80,
+	0xe7, 0x89, 0x59, 0x80, 0x39, 0x2c, 0x62, 0x62, 0x86, 0x59, 0x88, 0x54, 0x51, 0x4d, 0x58, 0x9e,
+	0xa3, 0x66, 0x01, 0xe8, 0xc7, 0x45, 0xd0, 0x65, 0xa5, 0xcd, 0xce, 0x61, 0x1a, 0x16, 0x34, 0x29,
+	0x29, 0x66, 0xea, 0x12, 0x7a, 0x44, 0x8c, 0xc8, 0xbb, 0xd4, 0x89, 0xc8, 0x84, 0xe1, 0x35, 0xcd,
+	0x4c, 0xa6, 0x47, 0x95, 0x09, 0x39, 0xb4, 0x52, 0x37, 0x11, 0x7b, 0x44, 0x8a, 0xf8, 0x23, 0xd0,
+	0x8f, 0xac, 0xf8, 0x8d, 0xb4, 0x56, 0x98, 0x15, 0x8e, 0xac, 0xf8, 0x39, 0xd7, 0x18, 0xbf, 0x43,
+	0x00, 0x39, 0xf1, 0xf8, 0x1e, 0xd4, 0xa2, 0xd4, 0x25, 0x71, 0xa1, 0x49, 0xe6, 0xf6, 0x11, 0x4d,
+	0x45, 0x9c, 0x3e, 0xb8, 0xa3, 0x2c, 0x91, 0xee, 0x14, 0x5e, 0x62, 0x7f, 0x13, 0x20, 0x77, 0x5b,
+	0x40, 0xc1, 0x4a, 0x91, 0x82, 0x76, 0x36, 0x06, 0x8d, 0x52, 0xcb, 0xff, 0x1b, 0x02, 0x8d, 0xcd,
+	0xe1, 0x45, 0x08, 0xf0, 0x1c, 0xdf, 0xf1, 0x52, 0x4f, 0x34, 0x18, 0x29, 0x32, 0x8b, 0x75, 0xcc,
+	0x2c, 0x15, 0x61, 0xb1, 0x8e, 0xa5, 0x45, 0xd2, 0x52, 0xe5, 0x96, 0x53, 0x48, 0xab, 0xcd, 0x92,
+	0x86, 0x3f, 0x84, 0x06, 0x75, 0xf0, 0x1c, 0x9f, 0x1d, 0xb8, 0x9a, 0x66, 0xfd, 0xc8, 0x8a, 0xb7,
+	0x1d, 0x3f, 0x33, 0x58, 0xc7, 0xbd, 0x46, 0x6e, 0xb0, 0x8e, 0x8d, 0xdf, 0x23, 0xd0, 0x95, 0xe5,
+	0x88, 0xef, 0x17, 0x79, 0xbe, 0x36, 0xbb, 0x5e, 0x2f, 0x44, 0xf4, 0xd6, 0x39, 0x44, 0xff, 0xa8,
+	0x48, 0x74, 0x27, 0x1f, 0x64, 0x96, 0xe9, 0xbf, 0x23, 0xd0, 0xc5, 0xca, 0xbe, 0x2c, 0xd7, 0x95,
+	0x53, 0xb9, 0xae, 0x9c, 0xca, 0x75, 0xe5, 0x7f, 0xca, 0xf5, 0x1f, 0x11, 0xb4, 0x0b, 0xdb, 0x14,
+	0xaf, 0x15, 0xd9, 0xbe, 0x31, 0xbf, 0x93, 0x2f, 0xc4, 0xf7, 0x57, 0xe7, 0xf0, 0xbd, 0xb0, 0x09,
+	0x29, 0xb4, 0xaa, 0x8c, 0xdb, 0x00, 0x7c, 0xd7, 0x5f, 0x76, 0x73, 0x6b, 0x97, 0xd8, 0xdc, 0x7f,
+	0x40, 0xd0, 0x52, 0x7b, 0x0b, 0x1e, 0x17, 0x89, 0xb8, 0x3e, 0xd7, 0x7d, 0x2e, 0xc4, 0xc3, 0x8b,
+	0x73, 0x78, 0x58, 0xd8, 0xdd, 0xf3, 0x6a, 0x55, 0x1a, 0xd6, 0x40, 0x7d, 0x08, 0xb8, 0x05, 0x0d,
+	0xef, 0x8c, 0x1b, 0xa0, 0xb0, 0x19, 0x2f, 0xa1, 0x78, 0xc5, 0xbe, 0x58, 0x58, 0xfe, 0x8b, 0x5f,
+	0x56, 0x6f, 0xc4, 0x4f, 0x60, 0x79, 0x93, 0x24, 0xdc, 0x77, 0xff, 0x24, 0x24, 0x2c, 0x91, 0xdb,
+	0x50, 0xb7, 0xf9, 0x0d, 0x0f, 0x2d, 0xbe, 0xe1, 0x71, 0xab, 0x61, 0x83, 0x96, 0x1d, 0xe6, 0xe9,
+	0x11, 0xe4, 0x20, 0x48, 0xfd, 0x09, 0xcf, 0x02, 0x99, 0x42, 0xa2, 0x7a, 0x3b, 0x48, 0xfd, 0x24,
+	0x66, 0x1c, 0x56, 0x4d, 0x21, 0xb1, 0x3b, 0x78, 0xd6, 0x96, 0xe8, 0x27, 0xcd, 0x90, 0xd9, 0xd8,
+	0x26, 0xa9, 0x9a, 0x5c, 0x30, 0xbe, 0x80, 0xe6, 0x2f, 0x52, 0xcb, 0x4f, 0x1c, 0xbe, 0x50, 0xde,
+	0x89, 0x6f, 0x96, 0x1a, 0x32, 0x33, 0x79, 0xf1, 0x19, 0xcb, 0xf8, 0x06, 0x1a, 0xe2, 0xe2, 0x80,
+	0xef, 0x82, 0x26, 0x9d, 0x25, 0x53, 0xbc, 0xf9, 0x4a, 0x78, 0x33, 0xb7, 0xcb, 0xec, 0xca, 0x0b,
+	0xb2, 0xab, 0xa8, 0xd9, 0xfd, 0x06, 0x20, 0xbf, 0x61, 0xe0, 0x35, 0xa8, 0xb3, 0x61, 0x17, 0x34,
+	0x36, 0xe6, 0x30, 0x62, 0xcf, 0x05, 0x62, 0x81, 0x09, 0x57, 0x7a, 0x70, 0x55, 0xd4, 0x97, 0x39,
+	0x3d, 0x1a, 0xdf, 0x29, 0x27, 0x74, 0x7a, 0xc4, 0x2f, 0x0c, 0xde, 0x2f, 0xde, 0x61, 0xfe, 0x0b,
+	0x63, 0xab, 0xc7, 0x5e, 0xe3, 0xd7, 0xec, 0xf5, 0xc2, 0x75, 0xe2, 0x23, 0xba, 0x64, 0xd4, 0x27,
+	0x1c, 0x34, 0xf3, 0x84, 0xa3, 0xbc, 0x6c, 0x94, 0x2f, 0xf4, 0xb2, 0x71, 0xe6, 0xbb, 0x82, 0xf1,
+	0x33, 0x68, 0x89, 0x81, 0xf9, 0xfa, 0x3f, 0x6b, 0xe8, 0x2b, 0xea, 0xf3, 0x93, 0x26, 0xde, 0x9b,
+	0x0c, 0x1b, 0x96, 0xc5, 0xe3, 0xd6, 0xb3, 0xa3, 0xd4, 0x7f, 0x4b, 0x26, 0xff, 0xd9, 0xc3, 0xdd,
+	0x75, 0xd0, 0x58, 0xfc, 0x9e, 0xf3, 0x9d, 0xbc, 0x54, 0xe4, 0x8a, 0x3b, 0xf7, 0xe5, 0xde, 0xa6,
+	0xd7, 0x39, 0xac, 0x43, 0xe3, 0xf5, 0xce, 0xcb, 0x9d, 0x57, 0xbf, 0xdc, 0xe9, 0x96, 0xa8, 0xf0,
+	0xec, 0xd5, 0xeb, 0x9d, 0xfd, 0x0d, 0xb3, 0x8b, 0xb0, 0x06, 0xb5, 0xcd, 0xf5, 0xd7, 0x9b, 0x1b,
+	0xdd, 0xf2, 0xf8, 0xfb, 0x32, 0x68, 0x22, 0xb1, 0x20, 0xc2, 0x0f, 0xa0, 0x23, 0x04, 0xc9, 0xd0,
+	0x6c, 0x36, 0xfd, 0xf9, 0xe7, 0x37, 0xa3, 0x84, 0xbf, 0x84, 0x0f, 0x8a, 0x71, 0xa2, 0x48, 0x7c,
+	0x55, 0x10, 0x3a, 0x53, 0xf9, 0x42, 0x94, 0x7b, 0x08, 0xff, 0x14, 0x3a, 0xc5, 0xd6, 0x20, 0x00,
+	0xe6, 0xfa, 0xc5, 0xe2, 0x34, 0x56, 0xa0, 0xba, 0xeb, 0xf8, 0x53, 0x0c, 0xcc, 0xc8, 0x5e, 0xe8,
+	0xfa, 0xc5, 0xe7, 0x3f, 0xa3, 0x84, 0x6f, 0xd1, 0xab, 0xb0, 0xeb, 0x62, 0x7e, 0xc5, 0x17, 0xb7,
+	0x99, 0x79, 0xb7, 0xc7, 0xb0, 0x34, 0x73, 0x1a, 0x2f, 0xc0, 0xfe, 0xf0, 0xd4, 0xf3, 0xba, 0x51,
+	0x1a, 0xff, 0x15, 0x81, 0x46, 0xdf, 0xd8, 0x48, 0x1c, 0x07, 0x11, 0x5e, 0x85, 0x86, 0x10, 0x04,
+	0x9b, 0xf9, 0x0b, 0xdc, 0xfb, 0x5d, 0xc6, 0x5f, 0x68, 0x19, 0x7c, 0xd9, 0x93, 0x08, 0xdf, 0x85,
+	0x86, 0x10, 0xe6, 0xcb, 0x98, 0x1b, 0xf6, 0x7d, 0x29, 0xe1, 0xb7, 0x65, 0x58, 0xda, 0x4b, 0x22,
+	0x62, 0x79, 0xf9, 0x22, 0x7f, 0x04, 0x6d, 0xae, 0x2a, 0xae, 0xf1, 0xfc, 0x41, 0xbd, 0xbf, 0xac,
+	0x2a, 0x04, 0xd4, 0x10, 0xfd, 0xff, 0xac, 0xcf, 0x7f, 0x20, 0xc9, 0x4a, 0x3e, 0xbd, 0x19, 0x2b,
+	0x73, 0x93, 0x2c, 0xfa, 0x6d, 0x7f, 0x59, 0x55, 0xa8, 0xac, 0xbc, 0x27, 0x65, 0x1d, 0xd4, 0xd9,
+	0x5f, 0x24, 0x6b, 0xff, 0x1e, 0x00, 0xff, 0xe1, 0x2e, 0x65, 0x60, 0x19, 0x00, 0x00,
 }
@@ -41,6 +41,7 @@ import (
 	"github.com/intelsdi-x/snap/control/plugin/cpolicy"
 	"github.com/intelsdi-x/snap/control/plugin/encoding"
 	"github.com/intelsdi-x/snap/control/plugin/encrypter"
+	"github.com/intelsdi-x/snap/core"
 	"github.com/intelsdi-x/snap/core/cdata"
 	"github.com/intelsdi-x/snap/core/ctypes"
 )
@@ -300,6 +301,11 @@ func init() {
 	gob.RegisterName("conf_policy_int", &cpolicy.IntRule{})
 	gob.RegisterName("conf_policy_float", &cpolicy.FloatRule{})
 	gob.RegisterName("conf_policy_bool", &cpolicy.BoolRule{})
+
+	gob.RegisterName("metric_histogram", core.Histogram{})
+	gob.RegisterName("metric_summary", core.Summary{})
+	gob.RegisterName("metric_float64_map", map[string]float64{})
+	gob.RegisterName("metric_string_map", map[string]string{})
 }
 
 // simpleFormatter is a logrus formatter that includes only the message.
@@ -23,11 +23,9 @@ package control
 
 import (
 	"crypto/sha256"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
-	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -37,16 +35,18 @@ import (
 	"sync"
 	"time"
 
-	"github.com/appc/spec/schema"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/intelsdi-x/gomit"
 	"github.com/intelsdi-x/snap/control/plugin"
 	"github.com/intelsdi-x/snap/control/plugin/client"
 	"github.com/intelsdi-x/snap/control/plugin/cpolicy"
+	"github.com/intelsdi-x/snap/control/strategy"
 	"github.com/intelsdi-x/snap/core"
 	"github.com/intelsdi-x/snap/core/cdata"
 	"github.com/intelsdi-x/snap/core/serror"
+	"github.com/intelsdi-x/snap/pkg/bundle"
+	"github.com/intelsdi-x/snap/pkg/psigning"
 )
 
 const (
@@ -177,15 +177,20 @@ type pluginDetails struct {
 	Exec        []string
 	ExecPath    string
 	IsPackage   bool
-	Manifest    *schema.ImageManifest
+	Manifest    *bundle.Manifest
 	Path        string
 	Signed      bool
 	Signature   []byte
+	Signer      *psigning.Signer
 	CertPath    string
 	KeyPath     string
 	CACertPaths string
 	TLSEnabled  bool
 	Uri         *url.URL
+	// Endpoints of a standalone plugin, the first one is Uri
+	Endpoints []core.RemoteEndpoint
+	// Lifecycle requested at load time, nil if not requested
+	Lifecycle *strategy.Lifecycle
 }
 
 type loadedPlugin struct {
@@ -204,6 +209,25 @@ func (lp *loadedPlugin) Name() string {
 	return lp.Meta.Name
 }
 
+// Metadata returns the description the plugin returned when loaded, nil if
+// it returned none
+func (lp *loadedPlugin) Metadata() *core.PluginMetadata {
+	return lp.Meta.Metadata
+}
+
+// Signer returns the key the plugin was signed with, nil if the plugin is not
+// signed
+func (lp *loadedPlugin) Signer() *core.PluginSigner {
+	if lp.Details == nil || lp.Details.Signer == nil {
+		return nil
+	}
+	return &core.PluginSigner{
+		KeyID:     lp.Details.Signer.KeyID,
+		Algorithm: lp.Details.Signer.Algorithm,
+		Identity:  lp.Details.Signer.Identity,
+	}
+}
+
 // PluginPath returns the plugin path
 func (lp *loadedPlugin) PluginPath() string {
 	return lp.Details.Path
@@ -260,6 +284,11 @@ type pluginManager struct {
 	pprof             bool
 	tempDirPath       string
 	grpcSecurity      client.GRPCSecurity
+	channels          pluginChannels
+
+	// when set, the plugins are given a Unix socket to listen on, handed out
+	// by channels.sockets
+	unixSockets bool
 }
 
 func newPluginManager(opts ...pluginManagerOpt) *pluginManager {
@@ -279,6 +308,9 @@ func newPluginManager(opts ...pluginManagerOpt) *pluginManager {
 	for _, opt := range mergedOpts {
 		opt(p)
 	}
+	if p.channels.sockets == nil {
+		p.channels.sockets = newPluginSockets(p.tempDirPath)
+	}
 
 	return p
 }
@@ -292,6 +324,14 @@ func OptSetTempDirPath(path string) pluginManagerOpt {
 	}
 }
 
+// OptSetUnixSockets makes the plugin manager offer the plugins a Unix socket
+// to listen on instead of a TCP port
+func OptSetUnixSockets(enable bool) pluginManagerOpt {
+	return func(p *pluginManager) {
+		p.unixSockets = enable
+	}
+}
+
 // OptSetPprof sets the pprof flag on the plugin manager
 func OptSetPprof(pprof bool) pluginManagerOpt {
 	return func(p *pluginManager) {
@@ -306,6 +346,14 @@ func OptEnableManagerTLS(grpcSecurity client.GRPCSecurity) pluginManagerOpt {
 	}
 }
 
+// OptSetManagerChannels sets the settings of the gRPC channels to the plugins
+// on the plugin manager
+func OptSetManagerChannels(channels pluginChannels) pluginManagerOpt {
+	return func(p *pluginManager) {
+		p.channels = channels
+	}
+}
+
 // OptSetPluginConfig sets the config on the plugin manager
 func OptSetPluginConfig(cf *pluginConfig) pluginManagerOpt {
 	return func(p *pluginManager) {
@@ -365,9 +413,10 @@ func (p *pluginManager) LoadPlugin(details *pluginDetails, emitter gomit.Emitter
 		lPlugin.State = DetectedState
 
 		var (
-			ePlugin *plugin.ExecutablePlugin
-			resp    plugin.Response
-			err     error
+			ePlugin  *plugin.ExecutablePlugin
+			resp     plugin.Response
+			err      error
+			security = p.grpcSecurity
 		)
 
 		if lPlugin.Details.Uri == nil {
@@ -427,26 +476,42 @@ func (p *pluginManager) LoadPlugin(details *pluginDetails, emitter gomit.Emitter
 				"_block": "load-plugin",
 				"uri":    lPlugin.Details.Uri.String(),
 			}).Info("plugin load called")
-			res, err := http.Get(lPlugin.Details.Uri.String())
-			if err != nil {
-				resultChan <- result{nil, serror.New(err)}
-				return
+			// the first reachable endpoint describes the plugin
+			for _, e := range lPlugin.Details.remoteEndpoints() {
+				if resp, err = remoteResponse(e); err == nil {
+					security = remoteSecurity(e, p.grpcSecurity)
+					break
+				}
+				pmLogger.WithFields(log.Fields{
+					"_block": "load-plugin",
+					"uri":    e.URI,
+					"error":  err.Error(),
+				}).Warn("standalone plugin endpoint unreachable")
 			}
-
-			body, err := ioutil.ReadAll(res.Body)
 			if err != nil {
 				resultChan <- result{nil, serror.New(err)}
 				return
 			}
-			err = json.Unmarshal(body, &resp)
-			if err != nil {
-				pmLogger.WithFields(log.Fields{
-					"_block": "load-plugin",
-					"error":  err.Error(),
-				}).Error("error during json unmarshal")
+		}
+		if err = validateMetadata(resp.Meta.Metadata, lPlugin.Details.Uri != nil); err != nil {
+			pmLogger.WithFields(log.Fields{
+				"_block":         "load-plugin",
+				"plugin-name":    resp.Meta.Name,
+				"plugin-version": resp.Meta.Version,
+				"plugin-type":    resp.Type.String(),
+				"error":          err.Error(),
+			}).Error("load plugin error while validating plugin metadata")
+			if ePlugin != nil {
+				ePlugin.Kill()
 			}
+			resultChan <- result{nil, serror.New(err, map[string]interface{}{
+				"plugin-name":    resp.Meta.Name,
+				"plugin-version": resp.Meta.Version,
+				"plugin-type":    resp.Type.String(),
+			})}
+			return
 		}
-		ap, err := newAvailablePlugin(resp, emitter, ePlugin, p.grpcSecurity)
+		ap, err := newAvailablePlugin(resp, emitter, ePlugin, security, p.channels)
 		if err != nil {
 			pmLogger.WithFields(log.Fields{
 				"_block": "load-plugin",
@@ -582,6 +647,7 @@ func (p *pluginManager) LoadPlugin(details *pluginDetails, emitter gomit.Emitter
 						tags:               nmt.Tags(),
 						description:        nmt.Description(),
 						unit:               nmt.Unit(),
+						kind:               nmt.Kind(),
 					}
 				}
 				// We quit and throw an error on bad metric versions (<1)
@@ -634,6 +700,9 @@ func (p *pluginManager) LoadPlugin(details *pluginDetails, emitter gomit.Emitter
 				resultChan <- result{nil, serror.New(err)}
 				return
 			}
+		} else {
+			// the endpoints of a standalone plugin are connected when in use
+			ap.client.Close()
 		}
 
 		if resp.State != plugin.PluginSuccess {
@@ -655,27 +724,6 @@ func (p *pluginManager) LoadPlugin(details *pluginDetails, emitter gomit.Emitter
 			}).Error("load plugin error while adding loaded plugin to load plugins collection")
 			resultChan <- result{nil, aErr}
 		}
-		if ap.isRemote && aErr == nil {
-			// monitor standalone plugins. Unload them from the plugin catalog and metrics list
-			// when we detect they are no longer online.
-			go func() {
-				defer ap.client.(client.PluginCollectorClient).Close()
-				for {
-					time.Sleep(5 * time.Second)
-					go ap.CheckHealth()
-					if ap.failedHealthChecks > 3 {
-						p.UnloadPlugin(lPlugin)
-						return
-					}
-					if _, err := p.loadedPlugins.get(lPlugin.Key()); err != nil {
-						// prevent leaking routine when plugin is unloaded normally
-						return
-					}
-
-				}
-			}()
-
-		}
 		resultChan <- result{lPlugin, nil}
 		return
 	}()
@@ -762,7 +810,66 @@ func (p *pluginManager) UnloadPlugin(pl core.Plugin) (*loadedPlugin, serror.Snap
 
 // GenerateArgs generates the cli args to send when stating a plugin
 func (p *pluginManager) GenerateArgs(logLevel int) plugin.Arg {
-	return plugin.NewArg(logLevel, p.pprof)
+	return plugin.NewArg(logLevel, p.pprof).SetListenSocket(p.nextSocket())
+}
+
+// nextSocket returns the path of a new Unix socket in a directory only
+// snapteld's user may access, or an empty path when the plugins listen on TCP
+// ports.
+func (p *pluginManager) nextSocket() string {
+	if !p.unixSockets {
+		return ""
+	}
+	socket, err := p.channels.sockets.next()
+	if err != nil {
+		pmLogger.WithFields(log.Fields{
+			"_block": "next-socket",
+			"path":   p.tempDirPath,
+			"error":  err.Error(),
+		}).Warn("unable to create the plugin sockets directory, plugins listen on TCP ports")
+		return ""
+	}
+	return socket
+}
+
+// pluginSockets hands out the paths of the Unix sockets the plugins and the
+// shims of native plugins listen on, in a directory only snapteld's user may
+// access which is created on first use.
+type pluginSockets struct {
+	tempDirPath string
+	mutex       sync.Mutex
+	dir         string
+	count       int
+}
+
+func newPluginSockets(tempDirPath string) *pluginSockets {
+	return &pluginSockets{tempDirPath: tempDirPath}
+}
+
+// next returns the path of a new Unix socket.
+func (s *pluginSockets) next() (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.dir == "" {
+		// the directory is created with 0700 permissions
+		dir, err := ioutil.TempDir(s.tempDirPath, "snap-plugin-sockets-")
+		if err != nil {
+			return "", err
+		}
+		s.dir = dir
+	}
+	s.count++
+	return filepath.Join(s.dir, fmt.Sprintf("%d.sock", s.count)), nil
+}
+
+// remove removes the directory of the sockets.
+func (s *pluginSockets) remove() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.dir != "" {
+		os.RemoveAll(s.dir)
+		s.dir = ""
+	}
 }
 
 func (p *pluginManager) teardown() {
@@ -777,6 +884,7 @@ func (p *pluginManager) teardown() {
 			}).Warn("error removing plugin in teardown:", err)
 		}
 	}
+	p.channels.sockets.remove()
 }
 
 func (p *pluginManager) get(key string) (*loadedPlugin, error) {
@@ -846,6 +954,7 @@ func (p *pluginManager) AddStandardAndWorkflowTags(m core.Metric, allTags map[st
 		Tags_:               tags,
 		Description_:        m.Description(),
 		Unit_:               m.Unit(),
+		Kind_:               m.Kind(),
 		Timestamp_:          m.Timestamp(),
 	}
 	return metric
@@ -24,6 +24,7 @@ package control
 import (
 	"bufio"
 	"errors"
+	"io/ioutil"
 	"net/url"
 	"os"
 	"os/exec"
@@ -339,3 +340,33 @@ func TestLoadedPlugin(t *testing.T) {
 		})
 	})
 }
+
+func TestGenerateArgsUnixSockets(t *testing.T) {
+	Convey("Given a plugin manager offering Unix sockets", t, func() {
+		tmp, err := ioutil.TempDir("", "snap-test-")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(tmp)
+		p := newPluginManager(OptSetTempDirPath(tmp), OptSetUnixSockets(true))
+
+		Convey("every plugin is given its own socket in a private directory", func() {
+			first := p.GenerateArgs(0).ListenSocket
+			second := p.GenerateArgs(0).ListenSocket
+			So(first, ShouldNotEqual, "")
+			So(second, ShouldNotEqual, first)
+			So(filepath.Dir(first), ShouldEqual, filepath.Dir(second))
+			fi, err := os.Stat(filepath.Dir(first))
+			So(err, ShouldBeNil)
+			So(fi.Mode().Perm(), ShouldEqual, os.FileMode(0700))
+
+			Convey("the directory is removed on teardown", func() {
+				p.teardown()
+				_, err := os.Stat(filepath.Dir(first))
+				So(os.IsNotExist(err), ShouldBeTrue)
+			})
+		})
+		Convey("no socket is given when they are disabled", func() {
+			p := newPluginManager(OptSetTempDirPath(tmp))
+			So(p.GenerateArgs(0).ListenSocket, ShouldEqual, "")
+		})
+	})
+}
@@ -0,0 +1,475 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package control
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/intelsdi-x/snap/control/plugin"
+	"github.com/intelsdi-x/snap/control/plugin/client"
+	"github.com/intelsdi-x/snap/control/strategy"
+	"github.com/intelsdi-x/snap/core"
+	"github.com/intelsdi-x/snap/core/serror"
+)
+
+const (
+	// remoteInitialBackoff is the delay before connecting again to an
+	// endpoint of a standalone plugin after its first failure, the delay
+	// doubles on each consecutive failure
+	remoteInitialBackoff = time.Second
+	// remoteMaxBackoff caps the delay between two connections to an endpoint
+	remoteMaxBackoff = 2 * time.Minute
+	// remoteReconnectInterval is how often the endpoints due for a
+	// connection are looked for
+	remoteReconnectInterval = time.Second
+)
+
+var (
+	// ErrRemoteEndpointsUnreachable - error message when no endpoint of a
+	// standalone plugin can be connected to
+	ErrRemoteEndpointsUnreachable = errors.New("no endpoint of the standalone plugin is reachable")
+
+	errRemoteHealthCheckFailed = errors.New("health check failed")
+)
+
+// remoteEndpoint is an endpoint of a standalone plugin and its connection
+type remoteEndpoint struct {
+	core.RemoteEndpoint
+	// ap is the available plugin connected to the endpoint, nil when the
+	// endpoint is not connected
+	ap *availablePlugin
+	// failures is the number of consecutive failed connections
+	failures    int
+	nextAttempt time.Time
+	lastError   error
+	// dialing is closed once the connection in progress, if any, is made
+	// or failed
+	dialing chan struct{}
+}
+
+// due returns true when the endpoint is not connected, no connection to it is
+// in progress and its backoff is over
+func (e *remoteEndpoint) due(now time.Time) bool {
+	return e.ap == nil && e.dialing == nil && !now.Before(e.nextAttempt)
+}
+
+// startDial records that a connection to the endpoint is in progress
+func (e *remoteEndpoint) startDial() {
+	e.dialing = make(chan struct{})
+}
+
+// endDial records that the connection in progress is made or failed
+func (e *remoteEndpoint) endDial() {
+	close(e.dialing)
+	e.dialing = nil
+}
+
+func (e *remoteEndpoint) connected(ap *availablePlugin) {
+	e.ap = ap
+	e.failures = 0
+	e.nextAttempt = time.Time{}
+	e.lastError = nil
+}
+
+// failed records a failed connection and delays the next one
+func (e *remoteEndpoint) failed(err error) {
+	e.ap = nil
+	e.failures++
+	e.lastError = err
+	backoff := remoteInitialBackoff
+	for i := 1; i < e.failures && backoff < remoteMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > remoteMaxBackoff {
+		backoff = remoteMaxBackoff
+	}
+	e.nextAttempt = time.Now().Add(backoff)
+}
+
+func (e *remoteEndpoint) status() core.RemoteEndpointStatus {
+	s := core.RemoteEndpointStatus{
+		URI:               e.URI,
+		Connected:         e.ap != nil,
+		FailedConnections: e.failures,
+		NextAttempt:       e.nextAttempt,
+	}
+	if e.lastError != nil {
+		s.LastError = e.lastError.Error()
+	}
+	return s
+}
+
+// remotePlugins keeps track of the endpoints of the standalone plugins in
+// use.  Each connected endpoint is an available plugin in the pool of its
+// plugin; an endpoint found dead by the monitor, or which cannot be reached,
+// is connected again with an exponential backoff.
+type remotePlugins struct {
+	sync.Mutex
+	// endpoints keyed by plugin key
+	endpoints map[string][]*remoteEndpoint
+	quit      chan struct{}
+}
+
+func newRemotePlugins() *remotePlugins {
+	return &remotePlugins{
+		endpoints: map[string][]*remoteEndpoint{},
+	}
+}
+
+// tracked returns true when e is still an endpoint of the plugin key, the
+// caller holds the lock
+func (rp *remotePlugins) tracked(key string, e *remoteEndpoint) bool {
+	for _, te := range rp.endpoints[key] {
+		if te == e {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteEndpoints returns the endpoints of a standalone plugin
+func (d *pluginDetails) remoteEndpoints() []core.RemoteEndpoint {
+	if len(d.Endpoints) > 0 {
+		return d.Endpoints
+	}
+	if d.Uri != nil {
+		return []core.RemoteEndpoint{{URI: d.Uri.String()}}
+	}
+	return nil
+}
+
+// remoteSecurity returns the security of the connection to an endpoint, the
+// one of control unless the endpoint has its own TLS settings
+func remoteSecurity(e core.RemoteEndpoint, defaults client.GRPCSecurity) client.GRPCSecurity {
+	if !e.TLSEnabled() {
+		return defaults
+	}
+	if e.CACertPaths != "" {
+		return client.SecurityTLSExtended(e.CertPath, e.KeyPath, client.SecureClient, filepath.SplitList(e.CACertPaths))
+	}
+	return client.SecurityTLSEnabled(e.CertPath, e.KeyPath, client.SecureClient)
+}
+
+// remoteHTTPClient returns the client reading the response of a standalone
+// plugin, presenting the certificate of the endpoint if any
+func remoteHTTPClient(e core.RemoteEndpoint) (*http.Client, error) {
+	c := &http.Client{Timeout: DefaultClientTimeout}
+	if !e.TLSEnabled() && e.CACertPaths == "" {
+		return c, nil
+	}
+	cfg := &tls.Config{}
+	if e.TLSEnabled() {
+		cert, err := tls.LoadX509KeyPair(e.CertPath, e.KeyPath)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	if e.CACertPaths != "" {
+		cfg.RootCAs = x509.NewCertPool()
+		for _, p := range filepath.SplitList(e.CACertPaths) {
+			b, err := ioutil.ReadFile(p)
+			if err != nil {
+				return nil, err
+			}
+			if !cfg.RootCAs.AppendCertsFromPEM(b) {
+				return nil, fmt.Errorf("no CA certificate found in %v", p)
+			}
+		}
+	}
+	c.Transport = &http.Transport{TLSClientConfig: cfg}
+	return c, nil
+}
+
+// remoteResponse reads the response of a standalone plugin, the one a
+// plugin started by snapteld writes to its standard output
+func remoteResponse(e core.RemoteEndpoint) (plugin.Response, error) {
+	var resp plugin.Response
+	c, err := remoteHTTPClient(e)
+	if err != nil {
+		return resp, err
+	}
+	res, err := c.Get(e.URI)
+	if err != nil {
+		return resp, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return resp, fmt.Errorf("%v: %v", e.URI, res.Status)
+	}
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		return resp, fmt.Errorf("%v: %v", e.URI, err)
+	}
+	return resp, nil
+}
+
+// connectRemote connects to the endpoints of a standalone plugin which are
+// not connected yet and inserts them in the pool of the plugin.  It fails if
+// none of the endpoints is connected.  The endpoints are dialed without
+// holding the lock of r.remotes, the connections in progress are waited for.
+func (r *runner) connectRemote(key string) error {
+	lp, err := r.pluginManager.get(key)
+	if err != nil {
+		return err
+	}
+	pool, err := r.availablePlugins.getOrCreatePool(key)
+	if err != nil {
+		return err
+	}
+	r.remotes.Lock()
+	eps, ok := r.remotes.endpoints[key]
+	if !ok {
+		for _, e := range lp.Details.remoteEndpoints() {
+			eps = append(eps, &remoteEndpoint{RemoteEndpoint: e})
+		}
+		r.remotes.endpoints[key] = eps
+	}
+	var due []*remoteEndpoint
+	var inProgress []chan struct{}
+	now := time.Now()
+	for _, e := range eps {
+		if e.dialing != nil {
+			inProgress = append(inProgress, e.dialing)
+		} else if e.due(now) {
+			e.startDial()
+			due = append(due, e)
+		}
+	}
+	r.remotes.Unlock()
+
+	for _, e := range due {
+		r.connectEndpoint(key, pool, e)
+	}
+	for _, dialing := range inProgress {
+		<-dialing
+	}
+
+	r.remotes.Lock()
+	defer r.remotes.Unlock()
+	if pool.Count() < 1 {
+		for _, e := range eps {
+			if e.lastError != nil {
+				return fmt.Errorf("%v: %v", ErrRemoteEndpointsUnreachable, e.lastError)
+			}
+		}
+		return ErrRemoteEndpointsUnreachable
+	}
+	return nil
+}
+
+// connectEndpoint connects to an endpoint of a standalone plugin and inserts
+// it in the pool.  The caller recorded the connection in progress with
+// startDial and does not hold the lock of r.remotes, which is taken once the
+// endpoint is dialed to record the outcome.
+func (r *runner) connectEndpoint(key string, pool strategy.Pool, e *remoteEndpoint) {
+	f := log.Fields{
+		"_block": "connect-endpoint",
+		"plugin": key,
+		"uri":    e.URI,
+	}
+	ap, err := r.dialEndpoint(e.RemoteEndpoint)
+
+	r.remotes.Lock()
+	defer r.remotes.Unlock()
+	defer e.endDial()
+	if !r.remotes.tracked(key, e) {
+		// the plugin was unloaded while the endpoint was dialed
+		if ap != nil {
+			ap.Stop("plugin unloaded")
+		}
+		return
+	}
+	if err == nil {
+		err = pool.Insert(ap)
+	}
+	if err != nil {
+		if ap != nil {
+			ap.Stop("connection failed")
+		}
+		e.failed(err)
+		runnerLog.WithFields(f).Warnf("connection to standalone plugin failed, next attempt at %v: %v", e.nextAttempt.Format(time.RFC3339), err)
+		return
+	}
+	e.connected(ap)
+	runnerLog.WithFields(f).Info("connected to standalone plugin")
+}
+
+func (r *runner) dialEndpoint(e core.RemoteEndpoint) (*availablePlugin, error) {
+	resp, err := remoteResponse(e)
+	if err != nil {
+		return nil, err
+	}
+	ap, err := newAvailablePlugin(resp, r.emitter, nil, remoteSecurity(e, r.grpcSecurity), r.channels)
+	if err != nil {
+		return nil, err
+	}
+	ap.SetIsRemote(true)
+	if resp.Meta.Unsecure {
+		err = ap.client.Ping()
+	} else {
+		err = ap.client.SetKey()
+	}
+	if err != nil {
+		ap.Stop("connection failed")
+		return nil, err
+	}
+	return ap, nil
+}
+
+// reconnectRemotes connects to the endpoints of the standalone plugins in
+// use whose backoff is over
+func (r *runner) reconnectRemotes() {
+	type dial struct {
+		key  string
+		pool strategy.Pool
+		e    *remoteEndpoint
+	}
+	var dials []dial
+	r.remotes.Lock()
+	now := time.Now()
+	for key, eps := range r.remotes.endpoints {
+		pool, _ := r.availablePlugins.getPool(key)
+		if pool == nil {
+			continue
+		}
+		for _, e := range eps {
+			if e.due(now) {
+				e.startDial()
+				dials = append(dials, dial{key: key, pool: pool, e: e})
+			}
+		}
+	}
+	r.remotes.Unlock()
+
+	for _, d := range dials {
+		r.connectEndpoint(d.key, d.pool, d.e)
+	}
+}
+
+// startReconnecting starts connecting again to the lost endpoints
+func (r *runner) startReconnecting() {
+	r.remotes.quit = make(chan struct{})
+	ticker := time.NewTicker(remoteReconnectInterval)
+	go func(quit chan struct{}) {
+		for {
+			select {
+			case <-ticker.C:
+				r.reconnectRemotes()
+			case <-quit:
+				ticker.Stop()
+				return
+			}
+		}
+	}(r.remotes.quit)
+}
+
+func (r *runner) stopReconnecting() {
+	if r.remotes.quit != nil {
+		close(r.remotes.quit)
+		r.remotes.quit = nil
+	}
+}
+
+// remoteLost records that the available plugin id of a standalone plugin is
+// dead so that its endpoint is connected again.  It returns false if id is
+// not an endpoint of a standalone plugin.
+func (r *runner) remoteLost(key string, id uint32) bool {
+	r.remotes.Lock()
+	defer r.remotes.Unlock()
+	for _, e := range r.remotes.endpoints[key] {
+		if e.ap != nil && e.ap.ID() == id {
+			e.failed(errRemoteHealthCheckFailed)
+			runnerLog.WithFields(log.Fields{
+				"_block": "remote-lost",
+				"plugin": key,
+				"uri":    e.URI,
+			}).Warnf("standalone plugin lost, next attempt at %v", e.nextAttempt.Format(time.RFC3339))
+			return true
+		}
+	}
+	return false
+}
+
+// forgetRemote disconnects the endpoints of an unloaded standalone plugin
+func (r *runner) forgetRemote(key string) {
+	r.remotes.Lock()
+	defer r.remotes.Unlock()
+	eps, ok := r.remotes.endpoints[key]
+	if !ok {
+		return
+	}
+	delete(r.remotes.endpoints, key)
+	pool, _ := r.availablePlugins.getPool(key)
+	for _, e := range eps {
+		if e.ap != nil && pool != nil {
+			pool.Kill(e.ap.ID(), "plugin unloaded")
+		}
+	}
+}
+
+// remoteStatus returns the health of the endpoints of a standalone plugin
+func (r *runner) remoteStatus(key string) []core.RemoteEndpointStatus {
+	r.remotes.Lock()
+	defer r.remotes.Unlock()
+	eps, ok := r.remotes.endpoints[key]
+	if !ok {
+		// the plugin is not in use yet
+		lp, err := r.pluginManager.get(key)
+		if err != nil {
+			return nil
+		}
+		for _, e := range lp.Details.remoteEndpoints() {
+			eps = append(eps, &remoteEndpoint{RemoteEndpoint: e})
+		}
+	}
+	status := make([]core.RemoteEndpointStatus, len(eps))
+	for i, e := range eps {
+		status[i] = e.status()
+	}
+	return status
+}
+
+// RemotePluginEndpoints returns the health of the endpoints of a standalone
+// plugin, nil if the plugin is not a standalone plugin
+func (p *pluginControl) RemotePluginEndpoints(pl core.Plugin) ([]core.RemoteEndpointStatus, serror.SnapError) {
+	lp, err := p.pluginManager.get(fmt.Sprintf("%s"+core.Separator+"%s"+core.Separator+"%d", pl.TypeName(), pl.Name(), pl.Version()))
+	if err != nil {
+		return nil, serror.New(ErrPluginNotFound, map[string]interface{}{
+			"plugin-name":    pl.Name(),
+			"plugin-version": pl.Version(),
+			"plugin-type":    pl.TypeName(),
+		})
+	}
+	if lp.Details.Uri == nil {
+		return nil, nil
+	}
+	return p.pluginRunner.remoteStatus(lp.Key()), nil
+}
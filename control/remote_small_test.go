@@ -0,0 +1,95 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package control
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/intelsdi-x/snap/control/plugin/client"
+	"github.com/intelsdi-x/snap/core"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRemoteEndpoint(t *testing.T) {
+	Convey("Given an endpoint of a standalone plugin", t, func() {
+		e := &remoteEndpoint{RemoteEndpoint: core.RemoteEndpoint{URI: "http://127.0.0.1:8183"}}
+		So(e.due(time.Now()), ShouldBeTrue)
+
+		Convey("Failed connections are delayed with an exponential backoff", func() {
+			e.failed(errors.New("connection refused"))
+			So(e.due(time.Now()), ShouldBeFalse)
+			So(e.nextAttempt, ShouldHappenWithin, remoteInitialBackoff+time.Second, time.Now())
+			e.failed(errors.New("connection refused"))
+			e.failed(errors.New("connection refused"))
+			So(e.nextAttempt, ShouldHappenAfter, time.Now().Add(3*remoteInitialBackoff))
+			for i := 0; i < 100; i++ {
+				e.failed(errors.New("connection refused"))
+			}
+			So(e.nextAttempt, ShouldHappenWithin, time.Second, time.Now().Add(remoteMaxBackoff))
+			s := e.status()
+			So(s.Connected, ShouldBeFalse)
+			So(s.FailedConnections, ShouldEqual, 103)
+			So(s.LastError, ShouldEqual, "connection refused")
+		})
+		Convey("An endpoint being dialed is not due", func() {
+			e.startDial()
+			dialing := e.dialing
+			So(e.due(time.Now()), ShouldBeFalse)
+			e.endDial()
+			_, open := <-dialing
+			So(open, ShouldBeFalse)
+			So(e.due(time.Now()), ShouldBeTrue)
+		})
+		Convey("A connection resets the backoff", func() {
+			e.failed(errors.New("connection refused"))
+			e.connected(&availablePlugin{})
+			So(e.due(time.Now().Add(time.Hour)), ShouldBeFalse)
+			s := e.status()
+			So(s.Connected, ShouldBeTrue)
+			So(s.FailedConnections, ShouldEqual, 0)
+			So(s.LastError, ShouldBeEmpty)
+		})
+	})
+	Convey("Endpoints use the TLS settings of control unless they have their own", t, func() {
+		defaults := client.SecurityTLSOff()
+		So(remoteSecurity(core.RemoteEndpoint{URI: "http://127.0.0.1:8183"}, defaults), ShouldResemble, defaults)
+		s := remoteSecurity(core.RemoteEndpoint{
+			URI:         "http://127.0.0.1:8183",
+			CertPath:    "snap.crt",
+			KeyPath:     "snap.key",
+			CACertPaths: "ca1.crt:ca2.crt",
+		}, defaults)
+		So(s.TLSEnabled, ShouldBeTrue)
+		So(s.SecureSide, ShouldEqual, client.SecureClient)
+		So(s.CACertPaths, ShouldResemble, []string{"ca1.crt", "ca2.crt"})
+	})
+	Convey("The endpoints of a plugin loaded by URI default to the URI", t, func() {
+		u, _ := url.Parse("http://127.0.0.1:8183")
+		d := &pluginDetails{Uri: u}
+		So(d.remoteEndpoints(), ShouldResemble, []core.RemoteEndpoint{{URI: "http://127.0.0.1:8183"}})
+		So((&pluginDetails{}).remoteEndpoints(), ShouldBeEmpty)
+	})
+}
@@ -0,0 +1,160 @@
+// +build legacy
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package control
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/intelsdi-x/snap/control/fixtures"
+	"github.com/intelsdi-x/snap/control/plugin"
+	"github.com/intelsdi-x/snap/core"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// waitFor polls cond until it is true or the timeout expires
+func waitFor(timeout time.Duration, cond func() bool) bool {
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return true
+}
+
+func TestRemotePlugins(t *testing.T) {
+	if fixtures.SnapPath == "" {
+		t.Fatal("SNAP_PATH not set. Cannot test loading plugins.")
+	}
+	// serve the response of a running plugin as a standalone plugin does
+	ePlugin, err := plugin.NewExecutablePlugin(plugin.Arg{}, fixtures.PluginPathMock1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := ePlugin.Run(10 * time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ePlugin.Kill()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	Convey("Given a standalone plugin with an unreachable endpoint", t, func() {
+		c := New(getTestConfig())
+		c.Start()
+		defer c.Stop()
+		rp, err := core.NewRequestedRemotePlugin([]core.RemoteEndpoint{
+			{URI: "http://127.0.0.1:1"},
+			{URI: srv.URL},
+		})
+		So(err, ShouldBeNil)
+		lp, serr := c.Load(rp)
+		So(serr, ShouldBeNil)
+		So(lp.Name(), ShouldEqual, "mock")
+		key := lp.(*loadedPlugin).Key()
+
+		Convey("The reachable endpoints are pooled", func() {
+			So(c.pluginRunner.connectRemote(key), ShouldBeNil)
+			pool, _ := c.pluginRunner.AvailablePlugins().getPool(key)
+			So(pool.Count(), ShouldEqual, 1)
+			status, serr := c.RemotePluginEndpoints(lp)
+			So(serr, ShouldBeNil)
+			So(status, ShouldHaveLength, 2)
+			So(status[0].Connected, ShouldBeFalse)
+			So(status[0].FailedConnections, ShouldEqual, 1)
+			So(status[0].LastError, ShouldNotBeEmpty)
+			So(status[1].Connected, ShouldBeTrue)
+
+			Convey("A lost endpoint is connected again", func() {
+				var id uint32
+				for _, ap := range pool.Plugins() {
+					id = ap.ID()
+				}
+				pool.Kill(id, "testing")
+				So(c.pluginRunner.(*runner).remoteLost(key, id), ShouldBeTrue)
+				So(c.pluginRunner.remoteStatus(key)[1].Connected, ShouldBeFalse)
+				So(waitFor(5*time.Second, func() bool {
+					return c.pluginRunner.remoteStatus(key)[1].Connected
+				}), ShouldBeTrue)
+				So(pool.Count(), ShouldEqual, 1)
+			})
+			Convey("The endpoints are disconnected when the plugin is unloaded", func() {
+				_, serr := c.Unload(lp)
+				So(serr, ShouldBeNil)
+				So(waitFor(5*time.Second, func() bool {
+					return pool.Count() == 0
+				}), ShouldBeTrue)
+			})
+		})
+		Convey("An unknown plugin is not found", func() {
+			status, serr := c.RemotePluginEndpoints(&loadedPlugin{})
+			So(serr, ShouldNotBeNil)
+			So(status, ShouldBeNil)
+		})
+	})
+	Convey("Given a standalone plugin with a slow endpoint", t, func() {
+		requested := make(chan struct{}, 1)
+		release := make(chan struct{})
+		slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested <- struct{}{}
+			<-release
+			json.NewEncoder(w).Encode(resp)
+		}))
+		defer slow.Close()
+		c := New(getTestConfig())
+		c.Start()
+		defer c.Stop()
+		rp, err := core.NewRequestedRemotePlugin([]core.RemoteEndpoint{{URI: slow.URL}})
+		So(err, ShouldBeNil)
+		// the response of the endpoint is read once when the plugin is loaded
+		go func() { <-requested; release <- struct{}{} }()
+		lp, serr := c.Load(rp)
+		So(serr, ShouldBeNil)
+		key := lp.(*loadedPlugin).Key()
+
+		Convey("The status of the endpoints is available while it is dialed", func() {
+			connected := make(chan error, 1)
+			go func() { connected <- c.pluginRunner.connectRemote(key) }()
+			<-requested
+			status := make(chan []core.RemoteEndpointStatus, 1)
+			go func() { status <- c.pluginRunner.remoteStatus(key) }()
+			select {
+			case s := <-status:
+				So(s, ShouldHaveLength, 1)
+				So(s[0].Connected, ShouldBeFalse)
+			case <-time.After(5 * time.Second):
+				t.Error("the status of the endpoints waited for the endpoint to be dialed")
+			}
+			close(release)
+			So(<-connected, ShouldBeNil)
+			So(c.pluginRunner.remoteStatus(key)[0].Connected, ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,554 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package control
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/intelsdi-x/snap/core"
+	"github.com/intelsdi-x/snap/core/serror"
+)
+
+const (
+	// repositoryEntryFile is the file describing a stored plugin
+	repositoryEntryFile = "plugin.json"
+	// repositoryRemoteFile is the file recording the standalone plugins
+	repositoryRemoteFile = "remote.json"
+)
+
+var (
+	// ErrRepositoryDisabled - error message when the plugin repository is not
+	// configured
+	ErrRepositoryDisabled = errors.New("plugin repository is disabled")
+
+	// ErrRepositoryPluginNotFound - error message when no stored plugin
+	// matches a checksum
+	ErrRepositoryPluginNotFound = errors.New("plugin not found in repository")
+
+	// ErrRepositoryAmbiguousCheckSum - error message when a checksum prefix
+	// matches several stored plugins
+	ErrRepositoryAmbiguousCheckSum = errors.New("checksum matches several plugins in repository")
+)
+
+// pluginRepository stores the plugins loaded through the REST API in a
+// directory named after the SHA-256 of each plugin, along with its signature
+// and TLS settings, so that they are loaded again when snapteld restarts.
+type pluginRepository struct {
+	sync.Mutex
+	path string
+}
+
+func newPluginRepository(path string) (*pluginRepository, error) {
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return nil, err
+	}
+	return &pluginRepository{path: path}, nil
+}
+
+// store copies a loaded plugin into the repository, unless it is already
+// stored, and records it as loaded.  Standalone plugins are only recorded
+// along with their endpoints, auto loaded plugins are not stored.
+func (r *pluginRepository) store(rp *core.RequestedPlugin, lp *loadedPlugin) error {
+	if r == nil || rp.AutoLoaded() {
+		return nil
+	}
+	r.Lock()
+	defer r.Unlock()
+	if rp.Uri() != nil {
+		return r.storeRemote(rp, lp)
+	}
+
+	checkSum := rp.CheckSum()
+	sum := hex.EncodeToString(checkSum[:])
+	entry, err := r.read(sum)
+	if err != nil {
+		entry = &core.RepositoryPlugin{
+			CheckSum: sum,
+			FileName: filepath.Base(rp.Path()),
+			Stored:   time.Now(),
+		}
+		if err := os.MkdirAll(filepath.Join(r.path, sum), 0700); err != nil {
+			return err
+		}
+		if entry.Size, err = copyFile(rp.Path(), r.file(entry)); err != nil {
+			os.RemoveAll(filepath.Join(r.path, sum))
+			return err
+		}
+	}
+	entry.Signed = rp.Signature() != nil
+	if entry.Signed {
+		if err := ioutil.WriteFile(r.file(entry)+".asc", rp.Signature(), 0600); err != nil {
+			return err
+		}
+	}
+	entry.Type = lp.TypeName()
+	entry.Name = lp.Name()
+	entry.Version = lp.Version()
+	entry.CertPath = rp.CertPath()
+	entry.KeyPath = rp.KeyPath()
+	entry.CACertPaths = rp.CACertPaths()
+	entry.TLSEnabled = rp.TLSEnabled()
+	entry.Lifecycle = rp.Lifecycle()
+	entry.Loaded = true
+	return r.write(entry)
+}
+
+// unloaded records that a stored plugin is unloaded so that it is not
+// loaded again on restart.
+func (r *pluginRepository) unloaded(lp *loadedPlugin) error {
+	if r == nil || lp.Details == nil {
+		return nil
+	}
+	r.Lock()
+	defer r.Unlock()
+	if lp.Details.Uri != nil {
+		return r.unloadedRemote(lp)
+	}
+
+	entry, err := r.read(hex.EncodeToString(lp.Details.CheckSum[:]))
+	if err != nil {
+		// the plugin is not stored
+		return nil
+	}
+	entry.Loaded = false
+	return r.write(entry)
+}
+
+// storeRemote records a standalone plugin, replacing the record of the
+// plugin with the same first endpoint
+func (r *pluginRepository) storeRemote(rp *core.RequestedPlugin, lp *loadedPlugin) error {
+	entries, err := r.remoteEntries()
+	if err != nil {
+		return err
+	}
+	endpoints := rp.Endpoints()
+	kept := entries[:0]
+	for _, e := range entries {
+		if len(e.Endpoints) > 0 && e.Endpoints[0].URI == endpoints[0].URI {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	kept = append(kept, core.RepositoryPlugin{
+		Type:      lp.TypeName(),
+		Name:      lp.Name(),
+		Version:   lp.Version(),
+		Lifecycle: rp.Lifecycle(),
+		Endpoints: endpoints,
+		Loaded:    true,
+		Stored:    time.Now(),
+	})
+	return r.writeRemote(kept)
+}
+
+// unloadedRemote removes the record of an unloaded standalone plugin
+func (r *pluginRepository) unloadedRemote(lp *loadedPlugin) error {
+	entries, err := r.remoteEntries()
+	if err != nil {
+		return err
+	}
+	kept := entries[:0]
+	for _, e := range entries {
+		if e.Type == lp.TypeName() && e.Name == lp.Name() && e.Version == lp.Version() {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	return r.writeRemote(kept)
+}
+
+// remote returns the recorded standalone plugins
+func (r *pluginRepository) remote() ([]core.RepositoryPlugin, error) {
+	if r == nil {
+		return nil, ErrRepositoryDisabled
+	}
+	r.Lock()
+	defer r.Unlock()
+	return r.remoteEntries()
+}
+
+func (r *pluginRepository) remoteEntries() ([]core.RepositoryPlugin, error) {
+	entries := []core.RepositoryPlugin{}
+	b, err := ioutil.ReadFile(filepath.Join(r.path, repositoryRemoteFile))
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *pluginRepository) writeRemote(entries []core.RepositoryPlugin) error {
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(r.path, repositoryRemoteFile), b, 0600)
+}
+
+// list returns the stored plugins sorted by type, name and version.
+func (r *pluginRepository) list() ([]core.RepositoryPlugin, error) {
+	if r == nil {
+		return nil, ErrRepositoryDisabled
+	}
+	r.Lock()
+	defer r.Unlock()
+	return r.entries()
+}
+
+// find returns the stored plugin whose checksum starts with the given one
+// and the path of the stored file.
+func (r *pluginRepository) find(checkSum string) (core.RepositoryPlugin, string, error) {
+	if r == nil {
+		return core.RepositoryPlugin{}, "", ErrRepositoryDisabled
+	}
+	r.Lock()
+	defer r.Unlock()
+	entries, err := r.entries()
+	if err != nil {
+		return core.RepositoryPlugin{}, "", err
+	}
+	var found []core.RepositoryPlugin
+	for _, e := range entries {
+		if checkSum != "" && strings.HasPrefix(e.CheckSum, checkSum) {
+			found = append(found, e)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return core.RepositoryPlugin{}, "", ErrRepositoryPluginNotFound
+	case 1:
+		return found[0], r.file(&found[0]), nil
+	default:
+		return core.RepositoryPlugin{}, "", ErrRepositoryAmbiguousCheckSum
+	}
+}
+
+// gc removes the stored plugins which are not loaded, keeping the given
+// number of the highest versions of each plugin.  It returns the removed
+// plugins.
+func (r *pluginRepository) gc(keep int) ([]core.RepositoryPlugin, error) {
+	if r == nil {
+		return nil, ErrRepositoryDisabled
+	}
+	r.Lock()
+	defer r.Unlock()
+	entries, err := r.entries()
+	if err != nil {
+		return nil, err
+	}
+	// entries are sorted by version so the lowest versions are removed first
+	unloaded := map[string]int{}
+	for _, e := range entries {
+		if !e.Loaded {
+			unloaded[e.Type+":"+e.Name]++
+		}
+	}
+	removed := []core.RepositoryPlugin{}
+	for _, e := range entries {
+		key := e.Type + ":" + e.Name
+		if e.Loaded || unloaded[key] <= keep {
+			continue
+		}
+		unloaded[key]--
+		if err := os.RemoveAll(filepath.Join(r.path, e.CheckSum)); err != nil {
+			return removed, err
+		}
+		removed = append(removed, e)
+	}
+	return removed, nil
+}
+
+// file returns the path of a stored plugin
+func (r *pluginRepository) file(e *core.RepositoryPlugin) string {
+	return filepath.Join(r.path, e.CheckSum, e.FileName)
+}
+
+func (r *pluginRepository) read(sum string) (*core.RepositoryPlugin, error) {
+	b, err := ioutil.ReadFile(filepath.Join(r.path, sum, repositoryEntryFile))
+	if err != nil {
+		return nil, err
+	}
+	entry := &core.RepositoryPlugin{}
+	if err := json.Unmarshal(b, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (r *pluginRepository) write(e *core.RepositoryPlugin) error {
+	b, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(r.path, e.CheckSum, repositoryEntryFile), b, 0600)
+}
+
+func (r *pluginRepository) entries() ([]core.RepositoryPlugin, error) {
+	dirs, err := ioutil.ReadDir(r.path)
+	if err != nil {
+		return nil, err
+	}
+	entries := repositoryPlugins{}
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		entry, err := r.read(d.Name())
+		if err != nil {
+			controlLogger.WithFields(log.Fields{
+				"_block": "repository-entries",
+				"entry":  d.Name(),
+			}).Warn("skipping invalid repository entry: ", err)
+			continue
+		}
+		entries = append(entries, *entry)
+	}
+	sort.Sort(entries)
+	return entries, nil
+}
+
+// repositoryPlugins sorts the stored plugins by type, name and version
+type repositoryPlugins []core.RepositoryPlugin
+
+func (r repositoryPlugins) Len() int      { return len(r) }
+func (r repositoryPlugins) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r repositoryPlugins) Less(i, j int) bool {
+	if r[i].Type != r[j].Type {
+		return r[i].Type < r[j].Type
+	}
+	if r[i].Name != r[j].Name {
+		return r[i].Name < r[j].Name
+	}
+	if r[i].Version != r[j].Version {
+		return r[i].Version < r[j].Version
+	}
+	return r[i].Stored.Before(r[j].Stored)
+}
+
+func copyFile(src, dst string) (int64, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0700)
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(out, in)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	return n, err
+}
+
+// storePlugin stores a plugin loaded through the REST API in the repository
+func (p *pluginControl) storePlugin(rp *core.RequestedPlugin, lp *loadedPlugin) {
+	if err := p.repository.store(rp, lp); err != nil {
+		controlLogger.WithFields(log.Fields{
+			"_block":         "store-plugin",
+			"plugin-name":    lp.Name(),
+			"plugin-version": lp.Version(),
+			"plugin-type":    lp.TypeName(),
+		}).Error(err)
+	}
+}
+
+// unstorePlugin records that a stored plugin is unloaded
+func (p *pluginControl) unstorePlugin(lp *loadedPlugin) {
+	if err := p.repository.unloaded(lp); err != nil {
+		controlLogger.WithFields(log.Fields{
+			"_block":         "unstore-plugin",
+			"plugin-name":    lp.Name(),
+			"plugin-version": lp.Version(),
+			"plugin-type":    lp.TypeName(),
+		}).Error(err)
+	}
+}
+
+// loadRepository loads the plugins stored in the repository which were
+// loaded when snapteld stopped.
+func (p *pluginControl) loadRepository() {
+	entries, err := p.repository.list()
+	if err != nil {
+		if err != ErrRepositoryDisabled {
+			controlLogger.WithFields(log.Fields{
+				"_block": "load-repository",
+			}).Error(err)
+		}
+		return
+	}
+	for _, e := range entries {
+		if !e.Loaded {
+			continue
+		}
+		f := log.Fields{
+			"_block":         "load-repository",
+			"checksum":       e.CheckSum,
+			"plugin-name":    e.Name,
+			"plugin-version": e.Version,
+			"plugin-type":    e.Type,
+		}
+		rp, err := p.repositoryRequestedPlugin(e)
+		if err != nil {
+			controlLogger.WithFields(f).Error(err)
+			continue
+		}
+		if _, err := p.Load(rp); err != nil {
+			controlLogger.WithFields(f).Error(err)
+			continue
+		}
+		controlLogger.WithFields(f).Info("Loading plugin from repository")
+	}
+	p.loadRemotePlugins()
+}
+
+// loadRemotePlugins connects again to the standalone plugins recorded in the
+// repository, the plugins which cannot be reached are retried in the
+// background.
+func (p *pluginControl) loadRemotePlugins() {
+	entries, err := p.repository.remote()
+	if err != nil {
+		controlLogger.WithFields(log.Fields{
+			"_block": "load-remote-plugins",
+		}).Error(err)
+		return
+	}
+	for _, e := range entries {
+		rp, err := core.NewRequestedRemotePlugin(e.Endpoints)
+		if err != nil {
+			controlLogger.WithFields(log.Fields{
+				"_block":      "load-remote-plugins",
+				"plugin-name": e.Name,
+			}).Error(err)
+			continue
+		}
+		rp.SetLifecycle(e.Lifecycle)
+		go p.loadRemotePlugin(e, rp, p.quitChan)
+	}
+}
+
+// loadRemotePlugin loads a recorded standalone plugin, with an exponential
+// backoff until one of its endpoints is reachable or quit is closed when
+// control stops
+func (p *pluginControl) loadRemotePlugin(e core.RepositoryPlugin, rp *core.RequestedPlugin, quit <-chan struct{}) {
+	f := log.Fields{
+		"_block":         "load-remote-plugin",
+		"plugin-name":    e.Name,
+		"plugin-version": e.Version,
+		"plugin-type":    e.Type,
+		"uri":            rp.Uri().String(),
+	}
+	backoff := remoteInitialBackoff
+	for {
+		_, err := p.Load(rp)
+		if err == nil {
+			controlLogger.WithFields(f).Info("Loading standalone plugin from repository")
+			return
+		}
+		if err.Error() == ErrPluginAlreadyLoaded.Error() {
+			return
+		}
+		controlLogger.WithFields(f).Warnf("standalone plugin not loaded, next attempt in %v: %v", backoff, err)
+		select {
+		case <-quit:
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > remoteMaxBackoff {
+			backoff = remoteMaxBackoff
+		}
+	}
+}
+
+// repositoryRequestedPlugin returns the requested plugin of a stored plugin
+func (p *pluginControl) repositoryRequestedPlugin(e core.RepositoryPlugin) (*core.RequestedPlugin, error) {
+	file := p.repository.file(&e)
+	rp, err := core.NewRequestedPlugin(file, p.GetTempDir(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if e.Signed {
+		if err := rp.ReadSignatureFile(file + ".asc"); err != nil {
+			return nil, err
+		}
+	}
+	rp.SetCertPath(e.CertPath)
+	rp.SetKeyPath(e.KeyPath)
+	rp.SetCACertPaths(e.CACertPaths)
+	rp.SetTLSEnabled(e.TLSEnabled)
+	rp.SetLifecycle(e.Lifecycle)
+	return rp, nil
+}
+
+// PluginRepository returns the plugins stored in the plugin repository
+func (p *pluginControl) PluginRepository() ([]core.RepositoryPlugin, serror.SnapError) {
+	entries, err := p.repository.list()
+	if err != nil {
+		return nil, serror.New(err)
+	}
+	return entries, nil
+}
+
+// GCPluginRepository removes the stored plugins which are not loaded but the
+// given number of the highest versions of each plugin.
+func (p *pluginControl) GCPluginRepository(keep int) ([]core.RepositoryPlugin, serror.SnapError) {
+	removed, err := p.repository.gc(keep)
+	if err != nil {
+		return removed, serror.New(err)
+	}
+	for _, e := range removed {
+		controlLogger.WithFields(log.Fields{
+			"_block":         "gc-repository",
+			"checksum":       e.CheckSum,
+			"plugin-name":    e.Name,
+			"plugin-version": e.Version,
+			"plugin-type":    e.Type,
+		}).Info("plugin removed from repository")
+	}
+	return removed, nil
+}
+
+// ExportPlugin returns a stored plugin given its checksum, or a unique
+// prefix of it, and the path of the stored file.
+func (p *pluginControl) ExportPlugin(checkSum string) (core.RepositoryPlugin, string, serror.SnapError) {
+	entry, path, err := p.repository.find(checkSum)
+	if err != nil {
+		return entry, "", serror.New(err, map[string]interface{}{"checksum": checkSum})
+	}
+	return entry, path, nil
+}
@@ -0,0 +1,163 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package control
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/intelsdi-x/snap/control/plugin"
+	"github.com/intelsdi-x/snap/core"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func storeRepositoryPlugin(r *pluginRepository, sum string, version int, loaded bool) {
+	e := &core.RepositoryPlugin{
+		CheckSum: sum,
+		FileName: "snap-plugin-collector-mock",
+		Type:     "collector",
+		Name:     "mock",
+		Version:  version,
+		Loaded:   loaded,
+		Stored:   time.Now(),
+	}
+	So(os.MkdirAll(filepath.Join(r.path, sum), 0700), ShouldBeNil)
+	So(ioutil.WriteFile(r.file(e), []byte("plugin"), 0700), ShouldBeNil)
+	So(r.write(e), ShouldBeNil)
+}
+
+func TestPluginRepository(t *testing.T) {
+	Convey("Given a plugin repository", t, func() {
+		dir, err := ioutil.TempDir("", "snap-repository-")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		r, err := newPluginRepository(filepath.Join(dir, "plugins"))
+		So(err, ShouldBeNil)
+
+		storeRepositoryPlugin(r, "aaa1", 3, true)
+		storeRepositoryPlugin(r, "aab2", 1, false)
+		storeRepositoryPlugin(r, "bbb3", 2, false)
+
+		Convey("The stored plugins are listed by version", func() {
+			entries, err := r.list()
+			So(err, ShouldBeNil)
+			So(len(entries), ShouldEqual, 3)
+			So(entries[0].Version, ShouldEqual, 1)
+			So(entries[2].Version, ShouldEqual, 3)
+		})
+		Convey("Invalid entries are skipped", func() {
+			So(os.MkdirAll(filepath.Join(r.path, "invalid"), 0700), ShouldBeNil)
+			entries, err := r.list()
+			So(err, ShouldBeNil)
+			So(len(entries), ShouldEqual, 3)
+		})
+		Convey("A stored plugin is found by a prefix of its checksum", func() {
+			e, path, err := r.find("bb")
+			So(err, ShouldBeNil)
+			So(e.Version, ShouldEqual, 2)
+			So(path, ShouldEqual, filepath.Join(r.path, "bbb3", "snap-plugin-collector-mock"))
+
+			_, _, err = r.find("aa")
+			So(err, ShouldEqual, ErrRepositoryAmbiguousCheckSum)
+			_, _, err = r.find("ccc")
+			So(err, ShouldEqual, ErrRepositoryPluginNotFound)
+		})
+		Convey("gc keeps the loaded plugins and the highest unloaded versions", func() {
+			removed, err := r.gc(1)
+			So(err, ShouldBeNil)
+			So(len(removed), ShouldEqual, 1)
+			So(removed[0].CheckSum, ShouldEqual, "aab2")
+
+			entries, err := r.list()
+			So(err, ShouldBeNil)
+			So(len(entries), ShouldEqual, 2)
+
+			removed, err = r.gc(0)
+			So(err, ShouldBeNil)
+			So(len(removed), ShouldEqual, 1)
+			So(removed[0].CheckSum, ShouldEqual, "bbb3")
+		})
+		Convey("Standalone plugins are recorded by their first endpoint", func() {
+			rp, err := core.NewRequestedRemotePlugin([]core.RemoteEndpoint{
+				{URI: "http://127.0.0.1:8183"},
+				{URI: "http://127.0.0.1:8184"},
+			})
+			So(err, ShouldBeNil)
+			lp := &loadedPlugin{
+				Meta:    plugin.PluginMeta{Name: "mock-grpc", Version: 1},
+				Details: &pluginDetails{Uri: rp.Uri()},
+			}
+			So(r.store(rp, lp), ShouldBeNil)
+			lp.Meta.Version = 2
+			So(r.store(rp, lp), ShouldBeNil)
+
+			remote, err := r.remote()
+			So(err, ShouldBeNil)
+			So(len(remote), ShouldEqual, 1)
+			So(remote[0].Version, ShouldEqual, 2)
+			So(remote[0].Endpoints, ShouldResemble, rp.Endpoints())
+			entries, err := r.list()
+			So(err, ShouldBeNil)
+			So(len(entries), ShouldEqual, 3)
+
+			So(r.unloaded(lp), ShouldBeNil)
+			remote, err = r.remote()
+			So(err, ShouldBeNil)
+			So(remote, ShouldBeEmpty)
+		})
+	})
+	Convey("Given a disabled plugin repository", t, func() {
+		var r *pluginRepository
+		_, err := r.list()
+		So(err, ShouldEqual, ErrRepositoryDisabled)
+	})
+}
+
+func TestLoadRemotePlugin(t *testing.T) {
+	Convey("Given a standalone plugin which cannot be reached", t, func() {
+		c := New(GetDefaultConfig())
+		c.Started = true
+		rp, err := core.NewRequestedRemotePlugin([]core.RemoteEndpoint{{URI: "http://127.0.0.1:1"}})
+		So(err, ShouldBeNil)
+		e := core.RepositoryPlugin{Type: "collector", Name: "mock-grpc", Version: 1}
+		quit := make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			c.loadRemotePlugin(e, rp, quit)
+			close(done)
+		}()
+		Convey("the attempts to load it end once control stops", func() {
+			// let a first attempt fail
+			time.Sleep(100 * time.Millisecond)
+			close(quit)
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Error("the attempts to load the plugin did not end")
+			}
+		})
+	})
+}
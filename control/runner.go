@@ -24,6 +24,9 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/intelsdi-x/gomit"
@@ -33,7 +36,7 @@ import (
 	"github.com/intelsdi-x/snap/control/plugin/client"
 	"github.com/intelsdi-x/snap/core"
 	"github.com/intelsdi-x/snap/core/control_event"
-	"github.com/intelsdi-x/snap/pkg/aci"
+	"github.com/intelsdi-x/snap/pkg/bundle"
 )
 
 var (
@@ -65,6 +68,7 @@ var (
 type executablePlugin interface {
 	Run(time.Duration) (plugin.Response, error)
 	Kill() error
+	ListenSocket() string
 }
 
 // Handles events pertaining to plugins and control the runnning state accordingly.
@@ -72,24 +76,36 @@ type runner struct {
 	delegates         []gomit.Delegator
 	emitter           gomit.Emitter
 	monitor           *monitor
+	autoscaler        *autoscaler
 	availablePlugins  *availablePlugins
 	metricCatalog     catalogsMetrics
 	pluginManager     managesPlugins
 	grpcSecurity      client.GRPCSecurity
+	channels          pluginChannels
 	pluginLoadTimeout int
+	// serializes the start of on-demand instances
+	onDemandMutex *sync.Mutex
+	// endpoints of the standalone plugins in use
+	remotes *remotePlugins
 }
 
 func newRunner(opts ...pluginRunnerOpt) *runner {
 	r := &runner{
 		pluginLoadTimeout: defaultPluginLoadTimeout,
 		monitor:           newMonitor(),
+		autoscaler:        newAutoscaler(),
 		availablePlugins:  newAvailablePlugins(),
+		onDemandMutex:     &sync.Mutex{},
+		remotes:           newRemotePlugins(),
 	}
 	mergedOpts := append([]pluginRunnerOpt{}, defaultRunnerOpts...)
 	mergedOpts = append(mergedOpts, opts...)
 	for _, opt := range append(mergedOpts) {
 		opt(r)
 	}
+	if r.channels.sockets == nil {
+		r.channels.sockets = newPluginSockets("")
+	}
 	return r
 }
 
@@ -102,6 +118,14 @@ func OptEnableRunnerTLS(grpcSecurity client.GRPCSecurity) pluginRunnerOpt {
 	}
 }
 
+// OptSetRunnerChannels sets the settings of the gRPC channels to the plugins
+// on the runner
+func OptSetRunnerChannels(channels pluginChannels) pluginRunnerOpt {
+	return func(r *runner) {
+		r.channels = channels
+	}
+}
+
 func optDefaultRunnerSecurity() pluginRunnerOpt {
 	return func(r *runner) {
 		r.grpcSecurity = client.SecurityTLSOff()
@@ -128,6 +152,20 @@ func (r *runner) Monitor() *monitor {
 	return r.monitor
 }
 
+func (r *runner) Autoscaler() *autoscaler {
+	return r.autoscaler
+}
+
+// StartAutoscaler starts evaluating the load of the pools at the given
+// interval.  It is a no-op if the autoscaler is already started.
+func (r *runner) StartAutoscaler(interval time.Duration) {
+	if r.autoscaler.State == AutoscalerStarted {
+		return
+	}
+	r.autoscaler.Option(AutoscaleIntervalOption(interval))
+	r.autoscaler.Start(r)
+}
+
 // SetPluginLoadTimeout sets plugin load timeout
 func (r *runner) SetPluginLoadTimeout(timeout int) {
 	r.pluginLoadTimeout = timeout
@@ -158,6 +196,9 @@ func (r *runner) Start() error {
 
 	// Start the monitor
 	r.monitor.Start(r.availablePlugins)
+
+	// Start connecting again to the lost standalone plugins
+	r.startReconnecting()
 	runnerLog.WithFields(log.Fields{
 		"_block": "start",
 	}).Debug("started")
@@ -171,8 +212,15 @@ func (r *runner) Stop() []error {
 	// Stop the monitor
 	r.monitor.Stop()
 
+	// Stop the autoscaler
+	r.autoscaler.Stop()
+
+	r.stopReconnecting()
+
 	// TODO: Actually stop the plugins
 
+	r.channels.sockets.remove()
+
 	// For each delegate unregister needed handlers
 	for _, del := range r.delegates {
 		e := del.UnregisterHandler(HandlerRegistrationName)
@@ -216,7 +264,7 @@ func (r *runner) startPlugin(p executablePlugin) (*availablePlugin, error) {
 		}
 
 		// build availablePlugin
-		ap, err := newAvailablePlugin(resp, r.emitter, p, r.grpcSecurity)
+		ap, err := newAvailablePlugin(resp, r.emitter, p, r.grpcSecurity, r.channels)
 		if err != nil {
 			resultChan <- result{nil, err}
 			return
@@ -293,6 +341,11 @@ func (r *runner) HandleGomitEvent(e gomit.Event) {
 			pool.Kill(v.Id, "plugin dead")
 		}
 
+		// standalone plugins are connected again rather than restarted
+		if r.remoteLost(v.Key, v.Id) {
+			return
+		}
+
 		if pool.Eligible() {
 			if pool.RestartCount() < MaxPluginRestartCount || MaxPluginRestartCount == -1 {
 				e := r.restartPlugin(v.Key)
@@ -346,6 +399,8 @@ func (r *runner) HandleGomitEvent(e gomit.Event) {
 		if err != nil {
 			return
 		}
+	case *control_event.UnloadPluginEvent:
+		r.forgetRemote(fmt.Sprintf("%s"+core.Separator+"%s"+core.Separator+"%d", core.PluginType(v.Type).String(), v.Name, v.Version))
 	default:
 		runnerLog.WithFields(log.Fields{
 			"_block": "handle-events",
@@ -361,11 +416,11 @@ func (r *runner) runPlugin(name string, details *pluginDetails) error {
 			return err
 		}
 		defer f.Close()
-		tempPath, err := aci.Extract(f)
+		tempPath, err := bundle.Extract(f, "")
 		if err != nil {
 			return err
 		}
-		details.ExecPath = path.Join(tempPath, "rootfs")
+		details.ExecPath = path.Join(tempPath, bundle.RootFS)
 	}
 	commands := make([]string, len(details.Exec))
 	for i, e := range details.Exec {
@@ -434,11 +489,66 @@ func (r *runner) handleUnsubscription(pType, pName string, pVersion int, taskID
 				"error":                   err.Error(),
 			}).Error("unable to get loaded plugin")
 		}
+		// keep the warm instances of an eager plugin running
+		if pool.Count() <= pool.Lifecycle().Warm() {
+			pool.Release(taskID)
+			return nil
+		}
 		pool.SelectAndKill(taskID, "unsubscription event")
 	}
 	return nil
 }
 
+// startOnDemand starts an instance of the on-demand plugin with the given
+// key when its pool has no instance able to serve a request.
+func (r *runner) startOnDemand(key string) error {
+	pool, _ := r.availablePlugins.getPool(key)
+	if pool == nil {
+		// an empty pool is not found for the latest version of a plugin
+		lp, err := r.latestPlugin(key)
+		if err != nil || lp == nil {
+			return nil
+		}
+		key = lp.Key()
+		pool, _ = r.availablePlugins.getPool(key)
+		if pool == nil {
+			return nil
+		}
+	}
+	if !pool.Starved() {
+		return nil
+	}
+	r.onDemandMutex.Lock()
+	defer r.onDemandMutex.Unlock()
+	if !pool.Starved() {
+		return nil
+	}
+	runnerLog.WithFields(log.Fields{
+		"_block": "start-on-demand",
+		"pool":   key,
+	}).Debug("starting an on-demand instance")
+	return r.restartPlugin(key)
+}
+
+// latestPlugin returns the loaded plugin of the highest version for a key
+// {plugin_type}:{plugin_name}:{plugin_version} with a version lower than 1.
+func (r *runner) latestPlugin(key string) (*loadedPlugin, error) {
+	tnv := strings.Split(key, core.Separator)
+	if len(tnv) != 3 {
+		return nil, ErrBadKey
+	}
+	if v, err := strconv.Atoi(tnv[2]); err != nil || v > 0 {
+		return nil, nil
+	}
+	var latest *loadedPlugin
+	for _, lp := range r.pluginManager.all() {
+		if lp.TypeName() == tnv[0] && lp.Name() == tnv[1] && (latest == nil || lp.Version() > latest.Version()) {
+			latest = lp
+		}
+	}
+	return latest, nil
+}
+
 func (r *runner) restartPlugin(key string) error {
 	lp, err := r.pluginManager.get(key)
 	if err != nil {
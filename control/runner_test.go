@@ -72,6 +72,10 @@ func (m *MockExecutablePlugin) Wait() error {
 	return nil
 }
 
+func (m *MockExecutablePlugin) ListenSocket() string {
+	return ""
+}
+
 func (m *MockExecutablePlugin) Run(t time.Duration) (plugin.Response, error) {
 	if m.Timeout {
 		return plugin.Response{}, errors.New("timeout")
@@ -0,0 +1,227 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package strategy
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/intelsdi-x/snap/core"
+)
+
+var (
+	// AutoscalingEnabled turns on growing and shrinking pools based on load.
+	// It is initialized at runtime via the snapteld config.
+	AutoscalingEnabled = false
+
+	// DefaultAutoscalePolicy is the policy of the pools for which no plugin
+	// specific policy is defined in AutoscalePolicies.
+	DefaultAutoscalePolicy = AutoscalePolicy{}
+
+	// AutoscalePolicies holds the plugin specific policies keyed by
+	// {plugin_type}:{plugin_name}.
+	AutoscalePolicies = map[string]AutoscalePolicy{}
+)
+
+// latencyWeight is the weight of the last request in the average latency of a pool.
+const latencyWeight = 0.2
+
+// AutoscalePolicy defines when the pool of a plugin grows and shrinks.
+type AutoscalePolicy struct {
+	// Min is the number of instances never reaped, at least 1.
+	Min int
+	// Max is the maximum number of instances, MaximumRunningPlugins when 0.
+	Max int
+	// LatencyThreshold is the average request latency above which the pool
+	// grows.  Zero disables the check.
+	LatencyThreshold time.Duration
+	// InFlightThreshold is the number of requests in flight per instance
+	// above which the pool grows.  Zero disables the check.
+	InFlightThreshold int
+	// IdleTimeout is the time after which an instance not used is reaped.
+	// Zero disables reaping.
+	IdleTimeout time.Duration
+}
+
+// autoscalePolicy returns the policy for the pool with the given key:
+// {plugin_type}{core.Separator}{plugin_name}{core.Separator}{plugin_version}
+func autoscalePolicy(key string) AutoscalePolicy {
+	policy := DefaultAutoscalePolicy
+	if tnv := strings.Split(key, core.Separator); len(tnv) == 3 {
+		if p, ok := AutoscalePolicies[tnv[0]+":"+tnv[1]]; ok {
+			if p.Min > 0 {
+				policy.Min = p.Min
+			}
+			if p.Max > 0 {
+				policy.Max = p.Max
+			}
+		}
+	}
+	if policy.Min < 1 {
+		policy.Min = 1
+	}
+	return policy
+}
+
+// RequestStarted records the start of a request to the pool.
+func (p *pool) RequestStarted() {
+	p.loadMutex.Lock()
+	defer p.loadMutex.Unlock()
+	p.inFlight++
+}
+
+// RequestFinished records the end of a request to the pool and its latency.
+func (p *pool) RequestFinished(latency time.Duration) {
+	p.loadMutex.Lock()
+	defer p.loadMutex.Unlock()
+	p.inFlight--
+	if p.latency == 0 {
+		p.latency = latency
+		return
+	}
+	p.latency += time.Duration(latencyWeight * float64(latency-p.latency))
+}
+
+// Load returns the number of requests in flight and the average latency
+// of the requests to the pool.
+func (p *pool) Load() (int, time.Duration) {
+	p.loadMutex.Lock()
+	defer p.loadMutex.Unlock()
+	return p.inFlight, p.latency
+}
+
+// autoscaled returns a bool indicating whether the pool grows and shrinks
+// on load.  Streaming pools and strategies pinning tasks or configs to
+// instances keep growing on subscriptions.
+func (p *pool) autoscaled() bool {
+	if !AutoscalingEnabled || p.streaming {
+		return false
+	}
+	switch p.RoutingAndCaching.(type) {
+	case *lru, *consistentHash:
+		return true
+	}
+	return false
+}
+
+// Overloaded returns a bool indicating whether the pool has less instances
+// than the minimum of its autoscale policy or its load exceeds the
+// thresholds of the policy, and the pool may grow.
+func (p *pool) Overloaded() bool {
+	p.RLock()
+	autoscaled := p.autoscaled()
+	count, max, subs := len(p.plugins), p.max, len(p.subs)
+	p.RUnlock()
+	// empty pools grow on subscription
+	if !autoscaled || count == 0 || count >= max || subs == 0 {
+		return false
+	}
+	if count < p.policy.Min {
+		return true
+	}
+	inFlight, latency := p.Load()
+	if p.policy.InFlightThreshold > 0 && inFlight > p.policy.InFlightThreshold*count {
+		return true
+	}
+	if p.policy.LatencyThreshold > 0 && latency > p.policy.LatencyThreshold {
+		return true
+	}
+	return false
+}
+
+// Reap stops, kills and removes from the pool the instances which were not
+// used for longer than the idle timeout, leaving at least the minimum number
+// of instances.  It returns the number of instances reaped.  Autoscaled
+// pools are reaped down to the minimum of their autoscale policy, eager
+// pools keep their warm instances and on-demand pools are emptied once all
+// their instances are idle.
+func (p *pool) Reap(now time.Time, reason string) int {
+	// requests hold a read lock on the pool while using an instance
+	p.Lock()
+	min, timeout := p.reapLimits(now)
+	idle := []AvailablePlugin{}
+	if timeout > 0 {
+		reapable := len(p.plugins) - min
+		for id, ap := range p.plugins {
+			if len(idle) >= reapable {
+				break
+			}
+			if now.Sub(ap.LastHit()) > timeout {
+				idle = append(idle, ap)
+				delete(p.plugins, id)
+			}
+		}
+	}
+	p.Unlock()
+
+	for _, ap := range idle {
+		log.WithFields(log.Fields{
+			"_block": "Reap",
+			"reason": reason,
+		}).Debug(fmt.Sprintf("reaping plugin '%v:%v' from pool '%v'", ap.Name(), ap.Version(), p.String()))
+		if err := ap.Stop(reason); err != nil {
+			log.WithFields(log.Fields{
+				"_block": "Reap",
+				"reason": reason,
+			}).Error(err)
+		}
+		if err := ap.Kill(reason); err != nil {
+			log.WithFields(log.Fields{
+				"_block": "Reap",
+				"reason": reason,
+			}).Error(err)
+		}
+	}
+	return len(idle)
+}
+
+// reapLimits returns the number of instances to keep and the idle timeout
+// of the pool, zero if its instances are not reaped.
+func (p *pool) reapLimits(now time.Time) (int, time.Duration) {
+	min, timeout := 0, time.Duration(0)
+	if p.autoscaled() {
+		min, timeout = p.policy.Min, p.policy.IdleTimeout
+	}
+	switch p.lifecycle.Mode {
+	case EagerLifecycle:
+		if min < p.lifecycle.WarmInstances {
+			min = p.lifecycle.WarmInstances
+		}
+	case OnDemandLifecycle:
+		// streams do not record the use of their instances
+		if p.streaming || p.lifecycle.IdleTimeout <= 0 {
+			break
+		}
+		allIdle := true
+		for _, ap := range p.plugins {
+			if now.Sub(ap.LastHit()) <= p.lifecycle.IdleTimeout {
+				allIdle = false
+				break
+			}
+		}
+		if allIdle {
+			min, timeout = 0, p.lifecycle.IdleTimeout
+		}
+	}
+	return min, timeout
+}
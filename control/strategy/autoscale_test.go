@@ -0,0 +1,158 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package strategy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/intelsdi-x/snap/control/plugin"
+	. "github.com/intelsdi-x/snap/control/strategy/fixtures"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func newAutoscaledPool(strategy plugin.RoutingStrategyType, lastHits ...time.Time) Pool {
+	var pool Pool
+	for i, last := range lastHits {
+		plg := NewMockAvailablePlugin().
+			WithPluginType(plugin.CollectorPluginType).
+			WithStrategy(strategy).
+			WithID(uint32(i)).
+			WithLastHit(last)
+		if pool == nil {
+			pool, _ = NewPool(plg.String())
+		}
+		pool.Insert(plg)
+	}
+	return pool
+}
+
+func TestAutoscalePolicy(t *testing.T) {
+	Convey("Given autoscale policies", t, func() {
+		defer func(d AutoscalePolicy, p map[string]AutoscalePolicy) {
+			DefaultAutoscalePolicy, AutoscalePolicies = d, p
+		}(DefaultAutoscalePolicy, AutoscalePolicies)
+		DefaultAutoscalePolicy = AutoscalePolicy{IdleTimeout: time.Minute}
+		AutoscalePolicies = map[string]AutoscalePolicy{
+			"collector:mock": {Min: 2, Max: 5},
+		}
+		Convey("The plugin specific limits are applied", func() {
+			p := autoscalePolicy(NewMockAvailablePlugin().WithPluginType(plugin.CollectorPluginType).String())
+			So(p.Min, ShouldEqual, 2)
+			So(p.Max, ShouldEqual, 5)
+			So(p.IdleTimeout, ShouldEqual, time.Minute)
+		})
+		Convey("The default policy is applied to other plugins", func() {
+			p := autoscalePolicy(NewMockAvailablePlugin().WithPluginType(plugin.CollectorPluginType).WithName("other").String())
+			So(p.Min, ShouldEqual, 1)
+			So(p.Max, ShouldEqual, 0)
+			So(p.IdleTimeout, ShouldEqual, time.Minute)
+		})
+	})
+}
+
+func TestPoolAutoscaling(t *testing.T) {
+	Convey("Given autoscaling is enabled", t, func() {
+		defer func(e bool, d AutoscalePolicy, p map[string]AutoscalePolicy) {
+			AutoscalingEnabled, DefaultAutoscalePolicy, AutoscalePolicies = e, d, p
+		}(AutoscalingEnabled, DefaultAutoscalePolicy, AutoscalePolicies)
+		AutoscalingEnabled = true
+		DefaultAutoscalePolicy = AutoscalePolicy{
+			LatencyThreshold:  100 * time.Millisecond,
+			InFlightThreshold: 2,
+			IdleTimeout:       time.Minute,
+		}
+		AutoscalePolicies = map[string]AutoscalePolicy{}
+		now := time.Now()
+
+		Convey("The pool does not grow on subscriptions once it has an instance", func() {
+			pool := newAutoscaledPool(plugin.DefaultRouting, now)
+			pool.Subscribe("1")
+			pool.Subscribe("2")
+			So(pool.Eligible(), ShouldBeFalse)
+			So(pool.Overloaded(), ShouldBeFalse)
+		})
+		Convey("The pool grows when requests are in flight", func() {
+			pool := newAutoscaledPool(plugin.DefaultRouting, now)
+			pool.Subscribe("1")
+			for i := 0; i < 3; i++ {
+				pool.RequestStarted()
+			}
+			So(pool.Overloaded(), ShouldBeTrue)
+			pool.RequestFinished(time.Millisecond)
+			So(pool.Overloaded(), ShouldBeFalse)
+		})
+		Convey("The pool grows when the average latency is too high", func() {
+			pool := newAutoscaledPool(plugin.DefaultRouting, now)
+			pool.Subscribe("1")
+			pool.RequestStarted()
+			pool.RequestFinished(time.Second)
+			So(pool.Overloaded(), ShouldBeTrue)
+			for i := 0; i < 20; i++ {
+				pool.RequestStarted()
+				pool.RequestFinished(time.Millisecond)
+			}
+			So(pool.Overloaded(), ShouldBeFalse)
+		})
+		Convey("The pool does not grow beyond the maximum of the policy", func() {
+			AutoscalePolicies["collector:mock"] = AutoscalePolicy{Max: 2}
+			pool := newAutoscaledPool(plugin.DefaultRouting, now, now)
+			pool.Subscribe("1")
+			pool.RequestStarted()
+			pool.RequestFinished(time.Second)
+			So(pool.Overloaded(), ShouldBeFalse)
+		})
+		Convey("The pool grows to the minimum of the policy", func() {
+			AutoscalePolicies["collector:mock"] = AutoscalePolicy{Min: 2}
+			pool := newAutoscaledPool(plugin.DefaultRouting, now)
+			pool.Subscribe("1")
+			So(pool.Overloaded(), ShouldBeTrue)
+		})
+		Convey("Idle instances are reaped down to the minimum", func() {
+			idle := now.Add(-2 * time.Minute)
+			pool := newAutoscaledPool(plugin.DefaultRouting, idle, idle, now)
+			So(pool.Reap(now, "idle"), ShouldEqual, 2)
+			So(pool.Count(), ShouldEqual, 1)
+			So(pool.Reap(now, "idle"), ShouldEqual, 0)
+		})
+		Convey("The last instance is not reaped", func() {
+			idle := now.Add(-2 * time.Minute)
+			pool := newAutoscaledPool(plugin.DefaultRouting, idle)
+			So(pool.Reap(now, "idle"), ShouldEqual, 0)
+			So(pool.Count(), ShouldEqual, 1)
+		})
+		Convey("Instances pinned by the strategy are not reaped", func() {
+			idle := now.Add(-2 * time.Minute)
+			pool := newAutoscaledPool(plugin.StickyRouting, idle, idle)
+			So(pool.Reap(now, "idle"), ShouldEqual, 0)
+			So(pool.Count(), ShouldEqual, 2)
+		})
+	})
+	Convey("Given autoscaling is disabled", t, func() {
+		pool := newAutoscaledPool(plugin.DefaultRouting, time.Time{}, time.Time{})
+		pool.Subscribe("1")
+		pool.RequestStarted()
+		pool.RequestFinished(time.Hour)
+		So(pool.Overloaded(), ShouldBeFalse)
+		So(pool.Reap(time.Now(), "idle"), ShouldEqual, 0)
+	})
+}
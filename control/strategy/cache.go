@@ -20,11 +20,18 @@ limitations under the License.
 package strategy
 
 import (
+	"container/list"
 	"errors"
+	"expvar"
 	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/intelsdi-x/snap/core"
+	"github.com/intelsdi-x/snap/core/ctypes"
 	"github.com/intelsdi-x/snap/pkg/chrono"
 	log "github.com/sirupsen/logrus"
 )
@@ -33,41 +40,195 @@ import (
 // A plugin can override the GlobalCacheExpiration (default).
 var GlobalCacheExpiration time.Duration
 
+var (
+	// GlobalCacheMaxEntries is the maximum number of entries held by the
+	// metric cache shared by all plugins. Zero means no limit.
+	GlobalCacheMaxEntries int
+	// GlobalCacheMaxBytes is the approximate maximum size in bytes of the
+	// metric cache shared by all plugins. Zero means no limit.
+	GlobalCacheMaxBytes int64
+)
+
 var (
 	cacheLog = log.WithField("_module", "routing-cache")
 
 	ErrCacheEntryDoesNotExist = errors.New("cache entry does not exist")
+
+	// metricCache is the store backing every cache created by the strategies.
+	// It accounts for the entries and bytes held on behalf of each plugin and
+	// evicts the least recently used entries when a limit is reached.
+	metricCache = newCacheStore()
 )
 
+func init() {
+	expvar.Publish("metric_cache", expvar.Func(func() interface{} {
+		return CacheStats()
+	}))
+}
+
+// cacheEntryOverhead approximates the memory taken by a cache entry
+// regardless of the metrics it holds.
+const cacheEntryOverhead = 128
+
 type cachecell struct {
 	time    time.Time
 	metric  core.Metric
 	metrics []core.Metric
 	hits    uint64
 	misses  uint64
+
+	// bookkeeping for the shared store
+	owner *cache
+	key   string
+	size  int64
+	elem  *list.Element
+}
+
+type cacheStore struct {
+	*sync.Mutex
+	lru   *list.List
+	bytes int64
+	stats map[string]*core.CacheStats
+}
+
+func newCacheStore() *cacheStore {
+	return &cacheStore{
+		Mutex: &sync.Mutex{},
+		lru:   list.New(),
+		stats: map[string]*core.CacheStats{},
+	}
 }
 
+// pluginStats returns the stats for the given plugin.
+// the method is not thread safe, it should be protected outside of the body
+func (s *cacheStore) pluginStats(plugin string) *core.CacheStats {
+	st, ok := s.stats[plugin]
+	if !ok {
+		st = &core.CacheStats{Plugin: plugin}
+		s.stats[plugin] = st
+	}
+	return st
+}
+
+// insert adds a cell to the store and evicts entries if a limit is exceeded.
+// the method is not thread safe, it should be protected outside of the body
+func (s *cacheStore) insert(c *cache, key string, cell *cachecell) {
+	cell.owner = c
+	cell.key = key
+	cell.size = cacheEntryOverhead + int64(len(key))
+	cell.elem = s.lru.PushFront(cell)
+	c.table[key] = cell
+	st := s.pluginStats(c.plugin)
+	st.Entries++
+	st.Bytes += cell.size
+	s.bytes += cell.size
+	s.evict()
+}
+
+// resize updates the accounted size of a cell after its data was replaced.
+// the method is not thread safe, it should be protected outside of the body
+func (s *cacheStore) resize(cell *cachecell, size int64) {
+	size += cacheEntryOverhead + int64(len(cell.key))
+	st := s.pluginStats(cell.owner.plugin)
+	st.Bytes += size - cell.size
+	s.bytes += size - cell.size
+	cell.size = size
+	s.lru.MoveToFront(cell.elem)
+	s.evict()
+}
+
+// remove removes a cell from the store and from the cache owning it.
+// the method is not thread safe, it should be protected outside of the body
+func (s *cacheStore) remove(cell *cachecell) {
+	delete(cell.owner.table, cell.key)
+	if cell.elem == nil {
+		// a placeholder counting misses, never stored
+		return
+	}
+	s.lru.Remove(cell.elem)
+	st := s.pluginStats(cell.owner.plugin)
+	st.Entries--
+	st.Bytes -= cell.size
+	s.bytes -= cell.size
+}
+
+// evict removes the least recently used cells until the store is within its limits.
+// the method is not thread safe, it should be protected outside of the body
+func (s *cacheStore) evict() {
+	for s.lru.Len() > 0 &&
+		((GlobalCacheMaxEntries > 0 && s.lru.Len() > GlobalCacheMaxEntries) ||
+			(GlobalCacheMaxBytes > 0 && s.bytes > GlobalCacheMaxBytes)) {
+		cell := s.lru.Back().Value.(*cachecell)
+		s.remove(cell)
+		s.pluginStats(cell.owner.plugin).Evictions++
+		cacheLog.WithFields(log.Fields{
+			"_block":    "evict",
+			"namespace": cell.key,
+			"plugin":    cell.owner.plugin,
+		}).Debug(fmt.Sprintf("cache eviction [%s]", cell.key))
+	}
+}
+
+// CacheStats returns the usage of the metric cache for each plugin
+// sorted by plugin key.
+func CacheStats() []core.CacheStats {
+	metricCache.Lock()
+	defer metricCache.Unlock()
+	stats := make([]core.CacheStats, 0, len(metricCache.stats))
+	for _, st := range metricCache.stats {
+		stats = append(stats, *st)
+	}
+	sort.Sort(byPlugin(stats))
+	return stats
+}
+
+type byPlugin []core.CacheStats
+
+func (b byPlugin) Len() int           { return len(b) }
+func (b byPlugin) Less(i, j int) bool { return b[i].Plugin < b[j].Plugin }
+func (b byPlugin) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+
 type cache struct {
-	table map[string]*cachecell
-	ttl   time.Duration
+	table  map[string]*cachecell
+	ttl    time.Duration
+	plugin string
+	store  *cacheStore
 }
 
 func NewCache(expiration time.Duration) *cache {
+	return newPluginCache("", expiration)
+}
+
+// newPluginCache returns a cache which entries are accounted to the given plugin.
+func newPluginCache(plugin string, expiration time.Duration) *cache {
 	return &cache{
-		table: make(map[string]*cachecell),
-		ttl:   expiration,
+		table:  make(map[string]*cachecell),
+		ttl:    expiration,
+		plugin: plugin,
+		store:  metricCache,
 	}
 }
 
 func (c *cache) get(ns string, version int) interface{} {
+	return c.getScoped(ns, version, "")
+}
+
+// getScoped returns the cached metrics for the namespace and version
+// requested within the given scope.
+func (c *cache) getScoped(ns string, version int, scope string) interface{} {
+	c.store.Lock()
+	defer c.store.Unlock()
 	var (
 		cell *cachecell
 		ok   bool
 	)
 
-	key := fmt.Sprintf("%v:%v", ns, version)
+	key := cacheKey(ns, version, scope)
+	st := c.store.pluginStats(c.plugin)
 	if cell, ok = c.table[key]; ok && chrono.Chrono.Now().Sub(cell.time) < c.ttl {
 		cell.hits++
+		st.Hits++
+		c.store.lru.MoveToFront(cell.elem)
 		cacheLog.WithFields(log.Fields{
 			"namespace": key,
 			"hits":      cell.hits,
@@ -79,42 +240,48 @@ func (c *cache) get(ns string, version int) interface{} {
 		return cell.metrics
 	}
 	if !ok {
-		c.table[key] = &cachecell{
-			time:    time.Time{},
-			metrics: nil,
-		}
+		// the placeholder counting the misses of the key is only stored
+		// once metrics are put in it, until then it is neither sized nor
+		// evicted
+		cell = &cachecell{owner: c, key: key}
+		c.table[key] = cell
 	}
-	c.table[key].misses++
+	st.Misses++
+	cell.misses++
 	cacheLog.WithFields(log.Fields{
 		"namespace": key,
-		"hits":      c.table[key].hits,
-		"misses":    c.table[key].misses,
+		"hits":      cell.hits,
+		"misses":    cell.misses,
 	}).Debug(fmt.Sprintf("cache miss [%s]", key))
 	return nil
 }
 
 func (c *cache) put(ns string, version int, m interface{}) {
-	key := fmt.Sprintf("%v:%v", ns, version)
+	c.putScoped(ns, version, "", m)
+}
+
+// putScoped caches the metrics for the namespace and version within the
+// given scope.
+func (c *cache) putScoped(ns string, version int, scope string, m interface{}) {
+	c.store.Lock()
+	defer c.store.Unlock()
+	key := cacheKey(ns, version, scope)
 	switch metric := m.(type) {
 	case core.Metric:
-		if _, ok := c.table[key]; ok {
-			c.table[key].time = chrono.Chrono.Now()
-			c.table[key].metric = metric
-		} else {
-			c.table[key] = &cachecell{
-				time:   chrono.Chrono.Now(),
-				metric: metric,
-			}
+		if cell, ok := c.stored(key); ok {
+			cell.time = chrono.Chrono.Now()
+			cell.metric = metric
+			c.store.resize(cell, metricSize(metric))
 		}
 	case []core.Metric:
-		if _, ok := c.table[key]; ok {
-			c.table[key].time = chrono.Chrono.Now()
-			c.table[key].metrics = metric
-		} else {
-			c.table[key] = &cachecell{
-				time:    chrono.Chrono.Now(),
-				metrics: metric,
+		if cell, ok := c.stored(key); ok {
+			cell.time = chrono.Chrono.Now()
+			cell.metrics = metric
+			var size int64
+			for _, mt := range metric {
+				size += metricSize(mt)
 			}
+			c.store.resize(cell, size)
 		}
 	default:
 		cacheLog.WithFields(log.Fields{
@@ -124,9 +291,33 @@ func (c *cache) put(ns string, version int, m interface{}) {
 	}
 }
 
+// stored returns the cell of the key, adding it to the store when it is new
+// or a placeholder counting misses.  ok is false when the cell was evicted
+// straight away.
+// the method is not thread safe, it should be protected outside of the body
+func (c *cache) stored(key string) (*cachecell, bool) {
+	cell, ok := c.table[key]
+	if !ok {
+		cell = &cachecell{}
+	}
+	if cell.elem == nil {
+		c.store.insert(c, key, cell)
+	}
+	cell, ok = c.table[key]
+	return cell, ok
+}
+
+// clear removes all entries of the cache from the shared store.
+func (c *cache) clear() {
+	c.store.Lock()
+	defer c.store.Unlock()
+	for _, cell := range c.table {
+		c.store.remove(cell)
+	}
+}
 func (c *cache) checkCache(mts []core.Metric) (metricsToCollect []core.Metric, fromCache []core.Metric) {
 	for _, mt := range mts {
-		if m := c.get(mt.Namespace().String(), mt.Version()); m != nil {
+		if m := c.getScoped(mt.Namespace().String(), mt.Version(), metricScope(mt)); m != nil {
 			switch metric := m.(type) {
 			case core.Metric:
 				fromCache = append(fromCache, metric)
@@ -150,11 +341,29 @@ type listMetricInfo struct {
 	metrics   []core.Metric
 	namespace string
 	version   int
+	scope     string
 }
 
 func (c *cache) updateCache(mts []core.Metric) {
+	c.updateCollected(nil, mts)
+}
+
+// updateCollected caches the collected metrics within the scope of the
+// metrics requested from the plugin.  The plugin is not required to pass
+// the config and tags along so they are taken from the matching request.
+func (c *cache) updateCollected(requested, collected []core.Metric) {
+	scopes := map[string]string{}
+	for _, mt := range requested {
+		scopes[cacheKey(mt.Namespace().String(), mt.Version(), "")] = metricScope(mt)
+	}
+	scopeOf := func(ns string, mt core.Metric) string {
+		if scope, ok := scopes[cacheKey(ns, mt.Version(), "")]; ok {
+			return scope
+		}
+		return metricScope(mt)
+	}
 	dc := map[string]*listMetricInfo{}
-	for _, mt := range mts {
+	for _, mt := range collected {
 		isDynamic, idx := mt.Namespace().IsDynamic()
 		if isDynamic {
 			// cache dynamic metrics
@@ -163,27 +372,31 @@ func (c *cache) updateCache(mts []core.Metric) {
 			for _, v := range idx {
 				dynNS[v].Value = "*"
 			}
-			key := fmt.Sprintf("%v:%v", dynNS.String(), mt.Version())
+			scope := scopeOf(dynNS.String(), mt)
+			key := cacheKey(dynNS.String(), mt.Version(), scope)
 			if _, ok := dc[key]; !ok {
 				dc[key] = &listMetricInfo{
 					metrics:   []core.Metric{},
 					namespace: dynNS.String(),
 					version:   mt.Version(),
+					scope:     scope,
 				}
 			}
 			dc[key].metrics = append(dc[key].metrics, mt)
 			continue
 		}
 		// cache the individual metric
-		c.put(mt.Namespace().String(), mt.Version(), mt)
+		c.putScoped(mt.Namespace().String(), mt.Version(), scopeOf(mt.Namespace().String(), mt), mt)
 	}
 	// write our dynamic metrics to the cache.
 	for _, v := range dc {
-		c.put(v.namespace, v.version, v.metrics)
+		c.putScoped(v.namespace, v.version, v.scope, v.metrics)
 	}
 }
 
 func (c *cache) allCacheHits() uint64 {
+	c.store.Lock()
+	defer c.store.Unlock()
 	var hits uint64
 	for _, v := range c.table {
 		hits += v.hits
@@ -192,6 +405,8 @@ func (c *cache) allCacheHits() uint64 {
 }
 
 func (c *cache) allCacheMisses() uint64 {
+	c.store.Lock()
+	defer c.store.Unlock()
 	var misses uint64
 	for _, v := range c.table {
 		misses += v.misses
@@ -199,18 +414,186 @@ func (c *cache) allCacheMisses() uint64 {
 	return misses
 }
 
+// cacheHits returns the cache hits for the namespace and version across
+// all scopes.
 func (c *cache) cacheHits(ns string, version int) (uint64, error) {
-	key := fmt.Sprintf("%v:%v", ns, version)
-	if v, ok := c.table[key]; ok {
-		return v.hits, nil
+	c.store.Lock()
+	defer c.store.Unlock()
+	var (
+		hits  uint64
+		found bool
+	)
+	for _, cell := range c.cells(ns, version) {
+		hits += cell.hits
+		found = true
 	}
-	return 0, ErrCacheEntryDoesNotExist
+	if !found {
+		return 0, ErrCacheEntryDoesNotExist
+	}
+	return hits, nil
 }
 
+// cacheMisses returns the cache misses for the namespace and version across
+// all scopes.
 func (c *cache) cacheMisses(ns string, version int) (uint64, error) {
-	key := fmt.Sprintf("%v:%v", ns, version)
-	if v, ok := c.table[key]; ok {
-		return v.misses, nil
+	c.store.Lock()
+	defer c.store.Unlock()
+	var (
+		misses uint64
+		found  bool
+	)
+	for _, cell := range c.cells(ns, version) {
+		misses += cell.misses
+		found = true
+	}
+	if !found {
+		return 0, ErrCacheEntryDoesNotExist
+	}
+	return misses, nil
+}
+
+// cells returns the cells for the namespace and version across all scopes.
+// the method is not thread safe, it should be protected outside of the body
+func (c *cache) cells(ns string, version int) []*cachecell {
+	key := cacheKey(ns, version, "")
+	cells := []*cachecell{}
+	for k, cell := range c.table {
+		if k == key || strings.HasPrefix(k, key+scopeSeparator) {
+			cells = append(cells, cell)
+		}
+	}
+	return cells
+}
+
+// scopeSeparator separates the namespace and version from the scope in a cache key
+const scopeSeparator = "#"
+
+// cacheKey returns the key of a cache entry.  Metrics requested with a config
+// or tags are cached within a scope so that requests which differ only by
+// their config or tags are never served each other's metrics.
+func cacheKey(ns string, version int, scope string) string {
+	if scope == "" {
+		return fmt.Sprintf("%v:%v", ns, version)
+	}
+	return fmt.Sprintf("%v:%v%v%v", ns, version, scopeSeparator, scope)
+}
+
+// metricScope returns a digest of the config and tags of a metric or an
+// empty string when the metric has neither.
+func metricScope(m core.Metric) string {
+	var table map[string]ctypes.ConfigValue
+	if cfg := m.Config(); cfg != nil {
+		table = cfg.Table()
+	}
+	tags := m.Tags()
+	if len(table) == 0 && len(tags) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(table)+len(tags))
+	for k, v := range table {
+		parts = append(parts, fmt.Sprintf("c:%s=%s:%v", k, v.Type(), v))
+	}
+	for k, v := range tags {
+		parts = append(parts, fmt.Sprintf("t:%s=%s", k, v))
+	}
+	sort.Strings(parts)
+	h := fnv.New64a()
+	h.Write([]byte(strings.Join(parts, "\x00")))
+	return fmt.Sprintf("%x", h.Sum64())
+}
+
+// cacheMap holds the caches of a strategy keyed by id
+type cacheMap struct {
+	*sync.Mutex
+	caches map[string]*cache
+	plugin string
+	ttl    time.Duration
+}
+
+func newCacheMap(ttl time.Duration) *cacheMap {
+	return &cacheMap{
+		Mutex:  &sync.Mutex{},
+		caches: map[string]*cache{},
+		ttl:    ttl,
+	}
+}
+
+// setPlugin sets the plugin the caches are accounted to.
+func (m *cacheMap) setPlugin(plugin string) {
+	m.Lock()
+	defer m.Unlock()
+	m.plugin = plugin
+}
+
+// get returns the cache for the given id, creating it if needed.
+func (m *cacheMap) get(id string) *cache {
+	m.Lock()
+	defer m.Unlock()
+	if _, ok := m.caches[id]; !ok {
+		m.caches[id] = newPluginCache(m.plugin, m.ttl)
+	}
+	return m.caches[id]
+}
+
+// lookup returns the cache for the given id if it exists.
+func (m *cacheMap) lookup(id string) (*cache, bool) {
+	m.Lock()
+	defer m.Unlock()
+	c, ok := m.caches[id]
+	return c, ok
+}
+
+// remove releases the entries of the cache for the given id and removes it.
+func (m *cacheMap) remove(id string) {
+	m.Lock()
+	defer m.Unlock()
+	if c, ok := m.caches[id]; ok {
+		c.clear()
+		delete(m.caches, id)
+	}
+}
+
+// all returns all the caches.
+func (m *cacheMap) all() []*cache {
+	m.Lock()
+	defer m.Unlock()
+	caches := make([]*cache, 0, len(m.caches))
+	for _, c := range m.caches {
+		caches = append(caches, c)
+	}
+	return caches
+}
+
+// metricSize approximates the memory taken by a metric.
+func metricSize(m core.Metric) int64 {
+	size := int64(64)
+	for _, e := range m.Namespace() {
+		size += int64(len(e.Value) + len(e.Name) + len(e.Description))
+	}
+	for k, v := range m.Tags() {
+		size += int64(len(k) + len(v))
+	}
+	size += int64(len(m.Unit()) + len(m.Description()))
+	switch d := m.Data().(type) {
+	case string:
+		size += int64(len(d))
+	case []byte:
+		size += int64(len(d))
+	case core.Histogram:
+		size += int64(16 * (len(d.Bounds) + 2))
+	case core.Summary:
+		size += int64(16 * (len(d.Quantiles) + 1))
+	case map[string]float64:
+		for k := range d {
+			size += int64(len(k) + 8)
+		}
+	case map[string]string:
+		for k, v := range d {
+			size += int64(len(k) + len(v))
+		}
+	case nil:
+	default:
+		size += 8
 	}
-	return 0, ErrCacheEntryDoesNotExist
+	return size
 }
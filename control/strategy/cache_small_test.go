@@ -22,11 +22,17 @@ limitations under the License.
 package strategy
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/intelsdi-x/snap/control/fixtures"
+	"github.com/intelsdi-x/snap/control/plugin"
+	strategyfixtures "github.com/intelsdi-x/snap/control/strategy/fixtures"
 	"github.com/intelsdi-x/snap/core"
+	"github.com/intelsdi-x/snap/core/cdata"
+	"github.com/intelsdi-x/snap/core/ctypes"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -127,3 +133,181 @@ func TestUpdateCache(t *testing.T) {
 		})
 	})
 }
+
+func TestCacheEviction(t *testing.T) {
+	Convey("Given caches of two plugins sharing a bounded store", t, func() {
+		defer func(e int, b int64) {
+			GlobalCacheMaxEntries, GlobalCacheMaxBytes = e, b
+		}(GlobalCacheMaxEntries, GlobalCacheMaxBytes)
+		GlobalCacheMaxEntries, GlobalCacheMaxBytes = 3, 0
+		store := newCacheStore()
+		foo := newPluginCache("collector:foo:1", time.Second)
+		foo.store = store
+		bar := newPluginCache("collector:bar:1", time.Second)
+		bar.store = store
+		mt := func(elems ...string) core.Metric {
+			return fixtures.MockMetricType{Namespace_: core.NewNamespace(elems...)}
+		}
+
+		foo.updateCache([]core.Metric{mt("foo", "a"), mt("foo", "b")})
+		bar.updateCache([]core.Metric{mt("bar", "a")})
+		So(store.lru.Len(), ShouldEqual, 3)
+
+		Convey("The least recently used entry is evicted when the limit is reached", func() {
+			// touch /foo/a so that /foo/b becomes the least recently used
+			toCollect, fromCache := foo.checkCache([]core.Metric{mt("foo", "a")})
+			So(toCollect, ShouldBeEmpty)
+			So(fromCache, ShouldHaveLength, 1)
+			bar.updateCache([]core.Metric{mt("bar", "b")})
+			So(store.lru.Len(), ShouldEqual, 3)
+			So(foo.table, ShouldContainKey, "/foo/a:0")
+			So(foo.table, ShouldNotContainKey, "/foo/b:0")
+
+			stats := store.stats["collector:foo:1"]
+			So(stats.Entries, ShouldEqual, 1)
+			So(stats.Evictions, ShouldEqual, 1)
+			So(stats.Hits, ShouldEqual, 1)
+			So(store.stats["collector:bar:1"].Entries, ShouldEqual, 2)
+		})
+		Convey("Entries are evicted when the byte budget is exceeded", func() {
+			GlobalCacheMaxEntries, GlobalCacheMaxBytes = 0, store.bytes
+			foo.updateCache([]core.Metric{mt("foo", "c")})
+			So(store.bytes, ShouldBeLessThanOrEqualTo, GlobalCacheMaxBytes)
+			So(foo.table, ShouldNotContainKey, "/foo/a:0")
+			So(foo.table, ShouldContainKey, "/foo/c:0")
+		})
+		Convey("Misses neither take room in the store nor evict entries", func() {
+			bytes := store.bytes
+			toCollect, _ := foo.checkCache([]core.Metric{mt("foo", "c"), mt("foo", "d")})
+			So(toCollect, ShouldHaveLength, 2)
+			So(store.lru.Len(), ShouldEqual, 3)
+			So(store.bytes, ShouldEqual, bytes)
+			So(foo.table, ShouldContainKey, "/foo/a:0")
+			So(foo.table, ShouldContainKey, "/foo/b:0")
+
+			stats := store.stats["collector:foo:1"]
+			So(stats.Entries, ShouldEqual, 2)
+			So(stats.Misses, ShouldEqual, 2)
+			So(stats.Evictions, ShouldEqual, 0)
+			So(foo.allCacheMisses(), ShouldEqual, 2)
+
+			Convey("and count once metrics are put in their entry", func() {
+				foo.updateCache([]core.Metric{mt("foo", "c")})
+				So(store.lru.Len(), ShouldEqual, 3)
+				So(foo.table["/foo/c:0"].misses, ShouldEqual, 1)
+				So(foo.table, ShouldNotContainKey, "/foo/a:0")
+			})
+		})
+		Convey("Bytes are released when a cache is cleared", func() {
+			So(store.stats["collector:foo:1"].Bytes, ShouldBeGreaterThan, 0)
+			foo.clear()
+			So(foo.table, ShouldBeEmpty)
+			So(store.stats["collector:foo:1"].Entries, ShouldEqual, 0)
+			So(store.stats["collector:foo:1"].Bytes, ShouldEqual, 0)
+			So(store.bytes, ShouldEqual, store.stats["collector:bar:1"].Bytes)
+		})
+	})
+}
+
+func TestCacheStatsHitRatio(t *testing.T) {
+	Convey("Given cache stats", t, func() {
+		So(core.CacheStats{}.HitRatio(), ShouldEqual, 0)
+		So(core.CacheStats{Hits: 3, Misses: 1}.HitRatio(), ShouldEqual, 0.75)
+	})
+}
+
+func TestCacheScope(t *testing.T) {
+	Convey("Given a cache", t, func() {
+		c := NewCache(time.Second)
+		c.store = newCacheStore()
+		ns := core.NewNamespace("foo", "bar")
+		requested := func(host string, tags map[string]string) core.Metric {
+			cfg := cdata.NewNode()
+			cfg.AddItem("host", ctypes.ConfigValueStr{Value: host})
+			return &plugin.MetricType{Namespace_: ns, Config_: cfg, Tags_: tags}
+		}
+		// the plugin does not pass the config and tags along
+		collected := func(host string) core.Metric {
+			return &plugin.MetricType{Namespace_: ns, Data_: host}
+		}
+
+		Convey("Metrics requested with different configs are cached separately", func() {
+			a, b := requested("a", nil), requested("b", nil)
+			c.updateCollected([]core.Metric{a}, []core.Metric{collected("a")})
+			c.updateCollected([]core.Metric{b}, []core.Metric{collected("b")})
+			So(c.table, ShouldHaveLength, 2)
+
+			_, fromCache := c.checkCache([]core.Metric{a})
+			So(fromCache, ShouldHaveLength, 1)
+			So(fromCache[0].Data(), ShouldEqual, "a")
+			_, fromCache = c.checkCache([]core.Metric{b})
+			So(fromCache, ShouldHaveLength, 1)
+			So(fromCache[0].Data(), ShouldEqual, "b")
+
+			hits, err := c.cacheHits(ns.String(), 0)
+			So(err, ShouldBeNil)
+			So(hits, ShouldEqual, 2)
+		})
+		Convey("Metrics requested with the same config are shared", func() {
+			c.updateCollected([]core.Metric{requested("a", nil)}, []core.Metric{collected("a")})
+			toCollect, fromCache := c.checkCache([]core.Metric{requested("a", nil)})
+			So(toCollect, ShouldBeEmpty)
+			So(fromCache, ShouldHaveLength, 1)
+		})
+		Convey("Metrics requested with different tags are cached separately", func() {
+			c.updateCollected([]core.Metric{requested("a", map[string]string{"dc": "east"})}, []core.Metric{collected("a")})
+			toCollect, _ := c.checkCache([]core.Metric{requested("a", map[string]string{"dc": "west"})})
+			So(toCollect, ShouldHaveLength, 1)
+			toCollect, _ = c.checkCache([]core.Metric{requested("a", nil)})
+			So(toCollect, ShouldHaveLength, 1)
+			toCollect, _ = c.checkCache([]core.Metric{requested("a", map[string]string{"dc": "east"})})
+			So(toCollect, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestCacheConcurrentTasks(t *testing.T) {
+	Convey("Given tasks collecting the same metric with different configs from one plugin", t, func() {
+		p1 := strategyfixtures.NewMockAvailablePlugin().WithID(1)
+		pool, err := NewPool(p1.String(), p1)
+		So(err, ShouldBeNil)
+		ns := core.NewNamespace("foo", "bar")
+
+		const tasks = 8
+		errc := make(chan error, tasks)
+		var wg sync.WaitGroup
+		for i := 0; i < tasks; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				taskID := fmt.Sprintf("task-%d", i)
+				host := fmt.Sprintf("host-%d", i%4)
+				cfg := cdata.NewNode()
+				cfg.AddItem("host", ctypes.ConfigValueStr{Value: host})
+				requested := []core.Metric{&plugin.MetricType{Namespace_: ns, Config_: cfg}}
+				id := pool.CacheID(taskID, cfg.Table(), nil)
+				for n := 0; n < 50; n++ {
+					toCollect, fromCache := pool.CheckCache(requested, id)
+					for _, m := range fromCache {
+						if m.Data() != host {
+							errc <- fmt.Errorf("%s served %v instead of %v", taskID, m.Data(), host)
+							return
+						}
+					}
+					if len(toCollect) > 0 {
+						pool.UpdateCache(toCollect, []core.Metric{&plugin.MetricType{Namespace_: ns, Data_: host}}, id)
+					}
+				}
+			}(i)
+		}
+		wg.Wait()
+		close(errc)
+
+		Convey("Then each task is only served metrics collected with its config", func() {
+			for err := range errc {
+				So(err, ShouldBeNil)
+			}
+			So(pool.AllCacheHits(), ShouldBeGreaterThan, 0)
+		})
+	})
+}
@@ -32,14 +32,14 @@ import (
 // config-based provides a strategy that selects plugin based on given config
 type configBased struct {
 	plugins     map[string]AvailablePlugin
-	metricCache map[string]*cache
+	metricCache *cacheMap
 	logger      *log.Entry
 	cacheTTL    time.Duration
 }
 
 func NewConfigBased(cacheTTL time.Duration) *configBased {
 	return &configBased{
-		metricCache: make(map[string]*cache),
+		metricCache: newCacheMap(cacheTTL),
 		plugins:     make(map[string]AvailablePlugin),
 		cacheTTL:    cacheTTL,
 		logger: log.WithFields(log.Fields{
@@ -81,7 +81,7 @@ func (cb *configBased) Remove(aps []AvailablePlugin, id string) (AvailablePlugin
 	if err != nil {
 		return nil, err
 	}
-	delete(cb.metricCache, id)
+	cb.metricCache.remove(id)
 	delete(cb.plugins, id)
 	return ap, nil
 }
@@ -91,6 +91,11 @@ func (cb *configBased) String() string {
 	return "config-based"
 }
 
+// setCachePlugin sets the plugin the cached metrics are accounted to.
+func (cb *configBased) setCachePlugin(key string) {
+	cb.metricCache.setPlugin(key)
+}
+
 // CacheTTL returns the TTL for the cache.
 func (cb *configBased) CacheTTL(id string) (time.Duration, error) {
 	return cb.cacheTTL, nil
@@ -101,24 +106,19 @@ func (cb *configBased) CacheTTL(id string) (time.Duration, error) {
 //  - array of metrics that need to be collected
 //  - array of metrics that were returned from the cache
 func (cb *configBased) CheckCache(mts []core.Metric, id string) ([]core.Metric, []core.Metric) {
-	if _, ok := cb.metricCache[id]; !ok {
-		cb.metricCache[id] = NewCache(cb.cacheTTL)
-	}
-	return cb.metricCache[id].checkCache(mts)
+	return cb.metricCache.get(id).checkCache(mts)
 }
 
-// updateCache updates the cache with the given array of metrics.
-func (cb *configBased) UpdateCache(mts []core.Metric, id string) {
-	if _, ok := cb.metricCache[id]; !ok {
-		cb.metricCache[id] = NewCache(cb.cacheTTL)
-	}
-	cb.metricCache[id].updateCache(mts)
+// updateCache updates the cache with the metrics collected for the
+// requested metrics.
+func (cb *configBased) UpdateCache(requested, collected []core.Metric, id string) {
+	cb.metricCache.get(id).updateCollected(requested, collected)
 }
 
 // AllCacheHits returns cache hits across all metrics.
 func (cb *configBased) AllCacheHits() uint64 {
 	var total uint64
-	for _, cache := range cb.metricCache {
+	for _, cache := range cb.metricCache.all() {
 		total += cache.allCacheHits()
 	}
 	return total
@@ -127,7 +127,7 @@ func (cb *configBased) AllCacheHits() uint64 {
 // AllCacheMisses returns cache misses across all metrics.
 func (cb *configBased) AllCacheMisses() uint64 {
 	var total uint64
-	for _, cache := range cb.metricCache {
+	for _, cache := range cb.metricCache.all() {
 		total += cache.allCacheMisses()
 	}
 	return total
@@ -135,7 +135,7 @@ func (cb *configBased) AllCacheMisses() uint64 {
 
 // CacheHits returns the cache hits for a given metric namespace and version.
 func (cb *configBased) CacheHits(ns string, version int, id string) (uint64, error) {
-	if cache, ok := cb.metricCache[id]; ok {
+	if cache, ok := cb.metricCache.lookup(id); ok {
 		return cache.cacheHits(ns, version)
 	}
 	return 0, ErrCacheDoesNotExist
@@ -143,7 +143,7 @@ func (cb *configBased) CacheHits(ns string, version int, id string) (uint64, err
 
 // CacheMisses returns the cache misses for a given metric namespace and version.
 func (cb *configBased) CacheMisses(ns string, version int, id string) (uint64, error) {
-	if cache, ok := cb.metricCache[id]; ok {
+	if cache, ok := cb.metricCache.lookup(id); ok {
 		return cache.cacheMisses(ns, version)
 	}
 	return 0, ErrCacheDoesNotExist
@@ -0,0 +1,323 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package strategy
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/intelsdi-x/snap/core"
+	"github.com/intelsdi-x/snap/core/ctypes"
+	log "github.com/sirupsen/logrus"
+)
+
+// HashKeySource identifies what the consistent-hash strategy hashes on.
+type HashKeySource int
+
+const (
+	// HashKeyConfig hashes on the config provided to the plugin. When
+	// HashKey.Fields is set only the listed config keys are used.
+	HashKeyConfig HashKeySource = iota
+	// HashKeyTask hashes on the task ID.
+	HashKeyTask
+	// HashKeyTag hashes on the value of the tag named in HashKey.Fields.
+	HashKeyTag
+)
+
+// defaultHashReplicas is the number of points each running plugin
+// instance occupies on the hash ring.
+const defaultHashReplicas = 64
+
+var (
+	// ConsistentHashKey defines the key used by the consistent-hash strategy.
+	// It is initialized at runtime via the snapteld config.
+	ConsistentHashKey = HashKey{Source: HashKeyConfig}
+
+	ErrBadHashKey = errors.New("invalid routing hash key")
+)
+
+// HashKey describes the key used to route requests on the hash ring.
+type HashKey struct {
+	Source HashKeySource
+	Fields []string
+}
+
+// ParseHashKey parses a hash key given in one of the following forms:
+//  - "config" or "config:key1,key2"
+//  - "task"
+//  - "tag:name"
+func ParseHashKey(s string) (HashKey, error) {
+	src := s
+	fields := ""
+	if i := strings.Index(s, ":"); i > -1 {
+		src, fields = s[:i], s[i+1:]
+	}
+	hk := HashKey{}
+	if fields != "" {
+		for _, f := range strings.Split(fields, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				hk.Fields = append(hk.Fields, f)
+			}
+		}
+	}
+	switch strings.TrimSpace(src) {
+	case "config", "":
+		hk.Source = HashKeyConfig
+	case "task":
+		if len(hk.Fields) > 0 {
+			return HashKey{}, fmt.Errorf("%v: task key does not take fields: %v", ErrBadHashKey, s)
+		}
+		hk.Source = HashKeyTask
+	case "tag":
+		if len(hk.Fields) != 1 {
+			return HashKey{}, fmt.Errorf("%v: tag key requires exactly one tag name: %v", ErrBadHashKey, s)
+		}
+		hk.Source = HashKeyTag
+	default:
+		return HashKey{}, fmt.Errorf("%v: %v", ErrBadHashKey, s)
+	}
+	return hk, nil
+}
+
+// String returns the hash key in the form accepted by ParseHashKey.
+func (h HashKey) String() string {
+	var src string
+	switch h.Source {
+	case HashKeyTask:
+		src = "task"
+	case HashKeyTag:
+		src = "tag"
+	default:
+		src = "config"
+	}
+	if len(h.Fields) == 0 {
+		return src
+	}
+	return src + ":" + strings.Join(h.Fields, ",")
+}
+
+// id returns the routing id for a request.  Whenever the requested part of
+// the key is missing the task ID is used so that a task keeps hitting the
+// same instance.
+func (h HashKey) id(taskID string, cfg map[string]ctypes.ConfigValue, tags map[string]string) string {
+	switch h.Source {
+	case HashKeyTask:
+		return taskID
+	case HashKeyTag:
+		if v, ok := tags[h.Fields[0]]; ok {
+			return v
+		}
+		return taskID
+	default:
+		if len(h.Fields) == 0 {
+			return idFromCfg(cfg)
+		}
+		sub := make(map[string]ctypes.ConfigValue, len(h.Fields))
+		for _, f := range h.Fields {
+			if v, ok := cfg[f]; ok {
+				sub[f] = v
+			}
+		}
+		if len(sub) == 0 {
+			return taskID
+		}
+		return idFromCfg(sub)
+	}
+}
+
+// consistentHash provides a strategy that places the running instances of a
+// plugin on a hash ring and routes each key to the nearest instance.  Adding
+// or removing an instance only re-assigns the keys adjacent to it on the ring.
+type consistentHash struct {
+	// mutex guards the ring, which is rebuilt by selects made under the
+	// read lock of the pool
+	mutex       sync.Mutex
+	ring        hashRing
+	owners      map[uint32]AvailablePlugin
+	members     map[uint32]AvailablePlugin
+	replicas    int
+	metricCache *cacheMap
+	logger      *log.Entry
+	cacheTTL    time.Duration
+}
+
+func NewConsistentHash(cacheTTL time.Duration) *consistentHash {
+	return &consistentHash{
+		owners:      make(map[uint32]AvailablePlugin),
+		members:     make(map[uint32]AvailablePlugin),
+		replicas:    defaultHashReplicas,
+		metricCache: newCacheMap(cacheTTL),
+		cacheTTL:    cacheTTL,
+		logger: log.WithFields(log.Fields{
+			"_module": "control-routing",
+		}),
+	}
+}
+
+// Select selects an available plugin using the consistent-hash strategy.
+func (c *consistentHash) Select(aps []AvailablePlugin, id string) (AvailablePlugin, error) {
+	if len(aps) == 0 {
+		c.logger.WithFields(log.Fields{
+			"_block":   "select",
+			"strategy": c.String(),
+		}).Error(ErrCouldNotSelect)
+		return nil, ErrCouldNotSelect
+	}
+	c.mutex.Lock()
+	c.rebuild(aps)
+	h := hashKey(id)
+	idx := sort.Search(len(c.ring), func(i int) bool { return c.ring[i] >= h })
+	if idx == len(c.ring) {
+		idx = 0
+	}
+	ap := c.owners[c.ring[idx]]
+	c.mutex.Unlock()
+	c.logger.WithFields(log.Fields{
+		"_block":    "select",
+		"strategy":  c.String(),
+		"pool size": len(aps),
+		"index":     ap.String(),
+		"id":        ap.ID(),
+	}).Debug("plugin selected")
+	return ap, nil
+}
+
+// Remove selects a plugin and removes the cache for the given id
+func (c *consistentHash) Remove(aps []AvailablePlugin, id string) (AvailablePlugin, error) {
+	ap, err := c.Select(aps, id)
+	if err != nil {
+		return nil, err
+	}
+	c.metricCache.remove(id)
+	return ap, nil
+}
+
+// String returns the strategy name.
+func (c *consistentHash) String() string {
+	return "consistent-hash"
+}
+
+// setCachePlugin sets the plugin the cached metrics are accounted to.
+func (c *consistentHash) setCachePlugin(key string) {
+	c.metricCache.setPlugin(key)
+}
+
+// CacheTTL returns the TTL for the cache.
+func (c *consistentHash) CacheTTL(id string) (time.Duration, error) {
+	return c.cacheTTL, nil
+}
+
+// checkCache checks the cache for metric types.
+// returns:
+//  - array of metrics that need to be collected
+//  - array of metrics that were returned from the cache
+func (c *consistentHash) CheckCache(mts []core.Metric, id string) ([]core.Metric, []core.Metric) {
+	return c.metricCache.get(id).checkCache(mts)
+}
+
+// updateCache updates the cache with the metrics collected for the
+// requested metrics.
+func (c *consistentHash) UpdateCache(requested, collected []core.Metric, id string) {
+	c.metricCache.get(id).updateCollected(requested, collected)
+}
+
+// AllCacheHits returns cache hits across all metrics.
+func (c *consistentHash) AllCacheHits() uint64 {
+	var total uint64
+	for _, cache := range c.metricCache.all() {
+		total += cache.allCacheHits()
+	}
+	return total
+}
+
+// AllCacheMisses returns cache misses across all metrics.
+func (c *consistentHash) AllCacheMisses() uint64 {
+	var total uint64
+	for _, cache := range c.metricCache.all() {
+		total += cache.allCacheMisses()
+	}
+	return total
+}
+
+// CacheHits returns the cache hits for a given metric namespace and version.
+func (c *consistentHash) CacheHits(ns string, version int, id string) (uint64, error) {
+	if cache, ok := c.metricCache.lookup(id); ok {
+		return cache.cacheHits(ns, version)
+	}
+	return 0, ErrCacheDoesNotExist
+}
+
+// CacheMisses returns the cache misses for a given metric namespace and version.
+func (c *consistentHash) CacheMisses(ns string, version int, id string) (uint64, error) {
+	if cache, ok := c.metricCache.lookup(id); ok {
+		return cache.cacheMisses(ns, version)
+	}
+	return 0, ErrCacheDoesNotExist
+}
+
+// rebuild recreates the ring when the set of available plugins has changed,
+// c.mutex must be held.
+func (c *consistentHash) rebuild(aps []AvailablePlugin) {
+	if len(aps) == len(c.members) {
+		changed := false
+		for _, ap := range aps {
+			if m, ok := c.members[ap.ID()]; !ok || m != ap {
+				changed = true
+				break
+			}
+		}
+		if !changed {
+			return
+		}
+	}
+	c.members = make(map[uint32]AvailablePlugin, len(aps))
+	c.owners = make(map[uint32]AvailablePlugin, len(aps)*c.replicas)
+	c.ring = make(hashRing, 0, len(aps)*c.replicas)
+	for _, ap := range aps {
+		c.members[ap.ID()] = ap
+		for i := 0; i < c.replicas; i++ {
+			h := hashKey(fmt.Sprintf("%d#%d", ap.ID(), i))
+			if _, taken := c.owners[h]; taken {
+				continue
+			}
+			c.owners[h] = ap
+			c.ring = append(c.ring, h)
+		}
+	}
+	sort.Sort(c.ring)
+}
+
+// hashRing is a sorted list of points on the ring
+type hashRing []uint32
+
+func (r hashRing) Len() int           { return len(r) }
+func (r hashRing) Less(i, j int) bool { return r[i] < r[j] }
+func (r hashRing) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+
+func hashKey(s string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return h.Sum32()
+}
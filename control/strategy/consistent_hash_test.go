@@ -0,0 +1,163 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package strategy
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/intelsdi-x/snap/control/plugin"
+	. "github.com/intelsdi-x/snap/control/strategy/fixtures"
+	"github.com/intelsdi-x/snap/core/ctypes"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestConsistentHashRouter(t *testing.T) {
+	Convey("Given a consistent-hash router", t, func() {
+		router := NewConsistentHash(100 * time.Millisecond)
+		So(router, ShouldNotBeNil)
+		So(router.String(), ShouldResemble, "consistent-hash")
+		p1 := NewMockAvailablePlugin().WithName("p1").WithID(1)
+		p2 := NewMockAvailablePlugin().WithName("p2").WithID(2)
+		p3 := NewMockAvailablePlugin().WithName("p3").WithID(3)
+
+		Convey("Select returns the same plugin for the same key", func() {
+			sp1, err := router.Select([]AvailablePlugin{p1, p2}, "key1")
+			So(err, ShouldBeNil)
+			So(sp1, ShouldNotBeNil)
+			// the order of the available plugins does not matter
+			sp2, err := router.Select([]AvailablePlugin{p2, p1}, "key1")
+			So(err, ShouldBeNil)
+			So(sp2, ShouldEqual, sp1)
+		})
+		Convey("Select fails when there are no plugins", func() {
+			sp, err := router.Select([]AvailablePlugin{}, "key1")
+			So(sp, ShouldBeNil)
+			So(err, ShouldEqual, ErrCouldNotSelect)
+		})
+		Convey("Growing the pool only moves keys to the new plugin", func() {
+			before := map[string]AvailablePlugin{}
+			for i := 0; i < 200; i++ {
+				key := fmt.Sprintf("key%d", i)
+				before[key], _ = router.Select([]AvailablePlugin{p1, p2}, key)
+			}
+			moved := 0
+			for key, ap := range before {
+				after, err := router.Select([]AvailablePlugin{p1, p2, p3}, key)
+				So(err, ShouldBeNil)
+				if after != ap {
+					So(after, ShouldEqual, p3)
+					moved++
+				}
+			}
+			So(moved, ShouldBeGreaterThan, 0)
+			So(moved, ShouldBeLessThan, len(before))
+		})
+		Convey("Concurrent selects on changing pools select a plugin of the pool", func() {
+			pools := [][]AvailablePlugin{{p1, p2}, {p1, p2, p3}, {p3}}
+			var wg sync.WaitGroup
+			selected := make(chan bool, 300)
+			for i := 0; i < 300; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					pool := pools[i%len(pools)]
+					ap, err := router.Select(pool, fmt.Sprintf("key%d", i))
+					found := false
+					for _, p := range pool {
+						found = found || (err == nil && ap == p)
+					}
+					selected <- found
+				}(i)
+			}
+			wg.Wait()
+			close(selected)
+			for found := range selected {
+				So(found, ShouldBeTrue)
+			}
+		})
+	})
+}
+
+func TestParseHashKey(t *testing.T) {
+	Convey("Given hash key definitions", t, func() {
+		tcs := []struct {
+			key      string
+			expected HashKey
+			valid    bool
+		}{
+			{"", HashKey{Source: HashKeyConfig}, true},
+			{"config", HashKey{Source: HashKeyConfig}, true},
+			{"config:host, port", HashKey{Source: HashKeyConfig, Fields: []string{"host", "port"}}, true},
+			{"task", HashKey{Source: HashKeyTask}, true},
+			{"tag:plugin_running_on", HashKey{Source: HashKeyTag, Fields: []string{"plugin_running_on"}}, true},
+			{"task:foo", HashKey{}, false},
+			{"tag", HashKey{}, false},
+			{"tag:a,b", HashKey{}, false},
+			{"foo", HashKey{}, false},
+		}
+		for _, tc := range tcs {
+			Convey(fmt.Sprintf("Parsing %q", tc.key), func() {
+				hk, err := ParseHashKey(tc.key)
+				if tc.valid {
+					So(err, ShouldBeNil)
+					So(hk, ShouldResemble, tc.expected)
+				} else {
+					So(err, ShouldNotBeNil)
+				}
+			})
+		}
+	})
+}
+
+func TestPoolSelectAPConsistentHashRouter(t *testing.T) {
+	Convey("Given a pool with consistent-hash strategy", t, func() {
+		defer func(hk HashKey) { ConsistentHashKey = hk }(ConsistentHashKey)
+		p1 := NewMockAvailablePlugin().WithStrategy(plugin.ConsistentHashRouting).WithID(1)
+		pool, _ := NewPool(p1.String(), p1)
+		So(pool.Strategy().String(), ShouldEqual, plugin.ConsistentHashRouting.String())
+		cfg := map[string]ctypes.ConfigValue{"host": ctypes.ConfigValueStr{"a"}, "user": ctypes.ConfigValueStr{"x"}}
+		otherCfg := map[string]ctypes.ConfigValue{"host": ctypes.ConfigValueStr{"a"}, "user": ctypes.ConfigValueStr{"y"}}
+
+		Convey("Then all tasks are routed to the single instance", func() {
+			ap, err := pool.SelectAP("TaskID", cfg, nil)
+			So(err, ShouldBeNil)
+			So(ap, ShouldEqual, p1)
+			ap, err = pool.SelectAP("AnotherTaskID", otherCfg, nil)
+			So(err, ShouldBeNil)
+			So(ap, ShouldEqual, p1)
+		})
+		Convey("Then the configured hash key is used", func() {
+			ConsistentHashKey, _ = ParseHashKey("config:host")
+			So(ConsistentHashKey.id("t1", cfg, nil), ShouldEqual, ConsistentHashKey.id("t2", otherCfg, nil))
+
+			ConsistentHashKey, _ = ParseHashKey("task")
+			So(ConsistentHashKey.id("t1", cfg, nil), ShouldEqual, "t1")
+
+			ConsistentHashKey, _ = ParseHashKey("tag:dc")
+			So(ConsistentHashKey.id("t1", cfg, map[string]string{"dc": "east"}), ShouldEqual, "east")
+			So(ConsistentHashKey.id("t1", cfg, nil), ShouldEqual, "t1")
+		})
+	})
+}
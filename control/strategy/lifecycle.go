@@ -0,0 +1,206 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package strategy
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/intelsdi-x/snap/core"
+)
+
+// LifecycleMode defines when the instances of a plugin are started and stopped.
+type LifecycleMode int
+
+const (
+	// LazyLifecycle starts instances on subscription and stops them on
+	// unsubscription.
+	LazyLifecycle LifecycleMode = iota
+	// EagerLifecycle keeps warm instances running from load time.
+	EagerLifecycle
+	// OnDemandLifecycle starts instances on request and stops them once
+	// idle.
+	OnDemandLifecycle
+)
+
+const (
+	// DefaultWarmInstances is the number of instances kept running by
+	// the eager lifecycle when not specified.
+	DefaultWarmInstances = 1
+	// DefaultOnDemandIdleTimeout is the time after which the instances of
+	// the on-demand lifecycle are stopped when not specified.
+	DefaultOnDemandIdleTimeout = time.Minute
+)
+
+var (
+	// DefaultLifecycle is the lifecycle of the plugins for which no specific
+	// lifecycle is defined in Lifecycles.
+	// It is initialized at runtime via the snapteld config.
+	DefaultLifecycle = Lifecycle{Mode: LazyLifecycle}
+
+	// Lifecycles holds the plugin specific lifecycles keyed by
+	// {plugin_type}:{plugin_name}.
+	Lifecycles = map[string]Lifecycle{}
+
+	// ErrBadLifecycle is returned when a lifecycle cannot be parsed
+	ErrBadLifecycle = errors.New("Invalid lifecycle, expected lazy, eager[:instances] or on-demand[:idle-timeout]")
+)
+
+var lifecycleModes = map[string]LifecycleMode{
+	"lazy":      LazyLifecycle,
+	"eager":     EagerLifecycle,
+	"on-demand": OnDemandLifecycle,
+}
+
+func (m LifecycleMode) String() string {
+	switch m {
+	case EagerLifecycle:
+		return "eager"
+	case OnDemandLifecycle:
+		return "on-demand"
+	}
+	return "lazy"
+}
+
+// Lifecycle defines when the instances of a plugin are started and stopped.
+type Lifecycle struct {
+	Mode LifecycleMode
+	// WarmInstances is the number of instances kept running by the eager
+	// lifecycle.
+	WarmInstances int
+	// IdleTimeout is the time after which the instances of the on-demand
+	// lifecycle are stopped.
+	IdleTimeout time.Duration
+}
+
+// ParseLifecycle parses a lifecycle in the form lazy, eager[:instances] or
+// on-demand[:idle-timeout], e.g. "eager:2" or "on-demand:30s".
+func ParseLifecycle(s string) (Lifecycle, error) {
+	s = strings.TrimSpace(s)
+	arg := ""
+	if i := strings.Index(s, ":"); i > -1 {
+		s, arg = s[:i], s[i+1:]
+	}
+	mode, ok := lifecycleModes[s]
+	if !ok {
+		return Lifecycle{}, ErrBadLifecycle
+	}
+	l := Lifecycle{Mode: mode}
+	switch mode {
+	case LazyLifecycle:
+		if arg != "" {
+			return Lifecycle{}, ErrBadLifecycle
+		}
+	case EagerLifecycle:
+		l.WarmInstances = DefaultWarmInstances
+		if arg != "" {
+			n, err := strconv.Atoi(arg)
+			if err != nil || n < 1 {
+				return Lifecycle{}, ErrBadLifecycle
+			}
+			l.WarmInstances = n
+		}
+	case OnDemandLifecycle:
+		l.IdleTimeout = DefaultOnDemandIdleTimeout
+		if arg != "" {
+			d, err := time.ParseDuration(arg)
+			if err != nil || d <= 0 {
+				return Lifecycle{}, ErrBadLifecycle
+			}
+			l.IdleTimeout = d
+		}
+	}
+	return l, nil
+}
+
+func (l Lifecycle) String() string {
+	switch l.Mode {
+	case EagerLifecycle:
+		return fmt.Sprintf("%s:%d", l.Mode, l.WarmInstances)
+	case OnDemandLifecycle:
+		return fmt.Sprintf("%s:%s", l.Mode, l.IdleTimeout)
+	}
+	return l.Mode.String()
+}
+
+// Warm returns the number of instances kept running from load time.
+func (l Lifecycle) Warm() int {
+	if l.Mode != EagerLifecycle {
+		return 0
+	}
+	return l.WarmInstances
+}
+
+// LifecycleFor returns the lifecycle of the plugin with the given key:
+// {plugin_type}{core.Separator}{plugin_name}{core.Separator}{plugin_version}
+func LifecycleFor(key string) Lifecycle {
+	if tnv := strings.Split(key, core.Separator); len(tnv) == 3 {
+		if l, ok := Lifecycles[tnv[0]+":"+tnv[1]]; ok {
+			return l
+		}
+	}
+	return DefaultLifecycle
+}
+
+// SetLifecycle sets the lifecycle of the pool.
+func (p *pool) SetLifecycle(l Lifecycle) {
+	p.Lock()
+	defer p.Unlock()
+	p.lifecycle = l
+}
+
+// Lifecycle returns the lifecycle of the pool.
+func (p *pool) Lifecycle() Lifecycle {
+	p.RLock()
+	defer p.RUnlock()
+	return p.lifecycle
+}
+
+// Starved returns a bool indicating whether an on-demand pool needs a new
+// instance to serve a request.
+func (p *pool) Starved() bool {
+	p.RLock()
+	defer p.RUnlock()
+	if p.lifecycle.Mode != OnDemandLifecycle {
+		return false
+	}
+	return len(p.plugins) == 0 || p.eligible()
+}
+
+// Cold returns a bool indicating whether an eager pool runs less instances
+// than its warm instances.
+func (p *pool) Cold() bool {
+	p.RLock()
+	defer p.RUnlock()
+	return len(p.plugins) < p.lifecycle.Warm() && len(p.plugins) < p.max
+}
+
+// Release releases the instance held for the given task without stopping it.
+func (p *pool) Release(taskID string) {
+	p.Lock()
+	defer p.Unlock()
+	if p.RoutingAndCaching == nil || len(p.plugins) == 0 {
+		return
+	}
+	p.Remove(p.plugins.Values(), taskID)
+}
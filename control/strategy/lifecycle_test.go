@@ -0,0 +1,124 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package strategy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/intelsdi-x/snap/control/plugin"
+	. "github.com/intelsdi-x/snap/control/strategy/fixtures"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseLifecycle(t *testing.T) {
+	Convey("Parsing lifecycles", t, func() {
+		tcs := []struct {
+			input    string
+			expected Lifecycle
+			valid    bool
+		}{
+			{"lazy", Lifecycle{Mode: LazyLifecycle}, true},
+			{"eager", Lifecycle{Mode: EagerLifecycle, WarmInstances: DefaultWarmInstances}, true},
+			{"eager:3", Lifecycle{Mode: EagerLifecycle, WarmInstances: 3}, true},
+			{"on-demand", Lifecycle{Mode: OnDemandLifecycle, IdleTimeout: DefaultOnDemandIdleTimeout}, true},
+			{"on-demand:30s", Lifecycle{Mode: OnDemandLifecycle, IdleTimeout: 30 * time.Second}, true},
+			{"lazy:1", Lifecycle{}, false},
+			{"eager:0", Lifecycle{}, false},
+			{"eager:x", Lifecycle{}, false},
+			{"on-demand:-1s", Lifecycle{}, false},
+			{"always", Lifecycle{}, false},
+		}
+		for _, tc := range tcs {
+			l, err := ParseLifecycle(tc.input)
+			if tc.valid {
+				So(err, ShouldBeNil)
+				So(l, ShouldResemble, tc.expected)
+				parsed, _ := ParseLifecycle(l.String())
+				So(parsed, ShouldResemble, l)
+			} else {
+				So(err, ShouldEqual, ErrBadLifecycle)
+			}
+		}
+	})
+}
+
+func TestPoolLifecycle(t *testing.T) {
+	Convey("Given a pool", t, func() {
+		now := time.Now()
+		idle := now.Add(-2 * time.Minute)
+
+		Convey("An eager pool is cold until it runs its warm instances", func() {
+			pool := newAutoscaledPool(plugin.DefaultRouting, now)
+			pool.SetLifecycle(Lifecycle{Mode: EagerLifecycle, WarmInstances: 2})
+			So(pool.Cold(), ShouldBeTrue)
+			pool.Insert(NewMockAvailablePlugin().WithPluginType(plugin.CollectorPluginType).WithID(1).WithLastHit(idle))
+			So(pool.Cold(), ShouldBeFalse)
+		})
+		Convey("A lazy pool is never cold", func() {
+			pool := newAutoscaledPool(plugin.DefaultRouting, now)
+			So(pool.Cold(), ShouldBeFalse)
+		})
+		Convey("An on-demand pool does not grow on subscription", func() {
+			pool := newAutoscaledPool(plugin.DefaultRouting, now)
+			pool.SetLifecycle(Lifecycle{Mode: OnDemandLifecycle, IdleTimeout: time.Minute})
+			pool.Subscribe("1")
+			pool.Subscribe("2")
+			So(pool.Eligible(), ShouldBeFalse)
+			So(pool.Starved(), ShouldBeTrue)
+		})
+		Convey("An empty on-demand pool is starved", func() {
+			pool, _ := NewPool(NewMockAvailablePlugin().WithPluginType(plugin.CollectorPluginType).String())
+			So(pool.Starved(), ShouldBeFalse)
+			pool.SetLifecycle(Lifecycle{Mode: OnDemandLifecycle, IdleTimeout: time.Minute})
+			So(pool.Starved(), ShouldBeTrue)
+		})
+		Convey("An on-demand pool is emptied once all its instances are idle", func() {
+			pool := newAutoscaledPool(plugin.StickyRouting, idle, now)
+			pool.SetLifecycle(Lifecycle{Mode: OnDemandLifecycle, IdleTimeout: time.Minute})
+			So(pool.Reap(now, "idle"), ShouldEqual, 0)
+			So(pool.Reap(now.Add(2*time.Minute), "idle"), ShouldEqual, 2)
+			So(pool.Count(), ShouldEqual, 0)
+		})
+		Convey("An eager pool keeps its warm instances", func() {
+			defer func(e bool, d AutoscalePolicy) {
+				AutoscalingEnabled, DefaultAutoscalePolicy = e, d
+			}(AutoscalingEnabled, DefaultAutoscalePolicy)
+			AutoscalingEnabled = true
+			DefaultAutoscalePolicy = AutoscalePolicy{IdleTimeout: time.Minute}
+			pool := newAutoscaledPool(plugin.DefaultRouting, idle, idle, idle)
+			pool.SetLifecycle(Lifecycle{Mode: EagerLifecycle, WarmInstances: 2})
+			So(pool.Reap(now, "idle"), ShouldEqual, 1)
+			So(pool.Count(), ShouldEqual, 2)
+		})
+		Convey("Releasing a task does not stop its instance", func() {
+			pool := newAutoscaledPool(plugin.StickyRouting, now)
+			pool.Subscribe("1")
+			_, err := pool.SelectAP("1", nil, nil)
+			So(err, ShouldBeNil)
+			pool.Release("1")
+			So(pool.Count(), ShouldEqual, 1)
+			_, err = pool.SelectAP("2", nil, nil)
+			So(err, ShouldBeNil)
+		})
+	})
+}
@@ -46,6 +46,11 @@ func (l *lru) String() string {
 	return "least-recently-used"
 }
 
+// setCachePlugin sets the plugin the cached metrics are accounted to.
+func (l *lru) setCachePlugin(key string) {
+	l.cache.plugin = key
+}
+
 // CacheTTL returns the TTL for the cache.
 func (l *lru) CacheTTL(taskID string) (time.Duration, error) {
 	return l.ttl, nil
@@ -98,9 +103,10 @@ func (l *lru) CheckCache(mts []core.Metric, _ string) ([]core.Metric, []core.Met
 	return l.checkCache(mts)
 }
 
-// updateCache updates the cache with the given array of metrics.
-func (l *lru) UpdateCache(mts []core.Metric, _ string) {
-	l.updateCache(mts)
+// updateCache updates the cache with the metrics collected for the
+// requested metrics.
+func (l *lru) UpdateCache(requested, collected []core.Metric, _ string) {
+	l.updateCollected(requested, collected)
 }
 
 // AllCacheHits returns cache hits across all metrics.
@@ -60,7 +60,8 @@ type Pool interface {
 	RLock()
 	RUnlock()
 	SelectAndKill(taskID, reason string)
-	SelectAP(taskID string, configID map[string]ctypes.ConfigValue) (AvailablePlugin, serror.SnapError)
+	SelectAP(taskID string, configID map[string]ctypes.ConfigValue, tags map[string]string) (AvailablePlugin, serror.SnapError)
+	CacheID(taskID string, config map[string]ctypes.ConfigValue, tags map[string]string) string
 	Strategy() RoutingAndCaching
 	Subscribe(taskID string)
 	SubscriptionCount() int
@@ -69,6 +70,15 @@ type Pool interface {
 	RestartCount() int
 	IncRestartCount()
 	KillAll(string)
+	RequestStarted()
+	RequestFinished(time.Duration)
+	Overloaded() bool
+	Reap(now time.Time, reason string) int
+	SetLifecycle(Lifecycle)
+	Lifecycle() Lifecycle
+	Starved() bool
+	Cold() bool
+	Release(taskID string)
 }
 
 type AvailablePlugin interface {
@@ -125,6 +135,20 @@ type pool struct {
 	// restartCount the restart count of available plugins
 	// when the DeadAvailablePluginEvent occurs
 	restartCount int
+
+	// The autoscale policy of the pool.
+	policy AutoscalePolicy
+	// streaming pools hold long lived streams and are not autoscaled
+	streaming bool
+
+	// The lifecycle of the instances of the pool.
+	lifecycle Lifecycle
+
+	// used to coordinate changes to the load of a pool
+	loadMutex *sync.Mutex
+	// The number of requests in flight and their average latency.
+	inFlight int
+	latency  time.Duration
 }
 
 func NewPool(key string, plugins ...AvailablePlugin) (Pool, error) {
@@ -141,6 +165,9 @@ func NewPool(key string, plugins ...AvailablePlugin) (Pool, error) {
 		plugins:          MapAvailablePlugin{},
 		max:              MaximumRunningPlugins,
 		concurrencyCount: 1,
+		policy:           autoscalePolicy(key),
+		lifecycle:        LifecycleFor(key),
+		loadMutex:        &sync.Mutex{},
 	}
 
 	if len(plugins) > 0 {
@@ -180,7 +207,7 @@ func (p *pool) IncRestartCount() {
 
 // Insert inserts an AvailablePlugin into the pool
 func (p *pool) Insert(a AvailablePlugin) error {
-	if a.Type() != plugin.CollectorPluginType && a.Type() != plugin.ProcessorPluginType && a.Type() != plugin.PublisherPluginType && a.Type() != plugin.StreamCollectorPluginType {
+	if a.Type() != plugin.CollectorPluginType && a.Type() != plugin.ProcessorPluginType && a.Type() != plugin.PublisherPluginType && a.Type() != plugin.StreamCollectorPluginType && a.Type() != plugin.StreamPublisherPluginType {
 		return ErrBadType
 	}
 	// If an empty pool is created, it does not have
@@ -200,6 +227,11 @@ func (p *pool) Insert(a AvailablePlugin) error {
 
 // applyPluginMeta is called when the first plugin is added to the pool
 func (p *pool) applyPluginMeta(a AvailablePlugin) error {
+	// Use the max size of the autoscale policy if defined
+	if AutoscalingEnabled && p.policy.Max > 0 {
+		p.max = p.policy.Max
+	}
+
 	// Checking if plugin is exclusive
 	// (only one instance should be running).
 	if a.Exclusive() {
@@ -216,6 +248,8 @@ func (p *pool) applyPluginMeta(a AvailablePlugin) error {
 	// Set the concurrency count
 	p.concurrencyCount = a.ConcurrencyCount()
 
+	p.streaming = a.Type() == plugin.StreamCollectorPluginType
+
 	// Set the routing and caching strategy
 	switch a.RoutingStrategy() {
 	case plugin.DefaultRouting:
@@ -225,9 +259,14 @@ func (p *pool) applyPluginMeta(a AvailablePlugin) error {
 		p.concurrencyCount = 1
 	case plugin.ConfigRouting:
 		p.RoutingAndCaching = NewConfigBased(cacheTTL)
+	case plugin.ConsistentHashRouting:
+		p.RoutingAndCaching = NewConsistentHash(cacheTTL)
 	default:
 		return ErrBadStrategy
 	}
+	if ca, ok := p.RoutingAndCaching.(cacheAccounting); ok {
+		ca.setCachePlugin(p.key)
+	}
 
 	return nil
 }
@@ -261,12 +300,25 @@ func (p *pool) Eligible() bool {
 	p.RLock()
 	defer p.RUnlock()
 
+	// on-demand pools grow on request
+	if p.lifecycle.Mode == OnDemandLifecycle {
+		return false
+	}
+	return p.eligible()
+}
+
+func (p *pool) eligible() bool {
 	// optimization: don't even bother with concurrency
 	// count if we have already reached pool max
 	if len(p.plugins) >= p.max {
 		return false
 	}
 
+	// autoscaled pools grow on load once they have an instance running
+	if len(p.plugins) > 0 && p.autoscaled() {
+		return false
+	}
+
 	// Check if pool is eligible and number of plugins is less than maximum allowed
 	if len(p.subs) > p.concurrencyCount*len(p.plugins) {
 		return true
@@ -364,19 +416,12 @@ func (p *pool) SubscriptionCount() int {
 
 // SelectAP selects an available plugin from the pool
 // the method is not thread safe, it should be protected outside of the body
-func (p *pool) SelectAP(taskID string, config map[string]ctypes.ConfigValue) (AvailablePlugin, serror.SnapError) {
+func (p *pool) SelectAP(taskID string, config map[string]ctypes.ConfigValue, tags map[string]string) (AvailablePlugin, serror.SnapError) {
 	aps := p.plugins.Values()
 
-	var id string
-	switch p.Strategy().String() {
-	case "least-recently-used":
-		id = ""
-	case "sticky":
-		id = taskID
-	case "config-based":
-		id = idFromCfg(config)
-	default:
-		return nil, serror.New(ErrBadStrategy)
+	id, err := p.routingID(taskID, config, tags)
+	if err != nil {
+		return nil, serror.New(err)
 	}
 
 	ap, err := p.Select(aps, id)
@@ -386,6 +431,34 @@ func (p *pool) SelectAP(taskID string, config map[string]ctypes.ConfigValue) (Av
 	return ap, nil
 }
 
+// CacheID returns the id under which the strategy caches the metrics
+// requested by a task.  Tasks requesting metrics with an identical config
+// share the same id, and so the same cache, unless the strategy pins tasks
+// to a plugin instance.
+func (p *pool) CacheID(taskID string, config map[string]ctypes.ConfigValue, tags map[string]string) string {
+	id, err := p.routingID(taskID, config, tags)
+	if err != nil {
+		return taskID
+	}
+	return id
+}
+
+// routingID returns the id used by the strategy to route a request
+func (p *pool) routingID(taskID string, config map[string]ctypes.ConfigValue, tags map[string]string) (string, error) {
+	switch p.Strategy().String() {
+	case "least-recently-used":
+		return "", nil
+	case "sticky":
+		return taskID, nil
+	case "config-based":
+		return idFromCfg(config), nil
+	case "consistent-hash":
+		return ConsistentHashKey.id(taskID, config, tags), nil
+	default:
+		return "", ErrBadStrategy
+	}
+}
+
 func idFromCfg(cfg map[string]ctypes.ConfigValue) string {
 	//TODO: check for nil map
 	var buff bytes.Buffer
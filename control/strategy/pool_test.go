@@ -222,7 +222,7 @@ func TestPoolSelectAPDefaultRouter(t *testing.T) {
 		pool, _ := NewPool(plugin.String(), plugin)
 
 		Convey("Then AvailablePlugin is selected", func() {
-			ap, err := pool.SelectAP("TaskID", nil)
+			ap, err := pool.SelectAP("TaskID", nil, nil)
 			So(ap, ShouldNotBeNil)
 			So(err, ShouldBeNil)
 		})
@@ -239,16 +239,16 @@ func TestPoolSelectAPConfigRouter(t *testing.T) {
 			pool, _ := NewPool(plugin.String(), plugin)
 
 			Convey("Then given routering is handled", func() {
-				ap, err := pool.SelectAP("TaskID", cfg)
+				ap, err := pool.SelectAP("TaskID", cfg, nil)
 				So(ap, ShouldNotBeNil)
 				So(err, ShouldBeNil)
 
-				ap, err = pool.SelectAP("AnotherTaskID", cfg)
+				ap, err = pool.SelectAP("AnotherTaskID", cfg, nil)
 				So(ap, ShouldNotBeNil)
 				So(err, ShouldBeNil)
 				So(ap, ShouldEqual, plugin)
 
-				ap, err = pool.SelectAP("YetAnotherTaskID", otherCfg)
+				ap, err = pool.SelectAP("YetAnotherTaskID", otherCfg, nil)
 				So(ap, ShouldBeNil)
 				So(err, ShouldResemble, serror.New(ErrCouldNotSelect))
 			})
@@ -259,7 +259,7 @@ func TestPoolSelectAPConfigRouter(t *testing.T) {
 			pool, _ := NewPool(plugin.String(), plugin)
 
 			Convey("With empty config, for some task, then routing is handled", func() {
-				ap, err := pool.SelectAP("TaskID", map[string]ctypes.ConfigValue{})
+				ap, err := pool.SelectAP("TaskID", map[string]ctypes.ConfigValue{}, nil)
 				So(ap, ShouldNotBeNil)
 				So(err, ShouldBeNil)
 			})
@@ -273,17 +273,17 @@ func TestPoolSelectAPStickyRouter(t *testing.T) {
 		pool, _ := NewPool(plugin.String(), plugin)
 
 		Convey("With empty config, for some task, routering is handled", func() {
-			ap1, err := pool.SelectAP("TaskID", nil)
+			ap1, err := pool.SelectAP("TaskID", nil, nil)
 			So(ap1, ShouldNotBeNil)
 			So(err, ShouldBeNil)
 
 			cfg := map[string]ctypes.ConfigValue{"foo": ctypes.ConfigValueStr{"bar"}}
-			ap2, err := pool.SelectAP("TaskID", cfg)
+			ap2, err := pool.SelectAP("TaskID", cfg, nil)
 			So(ap2, ShouldNotBeNil)
 			So(err, ShouldBeNil)
 			So(ap2, ShouldEqual, ap1)
 
-			ap3, err := pool.SelectAP("AnotherTaskID", nil)
+			ap3, err := pool.SelectAP("AnotherTaskID", nil, nil)
 			So(ap3, ShouldBeNil)
 			So(err, ShouldResemble, serror.New(ErrCouldNotSelect))
 		})
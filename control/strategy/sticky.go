@@ -36,14 +36,14 @@ var (
 // sticky provides a strategy that ... concurrency count is 1
 type sticky struct {
 	plugins     map[string]AvailablePlugin
-	metricCache map[string]*cache
+	metricCache *cacheMap
 	logger      *log.Entry
 	cacheTTL    time.Duration
 }
 
 func NewSticky(cacheTTL time.Duration) *sticky {
 	return &sticky{
-		metricCache: make(map[string]*cache),
+		metricCache: newCacheMap(cacheTTL),
 		plugins:     make(map[string]AvailablePlugin),
 		cacheTTL:    cacheTTL,
 		logger: log.WithFields(log.Fields{
@@ -66,7 +66,7 @@ func (s *sticky) Remove(aps []AvailablePlugin, taskID string) (AvailablePlugin,
 	if err != nil {
 		return nil, err
 	}
-	delete(s.metricCache, taskID)
+	s.metricCache.remove(taskID)
 	delete(s.plugins, taskID)
 	return ap, nil
 }
@@ -76,6 +76,11 @@ func (s *sticky) String() string {
 	return "sticky"
 }
 
+// setCachePlugin sets the plugin the cached metrics are accounted to.
+func (s *sticky) setCachePlugin(key string) {
+	s.metricCache.setPlugin(key)
+}
+
 // CacheTTL returns the TTL for the cache.
 func (s *sticky) CacheTTL(taskID string) (time.Duration, error) {
 	return s.cacheTTL, nil
@@ -86,24 +91,19 @@ func (s *sticky) CacheTTL(taskID string) (time.Duration, error) {
 //  - array of metrics that need to be collected
 //  - array of metrics that were returned from the cache
 func (s *sticky) CheckCache(mts []core.Metric, taskID string) ([]core.Metric, []core.Metric) {
-	if _, ok := s.metricCache[taskID]; !ok {
-		s.metricCache[taskID] = NewCache(s.cacheTTL)
-	}
-	return s.metricCache[taskID].checkCache(mts)
+	return s.metricCache.get(taskID).checkCache(mts)
 }
 
-// updateCache updates the cache with the given array of metrics.
-func (s *sticky) UpdateCache(mts []core.Metric, taskID string) {
-	if _, ok := s.metricCache[taskID]; !ok {
-		s.metricCache[taskID] = NewCache(s.cacheTTL)
-	}
-	s.metricCache[taskID].updateCache(mts)
+// updateCache updates the cache with the metrics collected for the
+// requested metrics.
+func (s *sticky) UpdateCache(requested, collected []core.Metric, taskID string) {
+	s.metricCache.get(taskID).updateCollected(requested, collected)
 }
 
 // AllCacheHits returns cache hits across all metrics.
 func (s *sticky) AllCacheHits() uint64 {
 	var total uint64
-	for _, cache := range s.metricCache {
+	for _, cache := range s.metricCache.all() {
 		total += cache.allCacheHits()
 	}
 	return total
@@ -112,7 +112,7 @@ func (s *sticky) AllCacheHits() uint64 {
 // AllCacheMisses returns cache misses across all metrics.
 func (s *sticky) AllCacheMisses() uint64 {
 	var total uint64
-	for _, cache := range s.metricCache {
+	for _, cache := range s.metricCache.all() {
 		total += cache.allCacheMisses()
 	}
 	return total
@@ -120,7 +120,7 @@ func (s *sticky) AllCacheMisses() uint64 {
 
 // CacheHits returns the cache hits for a given metric namespace and version.
 func (s *sticky) CacheHits(ns string, version int, taskID string) (uint64, error) {
-	if cache, ok := s.metricCache[taskID]; ok {
+	if cache, ok := s.metricCache.lookup(taskID); ok {
 		return cache.cacheHits(ns, version)
 	}
 	return 0, ErrCacheDoesNotExist
@@ -128,7 +128,7 @@ func (s *sticky) CacheHits(ns string, version int, taskID string) (uint64, error
 
 // CacheMisses returns the cache misses for a given metric namespace and version.
 func (s *sticky) CacheMisses(ns string, version int, taskID string) (uint64, error) {
-	if cache, ok := s.metricCache[taskID]; ok {
+	if cache, ok := s.metricCache.lookup(taskID); ok {
 		return cache.cacheMisses(ns, version)
 	}
 	return 0, ErrCacheDoesNotExist
@@ -38,7 +38,7 @@ type RoutingAndCaching interface {
 	Select(availablePlugins []AvailablePlugin, id string) (AvailablePlugin, error)
 	Remove(availablePlugins []AvailablePlugin, id string) (AvailablePlugin, error)
 	CheckCache(metrics []core.Metric, id string) ([]core.Metric, []core.Metric)
-	UpdateCache(metrics []core.Metric, id string)
+	UpdateCache(requested, collected []core.Metric, id string)
 	CacheHits(ns string, ver int, id string) (uint64, error)
 	CacheMisses(ns string, ver int, id string) (uint64, error)
 	AllCacheHits() uint64
@@ -47,6 +47,12 @@ type RoutingAndCaching interface {
 	String() string
 }
 
+// cacheAccounting is implemented by strategies which account the metrics
+// they cache to the plugin owning the pool.
+type cacheAccounting interface {
+	setCachePlugin(key string)
+}
+
 // Values returns slice of map values
 func (sm MapAvailablePlugin) Values() []AvailablePlugin {
 	values := []AvailablePlugin{}
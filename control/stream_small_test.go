@@ -0,0 +1,90 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package control
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/intelsdi-x/snap/control/plugin"
+	"github.com/intelsdi-x/snap/core"
+)
+
+func TestMergeStreams(t *testing.T) {
+	Convey("Given the streams of two streaming collectors", t, func() {
+		metricChans := []chan []core.Metric{make(chan []core.Metric), make(chan []core.Metric)}
+		errChans := []chan error{make(chan error), make(chan error)}
+		stop := make(chan struct{})
+		done := make(chan struct{})
+		metricChan, errChan := mergeStreams(metricChans, errChans, stop, done)
+
+		Convey("the metrics of both are received on the merged stream", func() {
+			go func() {
+				metricChans[0] <- []core.Metric{plugin.MetricType{Namespace_: core.NewNamespace("a")}}
+				metricChans[1] <- []core.Metric{plugin.MetricType{Namespace_: core.NewNamespace("b")}}
+			}()
+			received := map[string]bool{}
+			for i := 0; i < 2; i++ {
+				mts := <-metricChan
+				So(mts, ShouldHaveLength, 1)
+				received[mts[0].Namespace().String()] = true
+			}
+			So(received, ShouldResemble, map[string]bool{"/a": true, "/b": true})
+		})
+		Convey("the errors of both are received on the merged stream", func() {
+			go func() { errChans[1] <- errors.New("collection failed") }()
+			So((<-errChan).Error(), ShouldEqual, "collection failed")
+		})
+		Convey("forwarding stops once the task stops", func() {
+			close(stop)
+			// let the forwarding goroutines see the stop
+			time.Sleep(50 * time.Millisecond)
+			select {
+			case metricChans[0] <- []core.Metric{}:
+				t.Error("a stream was forwarded after the task stopped")
+			case errChans[1] <- errors.New("collection failed"):
+				t.Error("a stream was forwarded after the task stopped")
+			case <-time.After(100 * time.Millisecond):
+			}
+		})
+		Convey("the streams are closed once the task stops", func() {
+			close(stop)
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Error("the streams were not closed after the task stopped")
+			}
+		})
+		Convey("the streams are closed once a stream breaks", func() {
+			go func() { errChans[0] <- errors.New("connection broken") }()
+			So((<-errChan).Error(), ShouldEqual, "connection broken")
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Error("the streams were not closed after a stream broke")
+			}
+		})
+	})
+}
@@ -20,14 +20,10 @@ limitations under the License.
 package control
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 	"sync"
 
-	"github.com/intelsdi-x/snap/control/plugin"
 	"github.com/intelsdi-x/snap/core"
 	"github.com/intelsdi-x/snap/core/cdata"
 	"github.com/intelsdi-x/snap/core/control_event"
@@ -46,6 +42,14 @@ var (
 	ErrSubscriptionGroupDoesNotExist = core.ErrSubscriptionGroupDoesNotExist
 
 	ErrConfigRequiredForMetric = errors.New("config required")
+
+	// ErrVersionConstraintNotMet - error message when no loaded version of
+	// a plugin meets the version constraint of a task
+	ErrVersionConstraintNotMet = errors.New("no loaded version of the plugin meets the version constraint")
+
+	// ErrVersionConstraintAmbiguous - error message when a version constraint
+	// is given for a metric exposed by several plugins
+	ErrVersionConstraintAmbiguous = errors.New("version constraint given for a metric exposed by several plugins")
 )
 
 // ManagesSubscriptionGroups is the interface implemented by an object that can
@@ -62,6 +66,8 @@ type ManagesSubscriptionGroups interface {
 		configTree *cdata.ConfigDataTree, asserts ...core.SubscribedPluginAssert) (serrs []serror.SnapError)
 	validateMetric(metric core.Metric) (serrs []serror.SnapError)
 	validatePluginUnloading(*loadedPlugin) (errs []serror.SnapError)
+	pluginVersion(id, typeName, name string, version int) int
+	subscribedPlugins(id string) []core.SubscribedPlugin
 }
 
 type subscriptionGroup struct {
@@ -202,10 +208,67 @@ func (s *subscriptionGroups) Process() (errs []serror.SnapError) {
 	return errs
 }
 
+// pluginVersion returns the version of a processor or a publisher the
+// subscription group resolved the last time it was processed.  The given
+// version is returned when it is pinned or when the group does not exist.
+func (s *subscriptionGroups) pluginVersion(id, typeName, name string, version int) int {
+	if version > 0 {
+		return version
+	}
+	s.Lock()
+	defer s.Unlock()
+	if sg, ok := s.subscriptionMap[id]; ok {
+		if v := sg.subscribedVersion(typeName, name); v > 0 {
+			return v
+		}
+	}
+	return version
+}
+
+// subscribedPlugins returns the plugins the subscription group resolved the
+// last time it was processed.
+func (s *subscriptionGroups) subscribedPlugins(id string) []core.SubscribedPlugin {
+	s.Lock()
+	defer s.Unlock()
+	sg, ok := s.subscriptionMap[id]
+	if !ok {
+		return nil
+	}
+	plugins := make([]core.SubscribedPlugin, len(sg.plugins))
+	copy(plugins, sg.plugins)
+	return plugins
+}
+
 func (s *subscriptionGroups) ValidateDeps(requested []core.RequestedMetric,
 	plugins []core.SubscribedPlugin,
 	configTree *cdata.ConfigDataTree, asserts ...core.SubscribedPluginAssert) (serrs []serror.SnapError) {
 
+	// resolve the version constraints of the requested metrics and plugins
+	sg := &subscriptionGroup{
+		requestedMetrics: requested,
+		requestedPlugins: plugins,
+		pluginControl:    s.pluginControl,
+	}
+	requested, serrs = sg.resolvedMetrics("")
+	resolved := make([]core.SubscribedPlugin, 0, len(plugins))
+	for _, plg := range plugins {
+		version, serr := sg.resolveVersion("", plg.TypeName(), plg.Name(), plg.Version(), core.GetVersionConstraint(plg))
+		if serr != nil {
+			serrs = append(serrs, serr)
+			continue
+		}
+		if version != plg.Version() {
+			plg = subscribedPlugin{
+				name:     plg.Name(),
+				typeName: plg.TypeName(),
+				version:  version,
+				config:   plg.Config(),
+			}
+		}
+		resolved = append(resolved, plg)
+	}
+	plugins = resolved
+
 	// resolve requested metrics and map to collectors
 	pluginToMetricMap, collectors, errs := s.getMetricsAndCollectors(requested, configTree)
 	if errs != nil {
@@ -425,7 +488,9 @@ func (s *subscriptionGroup) validatePluginUnloading(id string, plgToUnload *load
 
 func (s *subscriptionGroup) process(id string) (serrs []serror.SnapError) {
 	// gathers collectors based on requested metrics
-	pluginToMetricMap, plugins, serrs := s.getMetricsAndCollectors(s.requestedMetrics, s.configTree)
+	requested, serrs := s.resolvedMetrics(id)
+	pluginToMetricMap, plugins, errs := s.getMetricsAndCollectors(requested, s.configTree)
+	serrs = append(serrs, errs...)
 	controlLogger.WithFields(log.Fields{
 		"collectors": fmt.Sprintf("%+v", plugins),
 		"metrics":    fmt.Sprintf("%+v", s.requestedMetrics),
@@ -433,19 +498,23 @@ func (s *subscriptionGroup) process(id string) (serrs []serror.SnapError) {
 
 	// notice that requested plugins contains only processors and publishers
 	for _, plugin := range s.requestedPlugins {
+		version, serr := s.resolveVersion(id, plugin.TypeName(), plugin.Name(), plugin.Version(), core.GetVersionConstraint(plugin))
+		if serr != nil {
+			serrs = append(serrs, serr)
+			continue
+		}
 		// add defaults to plugins (exposed in a plugins ConfigPolicy)
 		if lp, err := s.pluginManager.get(
 			fmt.Sprintf("%s"+core.Separator+"%s"+core.Separator+"%d",
 				plugin.TypeName(),
 				plugin.Name(),
-				plugin.Version())); err == nil && lp.ConfigPolicy != nil {
+				version)); err == nil && lp.ConfigPolicy != nil {
 			if policy := lp.ConfigPolicy.Get([]string{""}); policy != nil && len(policy.Defaults()) > 0 {
 				// set defaults to plugin config
 				plugin.Config().ApplyDefaults(policy.Defaults())
 			}
 
 			// update version info for subscribed processor or publisher
-			version := plugin.Version()
 			if version < 1 {
 				version = lp.Version()
 			}
@@ -486,6 +555,122 @@ func (s *subscriptionGroup) process(id string) (serrs []serror.SnapError) {
 	return serrs
 }
 
+// resolvedMetrics pins the requested metrics without a version to the
+// version of the plugin exposing them resolved by resolveVersion.  The
+// metrics exposed by several plugins are not pinned.
+func (s *subscriptionGroup) resolvedMetrics(id string) (requested []core.RequestedMetric, serrs []serror.SnapError) {
+	if !s.canaries.active() && s.Config.AutoUpgradeTasks && !hasVersionConstraints(s.requestedMetrics) {
+		return s.requestedMetrics, nil
+	}
+	for _, r := range s.requestedMetrics {
+		if r.Version() > 0 {
+			requested = append(requested, r)
+			continue
+		}
+		// errors are reported when gathering the collectors
+		mts, err := s.metricCatalog.GetMetrics(r.Namespace(), r.Version())
+		if err != nil || len(mts) == 0 {
+			requested = append(requested, r)
+			continue
+		}
+		constraint := core.GetVersionConstraint(r)
+		cp := mts[0].Plugin
+		pinned := true
+		for _, mt := range mts[1:] {
+			if mt.Plugin.TypeName() != cp.TypeName() || mt.Plugin.Name() != cp.Name() {
+				pinned = false
+				break
+			}
+		}
+		if !pinned {
+			if constraint != nil {
+				serrs = append(serrs, serror.New(ErrVersionConstraintAmbiguous, map[string]interface{}{
+					"metric":             r.Namespace().String(),
+					"version-constraint": constraint.String(),
+				}))
+				continue
+			}
+			requested = append(requested, r)
+			continue
+		}
+		version, serr := s.resolveVersion(id, cp.TypeName(), cp.Name(), r.Version(), constraint)
+		if serr != nil {
+			serr.Fields()["metric"] = r.Namespace().String()
+			serrs = append(serrs, serr)
+			continue
+		}
+		if version < 1 {
+			requested = append(requested, r)
+			continue
+		}
+		requested = append(requested, pinnedMetric{namespace: r.Namespace(), version: version})
+	}
+	return requested, serrs
+}
+
+// resolveVersion returns the version of a plugin a task uses when it did not
+// request one: the version it is routed to during a staged swap, the version
+// it is subscribed to when auto upgrading tasks is disabled or else the
+// highest loaded version meeting its version constraint.  It returns -1, the
+// latest version, when the task has no version constraint.
+func (s *subscriptionGroup) resolveVersion(id, typeName, name string, version int, constraint *core.VersionConstraint) (int, serror.SnapError) {
+	if version > 0 {
+		return version, nil
+	}
+	// route the task during a staged swap of the plugin
+	if routed := s.canaries.version(typeName, name, version, id); routed > 0 && constraint.Matches(routed) {
+		return routed, nil
+	}
+	if constraint == nil && s.Config.AutoUpgradeTasks {
+		return -1, nil
+	}
+	latest := -1
+	for _, lp := range s.pluginManager.all() {
+		if lp.TypeName() != typeName || lp.Name() != name || !constraint.Matches(lp.Version()) {
+			continue
+		}
+		if !s.Config.AutoUpgradeTasks && s.subscribedVersion(typeName, name) == lp.Version() {
+			return lp.Version(), nil
+		}
+		if lp.Version() > latest {
+			latest = lp.Version()
+		}
+	}
+	if constraint == nil {
+		return -1, nil
+	}
+	if latest < 1 {
+		return -1, serror.New(ErrVersionConstraintNotMet, map[string]interface{}{
+			"type":               typeName,
+			"name":               name,
+			"version-constraint": constraint.String(),
+		})
+	}
+	return latest, nil
+}
+
+// subscribedVersion returns the version of a plugin the subscription group
+// is subscribed to, 0 if it is not subscribed to the plugin.
+func (s *subscriptionGroup) subscribedVersion(typeName, name string) int {
+	for _, sp := range s.plugins {
+		if sp.TypeName() == typeName && sp.Name() == name {
+			return sp.Version()
+		}
+	}
+	return 0
+}
+
+// hasVersionConstraints returns true if a requested metric declares a
+// version constraint.
+func hasVersionConstraints(requested []core.RequestedMetric) bool {
+	for _, r := range requested {
+		if core.GetVersionConstraint(r) != nil {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *subscriptionGroup) subscribePlugins(id string,
 	plugins []core.SubscribedPlugin) (serrs []serror.SnapError) {
 	plgs := make([]*loadedPlugin, len(plugins))
@@ -510,41 +695,11 @@ func (s *subscriptionGroup) subscribePlugins(id string,
 			"_block":  "subscriptionGroup.subscribePlugins",
 		}).Debug("plugin subscription")
 		if plg.Details.Uri != nil {
-			// this is a remote plugin
-			pool, err := s.pluginRunner.AvailablePlugins().getOrCreatePool(plg.Key())
-			if err != nil {
+			// this is a remote plugin, its endpoints are connected
+			if err := s.pluginRunner.connectRemote(plg.Key()); err != nil {
 				serrs = append(serrs, serror.New(err))
 				return serrs
 			}
-			if pool.Count() < 1 {
-				var resp plugin.Response
-				res, err := http.Get(plg.Details.Uri.String())
-				if err != nil {
-					serrs = append(serrs, serror.New(err))
-					return serrs
-				}
-				body, err := ioutil.ReadAll(res.Body)
-				if err != nil {
-					serrs = append(serrs, serror.New(err))
-					return serrs
-				}
-				err = json.Unmarshal(body, &resp)
-				if err != nil {
-					serrs = append(serrs, serror.New(err))
-					return serrs
-				}
-				ap, err := newAvailablePlugin(resp, s.eventManager, nil, s.grpcSecurity)
-				if err != nil {
-					serrs = append(serrs, serror.New(err))
-					return serrs
-				}
-				ap.SetIsRemote(true)
-				err = pool.Insert(ap)
-				if err != nil {
-					serrs = append(serrs, serror.New(err))
-					return serrs
-				}
-			}
 		} else {
 			pool, err := s.pluginRunner.AvailablePlugins().getOrCreatePool(plg.Key())
 			if err != nil {
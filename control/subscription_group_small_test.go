@@ -0,0 +1,114 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package control
+
+import (
+	"testing"
+
+	"github.com/intelsdi-x/snap/control/plugin"
+	"github.com/intelsdi-x/snap/core"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSubscriptionGroup_ResolveVersion(t *testing.T) {
+	Convey("Given versions 1, 2 and 3 of a processor", t, func() {
+		pm := newPluginManager()
+		for v := 1; v <= 3; v++ {
+			So(pm.loadedPlugins.add(&loadedPlugin{
+				Type: plugin.ProcessorPluginType,
+				Meta: plugin.PluginMeta{Name: "passthru", Version: v},
+			}), ShouldBeNil)
+		}
+		sg := &subscriptionGroup{
+			pluginControl: &pluginControl{
+				Config:        GetDefaultConfig(),
+				pluginManager: pm,
+			},
+		}
+		resolve := func(version int, constraint string) (int, error) {
+			c, err := core.ParseVersionConstraint(constraint)
+			So(err, ShouldBeNil)
+			v, serr := sg.resolveVersion("task", "processor", "passthru", version, c)
+			if serr != nil {
+				return v, serr
+			}
+			return v, nil
+		}
+
+		Convey("A requested version is used as is", func() {
+			v, err := resolve(1, "")
+			So(err, ShouldBeNil)
+			So(v, ShouldEqual, 1)
+		})
+		Convey("The latest version is used without a version constraint", func() {
+			v, err := resolve(-1, "")
+			So(err, ShouldBeNil)
+			So(v, ShouldEqual, -1)
+		})
+		Convey("The highest version meeting the version constraint is used", func() {
+			v, err := resolve(-1, ">=1,<3")
+			So(err, ShouldBeNil)
+			So(v, ShouldEqual, 2)
+			v, err = resolve(-1, "1")
+			So(err, ShouldBeNil)
+			So(v, ShouldEqual, 1)
+		})
+		Convey("An error is returned when no version meets the constraint", func() {
+			_, err := resolve(-1, ">3")
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, ErrVersionConstraintNotMet.Error())
+		})
+		Convey("When auto upgrading tasks is disabled", func() {
+			sg.Config.AutoUpgradeTasks = false
+			sg.plugins = []core.SubscribedPlugin{
+				subscribedPlugin{typeName: "processor", name: "passthru", version: 1},
+			}
+			Convey("The subscribed version is kept", func() {
+				v, err := resolve(-1, "")
+				So(err, ShouldBeNil)
+				So(v, ShouldEqual, 1)
+			})
+			Convey("The subscribed version is left when it no longer meets the constraint", func() {
+				v, err := resolve(-1, ">=2")
+				So(err, ShouldBeNil)
+				So(v, ShouldEqual, 3)
+			})
+			Convey("The latest version is used by new subscriptions", func() {
+				sg.plugins = nil
+				v, err := resolve(-1, "")
+				So(err, ShouldBeNil)
+				So(v, ShouldEqual, -1)
+			})
+		})
+		Convey("A task routed during a staged swap uses the version it is routed to", func() {
+			sg.canaries = newCanaries()
+			So(sg.canaries.add(newCanary("processor", "passthru", 2, 3, core.CanaryOptions{Tasks: []string{"task"}})), ShouldBeNil)
+			v, err := resolve(-1, "")
+			So(err, ShouldBeNil)
+			So(v, ShouldEqual, 3)
+			v, err = resolve(-1, "<3")
+			So(err, ShouldBeNil)
+			So(v, ShouldEqual, 2)
+		})
+	})
+}
@@ -35,7 +35,9 @@ var (
 	nsPriorityList            = []string{"/", "|", "%", ":", "-", ";", "_", "^", ">", "<", "+", "=", "&", "㊽", "Ä", "大", "小", "ᵹ", "☍", "ヒ"}
 )
 
-// Metric represents a snap metric collected or to be collected
+// Metric represents a snap metric collected or to be collected.  Besides
+// scalars, strings and bytes, its data can be a Histogram, a Summary, a
+// map[string]float64 or a map[string]string.
 type Metric interface {
 	RequestedMetric
 	Config() *cdata.ConfigDataNode
@@ -45,6 +47,8 @@ type Metric interface {
 	Timestamp() time.Time
 	Description() string
 	Unit() string
+	// Kind tells how the value of the metric changes between collections
+	Kind() MetricKind
 }
 
 type Namespace []NamespaceElement
@@ -118,6 +122,32 @@ func (n Namespace) IsDynamic() (bool, []int) {
 	return ret, idx
 }
 
+// Validate returns an error when an element of the namespace is a dynamic
+// element without a name or a static element with one, or is a tuple, or
+// when the namespace ends with an asterisk.  Plugins may not advertise such
+// namespaces.
+func (n Namespace) Validate() error {
+	value := ""
+	for _, i := range n {
+		// A dynamic element requires the name while a static element does not.
+		if i.Name != "" && i.Value != "*" {
+			return fmt.Errorf("A static element %s should not define name %s for namespace %s.", i.Value, i.Name, n.String())
+		}
+		if i.Name == "" && i.Value == "*" {
+			return fmt.Errorf("A dynamic element %s requires a name for namespace %s.", i.Value, n.String())
+		}
+		if strings.HasPrefix(i.Value, TuplePrefix) && strings.HasSuffix(i.Value, TupleSuffix) && strings.Contains(i.Value, TupleSeparator) {
+			return fmt.Errorf("A element %s should not define tuple for namespace %s.", i.Value, n.String())
+		}
+		value += i.Value
+	}
+	// plugin should NOT advertise metrics ending with a wildcard
+	if strings.HasSuffix(value, "*") {
+		return fmt.Errorf("Metric namespace %s ends with an asterisk is not allowed", n.String())
+	}
+	return nil
+}
+
 // NewNamespace takes an array of strings and returns a Namespace.  A Namespace
 // is an array of NamespaceElements.  The provided array of strings is used to
 // set the corresponding Value fields in the array of NamespaceElements.
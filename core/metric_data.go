@@ -0,0 +1,111 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MetricKind tells how the value of a metric changes between collections
+type MetricKind int
+
+const (
+	// UnknownMetricKind is the kind of the metrics which do not tell theirs
+	UnknownMetricKind MetricKind = iota
+	// CounterMetricKind is the kind of a value which only increases, until
+	// it is reset
+	CounterMetricKind
+	// GaugeMetricKind is the kind of a value which can go up and down
+	GaugeMetricKind
+)
+
+var metricKinds = map[MetricKind]string{
+	UnknownMetricKind: "unknown",
+	CounterMetricKind: "counter",
+	GaugeMetricKind:   "gauge",
+}
+
+func (k MetricKind) String() string {
+	if s, ok := metricKinds[k]; ok {
+		return s
+	}
+	return metricKinds[UnknownMetricKind]
+}
+
+var (
+	// ErrHistogramBuckets - error message when the counts of a histogram do not match its bounds
+	ErrHistogramBuckets = errors.New("Histogram must have one more count than bounds")
+	// ErrHistogramBounds - error message when the bounds of a histogram are not increasing
+	ErrHistogramBounds = errors.New("Histogram bounds must be increasing")
+)
+
+// Histogram is the distribution of the values observed during a period into
+// buckets.  Counts[i] is the number of values lower than or equal to
+// Bounds[i] and greater than the previous bound, the last count is the number
+// of values greater than the last bound.
+type Histogram struct {
+	Bounds []float64 `json:"bounds"`
+	Counts []uint64  `json:"counts"`
+	Sum    float64   `json:"sum"`
+	Count  uint64    `json:"count"`
+}
+
+// Validate checks that the buckets of the histogram are consistent
+func (h Histogram) Validate() error {
+	if len(h.Counts) != len(h.Bounds)+1 {
+		return fmt.Errorf("%v: %d bounds, %d counts", ErrHistogramBuckets, len(h.Bounds), len(h.Counts))
+	}
+	for i := 1; i < len(h.Bounds); i++ {
+		if h.Bounds[i] <= h.Bounds[i-1] {
+			return fmt.Errorf("%v: %v", ErrHistogramBounds, h.Bounds)
+		}
+	}
+	return nil
+}
+
+// ValidateMetricData checks that the data of a metric is consistent, the data
+// of the types without constraints is always valid
+func ValidateMetricData(data interface{}) error {
+	switch d := data.(type) {
+	case Histogram:
+		return d.Validate()
+	case *Histogram:
+		if d != nil {
+			return d.Validate()
+		}
+	}
+	return nil
+}
+
+// Quantile is the value below which the fraction Quantile of the observed
+// values falls
+type Quantile struct {
+	Quantile float64 `json:"quantile"`
+	Value    float64 `json:"value"`
+}
+
+// Summary is the distribution of the values observed during a period as
+// quantiles
+type Summary struct {
+	Quantiles []Quantile `json:"quantiles"`
+	Sum       float64    `json:"sum"`
+	Count     uint64     `json:"count"`
+}
@@ -0,0 +1,52 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestValidateMetricData(t *testing.T) {
+	Convey("Consistent histograms are valid", t, func() {
+		h := Histogram{Bounds: []float64{1, 5}, Counts: []uint64{2, 3, 1}, Sum: 17.5, Count: 6}
+		So(ValidateMetricData(h), ShouldBeNil)
+		So(ValidateMetricData(&h), ShouldBeNil)
+		So(ValidateMetricData(Histogram{Counts: []uint64{4}}), ShouldBeNil)
+	})
+	Convey("Histograms with a count per bound are invalid", t, func() {
+		err := ValidateMetricData(Histogram{Bounds: []float64{1, 5}, Counts: []uint64{2, 3}})
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldStartWith, ErrHistogramBuckets.Error())
+	})
+	Convey("Histograms with decreasing bounds are invalid", t, func() {
+		err := ValidateMetricData(&Histogram{Bounds: []float64{5, 1}, Counts: []uint64{2, 3, 1}})
+		So(err, ShouldNotBeNil)
+		So(err.Error(), ShouldStartWith, ErrHistogramBounds.Error())
+	})
+	Convey("Data of the other types is valid", t, func() {
+		So(ValidateMetricData(nil), ShouldBeNil)
+		So(ValidateMetricData(42), ShouldBeNil)
+		So(ValidateMetricData(Summary{}), ShouldBeNil)
+	})
+}
@@ -21,6 +21,28 @@ func TestMetricSeparator(t *testing.T) {
 	})
 }
 
+func TestNamespaceValidate(t *testing.T) {
+	Convey("Namespace.Validate()", t, func() {
+		Convey("passes with static and named dynamic elements", func() {
+			So(NewNamespace("mock", "foo").AddDynamicElement("host", "host name").AddStaticElement("bar").Validate(), ShouldBeNil)
+		})
+		Convey("fails for a dynamic element without a name", func() {
+			So(NewNamespace("mock", "*", "bar").Validate(), ShouldNotBeNil)
+		})
+		Convey("fails for a static element with a name", func() {
+			ns := NewNamespace("mock", "foo")
+			ns[1].Name = "static element"
+			So(ns.Validate(), ShouldNotBeNil)
+		})
+		Convey("fails for a tuple", func() {
+			So(NewNamespace("mock", TuplePrefix+"a"+TupleSeparator+"b"+TupleSuffix).Validate(), ShouldNotBeNil)
+		})
+		Convey("fails when ending with an asterisk", func() {
+			So(NewNamespace("mock", "foo*").Validate(), ShouldNotBeNil)
+		})
+	})
+}
+
 type testCase struct {
 	input    Namespace
 	expected string
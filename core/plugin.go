@@ -22,6 +22,7 @@ package core
 import (
 	"bufio"
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -39,6 +40,14 @@ import (
 	"github.com/intelsdi-x/snap/pkg/fileutils"
 )
 
+var (
+	// ErrNoRemoteEndpoint - error message when a standalone plugin is
+	// requested without endpoint
+	ErrNoRemoteEndpoint = errors.New("standalone plugin requires at least one endpoint")
+	// ErrBadRemoteEndpoint - error message when the URI of an endpoint is invalid
+	ErrBadRemoteEndpoint = errors.New("invalid standalone plugin endpoint")
+)
+
 type Plugin interface {
 	TypeName() string
 	Name() string
@@ -53,6 +62,7 @@ func ToPluginType(name string) (PluginType, error) {
 		"processor":           1,
 		"publisher":           2,
 		"streaming-collector": 3,
+		"streaming-publisher": 4,
 	}
 	t, ok := pts[name]
 	if !ok {
@@ -67,6 +77,7 @@ func CheckPluginType(id PluginType) bool {
 		1: "processor",
 		2: "publisher",
 		3: "streaming-collector",
+		4: "streaming-publisher",
 	}
 
 	_, ok := pts[id]
@@ -90,6 +101,7 @@ func (pt PluginType) String() string {
 		"processor",
 		"publisher",
 		"streaming-collector",
+		"streaming-publisher",
 	}[pt]
 }
 
@@ -99,6 +111,7 @@ const (
 	ProcessorPluginType
 	PublisherPluginType
 	StreamingCollectorPluginType
+	StreamingPublisherPluginType
 )
 
 type AvailablePlugin interface {
@@ -115,6 +128,8 @@ type AvailablePlugin interface {
 type CatalogedPlugin interface {
 	Plugin
 	IsSigned() bool
+	Signer() *PluginSigner
+	Metadata() *PluginMetadata
 	Status() string
 	PluginPath() string
 	LoadedTimestamp() *time.Time
@@ -122,6 +137,32 @@ type CatalogedPlugin interface {
 	Key() string
 }
 
+// PluginSigner describes the key a cataloged plugin was signed with
+type PluginSigner struct {
+	KeyID     string
+	Algorithm string
+	Identity  string
+}
+
+// PluginMetadata is the human facing description a plugin returns when it
+// is loaded, all the fields are optional
+type PluginMetadata struct {
+	Description string   `json:"description,omitempty"`
+	Maintainers []string `json:"maintainers,omitempty"`
+	License     string   `json:"license,omitempty"`
+	Homepage    string   `json:"homepage,omitempty"`
+	// Tags categorize the plugin, e.g. database or network
+	Tags []string `json:"tags,omitempty"`
+	// MinSnapVersion is the oldest version of snapteld the plugin runs with
+	MinSnapVersion string `json:"min_snap_version,omitempty"`
+	// Platforms are the GOOS or GOOS/GOARCH the plugin supports, any if empty
+	Platforms []string `json:"platforms,omitempty"`
+	// RequiredEnv are the environment variables the plugin needs to be set
+	RequiredEnv []string `json:"required_env,omitempty"`
+	// Extra holds any other information about the plugin
+	Extra map[string]string `json:"extra,omitempty"`
+}
+
 // the collection of cataloged plugins used
 // by mgmt modules
 type PluginCatalog []CatalogedPlugin
@@ -143,6 +184,12 @@ type RequestedPlugin struct {
 	tlsEnabled  bool
 	autoLoaded  bool
 	uri         *url.URL
+	// endpoints of a standalone plugin, the first one is uri
+	endpoints []RemoteEndpoint
+	lifecycle string
+	// download and signature URLs of a plugin fetched by control
+	downloadUrl  *url.URL
+	signatureUrl *url.URL
 }
 
 // NewRequestedPlugin returns a Requested Plugin which represents the plugin path and signature
@@ -152,7 +199,7 @@ func NewRequestedPlugin(path, fileName string, b []byte) (*RequestedPlugin, erro
 	// Checks if string is URL
 	if IsUri(path) {
 		if uri, err := url.ParseRequestURI(path); err == nil && uri != nil {
-			return &RequestedPlugin{uri: uri}, nil
+			return &RequestedPlugin{uri: uri, endpoints: []RemoteEndpoint{{URI: uri.String()}}}, nil
 		}
 	}
 	var rp *RequestedPlugin
@@ -205,6 +252,35 @@ func NewRequestedPlugin(path, fileName string, b []byte) (*RequestedPlugin, erro
 	return rp, nil
 }
 
+// NewRequestedPluginDownload returns a Requested Plugin which is downloaded
+// from an HTTP(S) URL by control before being loaded.  The downloaded
+// artifact must match the given SHA-256 checksum.
+func NewRequestedPluginDownload(downloadUrl *url.URL, checkSum [sha256.Size]byte) *RequestedPlugin {
+	return &RequestedPlugin{
+		downloadUrl: downloadUrl,
+		checkSum:    checkSum,
+	}
+}
+
+// NewRequestedRemotePlugin returns a Requested Plugin which represents a
+// standalone plugin served by each of the given endpoints
+func NewRequestedRemotePlugin(endpoints []RemoteEndpoint) (*RequestedPlugin, error) {
+	if len(endpoints) == 0 {
+		return nil, ErrNoRemoteEndpoint
+	}
+	var uri *url.URL
+	for i, e := range endpoints {
+		u, err := url.ParseRequestURI(e.URI)
+		if err != nil || !IsUri(e.URI) {
+			return nil, fmt.Errorf("%v: %v", ErrBadRemoteEndpoint, e.URI)
+		}
+		if i == 0 {
+			uri = u
+		}
+	}
+	return &RequestedPlugin{uri: uri, endpoints: endpoints}, nil
+}
+
 // Checks if string is URL
 func IsUri(url string) bool {
 	if !govalidator.IsURL(url) || !strings.HasPrefix(url, "http") {
@@ -249,6 +325,33 @@ func (p *RequestedPlugin) Uri() *url.URL {
 	return p.uri
 }
 
+// Endpoints returns the endpoints of a standalone plugin
+func (p *RequestedPlugin) Endpoints() []RemoteEndpoint {
+	return p.endpoints
+}
+
+// Lifecycle returns the lifecycle requested for the plugin, e.g. eager:2
+func (p *RequestedPlugin) Lifecycle() string {
+	return p.lifecycle
+}
+
+// DownloadUrl returns the URL the plugin is downloaded from, nil if the
+// plugin is not downloaded
+func (p *RequestedPlugin) DownloadUrl() *url.URL {
+	return p.downloadUrl
+}
+
+// SignatureUrl returns the URL the detached signature of a downloaded plugin
+// is downloaded from
+func (p *RequestedPlugin) SignatureUrl() *url.URL {
+	return p.signatureUrl
+}
+
+// AutoLoaded returns true if the plugin is loaded from the auto discover path
+func (p *RequestedPlugin) AutoLoaded() bool {
+	return p.autoLoaded
+}
+
 func (p *RequestedPlugin) SetPath(path string) {
 	p.path = path
 }
@@ -279,6 +382,27 @@ func (p *RequestedPlugin) SetSignature(data []byte) {
 
 func (p *RequestedPlugin) SetUri(uri *url.URL) {
 	p.uri = uri
+	p.endpoints = nil
+	if uri != nil {
+		p.endpoints = []RemoteEndpoint{{URI: uri.String()}}
+	}
+}
+
+// SetLifecycle sets the lifecycle requested for the plugin: lazy,
+// eager[:instances] or on-demand[:idle-timeout]
+func (p *RequestedPlugin) SetLifecycle(lifecycle string) {
+	p.lifecycle = lifecycle
+}
+
+// SetSignatureUrl sets the URL the detached signature of a downloaded plugin
+// is downloaded from
+func (p *RequestedPlugin) SetSignatureUrl(signatureUrl *url.URL) {
+	p.signatureUrl = signatureUrl
+}
+
+// SetAutoLoaded sets whether the plugin is loaded from the auto discover path
+func (p *RequestedPlugin) SetAutoLoaded(autoLoaded bool) {
+	p.autoLoaded = autoLoaded
 }
 
 func (p *RequestedPlugin) generateCheckSum() error {
@@ -300,3 +424,157 @@ func (p *RequestedPlugin) ReadSignatureFile(file string) error {
 	p.SetSignature(b)
 	return nil
 }
+
+// CacheStats describes the usage of the metric cache by a plugin
+type CacheStats struct {
+	Plugin    string
+	Entries   int
+	Bytes     int64
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+// HitRatio returns the ratio of cache hits to all cache lookups
+func (c CacheStats) HitRatio() float64 {
+	if c.Hits+c.Misses == 0 {
+		return 0
+	}
+	return float64(c.Hits) / float64(c.Hits+c.Misses)
+}
+
+const (
+	// CanaryRunning - the tasks are routed to both versions of the plugin
+	CanaryRunning = "running"
+	// CanaryCompleted - the old version of the plugin was unloaded
+	CanaryCompleted = "completed"
+	// CanaryRolledBack - the new version of the plugin was unloaded
+	CanaryRolledBack = "rolled-back"
+)
+
+// CanaryOptions defines how a staged swap routes the tasks to the new version
+// of a plugin and when the swap is rolled back.
+type CanaryOptions struct {
+	// Fraction is the fraction of the tasks routed to the new version
+	Fraction float64
+	// Tasks are the ids of the tasks always routed to the new version
+	Tasks []string
+	// Window is the time during which the failure rates are watched
+	Window time.Duration
+	// MaxFailureRate is the failure rate of the new version above which the
+	// swap is rolled back, zero stands for the default rate
+	MaxFailureRate float64
+	// MinCalls is the number of calls the new version must serve for the
+	// swap to complete, zero stands for the default number
+	MinCalls uint64
+}
+
+// CanaryVersionStats describes the requests served by a version of a plugin
+// during a staged swap
+type CanaryVersionStats struct {
+	Version  int
+	Calls    uint64
+	Failures uint64
+}
+
+// FailureRate returns the ratio of failed requests to all requests
+func (c CanaryVersionStats) FailureRate() float64 {
+	if c.Calls == 0 {
+		return 0
+	}
+	return float64(c.Failures) / float64(c.Calls)
+}
+
+// CanaryStatus describes the state of a staged swap
+type CanaryStatus struct {
+	Type    string
+	Name    string
+	From    CanaryVersionStats
+	To      CanaryVersionStats
+	Options CanaryOptions
+	// State is one of running, completed or rolled-back
+	State   string
+	Started time.Time
+	Ended   time.Time
+}
+
+// RepositoryPlugin describes a plugin stored in the plugin repository
+type RepositoryPlugin struct {
+	// CheckSum is the hex encoded SHA-256 of the plugin
+	CheckSum    string `json:"checksum"`
+	FileName    string `json:"file_name"`
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Version     int    `json:"version"`
+	Size        int64  `json:"size"`
+	Signed      bool   `json:"signed"`
+	CertPath    string `json:"cert_path,omitempty"`
+	KeyPath     string `json:"key_path,omitempty"`
+	CACertPaths string `json:"ca_cert_paths,omitempty"`
+	TLSEnabled  bool   `json:"tls_enabled"`
+	Lifecycle   string `json:"lifecycle,omitempty"`
+	// Endpoints of a standalone plugin, which has no file
+	Endpoints []RemoteEndpoint `json:"endpoints,omitempty"`
+	// Loaded is true if the plugin is loaded again on restart
+	Loaded bool      `json:"loaded"`
+	Stored time.Time `json:"stored"`
+}
+
+// RemoteEndpoint is an address a standalone plugin is served at, along with
+// the TLS settings snapteld connects to it with.  The TLS settings of control
+// apply when CertPath and KeyPath are empty.
+type RemoteEndpoint struct {
+	URI string `json:"uri"`
+	// CertPath and KeyPath are the client certificate and key
+	CertPath string `json:"cert_path,omitempty"`
+	KeyPath  string `json:"key_path,omitempty"`
+	// CACertPaths is the list of CA certificates the plugin certificate is
+	// verified with, separated by the OS path list separator
+	CACertPaths string `json:"ca_cert_paths,omitempty"`
+}
+
+// TLSEnabled returns true when the endpoint has its own TLS settings
+func (e RemoteEndpoint) TLSEnabled() bool {
+	return e.CertPath != "" && e.KeyPath != ""
+}
+
+// RemoteEndpointStatus describes the health of an endpoint of a standalone
+// plugin
+type RemoteEndpointStatus struct {
+	URI       string `json:"uri"`
+	Connected bool   `json:"connected"`
+	// FailedConnections is the number of consecutive failed attempts to
+	// connect to the endpoint
+	FailedConnections int `json:"failed_connections"`
+	// NextAttempt is when snapteld connects to the endpoint again if it is
+	// not connected
+	NextAttempt time.Time `json:"next_attempt,omitempty"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+const (
+	// PluginConfigSet merges a config into the global plugin config
+	PluginConfigSet = "set"
+	// PluginConfigDelete deletes fields from the global plugin config
+	PluginConfigDelete = "delete"
+)
+
+// PluginConfigChange is a change of the global plugin config.  The change
+// applies to all the plugins when Type is empty, to all the plugins of Type
+// when Name is empty and to all the versions of the plugin when Version is
+// lower than 1.
+type PluginConfigChange struct {
+	Time time.Time `json:"time"`
+	// User and Address of the client who made the change, if known
+	User    string `json:"user,omitempty"`
+	Address string `json:"address,omitempty"`
+	// Action is PluginConfigSet or PluginConfigDelete
+	Action  string `json:"action"`
+	Type    string `json:"type,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Version int    `json:"version,omitempty"`
+	// Config merged by a set
+	Config *cdata.ConfigDataNode `json:"config,omitempty"`
+	// Fields removed by a delete
+	Fields []string `json:"fields,omitempty"`
+}
@@ -81,7 +81,20 @@ func makeSchedule(s Schedule) (schedule.Schedule, error) {
 		}
 		return sch, nil
 	case "streaming":
-		return schedule.NewStreamingSchedule(), nil
+		// an interval is optional, the collectors of the task which are not
+		// streaming are polled on it
+		if s.Interval == "" {
+			return schedule.NewStreamingSchedule(), nil
+		}
+		d, err := time.ParseDuration(s.Interval)
+		if err != nil {
+			return nil, err
+		}
+		sch := schedule.NewPollingStreamingSchedule(d)
+		if err := sch.Validate(); err != nil {
+			return nil, err
+		}
+		return sch, nil
 	default:
 		return nil, fmt.Errorf("unknown schedule type `%s`", s.Type)
 	}
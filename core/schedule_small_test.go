@@ -27,6 +27,8 @@ import (
 	"time"
 
 	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/intelsdi-x/snap/pkg/schedule"
 )
 
 const (
@@ -219,4 +221,25 @@ func TestMakeSchedule(t *testing.T) {
 		So(err, ShouldNotBeNil)
 		So(err.Error(), ShouldStartWith, "Expected 5 or 6 fields, found ")
 	})
+
+	Convey("Streaming schedule without interval", t, func() {
+		sched1 := &Schedule{Type: "streaming"}
+		rsched, err := makeSchedule(*sched1)
+		So(err, ShouldBeNil)
+		So(rsched, ShouldResemble, schedule.NewStreamingSchedule())
+	})
+
+	Convey("Streaming schedule with polling interval", t, func() {
+		sched1 := &Schedule{Type: "streaming", Interval: "10s"}
+		rsched, err := makeSchedule(*sched1)
+		So(err, ShouldBeNil)
+		So(rsched, ShouldResemble, schedule.NewPollingStreamingSchedule(10*time.Second))
+	})
+
+	Convey("Streaming schedule with invalid interval", t, func() {
+		sched1 := &Schedule{Type: "streaming", Interval: "-10s"}
+		rsched, err := makeSchedule(*sched1)
+		So(rsched, ShouldBeNil)
+		So(err, ShouldEqual, schedule.ErrInvalidInterval)
+	})
 }
@@ -57,6 +57,22 @@ var (
 	}
 )
 
+// Policies for the batches of metrics streamed to a streaming task while the
+// buffer in front of its workflow is full.
+const (
+	// StreamDropOldest drops the oldest batch buffered to make room
+	StreamDropOldest = "oldest"
+	// StreamDropNewest drops the batch just streamed
+	StreamDropNewest = "newest"
+	// StreamDropBlock stops receiving from the stream until there is room
+	StreamDropBlock = "block"
+)
+
+var (
+	// ErrInvalidStreamDropPolicy - The error message for an unknown stream drop policy
+	ErrInvalidStreamDropPolicy = fmt.Errorf("Task stream-drop-policy must be one of %q, %q or %q", StreamDropOldest, StreamDropNewest, StreamDropBlock)
+)
+
 type TaskWatcherCloser interface {
 	Close() error
 }
@@ -94,6 +110,13 @@ type Task interface {
 	SetMaxCollectDuration(time.Duration)
 	MaxMetricsBuffer() int64
 	SetMaxMetricsBuffer(int64)
+	CacheBypass() bool
+	SetCacheBypass(bool)
+	StreamBuffer() int
+	SetStreamBuffer(int)
+	StreamDropPolicy() string
+	SetStreamDropPolicy(string)
+	DroppedCount() uint
 	GetStopOnFailure() int
 	Option(...TaskOption) TaskOption
 	WMap() *wmap.WorkflowMap
@@ -166,6 +189,36 @@ func SetMaxMetricsBuffer(b int64) TaskOption {
 	}
 }
 
+// SetCacheBypass sets whether the metrics of the task are always collected
+// from the plugins instead of being served from the metric cache.
+func SetCacheBypass(b bool) TaskOption {
+	return func(t Task) TaskOption {
+		previous := t.CacheBypass()
+		t.SetCacheBypass(b)
+		return SetCacheBypass(previous)
+	}
+}
+
+// SetStreamBuffer sets the number of batches of metrics streamed to a
+// streaming task which are buffered while its workflow is busy.
+func SetStreamBuffer(n int) TaskOption {
+	return func(t Task) TaskOption {
+		previous := t.StreamBuffer()
+		t.SetStreamBuffer(n)
+		return SetStreamBuffer(previous)
+	}
+}
+
+// SetStreamDropPolicy sets what a streaming task does with the batches of
+// metrics streamed to it when its buffer is full.
+func SetStreamDropPolicy(p string) TaskOption {
+	return func(t Task) TaskOption {
+		previous := t.StreamDropPolicy()
+		t.SetStreamDropPolicy(p)
+		return SetStreamDropPolicy(previous)
+	}
+}
+
 func SetMaxCollectDuration(d time.Duration) TaskOption {
 	return func(t Task) TaskOption {
 		previous := t.MaxCollectDuration()
@@ -188,6 +241,9 @@ type TaskCreationRequest struct {
 	MaxFailures        int               `json:"max-failures"`
 	MaxCollectDuration string            `json:"max-collect-duration"`
 	MaxMetricsBuffer   int64             `json:"max-metrics-buffer"`
+	CacheBypass        bool              `json:"cache-bypass"`
+	StreamBuffer       int               `json:"stream-buffer"`
+	StreamDropPolicy   string            `json:"stream-drop-policy"`
 }
 
 func (tr *TaskCreationRequest) UnmarshalJSON(data []byte) error {
@@ -233,6 +289,18 @@ func (tr *TaskCreationRequest) UnmarshalJSON(data []byte) error {
 			if err := json.Unmarshal(v, &(tr.MaxMetricsBuffer)); err != nil {
 				return fmt.Errorf("%v (while parsing 'max-metrics-buffer')", err)
 			}
+		case "cache-bypass":
+			if err := json.Unmarshal(v, &(tr.CacheBypass)); err != nil {
+				return fmt.Errorf("%v (while parsing 'cache-bypass')", err)
+			}
+		case "stream-buffer":
+			if err := json.Unmarshal(v, &(tr.StreamBuffer)); err != nil {
+				return fmt.Errorf("%v (while parsing 'stream-buffer')", err)
+			}
+		case "stream-drop-policy":
+			if err := json.Unmarshal(v, &(tr.StreamDropPolicy)); err != nil {
+				return fmt.Errorf("%v (while parsing 'stream-drop-policy')", err)
+			}
 		default:
 			return fmt.Errorf("Unrecognized key '%v' in task creation request", k)
 		}
@@ -300,6 +368,18 @@ func CreateTaskFromContent(body io.ReadCloser,
 		opts = append(opts, SetMaxCollectDuration(dl))
 	}
 
+	if tr.CacheBypass {
+		opts = append(opts, SetCacheBypass(tr.CacheBypass))
+	}
+
+	if tr.StreamBuffer != 0 {
+		opts = append(opts, SetStreamBuffer(tr.StreamBuffer))
+	}
+
+	if tr.StreamDropPolicy != "" {
+		opts = append(opts, SetStreamDropPolicy(tr.StreamDropPolicy))
+	}
+
 	if fp == nil {
 		return nil, errors.New("Missing workflow creation routine")
 	}
@@ -351,5 +431,15 @@ func validateTaskRequest(tr *TaskCreationRequest) error {
 	if tr.Workflow == nil || *tr.Workflow == (wmap.WorkflowMap{}) {
 		return fmt.Errorf("Task must include a workflow, and the workflow must not be empty")
 	}
+
+	if tr.StreamBuffer < 0 {
+		return fmt.Errorf("Task stream-buffer must not be negative")
+	}
+
+	switch tr.StreamDropPolicy {
+	case "", StreamDropOldest, StreamDropNewest, StreamDropBlock:
+	default:
+		return ErrInvalidStreamDropPolicy
+	}
 	return nil
 }
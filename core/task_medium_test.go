@@ -24,7 +24,9 @@ package core
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/intelsdi-x/snap/core/serror"
@@ -305,4 +307,12 @@ func TestCreateTaskFromContent(t *testing.T) {
 			So(err, ShouldBeNil)
 		})
 	})
+
+	Convey("JSON with an unknown stream drop policy", t, func() {
+		content := strings.Replace(string(JSON_FILE_CONTENT), `"max-failures": 10,`, `"max-failures": 10, "stream-drop-policy": "sometimes",`, 1)
+		autoStart := true
+		task, err := CreateTaskFromContent(ioutil.NopCloser(strings.NewReader(content)), &autoStart, okRoutine)
+		So(task, ShouldBeNil)
+		So(err, ShouldEqual, ErrInvalidStreamDropPolicy)
+	})
 }
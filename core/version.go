@@ -0,0 +1,115 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// VersionConstraint restricts the versions of a plugin a task may use.  It
+// is made of comma separated conditions which all need to be met, each one
+// being an exact version ("3" or "=3") or a bound (">=2", ">2", "<=4", "<4"),
+// e.g. ">=2,<4" for any version 2 or 3.
+type VersionConstraint struct {
+	min, max int
+	raw      string
+}
+
+// VersionConstrained is implemented by the requested metrics and the
+// subscribed plugins which declare a version constraint.
+type VersionConstrained interface {
+	VersionConstraint() *VersionConstraint
+}
+
+// ParseVersionConstraint parses a version constraint.  An empty string
+// returns a nil constraint, which is met by every version.
+func ParseVersionConstraint(s string) (*VersionConstraint, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	c := &VersionConstraint{min: 1, raw: s}
+	for _, cond := range strings.Split(s, ",") {
+		cond = strings.TrimSpace(cond)
+		op := strings.TrimRight(cond, "0123456789 ")
+		v, err := strconv.Atoi(strings.TrimSpace(cond[len(op):]))
+		if err != nil || v < 1 {
+			return nil, fmt.Errorf("invalid version constraint '%s': '%s' is not a condition on a version greater than 0", s, cond)
+		}
+		switch op {
+		case "", "=", "==":
+			c.bound(v, v)
+		case ">=":
+			c.bound(v, 0)
+		case ">":
+			c.bound(v+1, 0)
+		case "<=":
+			c.bound(0, v)
+		case "<":
+			if v == 1 {
+				return nil, fmt.Errorf("invalid version constraint '%s': no version satisfies it", s)
+			}
+			c.bound(0, v-1)
+		default:
+			return nil, fmt.Errorf("invalid version constraint '%s': unknown operator '%s'", s, op)
+		}
+	}
+	if c.max > 0 && c.min > c.max {
+		return nil, fmt.Errorf("invalid version constraint '%s': no version satisfies it", s)
+	}
+	return c, nil
+}
+
+// bound narrows the range of versions, a zero value leaving a side open.
+func (c *VersionConstraint) bound(min, max int) {
+	if min > c.min {
+		c.min = min
+	}
+	if max > 0 && (c.max == 0 || max < c.max) {
+		c.max = max
+	}
+}
+
+// Matches returns true if the version meets the constraint.
+func (c *VersionConstraint) Matches(version int) bool {
+	if c == nil {
+		return true
+	}
+	return version >= c.min && (c.max == 0 || version <= c.max)
+}
+
+// String returns the constraint as it was declared.
+func (c *VersionConstraint) String() string {
+	if c == nil {
+		return ""
+	}
+	return c.raw
+}
+
+// GetVersionConstraint returns the version constraint declared by a requested
+// metric or a subscribed plugin, nil if there is none.
+func GetVersionConstraint(i interface{}) *VersionConstraint {
+	if vc, ok := i.(VersionConstrained); ok {
+		return vc.VersionConstraint()
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseVersionConstraint(t *testing.T) {
+	Convey("Parsing version constraints", t, func() {
+		Convey("An empty constraint is met by every version", func() {
+			c, err := ParseVersionConstraint("")
+			So(err, ShouldBeNil)
+			So(c, ShouldBeNil)
+			So(c.Matches(7), ShouldBeTrue)
+			So(GetVersionConstraint(nil), ShouldBeNil)
+		})
+		Convey("An exact version", func() {
+			for _, s := range []string{"3", "=3", "== 3"} {
+				c, err := ParseVersionConstraint(s)
+				So(err, ShouldBeNil)
+				So(c.Matches(3), ShouldBeTrue)
+				So(c.Matches(2), ShouldBeFalse)
+				So(c.Matches(4), ShouldBeFalse)
+			}
+		})
+		Convey("A minimum version", func() {
+			c, err := ParseVersionConstraint(">=2")
+			So(err, ShouldBeNil)
+			So(c.Matches(1), ShouldBeFalse)
+			So(c.Matches(2), ShouldBeTrue)
+			So(c.Matches(20), ShouldBeTrue)
+			c, err = ParseVersionConstraint(">2")
+			So(err, ShouldBeNil)
+			So(c.Matches(2), ShouldBeFalse)
+			So(c.Matches(3), ShouldBeTrue)
+		})
+		Convey("A range of versions", func() {
+			c, err := ParseVersionConstraint(">=2, <4")
+			So(err, ShouldBeNil)
+			So(c.String(), ShouldEqual, ">=2, <4")
+			So(c.Matches(1), ShouldBeFalse)
+			So(c.Matches(2), ShouldBeTrue)
+			So(c.Matches(3), ShouldBeTrue)
+			So(c.Matches(4), ShouldBeFalse)
+			c, err = ParseVersionConstraint("<=4")
+			So(err, ShouldBeNil)
+			So(c.Matches(1), ShouldBeTrue)
+			So(c.Matches(5), ShouldBeFalse)
+		})
+		Convey("Invalid constraints return an error", func() {
+			for _, s := range []string{"latest", "~2", ">=0", "<1", ">=4,<3", ">=2,"} {
+				_, err := ParseVersionConstraint(s)
+				So(err, ShouldNotBeNil)
+			}
+		})
+	})
+}
@@ -50,6 +50,7 @@ func ToMetric(co core.Metric) *Metric {
 	if co.Config() != nil {
 		cm.Config = ConfigToConfigMap(co.Config())
 	}
+	cm.Kind = MetricKind(co.Kind())
 	switch t := co.Data().(type) {
 	case string:
 		cm.Data = &Metric_StringData{t}
@@ -71,6 +72,18 @@ func ToMetric(co core.Metric) *Metric {
 		cm.Data = &Metric_BytesData{t}
 	case bool:
 		cm.Data = &Metric_BoolData{t}
+	case core.Histogram:
+		cm.Data = &Metric_HistogramData{toHistogram(t)}
+	case *core.Histogram:
+		cm.Data = &Metric_HistogramData{toHistogram(*t)}
+	case core.Summary:
+		cm.Data = &Metric_SummaryData{toSummary(t)}
+	case *core.Summary:
+		cm.Data = &Metric_SummaryData{toSummary(*t)}
+	case map[string]float64:
+		cm.Data = &Metric_Float64MapData{&Float64Map{Values: t}}
+	case map[string]string:
+		cm.Data = &Metric_StringMapData{&StringMap{Values: t}}
 	case nil:
 		cm.Data = nil
 	default:
@@ -119,6 +132,7 @@ type metric struct {
 	tags               map[string]string
 	description        string
 	unit               string
+	kind               core.MetricKind
 }
 
 func (m *metric) Namespace() core.Namespace     { return m.namespace }
@@ -130,6 +144,7 @@ func (m *metric) LastAdvertisedTime() time.Time { return m.lastAdvertisedTime }
 func (m *metric) Timestamp() time.Time          { return m.timeStamp }
 func (m *metric) Description() string           { return m.description }
 func (m *metric) Unit() string                  { return m.unit }
+func (m *metric) Kind() core.MetricKind         { return m.kind }
 
 // Convert common.Metric to core.Metric
 func ToCoreMetric(mt *Metric) core.Metric {
@@ -150,6 +165,7 @@ func ToCoreMetric(mt *Metric) core.Metric {
 		config:             ConfigMapToConfig(mt.Config),
 		description:        mt.Description,
 		unit:               mt.Unit,
+		kind:               core.MetricKind(mt.Kind),
 	}
 
 	switch mt.Data.(type) {
@@ -171,10 +187,46 @@ func ToCoreMetric(mt *Metric) core.Metric {
 		ret.data = mt.GetUint64Data()
 	case *Metric_BoolData:
 		ret.data = mt.GetBoolData()
+	case *Metric_HistogramData:
+		ret.data = toCoreHistogram(mt.GetHistogramData())
+	case *Metric_SummaryData:
+		ret.data = toCoreSummary(mt.GetSummaryData())
+	case *Metric_Float64MapData:
+		ret.data = mt.GetFloat64MapData().Values
+	case *Metric_StringMapData:
+		ret.data = mt.GetStringMapData().Values
 	}
 	return ret
 }
 
+// toHistogram converts a core.Histogram to a Histogram protobuf message
+func toHistogram(h core.Histogram) *Histogram {
+	return &Histogram{Bounds: h.Bounds, Counts: h.Counts, Sum: h.Sum, Count: h.Count}
+}
+
+// toCoreHistogram converts a Histogram protobuf message to a core.Histogram
+func toCoreHistogram(h *Histogram) core.Histogram {
+	return core.Histogram{Bounds: h.GetBounds(), Counts: h.GetCounts(), Sum: h.GetSum(), Count: h.GetCount()}
+}
+
+// toSummary converts a core.Summary to a Summary protobuf message
+func toSummary(s core.Summary) *Summary {
+	quantiles := make([]*Quantile, len(s.Quantiles))
+	for i, q := range s.Quantiles {
+		quantiles[i] = &Quantile{Quantile: q.Quantile, Value: q.Value}
+	}
+	return &Summary{Quantiles: quantiles, Sum: s.Sum, Count: s.Count}
+}
+
+// toCoreSummary converts a Summary protobuf message to a core.Summary
+func toCoreSummary(s *Summary) core.Summary {
+	quantiles := make([]core.Quantile, len(s.GetQuantiles()))
+	for i, q := range s.GetQuantiles() {
+		quantiles[i] = core.Quantile{Quantile: q.GetQuantile(), Value: q.GetValue()}
+	}
+	return core.Summary{Quantiles: quantiles, Sum: s.GetSum(), Count: s.GetCount()}
+}
+
 func MetricToRequested(mts []*Metric) []core.RequestedMetric {
 	ret := make([]core.RequestedMetric, len(mts))
 	for i, mt := range mts {
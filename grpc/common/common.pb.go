@@ -36,6 +36,11 @@ It has these top-level messages:
 	SubscribedPlugin
 	ConfigMap
 	Plugin
+	Histogram
+	Quantile
+	Summary
+	Float64Map
+	StringMap
 */
 package common
 
@@ -54,6 +59,33 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+// MetricKind tells how the value of a metric changes between collections
+type MetricKind int32
+
+const (
+	MetricKind_UNKNOWN MetricKind = 0
+	// the value only increases, until it is reset
+	MetricKind_COUNTER MetricKind = 1
+	// the value can go up and down
+	MetricKind_GAUGE MetricKind = 2
+)
+
+var MetricKind_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "COUNTER",
+	2: "GAUGE",
+}
+var MetricKind_value = map[string]int32{
+	"UNKNOWN": 0,
+	"COUNTER": 1,
+	"GAUGE":   2,
+}
+
+func (x MetricKind) String() string {
+	return proto.EnumName(MetricKind_name, int32(x))
+}
+func (MetricKind) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
 type Time struct {
 	Sec  int64 `protobuf:"varint,1,opt,name=sec" json:"sec,omitempty"`
 	Nsec int64 `protobuf:"varint,2,opt,name=nsec" json:"nsec,omitempty"`
@@ -154,7 +186,12 @@ type Metric struct {
 	//	*Metric_BoolData
 	//	*Metric_Uint32Data
 	//	*Metric_Uint64Data
+	//	*Metric_HistogramData
+	//	*Metric_SummaryData
+	//	*Metric_Float64MapData
+	//	*Metric_StringMapData
 	Data isMetric_Data `protobuf_oneof:"data"`
+	Kind MetricKind    `protobuf:"varint,22,opt,name=Kind,enum=common.MetricKind" json:"Kind,omitempty"`
 }
 
 func (m *Metric) Reset()                    { *m = Metric{} }
@@ -193,16 +230,32 @@ type Metric_Uint32Data struct {
 type Metric_Uint64Data struct {
 	Uint64Data uint64 `protobuf:"varint,17,opt,name=uint64_data,json=uint64Data,oneof"`
 }
+type Metric_HistogramData struct {
+	HistogramData *Histogram `protobuf:"bytes,18,opt,name=histogram_data,json=histogramData,oneof"`
+}
+type Metric_SummaryData struct {
+	SummaryData *Summary `protobuf:"bytes,19,opt,name=summary_data,json=summaryData,oneof"`
+}
+type Metric_Float64MapData struct {
+	Float64MapData *Float64Map `protobuf:"bytes,20,opt,name=float64_map_data,json=float64MapData,oneof"`
+}
+type Metric_StringMapData struct {
+	StringMapData *StringMap `protobuf:"bytes,21,opt,name=string_map_data,json=stringMapData,oneof"`
+}
 
-func (*Metric_StringData) isMetric_Data()  {}
-func (*Metric_Float32Data) isMetric_Data() {}
-func (*Metric_Float64Data) isMetric_Data() {}
-func (*Metric_Int32Data) isMetric_Data()   {}
-func (*Metric_Int64Data) isMetric_Data()   {}
-func (*Metric_BytesData) isMetric_Data()   {}
-func (*Metric_BoolData) isMetric_Data()    {}
-func (*Metric_Uint32Data) isMetric_Data()  {}
-func (*Metric_Uint64Data) isMetric_Data()  {}
+func (*Metric_StringData) isMetric_Data()     {}
+func (*Metric_Float32Data) isMetric_Data()    {}
+func (*Metric_Float64Data) isMetric_Data()    {}
+func (*Metric_Int32Data) isMetric_Data()      {}
+func (*Metric_Int64Data) isMetric_Data()      {}
+func (*Metric_BytesData) isMetric_Data()      {}
+func (*Metric_BoolData) isMetric_Data()       {}
+func (*Metric_Uint32Data) isMetric_Data()     {}
+func (*Metric_Uint64Data) isMetric_Data()     {}
+func (*Metric_HistogramData) isMetric_Data()  {}
+func (*Metric_SummaryData) isMetric_Data()    {}
+func (*Metric_Float64MapData) isMetric_Data() {}
+func (*Metric_StringMapData) isMetric_Data()  {}
 
 func (m *Metric) GetData() isMetric_Data {
 	if m != nil {
@@ -330,6 +383,41 @@ func (m *Metric) GetUint64Data() uint64 {
 	return 0
 }
 
+func (m *Metric) GetHistogramData() *Histogram {
+	if x, ok := m.GetData().(*Metric_HistogramData); ok {
+		return x.HistogramData
+	}
+	return nil
+}
+
+func (m *Metric) GetSummaryData() *Summary {
+	if x, ok := m.GetData().(*Metric_SummaryData); ok {
+		return x.SummaryData
+	}
+	return nil
+}
+
+func (m *Metric) GetFloat64MapData() *Float64Map {
+	if x, ok := m.GetData().(*Metric_Float64MapData); ok {
+		return x.Float64MapData
+	}
+	return nil
+}
+
+func (m *Metric) GetStringMapData() *StringMap {
+	if x, ok := m.GetData().(*Metric_StringMapData); ok {
+		return x.StringMapData
+	}
+	return nil
+}
+
+func (m *Metric) GetKind() MetricKind {
+	if m != nil {
+		return m.Kind
+	}
+	return MetricKind_UNKNOWN
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*Metric) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _Metric_OneofMarshaler, _Metric_OneofUnmarshaler, _Metric_OneofSizer, []interface{}{
@@ -342,6 +430,10 @@ func (*Metric) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error,
 		(*Metric_BoolData)(nil),
 		(*Metric_Uint32Data)(nil),
 		(*Metric_Uint64Data)(nil),
+		(*Metric_HistogramData)(nil),
+		(*Metric_SummaryData)(nil),
+		(*Metric_Float64MapData)(nil),
+		(*Metric_StringMapData)(nil),
 	}
 }
 
@@ -380,6 +472,26 @@ func _Metric_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
 	case *Metric_Uint64Data:
 		b.EncodeVarint(17<<3 | proto.WireVarint)
 		b.EncodeVarint(uint64(x.Uint64Data))
+	case *Metric_HistogramData:
+		b.EncodeVarint(18<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.HistogramData); err != nil {
+			return err
+		}
+	case *Metric_SummaryData:
+		b.EncodeVarint(19<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.SummaryData); err != nil {
+			return err
+		}
+	case *Metric_Float64MapData:
+		b.EncodeVarint(20<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Float64MapData); err != nil {
+			return err
+		}
+	case *Metric_StringMapData:
+		b.EncodeVarint(21<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.StringMapData); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("Metric.Data has unexpected type %T", x)
@@ -453,6 +565,38 @@ func _Metric_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer)
 		x, err := b.DecodeVarint()
 		m.Data = &Metric_Uint64Data{x}
 		return true, err
+	case 18: // data.histogram_data
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(Histogram)
+		err := b.DecodeMessage(msg)
+		m.Data = &Metric_HistogramData{msg}
+		return true, err
+	case 19: // data.summary_data
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(Summary)
+		err := b.DecodeMessage(msg)
+		m.Data = &Metric_SummaryData{msg}
+		return true, err
+	case 20: // data.float64_map_data
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(Float64Map)
+		err := b.DecodeMessage(msg)
+		m.Data = &Metric_Float64MapData{msg}
+		return true, err
+	case 21: // data.string_map_data
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(StringMap)
+		err := b.DecodeMessage(msg)
+		m.Data = &Metric_StringMapData{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -491,6 +635,26 @@ func _Metric_OneofSizer(msg proto.Message) (n int) {
 	case *Metric_Uint64Data:
 		n += proto.SizeVarint(17<<3 | proto.WireVarint)
 		n += proto.SizeVarint(uint64(x.Uint64Data))
+	case *Metric_HistogramData:
+		s := proto.Size(x.HistogramData)
+		n += proto.SizeVarint(18<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Metric_SummaryData:
+		s := proto.Size(x.SummaryData)
+		n += proto.SizeVarint(19<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Metric_Float64MapData:
+		s := proto.Size(x.Float64MapData)
+		n += proto.SizeVarint(20<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Metric_StringMapData:
+		s := proto.Size(x.StringMapData)
+		n += proto.SizeVarint(21<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -645,6 +809,137 @@ func (m *Plugin) GetVersion() int64 {
 	return 0
 }
 
+// Histogram is the distribution of the observed values into buckets, counts
+// has one more element than bounds for the values above the last bound
+type Histogram struct {
+	Bounds []float64 `protobuf:"fixed64,1,rep,packed,name=bounds" json:"bounds,omitempty"`
+	Counts []uint64  `protobuf:"varint,2,rep,packed,name=counts" json:"counts,omitempty"`
+	Sum    float64   `protobuf:"fixed64,3,opt,name=sum" json:"sum,omitempty"`
+	Count  uint64    `protobuf:"varint,4,opt,name=count" json:"count,omitempty"`
+}
+
+func (m *Histogram) Reset()                    { *m = Histogram{} }
+func (m *Histogram) String() string            { return proto.CompactTextString(m) }
+func (*Histogram) ProtoMessage()               {}
+func (*Histogram) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *Histogram) GetBounds() []float64 {
+	if m != nil {
+		return m.Bounds
+	}
+	return nil
+}
+
+func (m *Histogram) GetCounts() []uint64 {
+	if m != nil {
+		return m.Counts
+	}
+	return nil
+}
+
+func (m *Histogram) GetSum() float64 {
+	if m != nil {
+		return m.Sum
+	}
+	return 0
+}
+
+func (m *Histogram) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+type Quantile struct {
+	Quantile float64 `protobuf:"fixed64,1,opt,name=quantile" json:"quantile,omitempty"`
+	Value    float64 `protobuf:"fixed64,2,opt,name=value" json:"value,omitempty"`
+}
+
+func (m *Quantile) Reset()                    { *m = Quantile{} }
+func (m *Quantile) String() string            { return proto.CompactTextString(m) }
+func (*Quantile) ProtoMessage()               {}
+func (*Quantile) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *Quantile) GetQuantile() float64 {
+	if m != nil {
+		return m.Quantile
+	}
+	return 0
+}
+
+func (m *Quantile) GetValue() float64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+// Summary is the distribution of the observed values as quantiles
+type Summary struct {
+	Quantiles []*Quantile `protobuf:"bytes,1,rep,name=quantiles" json:"quantiles,omitempty"`
+	Sum       float64     `protobuf:"fixed64,2,opt,name=sum" json:"sum,omitempty"`
+	Count     uint64      `protobuf:"varint,3,opt,name=count" json:"count,omitempty"`
+}
+
+func (m *Summary) Reset()                    { *m = Summary{} }
+func (m *Summary) String() string            { return proto.CompactTextString(m) }
+func (*Summary) ProtoMessage()               {}
+func (*Summary) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *Summary) GetQuantiles() []*Quantile {
+	if m != nil {
+		return m.Quantiles
+	}
+	return nil
+}
+
+func (m *Summary) GetSum() float64 {
+	if m != nil {
+		return m.Sum
+	}
+	return 0
+}
+
+func (m *Summary) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+type Float64Map struct {
+	Values map[string]float64 `protobuf:"bytes,1,rep,name=values" json:"values,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+}
+
+func (m *Float64Map) Reset()                    { *m = Float64Map{} }
+func (m *Float64Map) String() string            { return proto.CompactTextString(m) }
+func (*Float64Map) ProtoMessage()               {}
+func (*Float64Map) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *Float64Map) GetValues() map[string]float64 {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+type StringMap struct {
+	Values map[string]string `protobuf:"bytes,1,rep,name=values" json:"values,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+}
+
+func (m *StringMap) Reset()                    { *m = StringMap{} }
+func (m *StringMap) String() string            { return proto.CompactTextString(m) }
+func (*StringMap) ProtoMessage()               {}
+func (*StringMap) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+func (m *StringMap) GetValues() map[string]string {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Time)(nil), "common.Time")
 	proto.RegisterType((*Empty)(nil), "common.Empty")
@@ -655,6 +950,12 @@ func init() {
 	proto.RegisterType((*SubscribedPlugin)(nil), "common.SubscribedPlugin")
 	proto.RegisterType((*ConfigMap)(nil), "common.ConfigMap")
 	proto.RegisterType((*Plugin)(nil), "common.Plugin")
+	proto.RegisterType((*Histogram)(nil), "common.Histogram")
+	proto.RegisterType((*Quantile)(nil), "common.Quantile")
+	proto.RegisterType((*Summary)(nil), "common.Summary")
+	proto.RegisterType((*Float64Map)(nil), "common.Float64Map")
+	proto.RegisterType((*StringMap)(nil), "common.StringMap")
+	proto.RegisterEnum("common.MetricKind", MetricKind_name, MetricKind_value)
 }
 
 func init() {
@@ -662,54 +963,72 @@ func init() {
 }
 
 var fileDescriptor0 = []byte{
-	// 774 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xac, 0x95, 0xd1, 0x4e, 0xdb, 0x30,
-	0x14, 0x86, 0x9b, 0x26, 0x4d, 0x9b, 0x93, 0xc2, 0x8a, 0xb5, 0x8b, 0xa8, 0x12, 0x23, 0x64, 0x37,
-	0xd9, 0xb4, 0xb5, 0x1a, 0x30, 0xc6, 0x00, 0x21, 0x8d, 0x51, 0xd4, 0x49, 0x30, 0x4d, 0x81, 0x71,
-	0x39, 0xe4, 0xb6, 0xa6, 0x8b, 0x96, 0x38, 0x51, 0xe2, 0x22, 0xfa, 0x04, 0x7b, 0xa2, 0x3d, 0xc4,
-	0x9e, 0x65, 0x2f, 0x31, 0xd9, 0x4e, 0xd2, 0x94, 0x32, 0x55, 0x48, 0xbb, 0x81, 0xe3, 0xdf, 0xdf,
-	0x7f, 0x72, 0x4e, 0x7c, 0x9c, 0xc2, 0xf6, 0xd8, 0x67, 0xdf, 0x27, 0x83, 0xce, 0x30, 0x0a, 0xbb,
-	0x3e, 0x65, 0x24, 0x48, 0x47, 0xfe, 0xeb, 0xbb, 0x6e, 0x4a, 0x71, 0xdc, 0x1d, 0x27, 0xf1, 0xb0,
-	0x3b, 0x8c, 0xc2, 0x30, 0xa2, 0xd9, 0xbf, 0x4e, 0x9c, 0x44, 0x2c, 0x42, 0xba, 0x5c, 0x39, 0xaf,
-	0x40, 0xbb, 0xf4, 0x43, 0x82, 0x5a, 0xa0, 0xa6, 0x64, 0x68, 0x29, 0xb6, 0xe2, 0xaa, 0x1e, 0x0f,
-	0x11, 0x02, 0x8d, 0x72, 0xa9, 0x2a, 0x24, 0x11, 0x3b, 0x75, 0xa8, 0xf5, 0xc2, 0x98, 0x4d, 0x9d,
-	0x5f, 0x0a, 0x18, 0x17, 0x14, 0xc7, 0xbd, 0x24, 0x89, 0x12, 0xb4, 0x09, 0x4d, 0xc2, 0x83, 0xeb,
-	0x94, 0x25, 0x3e, 0x1d, 0x8b, 0x2c, 0x86, 0x67, 0x0a, 0xed, 0x42, 0x48, 0xa8, 0x97, 0x23, 0x37,
-	0x3e, 0x09, 0x46, 0xa9, 0x55, 0xb5, 0x55, 0xd7, 0xdc, 0x72, 0x3a, 0x59, 0x51, 0x45, 0xae, 0x8e,
-	0xf8, 0x7b, 0x2a, 0xa0, 0x1e, 0x65, 0xc9, 0x34, 0x4b, 0x23, 0x95, 0xf6, 0x11, 0xb4, 0xee, 0x03,
-	0xbc, 0xf4, 0x1f, 0x64, 0x9a, 0x3d, 0x94, 0x87, 0xe8, 0x29, 0xd4, 0x6e, 0x71, 0x30, 0x21, 0xa2,
-	0x76, 0xc3, 0x93, 0x8b, 0xfd, 0xea, 0x9e, 0xe2, 0xbc, 0x81, 0xda, 0x19, 0x1e, 0x90, 0x80, 0x23,
-	0x3e, 0x1d, 0x91, 0x3b, 0x61, 0xd3, 0x3c, 0xb9, 0x10, 0x3d, 0xe3, 0x30, 0xf7, 0x89, 0xd8, 0xf9,
-	0x5d, 0x03, 0xfd, 0x9c, 0xb0, 0xc4, 0x1f, 0xa2, 0x5d, 0x30, 0x3e, 0xe3, 0x90, 0xa4, 0x31, 0x1e,
-	0x12, 0x4b, 0x11, 0x1d, 0x58, 0x79, 0x07, 0xc5, 0x46, 0x2f, 0x20, 0x21, 0xa1, 0xcc, 0x9b, 0xa1,
-	0xc8, 0x82, 0xfa, 0x15, 0x49, 0x52, 0x3f, 0xa2, 0xd9, 0xdb, 0xcc, 0x97, 0xe8, 0x05, 0xe8, 0x1f,
-	0x23, 0x7a, 0xe3, 0x8f, 0x2d, 0xd5, 0x56, 0x5c, 0x73, 0x6b, 0x2d, 0x4f, 0x27, 0xd5, 0x73, 0x1c,
-	0x7b, 0x19, 0x80, 0x0e, 0x01, 0x9d, 0xe1, 0x94, 0x7d, 0x18, 0xdd, 0x92, 0x84, 0xf9, 0x29, 0x19,
-	0xf1, 0x73, 0xb3, 0x34, 0x61, 0x6b, 0xe6, 0x36, 0xae, 0x79, 0x0f, 0x70, 0x88, 0x9f, 0x33, 0x1e,
-	0xa7, 0x56, 0x6d, 0xbe, 0x6a, 0xd9, 0x58, 0x87, 0x6f, 0xc9, 0xb7, 0x2d, 0x28, 0xf4, 0x12, 0x0c,
-	0xee, 0x4a, 0x19, 0x0e, 0x63, 0x4b, 0x7f, 0xe0, 0x11, 0xb3, 0x6d, 0xfe, 0xce, 0xbe, 0x52, 0x9f,
-	0x59, 0x75, 0xf9, 0xce, 0x78, 0x8c, 0x6c, 0x30, 0x4f, 0x48, 0x3a, 0x4c, 0xfc, 0x98, 0xf1, 0xa6,
-	0x1b, 0x72, 0x1e, 0x4a, 0x12, 0xda, 0x04, 0x53, 0x0e, 0xcb, 0xf5, 0x08, 0x33, 0x6c, 0x19, 0x9c,
-	0xe8, 0x57, 0x3c, 0x90, 0xe2, 0x09, 0x66, 0x18, 0x3d, 0x87, 0xe6, 0x4d, 0x10, 0x61, 0xb6, 0xbd,
-	0x25, 0x19, 0xb0, 0x15, 0xb7, 0xda, 0xaf, 0x78, 0x66, 0xa6, 0xce, 0x41, 0xbb, 0x3b, 0x12, 0x32,
-	0x6d, 0xc5, 0x55, 0x0a, 0x68, 0x77, 0x47, 0x40, 0x1b, 0x00, 0x3e, 0x2d, 0xf2, 0x34, 0x6d, 0xc5,
-	0xad, 0xf5, 0x2b, 0x9e, 0x21, 0xb4, 0x12, 0x90, 0xe7, 0x58, 0xe1, 0x67, 0x94, 0x01, 0xb3, 0x0c,
-	0x83, 0x29, 0x23, 0xa9, 0x04, 0x56, 0x6d, 0xc5, 0x6d, 0x72, 0x40, 0x68, 0x02, 0x58, 0x07, 0x63,
-	0x10, 0x45, 0x81, 0xdc, 0x7f, 0x62, 0x2b, 0x6e, 0xa3, 0x5f, 0xf1, 0x1a, 0x5c, 0x12, 0xdb, 0x9b,
-	0x60, 0x4e, 0x4a, 0x25, 0xb4, 0x6c, 0xc5, 0x5d, 0xe1, 0xed, 0x4e, 0x66, 0x35, 0x64, 0x48, 0x5e,
-	0xc4, 0x1a, 0x9f, 0xcb, 0x1c, 0x91, 0x55, 0xb4, 0xdf, 0x81, 0x51, 0x9c, 0xd4, 0x63, 0xc6, 0xfe,
-	0x58, 0x07, 0x8d, 0x27, 0x75, 0xbe, 0x41, 0xeb, 0xfe, 0x9c, 0x72, 0xd7, 0x95, 0x70, 0xc9, 0x4c,
-	0x72, 0x71, 0xff, 0x04, 0xab, 0x8b, 0x27, 0x88, 0x40, 0xe3, 0xb9, 0xc4, 0xe0, 0x1a, 0x9e, 0x88,
-	0x9d, 0x9f, 0x0a, 0xb4, 0x2e, 0x26, 0x03, 0x0e, 0x0d, 0xc8, 0xe8, 0x4b, 0x30, 0x19, 0xfb, 0x14,
-	0xb5, 0xa1, 0x71, 0x39, 0x8d, 0x89, 0x80, 0xe5, 0x33, 0x8a, 0x75, 0x91, 0xa4, 0x3a, 0x4b, 0x52,
-	0xbe, 0x2d, 0xea, 0xbf, 0x6e, 0x8b, 0xb6, 0xe4, 0xb6, 0x38, 0x7f, 0x54, 0x30, 0x0a, 0x15, 0xbd,
-	0x05, 0xfd, 0x13, 0x65, 0xe7, 0x38, 0xce, 0x6e, 0xed, 0xfa, 0x82, 0xb1, 0x23, 0xf7, 0xe5, 0x25,
-	0xc8, 0x60, 0x74, 0x04, 0x86, 0xfc, 0x7c, 0x71, 0xa7, 0xfc, 0x62, 0xd9, 0x8b, 0xce, 0x02, 0x91,
-	0xe6, 0x99, 0x05, 0x1d, 0x40, 0xe3, 0x94, 0x8f, 0x21, 0xb7, 0xab, 0xc2, 0xbe, 0xb1, 0x68, 0xcf,
-	0x09, 0xe9, 0x2e, 0x0c, 0x68, 0x0f, 0xea, 0xc7, 0x51, 0x14, 0x70, 0xaf, 0x26, 0xbc, 0xcf, 0x16,
-	0xbd, 0x19, 0x20, 0xad, 0x39, 0xde, 0x7e, 0x0f, 0x66, 0xa9, 0x9b, 0x65, 0x83, 0xa2, 0x96, 0x06,
-	0xa5, 0x7d, 0x08, 0xab, 0xf3, 0xed, 0x3c, 0x66, 0xcc, 0xda, 0x07, 0xb0, 0x32, 0xd7, 0xcd, 0x32,
-	0xb3, 0x52, 0x36, 0xef, 0x43, 0xb3, 0xdc, 0xce, 0x32, 0x6f, 0xa3, 0xfc, 0x59, 0xf7, 0x40, 0xff,
-	0xdf, 0xc3, 0x36, 0xd0, 0xc5, 0x0f, 0xe5, 0xf6, 0xdf, 0x00, 0x00, 0x00, 0xff, 0xff, 0xd1, 0xc9,
-	0x60, 0x28, 0x5f, 0x07, 0x00, 0x00,
+	// 1065 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xac, 0x56, 0xdb, 0x6e, 0xdb, 0x46,
+	0x10, 0x15, 0x25, 0xea, 0xc2, 0x91, 0x6c, 0x2b, 0xdb, 0x34, 0x20, 0x04, 0x38, 0xa1, 0x59, 0xa0,
+	0x50, 0x83, 0x56, 0x46, 0x6c, 0xd7, 0x4d, 0x6d, 0xc3, 0x40, 0x2e, 0x4a, 0x54, 0x24, 0x76, 0xda,
+	0xb5, 0x9d, 0xbe, 0x35, 0x58, 0x49, 0x6b, 0x85, 0x28, 0x6f, 0x25, 0x97, 0x41, 0xf4, 0x50, 0xf4,
+	0xb1, 0x5f, 0xd4, 0xff, 0xe9, 0x7b, 0x7f, 0xa2, 0xd8, 0xd9, 0x25, 0x45, 0x59, 0x0e, 0x0c, 0x03,
+	0x79, 0xb1, 0x77, 0x66, 0xce, 0x99, 0x9d, 0xd9, 0x3d, 0x3b, 0x14, 0xec, 0xce, 0x3c, 0xf1, 0x3e,
+	0x1b, 0x0f, 0x26, 0x51, 0xb0, 0xed, 0x85, 0x82, 0xfb, 0xe9, 0xd4, 0xfb, 0xee, 0xe3, 0x76, 0x1a,
+	0xb2, 0x78, 0x7b, 0x96, 0xc4, 0x93, 0xed, 0x49, 0x14, 0x04, 0x51, 0xa8, 0xff, 0x0d, 0xe2, 0x24,
+	0x12, 0x11, 0x69, 0x28, 0xcb, 0xfd, 0x16, 0xcc, 0x73, 0x2f, 0xe0, 0xa4, 0x0b, 0xb5, 0x94, 0x4f,
+	0x6c, 0xc3, 0x31, 0xfa, 0x35, 0x2a, 0x97, 0x84, 0x80, 0x19, 0x4a, 0x57, 0x15, 0x5d, 0xb8, 0x76,
+	0x9b, 0x50, 0x1f, 0x06, 0xb1, 0x98, 0xbb, 0xff, 0x18, 0x60, 0x9d, 0x85, 0x2c, 0x1e, 0x26, 0x49,
+	0x94, 0x90, 0x2d, 0xe8, 0x70, 0xb9, 0x78, 0x97, 0x8a, 0xc4, 0x0b, 0x67, 0x98, 0xc5, 0xa2, 0x6d,
+	0xf4, 0x9d, 0xa1, 0x8b, 0x0c, 0x73, 0xc8, 0xa5, 0xc7, 0xfd, 0x69, 0x6a, 0x57, 0x9d, 0x5a, 0xbf,
+	0xbd, 0xe3, 0x0e, 0x74, 0x51, 0x45, 0xae, 0x01, 0xfe, 0x7d, 0x81, 0xa0, 0x61, 0x28, 0x92, 0xb9,
+	0x4e, 0xa3, 0x3c, 0xbd, 0x63, 0xe8, 0x5e, 0x05, 0xc8, 0xd2, 0x7f, 0xe7, 0x73, 0xbd, 0xa9, 0x5c,
+	0x92, 0xbb, 0x50, 0xff, 0xc0, 0xfc, 0x8c, 0x63, 0xed, 0x16, 0x55, 0xc6, 0x41, 0xf5, 0xb1, 0xe1,
+	0x3e, 0x82, 0xfa, 0x6b, 0x36, 0xe6, 0xbe, 0x84, 0x78, 0xe1, 0x94, 0x7f, 0x44, 0x9a, 0x49, 0x95,
+	0x81, 0x3d, 0xb3, 0x20, 0xe7, 0xe1, 0xda, 0xfd, 0xb7, 0x09, 0x8d, 0x13, 0x2e, 0x12, 0x6f, 0x42,
+	0xf6, 0xc1, 0x3a, 0x65, 0x01, 0x4f, 0x63, 0x36, 0xe1, 0xb6, 0x81, 0x1d, 0xd8, 0x79, 0x07, 0x45,
+	0x60, 0xe8, 0xf3, 0x80, 0x87, 0x82, 0x2e, 0xa0, 0xc4, 0x86, 0xe6, 0x5b, 0x9e, 0xa4, 0x5e, 0x14,
+	0xea, 0xd3, 0xcc, 0x4d, 0xf2, 0x0d, 0x34, 0x9e, 0x45, 0xe1, 0xa5, 0x37, 0xb3, 0x6b, 0x8e, 0xd1,
+	0x6f, 0xef, 0xdc, 0xc9, 0xd3, 0x29, 0xef, 0x09, 0x8b, 0xa9, 0x06, 0x90, 0x23, 0x20, 0xaf, 0x59,
+	0x2a, 0x9e, 0x4c, 0x3f, 0xf0, 0x44, 0x78, 0x29, 0x9f, 0xca, 0x7b, 0xb3, 0x4d, 0xa4, 0x75, 0x72,
+	0x9a, 0xf4, 0xd1, 0x6b, 0x70, 0x44, 0xde, 0x33, 0x9b, 0xa5, 0x76, 0x7d, 0xb9, 0x6a, 0xd5, 0xd8,
+	0x40, 0x86, 0xd4, 0x69, 0x23, 0x8a, 0x3c, 0x04, 0x4b, 0xb2, 0x52, 0xc1, 0x82, 0xd8, 0x6e, 0x5c,
+	0xb3, 0xc5, 0x22, 0x2c, 0xcf, 0xec, 0x22, 0xf4, 0x84, 0xdd, 0x54, 0x67, 0x26, 0xd7, 0xc4, 0x81,
+	0xf6, 0x73, 0x9e, 0x4e, 0x12, 0x2f, 0x16, 0xb2, 0xe9, 0x96, 0xd2, 0x43, 0xc9, 0x45, 0xb6, 0xa0,
+	0xad, 0xc4, 0xf2, 0x6e, 0xca, 0x04, 0xb3, 0x2d, 0x89, 0x18, 0x55, 0x28, 0x28, 0xe7, 0x73, 0x26,
+	0x18, 0xf9, 0x0a, 0x3a, 0x97, 0x7e, 0xc4, 0xc4, 0xee, 0x8e, 0xc2, 0x80, 0x63, 0xf4, 0xab, 0xa3,
+	0x0a, 0x6d, 0x6b, 0xef, 0x12, 0x68, 0x7f, 0x4f, 0x81, 0xda, 0x8e, 0xd1, 0x37, 0x0a, 0xd0, 0xfe,
+	0x1e, 0x82, 0x1e, 0x00, 0x78, 0x61, 0x91, 0xa7, 0xe3, 0x18, 0xfd, 0xfa, 0xa8, 0x42, 0x2d, 0xf4,
+	0x95, 0x00, 0x79, 0x8e, 0x35, 0x79, 0x47, 0x1a, 0xb0, 0xc8, 0x30, 0x9e, 0x0b, 0x9e, 0x2a, 0xc0,
+	0xba, 0x63, 0xf4, 0x3b, 0x12, 0x80, 0x3e, 0x04, 0x6c, 0x82, 0x35, 0x8e, 0x22, 0x5f, 0xc5, 0x37,
+	0x1c, 0xa3, 0xdf, 0x1a, 0x55, 0x68, 0x4b, 0xba, 0x30, 0xbc, 0x05, 0xed, 0xac, 0x54, 0x42, 0xd7,
+	0x31, 0xfa, 0x6b, 0xb2, 0xdd, 0x6c, 0x51, 0x83, 0x86, 0xe4, 0x45, 0xdc, 0x91, 0xba, 0xcc, 0x21,
+	0xba, 0x8a, 0x03, 0x58, 0x7f, 0xef, 0xa5, 0x22, 0x9a, 0x25, 0x2c, 0x50, 0x28, 0xb2, 0xac, 0x9a,
+	0x51, 0x1e, 0x1d, 0x55, 0xe8, 0x5a, 0x01, 0x45, 0xee, 0x1e, 0x74, 0xd2, 0x2c, 0x08, 0x58, 0x32,
+	0x57, 0xcc, 0x2f, 0x90, 0xb9, 0x51, 0x3c, 0x40, 0x15, 0x93, 0x27, 0xa7, 0x61, 0xc8, 0x3a, 0x86,
+	0x6e, 0x7e, 0xbc, 0x01, 0x8b, 0x15, 0xf3, 0x2e, 0x32, 0x49, 0xce, 0x7c, 0xa1, 0xe2, 0x27, 0x2c,
+	0x1e, 0x55, 0xe8, 0xfa, 0x65, 0x61, 0x21, 0xff, 0x10, 0x36, 0xf4, 0x35, 0x17, 0xf4, 0x2f, 0x97,
+	0x4b, 0x56, 0xf3, 0x41, 0xb1, 0xd7, 0xd2, 0xdc, 0x40, 0xf2, 0xd7, 0x60, 0xbe, 0xf2, 0xc2, 0xa9,
+	0x7d, 0xcf, 0x31, 0xfa, 0xeb, 0x3b, 0x64, 0x59, 0xb3, 0x32, 0x42, 0x31, 0xde, 0xfb, 0x01, 0xac,
+	0x42, 0xc0, 0xb7, 0x99, 0x06, 0x4f, 0x1b, 0x60, 0xca, 0x92, 0xdc, 0xdf, 0xa0, 0x7b, 0xf5, 0xf9,
+	0x4a, 0xd6, 0x5b, 0x64, 0xa9, 0x4c, 0xca, 0xb8, 0x2a, 0xec, 0xea, 0xaa, 0xb0, 0x09, 0x98, 0x32,
+	0x17, 0xbe, 0x67, 0x8b, 0xe2, 0xda, 0xfd, 0xdb, 0x80, 0xee, 0x59, 0x36, 0x96, 0xa0, 0x31, 0x9f,
+	0xfe, 0xec, 0x67, 0x33, 0x2f, 0x24, 0x3d, 0x68, 0x9d, 0xcf, 0x63, 0x8e, 0x60, 0xb5, 0x47, 0x61,
+	0x17, 0x49, 0xaa, 0x8b, 0x24, 0xe5, 0x21, 0x52, 0xfb, 0xd4, 0x10, 0x31, 0x6f, 0x18, 0x22, 0xee,
+	0x7f, 0x35, 0xb0, 0x0a, 0x2f, 0xf9, 0x1e, 0x1a, 0x3f, 0x85, 0xe2, 0x84, 0xc5, 0x7a, 0x98, 0x6d,
+	0xae, 0x10, 0x07, 0x2a, 0xae, 0x66, 0x83, 0x06, 0x93, 0x63, 0xb0, 0x8a, 0x5b, 0xd3, 0x83, 0xdc,
+	0x59, 0x65, 0x16, 0x10, 0x45, 0x5e, 0x50, 0xc8, 0x21, 0xb4, 0x50, 0x34, 0x92, 0x5e, 0x43, 0xfa,
+	0x83, 0x55, 0x7a, 0x8e, 0x50, 0xec, 0x82, 0x40, 0x1e, 0x43, 0xf3, 0x69, 0x14, 0xf9, 0x92, 0x6b,
+	0x22, 0xf7, 0xfe, 0x2a, 0x57, 0x03, 0x14, 0x35, 0x87, 0xf7, 0x7e, 0x84, 0x76, 0xa9, 0x9b, 0x9b,
+	0x84, 0x52, 0x2b, 0x09, 0xa5, 0x77, 0x04, 0xeb, 0xcb, 0xed, 0xdc, 0x46, 0x66, 0xbd, 0x43, 0x58,
+	0x5b, 0xea, 0xe6, 0x26, 0xb2, 0x51, 0x26, 0x1f, 0x40, 0xa7, 0xdc, 0xce, 0x4d, 0xdc, 0x56, 0xf9,
+	0x6b, 0x47, 0xa1, 0xf1, 0xb9, 0xc5, 0xe6, 0x4e, 0xc0, 0x2a, 0xa6, 0x0c, 0xb9, 0x07, 0x8d, 0x71,
+	0x94, 0x85, 0xd3, 0x14, 0x05, 0x64, 0x50, 0x6d, 0x49, 0xff, 0x24, 0xca, 0x42, 0xa1, 0xbe, 0xf3,
+	0x26, 0xd5, 0x16, 0xfe, 0xca, 0xc8, 0x02, 0x4c, 0x69, 0x50, 0xb9, 0x94, 0xc5, 0x63, 0x0c, 0xa5,
+	0x6b, 0x52, 0x65, 0xb8, 0x47, 0xd0, 0xfa, 0x25, 0x63, 0xa1, 0xf0, 0x7c, 0x2e, 0x4b, 0xff, 0x43,
+	0xaf, 0xb1, 0x74, 0x83, 0x16, 0xf6, 0xf5, 0xc7, 0xe6, 0x32, 0x68, 0xea, 0x71, 0x46, 0x06, 0x60,
+	0xe5, 0xe0, 0x54, 0x8b, 0xbc, 0x9b, 0xeb, 0x25, 0xdf, 0x81, 0x2e, 0x20, 0x79, 0x81, 0xd5, 0x6b,
+	0x0a, 0xac, 0x95, 0x0b, 0xfc, 0x0b, 0x60, 0x31, 0xf7, 0xc8, 0x3e, 0x34, 0x70, 0xe7, 0x7c, 0x8b,
+	0xfb, 0xab, 0xb3, 0x71, 0x80, 0xf3, 0x43, 0x7f, 0x64, 0x35, 0x5a, 0x2a, 0xb2, 0xe4, 0xbe, 0x8d,
+	0x2c, 0xdc, 0x3f, 0x4b, 0x6f, 0x50, 0xbe, 0xe3, 0xa5, 0xfd, 0x37, 0x57, 0x86, 0xeb, 0x67, 0xd8,
+	0xbe, 0x2c, 0xe9, 0x87, 0x8f, 0x00, 0x16, 0x63, 0x98, 0xb4, 0xa1, 0x79, 0x71, 0xfa, 0xea, 0xf4,
+	0xcd, 0xaf, 0xa7, 0xdd, 0x8a, 0x34, 0x9e, 0xbd, 0xb9, 0x38, 0x3d, 0x1f, 0xd2, 0xae, 0x41, 0x2c,
+	0xa8, 0xbf, 0x7c, 0x72, 0xf1, 0x72, 0xd8, 0xad, 0x8e, 0x1b, 0xf8, 0xc3, 0x73, 0xf7, 0xff, 0x01,
+	0x00, 0x8d, 0x20, 0xdb, 0x88, 0xaf, 0x0a, 0x00, 0x00,
 }
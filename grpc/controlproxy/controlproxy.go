@@ -21,6 +21,8 @@ package controlproxy
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/intelsdi-x/snap/core"
@@ -30,33 +32,103 @@ import (
 	"github.com/intelsdi-x/snap/grpc/common"
 	"github.com/intelsdi-x/snap/grpc/controlproxy/rpc"
 	"github.com/intelsdi-x/snap/pkg/rpcutil"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 var (
 	MAX_CONNECTION_TIMEOUT = 10 * time.Second
 )
 
+// Channel holds the settings of the gRPC channel to a remote control.
+type Channel struct {
+	// Compression lists the compressors of the calls in order of
+	// preference, the first one the remote control accepts is used
+	Compression []string
+	// MaxMessageSize overrides the gRPC maximum size of the messages
+	// exchanged with the remote control when it is above 0
+	MaxMessageSize int
+}
+
 func getContext() context.Context {
 	cd, _ := context.WithTimeout(context.Background(), MAX_CONNECTION_TIMEOUT)
 	return cd
 }
 
+// getCallContext bounds the caller's context by MAX_CONNECTION_TIMEOUT so the
+// task's deadline and cancellation reach the remote control.
+func getCallContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithTimeout(ctx, MAX_CONNECTION_TIMEOUT)
+}
+
 // Implements managesMetrics interface provided by scheduler and
 // proxies those calls to the grpc client.
 type ControlProxy struct {
 	Client rpc.MetricManagerClient
+
+	// tasks whose metrics are collected bypassing the metric cache of the
+	// remote control
+	cacheBypass *cacheBypass
 }
 
-func New(addr string, port int) (ControlProxy, error) {
-	conn, err := rpcutil.GetClientConnection(context.Background(), addr, port)
+type cacheBypass struct {
+	sync.RWMutex
+	tasks map[string]bool
+}
+
+// New returns the proxy of the control listening on addr:port.  Its calls are
+// compressed with the first compressor of the channel the remote control
+// names in the header of its replies, and left uncompressed when it names
+// none of them.
+func New(addr string, port int, channel Channel) (ControlProxy, error) {
+	conn, err := dial(addr, port, "", channel.MaxMessageSize)
 	if err != nil {
 		return ControlProxy{}, err
 	}
 	c := rpc.NewMetricManagerClient(conn)
-	return ControlProxy{Client: c}, nil
+	if len(channel.Compression) == 0 {
+		return newControlProxy(c), nil
+	}
+	var header metadata.MD
+	if _, err := c.GetAutodiscoverPaths(getContext(), &common.Empty{}, grpc.Header(&header)); err != nil {
+		log.WithFields(log.Fields{
+			"_module": "control-proxy",
+			"_block":  "new",
+			"address": fmt.Sprintf("%v:%v", addr, port),
+			"error":   err,
+		}).Warn("unable to negotiate the compression with the remote control, calls are not compressed")
+		return newControlProxy(c), nil
+	}
+	compression := rpcutil.NegotiateCompression(channel.Compression, header[rpcutil.CompressionHeader])
+	if compression == "" {
+		return newControlProxy(c), nil
+	}
+	conn.Close()
+	if conn, err = dial(addr, port, compression, channel.MaxMessageSize); err != nil {
+		return ControlProxy{}, err
+	}
+	return newControlProxy(rpc.NewMetricManagerClient(conn)), nil
 }
 
-func (c ControlProxy) PublishMetrics(metrics []core.Metric,
+func newControlProxy(c rpc.MetricManagerClient) ControlProxy {
+	return ControlProxy{Client: c, cacheBypass: &cacheBypass{tasks: map[string]bool{}}}
+}
+
+// dial returns a connection to the control listening on addr:port.
+func dial(addr string, port int, compression string, maxMessageSize int) (*grpc.ClientConn, error) {
+	opts, err := rpcutil.ChannelDialOptions(compression, maxMessageSize)
+	if err != nil {
+		return nil, err
+	}
+	return rpcutil.GetClientConnectionWithCreds(context.Background(), addr, port, nil, opts...)
+}
+
+func (c ControlProxy) PublishMetrics(ctx context.Context,
+	metrics []core.Metric,
 	config map[string]ctypes.ConfigValue,
 	taskId string,
 	pluginName string,
@@ -69,7 +141,9 @@ func (c ControlProxy) PublishMetrics(metrics []core.Metric,
 		TaskId:        taskId,
 		Config:        common.ToConfigMap(config),
 	}
-	reply, err := c.Client.PublishMetrics(getContext(), req)
+	ctx, cancel := getCallContext(ctx)
+	defer cancel()
+	reply, err := c.Client.PublishMetrics(ctx, req)
 	var errs []error
 	if err != nil {
 		errs = append(errs, err)
@@ -80,7 +154,8 @@ func (c ControlProxy) PublishMetrics(metrics []core.Metric,
 	return errs
 }
 
-func (c ControlProxy) ProcessMetrics(metrics []core.Metric,
+func (c ControlProxy) ProcessMetrics(ctx context.Context,
+	metrics []core.Metric,
 	config map[string]ctypes.ConfigValue,
 	taskId string,
 	pluginName string,
@@ -92,7 +167,9 @@ func (c ControlProxy) ProcessMetrics(metrics []core.Metric,
 		TaskId:        taskId,
 		Config:        common.ToConfigMap(config),
 	}
-	reply, err := c.Client.ProcessMetrics(getContext(), req)
+	ctx, cancel := getCallContext(ctx)
+	defer cancel()
+	reply, err := c.Client.ProcessMetrics(ctx, req)
 	var errs []error
 	if err != nil {
 		errs = append(errs, err)
@@ -103,7 +180,7 @@ func (c ControlProxy) ProcessMetrics(metrics []core.Metric,
 	return common.ToCoreMetrics(reply.Metrics), errs
 }
 
-func (c ControlProxy) CollectMetrics(taskID string, AllTags map[string]map[string]string) ([]core.Metric, []error) {
+func (c ControlProxy) CollectMetrics(ctx context.Context, taskID string, AllTags map[string]map[string]string) ([]core.Metric, []error) {
 	var allTags map[string]*rpc.Map
 	for k, v := range AllTags {
 		tags := &rpc.Map{}
@@ -117,10 +194,13 @@ func (c ControlProxy) CollectMetrics(taskID string, AllTags map[string]map[strin
 		allTags[k] = tags
 	}
 	req := &rpc.CollectMetricsRequest{
-		TaskID:  taskID,
-		AllTags: allTags,
+		TaskID:      taskID,
+		AllTags:     allTags,
+		CacheBypass: c.bypassesCache(taskID),
 	}
-	reply, err := c.Client.CollectMetrics(getContext(), req)
+	ctx, cancel := getCallContext(ctx)
+	defer cancel()
+	reply, err := c.Client.CollectMetrics(ctx, req)
 	var errs []error
 	if err != nil {
 		errs = append(errs, err)
@@ -135,6 +215,31 @@ func (c ControlProxy) CollectMetrics(taskID string, AllTags map[string]map[strin
 	return metrics, nil
 }
 
+// SetCacheBypass sets whether the metrics of the given task are always
+// collected from the plugins of the remote control instead of being served
+// from its metric cache.  The setting is sent along with each collection.
+func (c ControlProxy) SetCacheBypass(id string, bypass bool) {
+	if c.cacheBypass == nil {
+		return
+	}
+	c.cacheBypass.Lock()
+	defer c.cacheBypass.Unlock()
+	if !bypass {
+		delete(c.cacheBypass.tasks, id)
+		return
+	}
+	c.cacheBypass.tasks[id] = true
+}
+
+func (c ControlProxy) bypassesCache(id string) bool {
+	if c.cacheBypass == nil {
+		return false
+	}
+	c.cacheBypass.RLock()
+	defer c.cacheBypass.RUnlock()
+	return c.cacheBypass.tasks[id]
+}
+
 func (c ControlProxy) StreamMetrics(
 	_ string,
 	_ map[string]map[string]string,
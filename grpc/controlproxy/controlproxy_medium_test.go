@@ -23,6 +23,7 @@ package controlproxy
 
 import (
 	"errors"
+	"net"
 	"testing"
 	"time"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/intelsdi-x/snap/core/ctypes"
 	"github.com/intelsdi-x/snap/grpc/common"
 	"github.com/intelsdi-x/snap/grpc/controlproxy/rpc"
+	"github.com/intelsdi-x/snap/pkg/rpcutil"
 
 	"github.com/intelsdi-x/snap/core/cdata"
 	. "github.com/smartystreets/goconvey/convey"
@@ -99,7 +101,7 @@ func (mc mockClient) UnsubscribeDeps(ctx context.Context, in *rpc.UnsubscribeDep
 func TestPublishMetrics(t *testing.T) {
 	Convey("RPC client errors", t, func() {
 		proxy := ControlProxy{Client: mockClient{RpcErr: true}}
-		errs := proxy.PublishMetrics([]core.Metric{}, map[string]ctypes.ConfigValue{}, "", "fake", 1)
+		errs := proxy.PublishMetrics(context.Background(), []core.Metric{}, map[string]ctypes.ConfigValue{}, "", "fake", 1)
 
 		Convey("So the error should be passed through", func() {
 			So(errs[0].Error(), ShouldResemble, rpcErr.Error())
@@ -112,7 +114,7 @@ func TestPublishMetrics(t *testing.T) {
 		}
 
 		proxy := ControlProxy{Client: mockClient{PublishReply: reply}}
-		errs := proxy.PublishMetrics([]core.Metric{}, map[string]ctypes.ConfigValue{}, "", "fake", 1)
+		errs := proxy.PublishMetrics(context.Background(), []core.Metric{}, map[string]ctypes.ConfigValue{}, "", "fake", 1)
 
 		Convey("So err should not be nil", func() {
 			So(errs, ShouldNotBeNil)
@@ -127,7 +129,7 @@ func TestPublishMetrics(t *testing.T) {
 		reply := &rpc.ErrorReply{Errors: []string{}}
 
 		proxy := ControlProxy{Client: mockClient{PublishReply: reply}}
-		errs := proxy.PublishMetrics([]core.Metric{}, map[string]ctypes.ConfigValue{}, "", "fake", 1)
+		errs := proxy.PublishMetrics(context.Background(), []core.Metric{}, map[string]ctypes.ConfigValue{}, "", "fake", 1)
 
 		Convey("So publishing should not error", func() {
 			So(len(errs), ShouldEqual, 0)
@@ -138,7 +140,7 @@ func TestPublishMetrics(t *testing.T) {
 func TestProcessMetrics(t *testing.T) {
 	Convey("RPC client errors", t, func() {
 		proxy := ControlProxy{Client: mockClient{RpcErr: true}}
-		_, errs := proxy.ProcessMetrics([]core.Metric{}, map[string]ctypes.ConfigValue{}, "", "fake", 1)
+		_, errs := proxy.ProcessMetrics(context.Background(), []core.Metric{}, map[string]ctypes.ConfigValue{}, "", "fake", 1)
 
 		Convey("So the error should be passed through", func() {
 			So(errs[0].Error(), ShouldResemble, rpcErr.Error())
@@ -152,7 +154,7 @@ func TestProcessMetrics(t *testing.T) {
 		}
 
 		proxy := ControlProxy{Client: mockClient{ProcessReply: reply}}
-		_, errs := proxy.ProcessMetrics([]core.Metric{}, map[string]ctypes.ConfigValue{}, "", "", 1)
+		_, errs := proxy.ProcessMetrics(context.Background(), []core.Metric{}, map[string]ctypes.ConfigValue{}, "", "", 1)
 
 		Convey("So errs should not be nil", func() {
 			So(errs, ShouldNotBeNil)
@@ -174,7 +176,7 @@ func TestProcessMetrics(t *testing.T) {
 		}
 
 		proxy := ControlProxy{Client: mockClient{ProcessReply: reply}}
-		_, errs := proxy.ProcessMetrics([]core.Metric{}, map[string]ctypes.ConfigValue{}, "", "", 1)
+		_, errs := proxy.ProcessMetrics(context.Background(), []core.Metric{}, map[string]ctypes.ConfigValue{}, "", "", 1)
 
 		Convey("So len of errs should be 0", func() {
 			So(len(errs), ShouldEqual, 0)
@@ -186,7 +188,7 @@ func TestProcessMetrics(t *testing.T) {
 func TestCollectMetrics(t *testing.T) {
 	Convey("RPC client errors", t, func() {
 		proxy := ControlProxy{Client: mockClient{RpcErr: true}}
-		_, errs := proxy.CollectMetrics("", map[string]map[string]string{})
+		_, errs := proxy.CollectMetrics(context.Background(), "", map[string]map[string]string{})
 
 		Convey("So the error should be passed through", func() {
 			So(errs[0].Error(), ShouldResemble, rpcErr.Error())
@@ -200,7 +202,7 @@ func TestCollectMetrics(t *testing.T) {
 		}
 
 		proxy := ControlProxy{Client: mockClient{CollectReply: reply}}
-		_, errs := proxy.CollectMetrics("", map[string]map[string]string{})
+		_, errs := proxy.CollectMetrics(context.Background(), "", map[string]map[string]string{})
 
 		Convey("So len of errs should be 1", func() {
 			So(len(errs), ShouldEqual, 1)
@@ -224,7 +226,7 @@ func TestCollectMetrics(t *testing.T) {
 		}
 
 		proxy := ControlProxy{Client: mockClient{CollectReply: reply}}
-		mts, errs := proxy.CollectMetrics("", map[string]map[string]string{})
+		mts, errs := proxy.CollectMetrics(context.Background(), "", map[string]map[string]string{})
 
 		Convey("So len of errs should be 0", func() {
 			So(len(errs), ShouldEqual, 0)
@@ -238,6 +240,34 @@ func TestCollectMetrics(t *testing.T) {
 			So(len(mts), ShouldEqual, 1)
 		})
 	})
+
+	Convey("The cache bypass of the task is sent to the remote control", t, func() {
+		client := &collectRequestClient{}
+		proxy := newControlProxy(client)
+		proxy.CollectMetrics(context.Background(), "task", map[string]map[string]string{})
+		So(client.request.CacheBypass, ShouldBeFalse)
+
+		proxy.SetCacheBypass("task", true)
+		proxy.CollectMetrics(context.Background(), "task", map[string]map[string]string{})
+		So(client.request.CacheBypass, ShouldBeTrue)
+		proxy.CollectMetrics(context.Background(), "other", map[string]map[string]string{})
+		So(client.request.CacheBypass, ShouldBeFalse)
+
+		proxy.SetCacheBypass("task", false)
+		proxy.CollectMetrics(context.Background(), "task", map[string]map[string]string{})
+		So(client.request.CacheBypass, ShouldBeFalse)
+	})
+}
+
+// collectRequestClient records the last collection request
+type collectRequestClient struct {
+	mockClient
+	request *rpc.CollectMetricsRequest
+}
+
+func (c *collectRequestClient) CollectMetrics(ctx context.Context, in *rpc.CollectMetricsRequest, opts ...grpc.CallOption) (*rpc.CollectMetricsResponse, error) {
+	c.request = in
+	return &rpc.CollectMetricsResponse{}, nil
 }
 
 func TestValidateDeps(t *testing.T) {
@@ -371,3 +401,43 @@ func TestGetAutoDiscoverPaths(t *testing.T) {
 		So(val, ShouldBeNil)
 	})
 }
+
+type mockServer struct {
+	rpc.MetricManagerServer
+}
+
+func (mockServer) GetAutodiscoverPaths(context.Context, *common.Empty) (*rpc.GetAutodiscoverPathsReply, error) {
+	return &rpc.GetAutodiscoverPathsReply{Paths: []string{"a"}}, nil
+}
+
+// serve starts a control gRPC server accepting the calls compressed with
+// compression and returns its port.
+func serve(compression string) (*grpc.Server, int) {
+	opts, err := rpcutil.ChannelServerOptions(compression, 0)
+	So(err, ShouldBeNil)
+	opts = append(opts, rpcutil.AnnounceCompression(compression))
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	So(err, ShouldBeNil)
+	s := grpc.NewServer(opts...)
+	rpc.RegisterMetricManagerServer(s, mockServer{})
+	go s.Serve(lis)
+	return s, lis.Addr().(*net.TCPAddr).Port
+}
+
+func TestNew(t *testing.T) {
+	Convey("The calls are compressed with the compressor the remote control accepts", t, func() {
+		s, port := serve(rpcutil.CompressionGzip)
+		defer s.Stop()
+		proxy, err := New("127.0.0.1", port, Channel{Compression: []string{rpcutil.CompressionSnappy, rpcutil.CompressionGzip}})
+		So(err, ShouldBeNil)
+		So(proxy.GetAutodiscoverPaths(), ShouldResemble, []string{"a"})
+	})
+
+	Convey("The calls are not compressed when the remote control accepts none of the compressors", t, func() {
+		s, port := serve("")
+		defer s.Stop()
+		proxy, err := New("127.0.0.1", port, Channel{Compression: []string{rpcutil.CompressionSnappy}})
+		So(err, ShouldBeNil)
+		So(proxy.GetAutodiscoverPaths(), ShouldResemble, []string{"a"})
+	})
+}
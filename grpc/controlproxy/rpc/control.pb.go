@@ -326,6 +326,9 @@ func (m *MapEntry) GetValue() string {
 type CollectMetricsRequest struct {
 	TaskID  string          `protobuf:"bytes,1,opt,name=TaskID" json:"TaskID,omitempty"`
 	AllTags map[string]*Map `protobuf:"bytes,2,rep,name=AllTags" json:"AllTags,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// CacheBypass is true when the metrics are collected from the plugins
+	// instead of being served from the metric cache
+	CacheBypass bool `protobuf:"varint,3,opt,name=CacheBypass" json:"CacheBypass,omitempty"`
 }
 
 func (m *CollectMetricsRequest) Reset()                    { *m = CollectMetricsRequest{} }
@@ -347,6 +350,13 @@ func (m *CollectMetricsRequest) GetAllTags() map[string]*Map {
 	return nil
 }
 
+func (m *CollectMetricsRequest) GetCacheBypass() bool {
+	if m != nil {
+		return m.CacheBypass
+	}
+	return false
+}
+
 type CollectMetricsResponse struct {
 	Metrics []*common.Metric `protobuf:"bytes,1,rep,name=Metrics" json:"Metrics,omitempty"`
 	Errors  []string         `protobuf:"bytes,2,rep,name=Errors" json:"Errors,omitempty"`
@@ -699,51 +709,52 @@ func init() {
 }
 
 var fileDescriptor0 = []byte{
-	// 733 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xac, 0x55, 0xdd, 0x4e, 0x1a, 0x41,
-	0x14, 0x66, 0x45, 0x44, 0x0e, 0x82, 0x75, 0x44, 0x8a, 0xdb, 0xc4, 0x90, 0x8d, 0xa9, 0x98, 0xb4,
-	0xd0, 0xe2, 0x4d, 0xd3, 0x0b, 0x0d, 0x15, 0x62, 0x1b, 0x43, 0x43, 0x16, 0x6b, 0x93, 0xde, 0x0d,
-	0xcb, 0x14, 0x37, 0x2e, 0x3b, 0xd3, 0x99, 0x59, 0x23, 0x6f, 0xd0, 0xcb, 0x3e, 0x4e, 0x9f, 0xa1,
-	0x4f, 0xd5, 0xec, 0xce, 0x2e, 0xec, 0xe2, 0xda, 0x56, 0xd3, 0x2b, 0xf6, 0xfc, 0xcc, 0x77, 0xbe,
-	0xef, 0xcc, 0x9c, 0x03, 0x1c, 0x4f, 0x6c, 0x79, 0xe5, 0x8d, 0x9a, 0x16, 0x9d, 0xb6, 0x6c, 0x57,
-	0x12, 0x47, 0x8c, 0xed, 0x97, 0xb7, 0x2d, 0xe1, 0x62, 0xd6, 0x9a, 0x70, 0x66, 0xb5, 0x2c, 0xea,
-	0x4a, 0x4e, 0x1d, 0xc6, 0xe9, 0xed, 0xac, 0x15, 0x73, 0x34, 0x19, 0xa7, 0x92, 0xa2, 0x2c, 0x67,
-	0x96, 0x7e, 0xf4, 0x77, 0x90, 0xe9, 0x94, 0xba, 0xe1, 0x8f, 0x3a, 0x69, 0x94, 0xa0, 0x38, 0x24,
-	0x9c, 0x53, 0x6e, 0x12, 0xe6, 0xcc, 0x8c, 0x5f, 0x1a, 0xec, 0x0c, 0xbc, 0xd1, 0x80, 0x53, 0xab,
-	0x4f, 0x24, 0xb7, 0x2d, 0x61, 0x92, 0x6f, 0x1e, 0x11, 0x12, 0x35, 0x20, 0x1f, 0x7a, 0x6a, 0x5a,
-	0x3d, 0xdb, 0x28, 0xb6, 0xcb, 0xcd, 0x10, 0x48, 0xb9, 0xcd, 0x28, 0x8c, 0xf6, 0x00, 0x06, 0x8e,
-	0x37, 0xb1, 0xdd, 0x8f, 0x78, 0x4a, 0x6a, 0x2b, 0x75, 0xad, 0x51, 0x30, 0x63, 0x1e, 0xb4, 0x0f,
-	0x25, 0x65, 0x5d, 0x12, 0x2e, 0x6c, 0xea, 0xd6, 0xb2, 0x75, 0xad, 0x91, 0x35, 0x93, 0x4e, 0x74,
-	0x08, 0x6b, 0xa7, 0xd4, 0xfd, 0x6a, 0x4f, 0x6a, 0xab, 0x75, 0xad, 0x51, 0x6c, 0x6f, 0x45, 0xe5,
-	0x94, 0xb7, 0x8f, 0x99, 0x19, 0x26, 0xa0, 0x2a, 0xac, 0x5d, 0x60, 0x71, 0xfd, 0x61, 0x5c, 0xcb,
-	0x05, 0xc5, 0x42, 0xcb, 0xd8, 0x07, 0xe8, 0xcd, 0xa5, 0xf9, 0x59, 0x81, 0xa5, 0xf8, 0x17, 0xcc,
-	0xd0, 0x32, 0x3e, 0xc3, 0xb6, 0x2f, 0x97, 0x08, 0x31, 0x57, 0xec, 0xa7, 0xff, 0xbb, 0xde, 0x05,
-	0xf0, 0x4a, 0x02, 0x58, 0xc0, 0xf6, 0x25, 0x76, 0xec, 0x31, 0x96, 0xa4, 0x4b, 0xd8, 0x23, 0x1a,
-	0xd9, 0x86, 0xbc, 0xea, 0x89, 0x42, 0x2e, 0xb6, 0x6b, 0x51, 0xe6, 0xd0, 0x1b, 0x09, 0x8b, 0xdb,
-	0x23, 0x32, 0x56, 0x09, 0x66, 0x94, 0x68, 0x1c, 0xc3, 0x56, 0xb2, 0xa8, 0xaf, 0xe5, 0x30, 0x21,
-	0x3d, 0xd6, 0xcb, 0xa1, 0x8b, 0x99, 0x6a, 0x51, 0x44, 0xfa, 0x87, 0x06, 0x95, 0x39, 0x7a, 0x9c,
-	0xf6, 0x0b, 0x28, 0x84, 0x9f, 0x64, 0x7c, 0x0f, 0xf1, 0x45, 0xc2, 0x63, 0xa8, 0xc7, 0xae, 0x31,
-	0x9b, 0xb8, 0xc6, 0x13, 0x40, 0x4b, 0x8c, 0x1e, 0xa8, 0xe9, 0x15, 0x54, 0x3f, 0xb9, 0x22, 0x4d,
-	0xd4, 0xa2, 0xa4, 0x96, 0x28, 0xd9, 0x81, 0xca, 0x9d, 0x13, 0x0f, 0x2c, 0xda, 0x84, 0x6c, 0x1f,
-	0x33, 0x74, 0x00, 0xf9, 0x9e, 0x2b, 0xb9, 0x4d, 0xa2, 0x23, 0xa5, 0x26, 0x67, 0x56, 0xb3, 0x8f,
-	0x99, 0xef, 0x9e, 0x99, 0x51, 0xd4, 0x68, 0xc3, 0x7a, 0xe4, 0x44, 0x4f, 0x20, 0x7b, 0x4e, 0x66,
-	0x21, 0x27, 0xff, 0x13, 0x55, 0x20, 0x77, 0x89, 0x1d, 0x2f, 0x1a, 0x27, 0x65, 0x18, 0x3f, 0x35,
-	0xd8, 0x39, 0xa5, 0x8e, 0x43, 0x2c, 0xb9, 0x34, 0xad, 0x91, 0xb0, 0x6e, 0x42, 0x58, 0x17, 0x75,
-	0x20, 0xdf, 0x71, 0x9c, 0x0b, 0x3c, 0x89, 0xee, 0xe5, 0x20, 0xa0, 0x93, 0x0a, 0xd2, 0x0c, 0x33,
-	0x43, 0xa2, 0xa1, 0xa5, 0x77, 0x61, 0x23, 0x1e, 0xf0, 0xc9, 0x5e, 0x2f, 0xc8, 0x5e, 0x93, 0x19,
-	0xda, 0x83, 0xdc, 0xcd, 0x9c, 0x6c, 0xb1, 0xbd, 0x1e, 0x29, 0x36, 0x95, 0xfb, 0xed, 0xca, 0x1b,
-	0xcd, 0xf8, 0x02, 0xd5, 0xe5, 0xa2, 0x82, 0x51, 0x57, 0x90, 0xff, 0x30, 0x78, 0x47, 0x50, 0xe8,
-	0x70, 0x3e, 0x94, 0xdc, 0x76, 0x27, 0xe8, 0x39, 0x68, 0xc3, 0x10, 0x68, 0xfe, 0x06, 0xfd, 0x35,
-	0x24, 0x18, 0xb6, 0x48, 0xcf, 0x21, 0x53, 0xe2, 0x4a, 0x53, 0x1b, 0x1a, 0xaf, 0x61, 0xf7, 0x8c,
-	0xc8, 0x8e, 0x27, 0xe9, 0xd8, 0x16, 0x16, 0xbd, 0x21, 0x7c, 0x80, 0xe5, 0x55, 0x78, 0xef, 0x15,
-	0xc8, 0x05, 0x56, 0xb8, 0x3a, 0x94, 0xd1, 0xfe, 0xbe, 0x0a, 0x25, 0xc5, 0xa5, 0x8f, 0x5d, 0x3c,
-	0x21, 0x1c, 0x9d, 0x43, 0x39, 0xa9, 0x0a, 0xe9, 0xf7, 0xf7, 0x57, 0x7f, 0x96, 0x1a, 0x53, 0x6d,
-	0x30, 0x32, 0xe8, 0x04, 0xca, 0x03, 0x6f, 0xe4, 0xd8, 0xe2, 0x2a, 0x09, 0x96, 0xba, 0x9f, 0xf5,
-	0xcd, 0x20, 0xb6, 0xd8, 0x77, 0x46, 0x06, 0xbd, 0x87, 0x72, 0x72, 0xb3, 0xfd, 0x11, 0xa0, 0xa6,
-	0x62, 0x77, 0x57, 0xa1, 0x91, 0x41, 0xef, 0x60, 0x23, 0xbe, 0x55, 0x90, 0xca, 0x4d, 0xd9, 0x6e,
-	0x7a, 0x35, 0x25, 0xa2, 0x30, 0x7a, 0x50, 0x4a, 0x8c, 0x31, 0xda, 0x0d, 0x52, 0xd3, 0x96, 0x8d,
-	0xfe, 0x34, 0x2d, 0xa4, 0x60, 0xce, 0x61, 0x73, 0x69, 0x34, 0x91, 0xea, 0x63, 0xfa, 0x88, 0xeb,
-	0xbb, 0xe9, 0x41, 0x05, 0x76, 0x06, 0x95, 0xb4, 0x4b, 0x47, 0xa5, 0xe8, 0xa5, 0xf4, 0xa6, 0x4c,
-	0xce, 0xf4, 0xbd, 0x00, 0xe3, 0xde, 0xe7, 0x61, 0x64, 0x46, 0x6b, 0xc1, 0xbf, 0xe9, 0xd1, 0xef,
-	0x00, 0x00, 0x00, 0xff, 0xff, 0x16, 0xa8, 0x2b, 0xc5, 0xc9, 0x07, 0x00, 0x00,
+	// 751 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xac, 0x55, 0xdd, 0x6e, 0xe2, 0x46,
+	0x14, 0xc6, 0x21, 0x84, 0x70, 0x08, 0xa4, 0x99, 0x10, 0xea, 0xb8, 0x52, 0x84, 0xac, 0xa8, 0x21,
+	0x52, 0x0b, 0x2d, 0xb9, 0xa9, 0x7a, 0x91, 0x88, 0x04, 0x94, 0x56, 0x11, 0x15, 0x32, 0x69, 0x56,
+	0xda, 0xbb, 0xc1, 0xcc, 0x82, 0x15, 0xe3, 0x99, 0x9d, 0x19, 0x47, 0xe1, 0x0d, 0xf6, 0x72, 0x9f,
+	0x6b, 0x1f, 0x61, 0x9f, 0x66, 0x65, 0x8f, 0x0d, 0x36, 0x71, 0x76, 0x37, 0xd1, 0x5e, 0xe1, 0xf3,
+	0x33, 0xdf, 0xf9, 0xbe, 0x73, 0x66, 0x0e, 0x70, 0x3e, 0x75, 0xe4, 0xcc, 0x1f, 0xb7, 0x6c, 0x3a,
+	0x6f, 0x3b, 0x9e, 0x24, 0xae, 0x98, 0x38, 0xbf, 0x3f, 0xb6, 0x85, 0x87, 0x59, 0x7b, 0xca, 0x99,
+	0xdd, 0xb6, 0xa9, 0x27, 0x39, 0x75, 0x19, 0xa7, 0x8f, 0x8b, 0x76, 0xc2, 0xd1, 0x62, 0x9c, 0x4a,
+	0x8a, 0xf2, 0x9c, 0xd9, 0xc6, 0xd9, 0xb7, 0x41, 0xe6, 0x73, 0xea, 0x45, 0x3f, 0xea, 0xa4, 0x59,
+	0x81, 0xf2, 0x88, 0x70, 0x4e, 0xb9, 0x45, 0x98, 0xbb, 0x30, 0x3f, 0x69, 0x70, 0x30, 0xf4, 0xc7,
+	0x43, 0x4e, 0xed, 0x01, 0x91, 0xdc, 0xb1, 0x85, 0x45, 0xde, 0xfb, 0x44, 0x48, 0xd4, 0x84, 0x62,
+	0xe4, 0xd1, 0xb5, 0x46, 0xbe, 0x59, 0xee, 0x54, 0x5b, 0x11, 0x90, 0x72, 0x5b, 0x71, 0x18, 0x1d,
+	0x01, 0x0c, 0x5d, 0x7f, 0xea, 0x78, 0xff, 0xe1, 0x39, 0xd1, 0x37, 0x1a, 0x5a, 0xb3, 0x64, 0x25,
+	0x3c, 0xe8, 0x18, 0x2a, 0xca, 0xba, 0x23, 0x5c, 0x38, 0xd4, 0xd3, 0xf3, 0x0d, 0xad, 0x99, 0xb7,
+	0xd2, 0x4e, 0x74, 0x0a, 0x5b, 0x57, 0xd4, 0x7b, 0xe7, 0x4c, 0xf5, 0xcd, 0x86, 0xd6, 0x2c, 0x77,
+	0xf6, 0xe2, 0x72, 0xca, 0x3b, 0xc0, 0xcc, 0x8a, 0x12, 0x50, 0x1d, 0xb6, 0x6e, 0xb1, 0xb8, 0xff,
+	0x77, 0xa2, 0x17, 0xc2, 0x62, 0x91, 0x65, 0x1e, 0x03, 0xf4, 0x97, 0xd2, 0x82, 0xac, 0xd0, 0x52,
+	0xfc, 0x4b, 0x56, 0x64, 0x99, 0x6f, 0x60, 0x3f, 0x90, 0x4b, 0x84, 0x58, 0x2a, 0x0e, 0xd2, 0xbf,
+	0x5f, 0xef, 0x0a, 0x78, 0x23, 0x05, 0x2c, 0x60, 0xff, 0x0e, 0xbb, 0xce, 0x04, 0x4b, 0xd2, 0x23,
+	0xec, 0x15, 0x8d, 0xec, 0x40, 0x51, 0xf5, 0x44, 0x21, 0x97, 0x3b, 0x7a, 0x9c, 0x39, 0xf2, 0xc7,
+	0xc2, 0xe6, 0xce, 0x98, 0x4c, 0x54, 0x82, 0x15, 0x27, 0x9a, 0xe7, 0xb0, 0x97, 0x2e, 0x1a, 0x68,
+	0x39, 0x4d, 0x49, 0x4f, 0xf4, 0x72, 0xe4, 0x61, 0xa6, 0x5a, 0x14, 0x93, 0xfe, 0xa8, 0x41, 0x6d,
+	0x89, 0x9e, 0xa4, 0xfd, 0x1b, 0x94, 0xa2, 0x4f, 0x32, 0x79, 0x86, 0xf8, 0x2a, 0xe1, 0x35, 0xd4,
+	0x13, 0x63, 0xcc, 0xa7, 0xc6, 0x78, 0x01, 0x68, 0x8d, 0xd1, 0x0b, 0x35, 0xfd, 0x01, 0xf5, 0xff,
+	0x3d, 0x91, 0x25, 0x6a, 0x55, 0x52, 0x4b, 0x95, 0xec, 0x42, 0xed, 0xc9, 0x89, 0x17, 0x16, 0x6d,
+	0x41, 0x7e, 0x80, 0x19, 0x3a, 0x81, 0x62, 0xdf, 0x93, 0xdc, 0x21, 0xf1, 0x91, 0x4a, 0x8b, 0x33,
+	0xbb, 0x35, 0xc0, 0x2c, 0x70, 0x2f, 0xac, 0x38, 0x6a, 0x76, 0x60, 0x3b, 0x76, 0xa2, 0x9f, 0x20,
+	0x7f, 0x43, 0x16, 0x11, 0xa7, 0xe0, 0x13, 0xd5, 0xa0, 0x70, 0x87, 0x5d, 0x3f, 0x7e, 0x4e, 0xca,
+	0x30, 0x3f, 0x6b, 0x70, 0x70, 0x45, 0x5d, 0x97, 0xd8, 0x72, 0xed, 0xb5, 0xc6, 0xc2, 0x7a, 0x29,
+	0x61, 0x3d, 0xd4, 0x85, 0x62, 0xd7, 0x75, 0x6f, 0xf1, 0x34, 0x9e, 0xcb, 0x49, 0x48, 0x27, 0x13,
+	0xa4, 0x15, 0x65, 0x46, 0x44, 0x23, 0x0b, 0x35, 0xa0, 0x7c, 0x85, 0xed, 0x19, 0xb9, 0x5c, 0x30,
+	0x2c, 0x44, 0x38, 0xab, 0x6d, 0x2b, 0xe9, 0x32, 0x7a, 0xb0, 0x93, 0x3c, 0x1a, 0xc8, 0xb9, 0x5f,
+	0xc9, 0xb9, 0x27, 0x0b, 0x74, 0x04, 0x85, 0x87, 0xa5, 0x9c, 0x72, 0x67, 0x3b, 0xee, 0x89, 0xa5,
+	0xdc, 0x7f, 0x6f, 0xfc, 0xa5, 0x99, 0x6f, 0xa1, 0xbe, 0x4e, 0x4b, 0x30, 0xea, 0x09, 0xf2, 0x03,
+	0x9e, 0xe6, 0x19, 0x94, 0xba, 0x9c, 0x8f, 0x24, 0x77, 0xbc, 0x29, 0xfa, 0x15, 0xb4, 0x91, 0xae,
+	0xa5, 0x6f, 0x69, 0xb0, 0xa8, 0x04, 0xc3, 0x36, 0xe9, 0xbb, 0x64, 0x4e, 0x3c, 0x69, 0x69, 0x23,
+	0xf3, 0x4f, 0x38, 0xbc, 0x26, 0xb2, 0xeb, 0x4b, 0x3a, 0x71, 0x84, 0x4d, 0x1f, 0x08, 0x1f, 0x62,
+	0x39, 0x8b, 0x6e, 0x46, 0x0d, 0x0a, 0xa1, 0x15, 0x2d, 0x17, 0x65, 0x74, 0x3e, 0x6c, 0x42, 0x45,
+	0x71, 0x19, 0x60, 0x0f, 0x4f, 0x09, 0x47, 0x37, 0x50, 0x4d, 0xab, 0x42, 0xc6, 0xf3, 0x13, 0x30,
+	0x7e, 0xc9, 0x8c, 0xa9, 0x36, 0x98, 0x39, 0x74, 0x01, 0xd5, 0xa1, 0x3f, 0x76, 0x1d, 0x31, 0x4b,
+	0x83, 0x65, 0x6e, 0x70, 0x63, 0x37, 0x8c, 0xad, 0x36, 0xa2, 0x99, 0x43, 0xff, 0x40, 0x35, 0xbd,
+	0xfb, 0xbe, 0x0a, 0xa0, 0xab, 0xd8, 0xd3, 0x65, 0x69, 0xe6, 0xd0, 0x25, 0xec, 0x24, 0xf7, 0x0e,
+	0x52, 0xb9, 0x19, 0xfb, 0xcf, 0xa8, 0x67, 0x44, 0x14, 0x46, 0x1f, 0x2a, 0xa9, 0x87, 0x8e, 0x0e,
+	0xc3, 0xd4, 0xac, 0x75, 0x64, 0xfc, 0x9c, 0x15, 0x52, 0x30, 0x37, 0xb0, 0xbb, 0xf6, 0x78, 0x91,
+	0xea, 0x63, 0xf6, 0x12, 0x30, 0x0e, 0xb3, 0x83, 0x0a, 0xec, 0x1a, 0x6a, 0x59, 0x43, 0x47, 0x95,
+	0xf8, 0xa6, 0xf4, 0xe7, 0x4c, 0x2e, 0x8c, 0xa3, 0x10, 0xe3, 0xd9, 0xeb, 0x61, 0xe6, 0xc6, 0x5b,
+	0xe1, 0xff, 0xed, 0xd9, 0x97, 0x01, 0x00, 0x57, 0x32, 0x80, 0x3b, 0xeb, 0x07, 0x00, 0x00,
 }
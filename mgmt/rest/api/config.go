@@ -12,4 +12,6 @@ type Config interface {
 	MergePluginConfigDataNodeAll(cdn *cdata.ConfigDataNode) cdata.ConfigDataNode
 	DeletePluginConfigDataNodeField(pluginType core.PluginType, name string, ver int, fields ...string) cdata.ConfigDataNode
 	DeletePluginConfigDataNodeFieldAll(fields ...string) cdata.ConfigDataNode
+	ChangePluginConfig(core.PluginConfigChange) (cdata.ConfigDataNode, error)
+	PluginConfigHistory() []core.PluginConfigChange
 }
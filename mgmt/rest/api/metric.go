@@ -12,8 +12,15 @@ type Metrics interface {
 	GetMetric(core.Namespace, int) (core.CatalogedMetric, error)
 	Load(*core.RequestedPlugin) (core.CatalogedPlugin, serror.SnapError)
 	Unload(core.Plugin) (core.CatalogedPlugin, serror.SnapError)
+	CanarySwap(*core.RequestedPlugin, core.Plugin, core.CanaryOptions) (core.CatalogedPlugin, serror.SnapError)
+	CanaryStatus(string, string) (core.CanaryStatus, serror.SnapError)
+	PluginRepository() ([]core.RepositoryPlugin, serror.SnapError)
+	GCPluginRepository(int) ([]core.RepositoryPlugin, serror.SnapError)
+	ExportPlugin(string) (core.RepositoryPlugin, string, serror.SnapError)
+	RemotePluginEndpoints(core.Plugin) ([]core.RemoteEndpointStatus, serror.SnapError)
 	PluginCatalog() core.PluginCatalog
 	AvailablePlugins() []core.AvailablePlugin
 	GetAutodiscoverPaths() []string
 	GetTempDir() string
+	MetricCacheStats() []core.CacheStats
 }
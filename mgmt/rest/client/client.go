@@ -297,30 +297,48 @@ func httpRespToAPIResp(rsp *http.Response) (*rbody.APIResponse, error) {
 	return resp, nil
 }
 
+// remoteEndpoints returns the endpoints of a standalone plugin given its
+// URI, or the comma separated URIs of its endpoints, nil if p is not a URI
+func remoteEndpoints(p string) []core.RemoteEndpoint {
+	var endpoints []core.RemoteEndpoint
+	for _, uri := range strings.Split(p, ",") {
+		if !core.IsUri(uri) {
+			return nil
+		}
+		if _, err := url.ParseRequestURI(uri); err != nil {
+			return nil
+		}
+		endpoints = append(endpoints, core.RemoteEndpoint{URI: uri})
+	}
+	return endpoints
+}
+
 func (c *Client) pluginUploadRequest(pluginPaths []string) (*rbody.APIResponse, error) {
-	if core.IsUri(pluginPaths[0]) {
-		if _, err := url.ParseRequestURI(pluginPaths[0]); err == nil {
-			req, err := http.NewRequest(
-				"POST",
-				c.prefix+"/plugins",
-				strings.NewReader(
-					fmt.Sprintf("{\"uri\": \"%s\"}", pluginPaths[0]),
-				),
-			)
-			if err != nil {
-				return nil, err
-			}
-			addAuth(req, c.Username, c.Password)
-			req.Header.Add("Content-Type", "application/json")
-			rsp, err := c.http.Do(req)
-			if err != nil {
-				if strings.Contains(err.Error(), "tls: oversized record") || strings.Contains(err.Error(), "malformed HTTP response") {
-					return nil, fmt.Errorf("error connecting to API URI: %s. Do you have an http/https mismatch?", c.URL)
-				}
-				return nil, fmt.Errorf("URL target is not available. %v", err)
+	return c.pluginUploadRequestTo("/plugins", pluginPaths)
+}
+
+// pluginUploadRequestTo uploads the plugin files to the given path of the
+// API, e.g. "/plugins".
+func (c *Client) pluginUploadRequestTo(path string, pluginPaths []string) (*rbody.APIResponse, error) {
+	if endpoints := remoteEndpoints(pluginPaths[0]); endpoints != nil {
+		b, err := json.Marshal(map[string][]core.RemoteEndpoint{"endpoints": endpoints})
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequest("POST", c.prefix+path, bytes.NewReader(b))
+		if err != nil {
+			return nil, err
+		}
+		addAuth(req, c.Username, c.Password)
+		req.Header.Add("Content-Type", "application/json")
+		rsp, err := c.http.Do(req)
+		if err != nil {
+			if strings.Contains(err.Error(), "tls: oversized record") || strings.Contains(err.Error(), "malformed HTTP response") {
+				return nil, fmt.Errorf("error connecting to API URI: %s. Do you have an http/https mismatch?", c.URL)
 			}
-			return httpRespToAPIResp(rsp)
+			return nil, fmt.Errorf("URL target is not available. %v", err)
 		}
+		return httpRespToAPIResp(rsp)
 	}
 	errChan := make(chan error)
 	pr, pw := io.Pipe()
@@ -345,7 +363,8 @@ func (c *Client) pluginUploadRequest(pluginPaths []string) (*rbody.APIResponse,
 		bufin := bufio.NewReader(file)
 		bufins = append(bufins, bufin)
 		if baseName := filepath.Base(pluginPath); strings.HasPrefix(baseName, v1.TLSCertPrefix) ||
-			strings.HasPrefix(baseName, v1.TLSKeyPrefix) || strings.HasPrefix(baseName, v1.TLSCACertsPrefix) {
+			strings.HasPrefix(baseName, v1.TLSKeyPrefix) || strings.HasPrefix(baseName, v1.TLSCACertsPrefix) ||
+			strings.HasPrefix(baseName, v1.LifecyclePrefix) {
 			defer os.Remove(pluginPath)
 		}
 		paths = append(paths, filepath.Base(pluginPath))
@@ -353,7 +372,7 @@ func (c *Client) pluginUploadRequest(pluginPaths []string) (*rbody.APIResponse,
 	// with io.Pipe the write needs to be async
 	go writePluginToWriter(pw, bufins, writer, paths, errChan)
 
-	req, err := http.NewRequest("POST", c.prefix+"/plugins", pr)
+	req, err := http.NewRequest("POST", c.prefix+path, pr)
 	addAuth(req, c.Username, c.Password)
 	if err != nil {
 		return nil, fmt.Errorf("URL target is not available. %v", err)
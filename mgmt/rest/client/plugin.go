@@ -24,8 +24,10 @@ import (
 	"fmt"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/intelsdi-x/snap/core"
 	"github.com/intelsdi-x/snap/core/serror"
 	"github.com/intelsdi-x/snap/mgmt/rest/v1/rbody"
 )
@@ -124,6 +126,65 @@ func (c *Client) SwapPlugin(loadPath []string, unloadType, unloadName string, un
 	return r
 }
 
+// CanarySwapPlugin loads a plugin alongside the plugin with the same type and
+// name given its version, and routes part of the tasks to it.  The swap is
+// completed or rolled back by snapteld once the window of the canary elapsed.
+func (c *Client) CanarySwapPlugin(loadPath []string, unloadType, unloadName string, unloadVersion int, opts core.CanaryOptions) *LoadPluginResult {
+	r := new(LoadPluginResult)
+	q := url.Values{}
+	if opts.Fraction > 0 {
+		q.Set("fraction", strconv.FormatFloat(opts.Fraction, 'f', -1, 64))
+	}
+	if len(opts.Tasks) > 0 {
+		q.Set("tasks", strings.Join(opts.Tasks, ","))
+	}
+	if opts.Window > 0 {
+		q.Set("window", opts.Window.String())
+	}
+	q.Set("max_failure_rate", strconv.FormatFloat(opts.MaxFailureRate, 'f', -1, 64))
+	if opts.MinCalls > 0 {
+		q.Set("min_calls", strconv.FormatUint(opts.MinCalls, 10))
+	}
+	path := fmt.Sprintf("/plugins/%s/%s/%d/canary?%s", unloadType, url.QueryEscape(unloadName), unloadVersion, q.Encode())
+	resp, err := c.pluginUploadRequestTo(path, loadPath)
+	if err != nil {
+		r.Err = serror.New(err)
+		return r
+	}
+
+	switch resp.Meta.Type {
+	case rbody.PluginsLoadedType:
+		pl := resp.Body.(*rbody.PluginsLoaded)
+		r.LoadedPlugins = convertLoadedPlugins(pl.LoadedPlugins)
+	case rbody.ErrorType:
+		r.Err = serror.New(resp.Body.(*rbody.Error))
+	default:
+		r.Err = serror.New(ErrAPIResponseMetaType)
+	}
+	return r
+}
+
+// GetCanary returns the status of the last staged swap of a plugin through
+// an HTTP GET request.
+func (c *Client) GetCanary(typ, name string, ver int) *GetCanaryResult {
+	r := &GetCanaryResult{}
+	resp, err := c.do("GET", fmt.Sprintf("/plugins/%s/%s/%d/canary", typ, url.QueryEscape(name), ver), ContentTypeJSON)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+
+	switch resp.Meta.Type {
+	case rbody.CanaryReturnedType:
+		r.CanaryReturned = resp.Body.(*rbody.CanaryReturned)
+	case rbody.ErrorType:
+		r.Err = resp.Body.(*rbody.Error)
+	default:
+		r.Err = ErrAPIResponseMetaType
+	}
+	return r
+}
+
 // GetPlugins returns the loaded and available plugins through an HTTP GET request.
 // By specifying the details flag to tweak output info. An error returns if it failed.
 func (c *Client) GetPlugins(details bool) *GetPluginsResult {
@@ -210,6 +271,12 @@ type UnloadPluginResult struct {
 	Err error
 }
 
+// GetCanaryResult is the response from snap/client on a GetCanary call.
+type GetCanaryResult struct {
+	*rbody.CanaryReturned
+	Err error
+}
+
 type SwapPluginsResult struct {
 	LoadedPlugin   LoadedPlugin
 	UnloadedPlugin *rbody.PluginUnloaded
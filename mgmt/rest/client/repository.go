@@ -0,0 +1,134 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/intelsdi-x/snap/mgmt/rest/v1/rbody"
+)
+
+// GetRepository returns the plugins stored in the plugin repository through
+// an HTTP GET request.
+func (c *Client) GetRepository() *GetRepositoryResult {
+	r := &GetRepositoryResult{}
+	resp, err := c.do("GET", "/repository", ContentTypeJSON)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+
+	switch resp.Meta.Type {
+	case rbody.RepositoryPluginListType:
+		r.Plugins = convertRepositoryPlugins(resp.Body.(*rbody.RepositoryPluginList).Plugins)
+	case rbody.ErrorType:
+		r.Err = resp.Body.(*rbody.Error)
+	default:
+		r.Err = ErrAPIResponseMetaType
+	}
+	return r
+}
+
+// GCRepository removes the plugins stored in the plugin repository which are
+// not loaded, keeping the given number of the highest versions of each
+// plugin, through an HTTP POST request.
+func (c *Client) GCRepository(keep int) *GetRepositoryResult {
+	r := &GetRepositoryResult{}
+	resp, err := c.do("POST", fmt.Sprintf("/repository/gc?keep=%d", keep), ContentTypeJSON)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+
+	switch resp.Meta.Type {
+	case rbody.RepositoryPluginsRemovedType:
+		r.Plugins = convertRepositoryPlugins(resp.Body.(*rbody.RepositoryPluginsRemoved).Plugins)
+	case rbody.ErrorType:
+		r.Err = resp.Body.(*rbody.Error)
+	default:
+		r.Err = ErrAPIResponseMetaType
+	}
+	return r
+}
+
+// ExportPlugin writes the plugin stored in the plugin repository under the
+// given checksum, or a unique prefix of it, to w through an HTTP GET request.
+// It returns the file name of the plugin.
+func (c *Client) ExportPlugin(checkSum string, w io.Writer) (string, error) {
+	req, err := http.NewRequest("GET", c.prefix+"/repository/"+url.QueryEscape(checkSum), nil)
+	if err != nil {
+		return "", err
+	}
+	addAuth(req, c.Username, c.Password)
+	rsp, err := c.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("URL target is not available. %v", err)
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != 200 {
+		resp, err := httpRespToAPIResp(rsp)
+		if err != nil {
+			return "", err
+		}
+		if e, ok := resp.Body.(*rbody.Error); ok {
+			return "", e
+		}
+		return "", ErrAPIResponseMetaType
+	}
+	_, params, err := mime.ParseMediaType(rsp.Header.Get("Content-Disposition"))
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(w, rsp.Body); err != nil {
+		return "", err
+	}
+	return params["filename"], nil
+}
+
+// GetRepositoryResult is the response from snap/client on a GetRepository or
+// GCRepository call.
+type GetRepositoryResult struct {
+	Plugins []RepositoryPlugin
+	Err     error
+}
+
+// The wrapper for RepositoryPlugin struct defined inside rbody package.
+type RepositoryPlugin struct {
+	*rbody.RepositoryPlugin
+}
+
+// StoredTime returns a unix time.
+func (r *RepositoryPlugin) StoredTime() time.Time {
+	return time.Unix(r.StoredTimestamp, 0)
+}
+
+func convertRepositoryPlugins(r []rbody.RepositoryPlugin) []RepositoryPlugin {
+	rp := make([]RepositoryPlugin, len(r))
+	for i := range r {
+		rp[i] = RepositoryPlugin{&r[i]}
+	}
+	return rp
+}
@@ -1,6 +1,8 @@
 package rest
 
 import (
+	"expvar"
+	"fmt"
 	"net/http"
 	"net/http/pprof"
 
@@ -18,6 +20,7 @@ func (s *Server) addPprofRoutes() {
 		s.r.GET("/debug/pprof/profile", s.profile)
 		s.r.GET("/debug/pprof/symbol", s.symbol)
 		s.r.GET("/debug/pprof/trace", s.trace)
+		s.r.GET("/debug/vars", s.vars)
 	}
 }
 
@@ -42,3 +45,18 @@ func (s *Server) symbol(w http.ResponseWriter, r *http.Request, _ httprouter.Par
 func (s *Server) trace(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	pprof.Trace(w, r)
 }
+
+// vars serves the published expvar variables, including the metric cache stats
+func (s *Server) vars(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	fmt.Fprintf(w, "{\n")
+	first := true
+	expvar.Do(func(kv expvar.KeyValue) {
+		if !first {
+			fmt.Fprintf(w, ",\n")
+		}
+		first = false
+		fmt.Fprintf(w, "%q: %s", kv.Key, kv.Value)
+	})
+	fmt.Fprintf(w, "\n}\n")
+}
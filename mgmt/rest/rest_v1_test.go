@@ -730,6 +730,55 @@ func TestV1Plugin(t *testing.T) {
 				string(body))
 		})
 
+		Convey("Get canary - v1/plugins/:type/:name/:version/canary", func() {
+			resp, err := http.Get(
+				fmt.Sprintf("http://localhost:%d/v1/plugins/collector/foo/1/canary", r.port))
+			So(err, ShouldBeNil)
+			So(resp.StatusCode, ShouldEqual, 200)
+			body, err := ioutil.ReadAll(resp.Body)
+			So(err, ShouldBeNil)
+			So(
+				fixtures.GET_CANARY_RESPONSE,
+				ShouldResemble,
+				string(body))
+		})
+
+		Convey("Post canary with invalid parameters - v1/plugins/:type/:name/:version/canary", func() {
+			resp, err := http.Post(
+				fmt.Sprintf("http://localhost:%d/v1/plugins/collector/foo/1/canary?window=soon", r.port),
+				"multipart/form-data", bytes.NewReader([]byte{}))
+			So(err, ShouldBeNil)
+			So(resp.StatusCode, ShouldEqual, 400)
+		})
+
+		Convey("Get repository - v1/repository", func() {
+			resp, err := http.Get(
+				fmt.Sprintf("http://localhost:%d/v1/repository", r.port))
+			So(err, ShouldBeNil)
+			So(resp.StatusCode, ShouldEqual, 200)
+			body, err := ioutil.ReadAll(resp.Body)
+			So(err, ShouldBeNil)
+			So(
+				fixtures.GET_REPOSITORY_RESPONSE,
+				ShouldResemble,
+				string(body))
+		})
+
+		Convey("Gc repository with invalid parameters - v1/repository/gc", func() {
+			resp, err := http.Post(
+				fmt.Sprintf("http://localhost:%d/v1/repository/gc?keep=-1", r.port),
+				"application/json", bytes.NewReader([]byte{}))
+			So(err, ShouldBeNil)
+			So(resp.StatusCode, ShouldEqual, 400)
+		})
+
+		Convey("Export unknown plugin - v1/repository/:checksum", func() {
+			resp, err := http.Get(
+				fmt.Sprintf("http://localhost:%d/v1/repository/abcdef", r.port))
+			So(err, ShouldBeNil)
+			So(resp.StatusCode, ShouldEqual, 404)
+		})
+
 		Convey("Get plugin config items - v1/plugins/:type/:name/:version/config", func() {
 			resp, err := http.Get(
 				fmt.Sprintf("http://localhost:%d/v1/plugins/publisher/bar/3/config", r.port))
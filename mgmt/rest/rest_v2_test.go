@@ -107,6 +107,24 @@ func TestV2Plugin(t *testing.T) {
 			So(resp1.StatusCode, ShouldEqual, 201)
 		})
 
+		Convey("Post plugin download - v2/plugins", func() {
+			resp, err := http.Post(
+				fmt.Sprintf("http://localhost:%d/v2/plugins", r.port),
+				"application/json",
+				bytes.NewReader([]byte(`{"url": "http://localhost/snap-plugin-collector-mock1", "sha256": "4f2d8c1a6e0b9f3d2c5a7e8b1d0f6a4c3b2e9d8f7a6c5b4e3d2c1b0a9f8e7d6c"}`)))
+			So(err, ShouldBeNil)
+			So(resp.StatusCode, ShouldEqual, 201)
+		})
+
+		Convey("Post plugin download with invalid checksum - v2/plugins", func() {
+			resp, err := http.Post(
+				fmt.Sprintf("http://localhost:%d/v2/plugins", r.port),
+				"application/json",
+				bytes.NewReader([]byte(`{"url": "http://localhost/snap-plugin-collector-mock1", "sha256": "4f2d"}`)))
+			So(err, ShouldBeNil)
+			So(resp.StatusCode, ShouldEqual, 400)
+		})
+
 		Convey("Get plugins - v2/plugins", func() {
 			resp, err := http.Get(
 				fmt.Sprintf("http://localhost:%d/v2/plugins", r.port))
@@ -462,5 +480,18 @@ func TestV2Metric(t *testing.T) {
 				ShouldResemble,
 				fmt.Sprintf(mock.GET_METRICS_RESPONSE, r.port))
 		})
+
+		Convey("Get metric cache - v2/cache", func() {
+			resp, err := http.Get(
+				fmt.Sprintf("http://localhost:%d/v2/cache", r.port))
+			So(err, ShouldBeNil)
+			So(resp.StatusCode, ShouldEqual, 200)
+			body, err := ioutil.ReadAll(resp.Body)
+			So(err, ShouldBeNil)
+			So(
+				string(body),
+				ShouldResemble,
+				mock.GET_CACHE_RESPONSE)
+		})
 	})
 }
@@ -14,6 +14,8 @@ const (
 	TLSKeyPrefix = "key."
 	// TLSCACertsPrefix defines a prefix for file fragment carrying paths to TLS CA certificates
 	TLSCACertsPrefix = "cacerts."
+	// LifecyclePrefix defines a prefix for file fragment carrying the lifecycle of the plugin
+	LifecyclePrefix = "lifecycle."
 
 	version = "v1"
 	prefix  = "/" + version
@@ -48,10 +50,17 @@ func (s *apiV1) GetRoutes() []api.Route {
 		api.Route{Method: "GET", Path: prefix + "/plugins/:type/:name/:version", Handle: s.getPlugin},
 		api.Route{Method: "POST", Path: prefix + "/plugins", Handle: s.loadPlugin},
 		api.Route{Method: "DELETE", Path: prefix + "/plugins/:type/:name/:version", Handle: s.unloadPlugin},
+		api.Route{Method: "POST", Path: prefix + "/plugins/:type/:name/:version/canary", Handle: s.canarySwapPlugin},
+		api.Route{Method: "GET", Path: prefix + "/plugins/:type/:name/:version/canary", Handle: s.getCanary},
 		api.Route{Method: "GET", Path: prefix + "/plugins/:type/:name/:version/config", Handle: s.getPluginConfigItem},
 		api.Route{Method: "PUT", Path: prefix + "/plugins/:type/:name/:version/config", Handle: s.setPluginConfigItem},
 		api.Route{Method: "DELETE", Path: prefix + "/plugins/:type/:name/:version/config", Handle: s.deletePluginConfigItem},
 
+		// plugin repository routes
+		api.Route{Method: "GET", Path: prefix + "/repository", Handle: s.getRepository},
+		api.Route{Method: "POST", Path: prefix + "/repository/gc", Handle: s.gcRepository},
+		api.Route{Method: "GET", Path: prefix + "/repository/:checksum", Handle: s.exportPlugin},
+
 		// metric routes
 		api.Route{Method: "GET", Path: prefix + "/metrics", Handle: s.getMetrics},
 		api.Route{Method: "GET", Path: prefix + "/metrics/*namespace", Handle: s.getMetricsFromTree},
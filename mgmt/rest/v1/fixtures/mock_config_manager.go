@@ -66,6 +66,22 @@ func (MockConfigManager) DeletePluginConfigDataNodeFieldAll(fields ...string) cd
 	return *mockConfig
 }
 
+func (m MockConfigManager) ChangePluginConfig(change core.PluginConfigChange) (cdata.ConfigDataNode, error) {
+	switch {
+	case change.Action == core.PluginConfigDelete && change.Type == "":
+		return m.DeletePluginConfigDataNodeFieldAll(change.Fields...), nil
+	case change.Action == core.PluginConfigDelete:
+		return m.DeletePluginConfigDataNodeField(core.CollectorPluginType, change.Name, change.Version, change.Fields...), nil
+	case change.Type == "":
+		return m.MergePluginConfigDataNodeAll(change.Config), nil
+	}
+	return m.MergePluginConfigDataNode(core.CollectorPluginType, change.Name, change.Version, change.Config), nil
+}
+
+func (MockConfigManager) PluginConfigHistory() []core.PluginConfigChange {
+	return []core.PluginConfigChange{}
+}
+
 // These constants are the expected plugin config responses from running
 // rest_v1_test.go on the plugin config routes found in mgmt/rest/server.go
 const (
@@ -34,7 +34,9 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/intelsdi-x/snap/control"
 	"github.com/intelsdi-x/snap/core"
 	"github.com/intelsdi-x/snap/core/serror"
 	"github.com/intelsdi-x/snap/mgmt/rest/api"
@@ -68,6 +70,102 @@ func (p *plugin) TypeName() string {
 }
 
 func (s *apiV1) loadPlugin(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	s.load(w, r, s.metricManager.Load)
+}
+
+// canarySwapPlugin loads a plugin alongside the plugin given in the path and
+// routes part of the tasks to it until the swap completes or is rolled back.
+func (s *apiV1) canarySwapPlugin(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	plVersion, iErr := strconv.ParseInt(p.ByName("version"), 10, 0)
+	out := &plugin{
+		name:       p.ByName("name"),
+		version:    int(plVersion),
+		pluginType: p.ByName("type"),
+	}
+	opts, err := canaryOptions(r)
+	if iErr != nil || err != nil {
+		se := serror.New(errors.New("invalid canary parameter(s)"))
+		se.SetFields(map[string]interface{}{
+			"plugin-name":    out.name,
+			"plugin-version": p.ByName("version"),
+			"plugin-type":    out.pluginType,
+		})
+		rbody.Write(400, rbody.FromSnapError(se), w)
+		return
+	}
+	s.load(w, r, func(rp *core.RequestedPlugin) (core.CatalogedPlugin, serror.SnapError) {
+		return s.metricManager.CanarySwap(rp, out, opts)
+	})
+}
+
+// canaryOptions reads the options of a staged swap from the query string:
+// fraction, tasks (comma separated), window, max_failure_rate and min_calls.
+func canaryOptions(r *http.Request) (core.CanaryOptions, error) {
+	q := r.URL.Query()
+	opts := core.CanaryOptions{MaxFailureRate: control.DefaultCanaryMaxFailureRate}
+	var err error
+	if v := q.Get("fraction"); v != "" {
+		if opts.Fraction, err = strconv.ParseFloat(v, 64); err != nil {
+			return opts, err
+		}
+	}
+	if v := q.Get("tasks"); v != "" {
+		opts.Tasks = strings.Split(v, ",")
+	}
+	if v := q.Get("window"); v != "" {
+		if opts.Window, err = time.ParseDuration(v); err != nil {
+			return opts, err
+		}
+	}
+	if v := q.Get("max_failure_rate"); v != "" {
+		if opts.MaxFailureRate, err = strconv.ParseFloat(v, 64); err != nil {
+			return opts, err
+		}
+	}
+	if v := q.Get("min_calls"); v != "" {
+		if opts.MinCalls, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return opts, err
+		}
+	}
+	return opts, nil
+}
+
+func (s *apiV1) getCanary(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	c, se := s.metricManager.CanaryStatus(p.ByName("type"), p.ByName("name"))
+	if se != nil {
+		rbody.Write(404, rbody.FromSnapError(se), w)
+		return
+	}
+	cr := &rbody.CanaryReturned{
+		Name:  c.Name,
+		Type:  c.Type,
+		State: c.State,
+		From: rbody.CanaryVersionStats{
+			Version:     c.From.Version,
+			Calls:       c.From.Calls,
+			Failures:    c.From.Failures,
+			FailureRate: c.From.FailureRate(),
+		},
+		To: rbody.CanaryVersionStats{
+			Version:     c.To.Version,
+			Calls:       c.To.Calls,
+			Failures:    c.To.Failures,
+			FailureRate: c.To.FailureRate(),
+		},
+		Fraction:         c.Options.Fraction,
+		Tasks:            c.Options.Tasks,
+		Window:           c.Options.Window.String(),
+		MaxFailureRate:   c.Options.MaxFailureRate,
+		MinCalls:         c.Options.MinCalls,
+		StartedTimestamp: c.Started.Unix(),
+	}
+	if !c.Ended.IsZero() {
+		cr.EndedTimestamp = c.Ended.Unix()
+	}
+	rbody.Write(200, cr, w)
+}
+
+func (s *apiV1) load(w http.ResponseWriter, r *http.Request, load func(*core.RequestedPlugin) (core.CatalogedPlugin, serror.SnapError)) {
 	lp := &rbody.PluginsLoaded{}
 	lp.LoadedPlugins = make([]rbody.LoadedPlugin, 0)
 	var rp *core.RequestedPlugin
@@ -80,6 +178,7 @@ func (s *apiV1) loadPlugin(w http.ResponseWriter, r *http.Request, _ httprouter.
 		var certPath string
 		var keyPath string
 		var caCertPaths string
+		var lifecycle string
 
 		var signature []byte
 		var checkSum [sha256.Size]byte
@@ -136,7 +235,7 @@ func (s *apiV1) loadPlugin(w http.ResponseWriter, r *http.Request, _ httprouter.
 					return
 				}
 				checkSum = sha256.Sum256(b)
-			case i < 5:
+			case i < 6:
 				if filepath.Ext(p.FileName()) == ".asc" {
 					signature = b
 				} else if strings.HasPrefix(p.FileName(), TLSCertPrefix) {
@@ -156,13 +255,16 @@ func (s *apiV1) loadPlugin(w http.ResponseWriter, r *http.Request, _ httprouter.
 				} else if strings.HasPrefix(p.FileName(), TLSCACertsPrefix) {
 					caCertPaths = string(b)
 					// validation will take place later; take it as it is
+				} else if strings.HasPrefix(p.FileName(), LifecyclePrefix) {
+					lifecycle = string(b)
+					// validation will take place on load
 				} else {
 					e := errors.New("Error: unrecognized file was passed")
 					rbody.Write(500, rbody.FromError(e), w)
 					return
 				}
-			case i == 5:
-				e := errors.New("Error: More than five files passed to the load plugin API")
+			case i == 6:
+				e := errors.New("Error: More than six files passed to the load plugin API")
 				rbody.Write(500, rbody.FromError(e), w)
 				return
 			}
@@ -179,6 +281,7 @@ func (s *apiV1) loadPlugin(w http.ResponseWriter, r *http.Request, _ httprouter.
 		rp.SetCertPath(certPath)
 		rp.SetKeyPath(keyPath)
 		rp.SetCACertPaths(caCertPaths)
+		rp.SetLifecycle(lifecycle)
 		if certPath != "" && keyPath != "" {
 			rp.SetTLSEnabled(true)
 		} else if certPath != "" || keyPath != "" {
@@ -187,7 +290,7 @@ func (s *apiV1) loadPlugin(w http.ResponseWriter, r *http.Request, _ httprouter.
 			return
 		}
 		restLogger.Info("Loading plugin: ", rp.Path())
-		pl, err := s.metricManager.Load(rp)
+		pl, err := load(rp)
 		if err != nil {
 			var ec int
 			restLogger.Error(err)
@@ -214,17 +317,18 @@ func (s *apiV1) loadPlugin(w http.ResponseWriter, r *http.Request, _ httprouter.
 			rbody.Write(500, rbody.FromError(err), w)
 			return
 		}
-		var resp map[string]string
+		var resp remotePluginRequest
 		err = json.Unmarshal(body, &resp)
 		if err != nil {
 			rbody.Write(500, rbody.FromError(err), w)
+			return
 		}
-		rp, err := core.NewRequestedPlugin(resp["uri"], "", nil)
+		rp, err := resp.requestedPlugin()
 		if err != nil {
 			rbody.Write(500, rbody.FromError(err), w)
 			return
 		}
-		pl, err := s.metricManager.Load(rp)
+		pl, err := load(rp)
 		if err != nil {
 			// TODO (JC) should return 409 if plugin already loaded
 			rbody.Write(500, rbody.FromError(err), w)
@@ -235,6 +339,20 @@ func (s *apiV1) loadPlugin(w http.ResponseWriter, r *http.Request, _ httprouter.
 	}
 }
 
+// remotePluginRequest is the request to load a standalone plugin given its
+// URI, or its endpoints along with their TLS settings
+type remotePluginRequest struct {
+	URI       string                `json:"uri"`
+	Endpoints []core.RemoteEndpoint `json:"endpoints"`
+}
+
+func (r remotePluginRequest) requestedPlugin() (*core.RequestedPlugin, error) {
+	if len(r.Endpoints) > 0 {
+		return core.NewRequestedRemotePlugin(r.Endpoints)
+	}
+	return core.NewRequestedRemotePlugin([]core.RemoteEndpoint{{URI: r.URI}})
+}
+
 func (s *apiV1) unloadPlugin(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
 	plName := p.ByName("name")
 	plType := p.ByName("type")
@@ -368,6 +486,19 @@ func catalogedPluginToLoaded(host string, c core.CatalogedPlugin) rbody.LoadedPl
 		Status:          c.Status(),
 		LoadedTimestamp: c.LoadedTimestamp().Unix(),
 		Href:            pluginURI(host, version, c),
+		Signer:          pluginSignerBody(c.Signer()),
+		Metadata:        c.Metadata(),
+	}
+}
+
+func pluginSignerBody(s *core.PluginSigner) *rbody.PluginSigner {
+	if s == nil {
+		return nil
+	}
+	return &rbody.PluginSigner{
+		KeyID:     s.KeyID,
+		Algorithm: s.Algorithm,
+		Identity:  s.Identity,
 	}
 }
 
@@ -420,7 +551,7 @@ func (s *apiV1) getPlugin(w http.ResponseWriter, r *http.Request, p httprouter.P
 	rd := r.FormValue("download")
 	d, _ := strconv.ParseBool(rd)
 	var configPolicy []rbody.PolicyTable
-	if plugin.TypeName() == "processor" || plugin.TypeName() == "publisher" {
+	if plugin.TypeName() == "processor" || plugin.TypeName() == "publisher" || plugin.TypeName() == "streaming-publisher" {
 		rules := plugin.Policy().Get([]string{""}).RulesAsTable()
 		configPolicy = make([]rbody.PolicyTable, 0, len(rules))
 		for _, r := range rules {
@@ -468,6 +599,8 @@ func (s *apiV1) getPlugin(w http.ResponseWriter, r *http.Request, p httprouter.P
 			LoadedTimestamp: plugin.LoadedTimestamp().Unix(),
 			Href:            pluginURI(r.Host, version, plugin),
 			ConfigPolicy:    configPolicy,
+			Signer:          pluginSignerBody(plugin.Signer()),
+			Metadata:        plugin.Metadata(),
 		}
 		rbody.Write(200, pluginRet, w)
 	}
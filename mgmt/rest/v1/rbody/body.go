@@ -113,6 +113,12 @@ func UnmarshalBody(t string, b []byte) (Body, error) {
 		return unmarshalAndHandleError(b, &PluginUnloaded{})
 	case PluginReturnedType:
 		return unmarshalAndHandleError(b, &PluginReturned{})
+	case CanaryReturnedType:
+		return unmarshalAndHandleError(b, &CanaryReturned{})
+	case RepositoryPluginListType:
+		return unmarshalAndHandleError(b, &RepositoryPluginList{})
+	case RepositoryPluginsRemovedType:
+		return unmarshalAndHandleError(b, &RepositoryPluginsRemoved{})
 	case ScheduledTaskListReturnedType:
 		return unmarshalAndHandleError(b, &ScheduledTaskListReturned{})
 	case ScheduledTaskReturnedType:
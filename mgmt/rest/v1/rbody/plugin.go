@@ -22,6 +22,8 @@ package rbody
 import (
 	"fmt"
 	"strings"
+
+	"github.com/intelsdi-x/snap/core"
 )
 
 const (
@@ -29,6 +31,10 @@ const (
 	PluginUnloadedType = "plugin_unloaded"
 	PluginListType     = "plugin_list_returned"
 	PluginReturnedType = "plugin_returned"
+	CanaryReturnedType = "canary_returned"
+
+	RepositoryPluginListType     = "repository_plugin_list_returned"
+	RepositoryPluginsRemovedType = "repository_plugins_removed"
 )
 
 // Successful response to the loading of a plugins
@@ -97,6 +103,16 @@ type LoadedPlugin struct {
 	LoadedTimestamp int64         `json:"loaded_timestamp"`
 	Href            string        `json:"href"`
 	ConfigPolicy    []PolicyTable `json:"policy,omitempty"`
+	Signer          *PluginSigner `json:"signer,omitempty"`
+	// Metadata the plugin returned when loaded
+	Metadata *core.PluginMetadata `json:"metadata,omitempty"`
+}
+
+// Key a plugin was signed with
+type PluginSigner struct {
+	KeyID     string `json:"key_id"`
+	Algorithm string `json:"algorithm"`
+	Identity  string `json:"identity,omitempty"`
 }
 
 type AvailablePlugin struct {
@@ -109,3 +125,75 @@ type AvailablePlugin struct {
 	Href             string `json:"href"`
 	PprofPort        string `json:"pprof_port"`
 }
+
+// Status of a staged swap of a plugin
+type CanaryReturned struct {
+	Name             string             `json:"name"`
+	Type             string             `json:"type"`
+	State            string             `json:"state"`
+	From             CanaryVersionStats `json:"from"`
+	To               CanaryVersionStats `json:"to"`
+	Fraction         float64            `json:"fraction"`
+	Tasks            []string           `json:"tasks,omitempty"`
+	Window           string             `json:"window"`
+	MaxFailureRate   float64            `json:"max_failure_rate"`
+	MinCalls         uint64             `json:"min_calls"`
+	StartedTimestamp int64              `json:"started_timestamp"`
+	EndedTimestamp   int64              `json:"ended_timestamp,omitempty"`
+}
+
+type CanaryVersionStats struct {
+	Version     int     `json:"version"`
+	Calls       uint64  `json:"calls"`
+	Failures    uint64  `json:"failures"`
+	FailureRate float64 `json:"failure_rate"`
+}
+
+func (c *CanaryReturned) ResponseBodyMessage() string {
+	return fmt.Sprintf("Canary swap of %s(%s v%d to v%d) %s", c.Name, c.Type, c.From.Version, c.To.Version, c.State)
+}
+
+func (c *CanaryReturned) ResponseBodyType() string {
+	return CanaryReturnedType
+}
+
+// RepositoryPlugin is a plugin stored in the plugin repository
+type RepositoryPlugin struct {
+	CheckSum        string `json:"checksum"`
+	FileName        string `json:"file_name"`
+	Name            string `json:"name"`
+	Type            string `json:"type"`
+	Version         int    `json:"version"`
+	Size            int64  `json:"size"`
+	Signed          bool   `json:"signed"`
+	TLSEnabled      bool   `json:"tls_enabled"`
+	Lifecycle       string `json:"lifecycle,omitempty"`
+	Loaded          bool   `json:"loaded"`
+	StoredTimestamp int64  `json:"stored_timestamp"`
+}
+
+// Successful response to the listing of the plugin repository
+type RepositoryPluginList struct {
+	Plugins []RepositoryPlugin `json:"plugins"`
+}
+
+func (r *RepositoryPluginList) ResponseBodyMessage() string {
+	return "Plugin repository returned"
+}
+
+func (r *RepositoryPluginList) ResponseBodyType() string {
+	return RepositoryPluginListType
+}
+
+// Successful response to the garbage collection of the plugin repository
+type RepositoryPluginsRemoved struct {
+	Plugins []RepositoryPlugin `json:"removed_plugins"`
+}
+
+func (r *RepositoryPluginsRemoved) ResponseBodyMessage() string {
+	return fmt.Sprintf("Plugins removed from repository: %d", len(r.Plugins))
+}
+
+func (r *RepositoryPluginsRemoved) ResponseBodyType() string {
+	return RepositoryPluginsRemovedType
+}
@@ -105,6 +105,7 @@ func AddSchedulerTaskFromTask(t core.Task) *AddScheduledTask {
 		LastRunTimestamp:   t.LastRunTime().Unix(),
 		HitCount:           int(t.HitCount()),
 		MissCount:          int(t.MissedCount()),
+		DroppedCount:       int(t.DroppedCount()),
 		FailedCount:        int(t.FailedCount()),
 		LastFailureMessage: t.LastFailureMessage(),
 		State:              t.State().String(),
@@ -127,6 +128,7 @@ type ScheduledTask struct {
 	LastRunTimestamp   int64             `json:"last_run_timestamp,omitempty"`
 	HitCount           int               `json:"hit_count,omitempty"`
 	MissCount          int               `json:"miss_count,omitempty"`
+	DroppedCount       int               `json:"dropped_count,omitempty"`
 	FailedCount        int               `json:"failed_count,omitempty"`
 	LastFailureMessage string            `json:"last_failure_message,omitempty"`
 	State              string            `json:"task_state"`
@@ -154,6 +156,7 @@ func SchedulerTaskFromTask(t core.Task) *ScheduledTask {
 		LastRunTimestamp:   t.LastRunTime().Unix(),
 		HitCount:           int(t.HitCount()),
 		MissCount:          int(t.MissedCount()),
+		DroppedCount:       int(t.DroppedCount()),
 		FailedCount:        int(t.FailedCount()),
 		LastFailureMessage: t.LastFailureMessage(),
 		State:              t.State().String(),
@@ -231,6 +234,14 @@ func assertSchedule(s schedule.Schedule, t *AddScheduledTask) {
 			Interval: v.Entry(),
 		}
 		return
+	case *schedule.StreamingSchedule:
+		t.Schedule = &core.Schedule{
+			Type: "streaming",
+		}
+		if v.Interval > 0 {
+			t.Schedule.Interval = v.Interval.String()
+		}
+		return
 	}
 }
 
@@ -0,0 +1,100 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/intelsdi-x/snap/control"
+	"github.com/intelsdi-x/snap/core"
+	"github.com/intelsdi-x/snap/core/serror"
+	"github.com/intelsdi-x/snap/mgmt/rest/v1/rbody"
+	"github.com/julienschmidt/httprouter"
+)
+
+func (s *apiV1) getRepository(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	plugins, se := s.metricManager.PluginRepository()
+	if se != nil {
+		rbody.Write(repositoryErrorCode(se), rbody.FromSnapError(se), w)
+		return
+	}
+	rbody.Write(200, &rbody.RepositoryPluginList{Plugins: repositoryPlugins(plugins)}, w)
+}
+
+func (s *apiV1) gcRepository(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	keep := 0
+	if k := r.URL.Query().Get("keep"); k != "" {
+		var err error
+		if keep, err = strconv.Atoi(k); err != nil || keep < 0 {
+			rbody.Write(400, rbody.FromError(fmt.Errorf("invalid number of versions to keep: %s", k)), w)
+			return
+		}
+	}
+	plugins, se := s.metricManager.GCPluginRepository(keep)
+	if se != nil {
+		rbody.Write(repositoryErrorCode(se), rbody.FromSnapError(se), w)
+		return
+	}
+	rbody.Write(200, &rbody.RepositoryPluginsRemoved{Plugins: repositoryPlugins(plugins)}, w)
+}
+
+func (s *apiV1) exportPlugin(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	plugin, path, se := s.metricManager.ExportPlugin(p.ByName("checksum"))
+	if se != nil {
+		rbody.Write(repositoryErrorCode(se), rbody.FromSnapError(se), w)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", plugin.FileName))
+	http.ServeFile(w, r, path)
+}
+
+func repositoryErrorCode(se serror.SnapError) int {
+	switch se.Error() {
+	case control.ErrRepositoryDisabled.Error(), control.ErrRepositoryPluginNotFound.Error():
+		return 404
+	case control.ErrRepositoryAmbiguousCheckSum.Error():
+		return 400
+	default:
+		return 500
+	}
+}
+
+func repositoryPlugins(plugins []core.RepositoryPlugin) []rbody.RepositoryPlugin {
+	rps := make([]rbody.RepositoryPlugin, len(plugins))
+	for i, p := range plugins {
+		rps[i] = rbody.RepositoryPlugin{
+			CheckSum:        p.CheckSum,
+			FileName:        p.FileName,
+			Name:            p.Name,
+			Type:            p.Type,
+			Version:         p.Version,
+			Size:            p.Size,
+			Signed:          p.Signed,
+			TLSEnabled:      p.TLSEnabled,
+			Lifecycle:       p.Lifecycle,
+			Loaded:          p.Loaded,
+			StoredTimestamp: p.Stored.Unix(),
+		}
+	}
+	return rps
+}
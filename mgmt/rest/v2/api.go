@@ -93,10 +93,12 @@ func (s *apiV2) GetRoutes() []api.Route {
 		//
 		// Load
 		//
-		// A plugin binary is required.
+		// A plugin binary or bundle is required, either uploaded or given by
+		// a download URL and its SHA-256 checksum.
 		//
 		// Consumes:
 		// multipart/form-data
+		// application/json
 		//
 		// Produces:
 		// application/json
@@ -184,6 +186,72 @@ func (s *apiV2) GetRoutes() []api.Route {
 		// 400: ErrorResponse
 		// 401: UnauthResponse
 		api.Route{Method: "DELETE", Path: prefix + "/plugins/:type/:name/:version/config", Handle: s.deletePluginConfigItem},
+		// swagger:route GET /plugins/{ptype}/{pname}/{pversion}/policy plugins getPluginPolicy
+		//
+		// Get Config Policy
+		//
+		// The config policy of the plugin is rendered as a JSON Schema per namespace.
+		//
+		// Produces:
+		// application/json
+		//
+		// Schemes: http, https
+		//
+		// Responses:
+		// 200: PluginPolicyResponse
+		// 400: ErrorResponse
+		// 404: ErrorResponse
+		// 401: UnauthResponse
+		api.Route{Method: "GET", Path: prefix + "/plugins/:type/:name/:version/policy", Handle: s.getPluginPolicy},
+		// swagger:route POST /plugins/{ptype}/{pname}/{pversion}/policy/validate plugins validatePluginPolicy
+		//
+		// Validate Config
+		//
+		// The config is validated against the config policy of the plugin the way it is when a task is created.
+		//
+		// Consumes:
+		// application/json
+		//
+		// Produces:
+		// application/json
+		//
+		// Schemes: http, https
+		//
+		// Responses:
+		// 200: PolicyValidationResponse
+		// 400: ErrorResponse
+		// 404: ErrorResponse
+		// 401: UnauthResponse
+		api.Route{Method: "POST", Path: prefix + "/plugins/:type/:name/:version/policy/validate", Handle: s.validatePluginPolicy},
+		// The global plugin config of all the plugins, of the plugins of a type,
+		// of all the versions of a plugin and of a version of a plugin.
+		api.Route{Method: "GET", Path: prefix + "/config/plugins", Handle: s.getPluginConfigItem},
+		api.Route{Method: "GET", Path: prefix + "/config/plugins/:type", Handle: s.getPluginConfigItem},
+		api.Route{Method: "GET", Path: prefix + "/config/plugins/:type/:name", Handle: s.getPluginConfigItem},
+		api.Route{Method: "GET", Path: prefix + "/config/plugins/:type/:name/:version", Handle: s.getPluginConfigItem},
+		api.Route{Method: "PUT", Path: prefix + "/config/plugins", Handle: s.setPluginConfigItem},
+		api.Route{Method: "PUT", Path: prefix + "/config/plugins/:type", Handle: s.setPluginConfigItem},
+		api.Route{Method: "PUT", Path: prefix + "/config/plugins/:type/:name", Handle: s.setPluginConfigItem},
+		api.Route{Method: "PUT", Path: prefix + "/config/plugins/:type/:name/:version", Handle: s.setPluginConfigItem},
+		api.Route{Method: "DELETE", Path: prefix + "/config/plugins", Handle: s.deletePluginConfigItem},
+		api.Route{Method: "DELETE", Path: prefix + "/config/plugins/:type", Handle: s.deletePluginConfigItem},
+		api.Route{Method: "DELETE", Path: prefix + "/config/plugins/:type/:name", Handle: s.deletePluginConfigItem},
+		api.Route{Method: "DELETE", Path: prefix + "/config/plugins/:type/:name/:version", Handle: s.deletePluginConfigItem},
+		// swagger:route GET /config/history plugins getPluginConfigHistory
+		//
+		// Get Config History
+		//
+		// The changes made to the global plugin config, the oldest first.
+		//
+		// Produces:
+		// application/json
+		//
+		// Schemes: http, https
+		//
+		// Responses:
+		// 200: PluginConfigHistoryResponse
+		// 401: UnauthResponse
+		api.Route{Method: "GET", Path: prefix + "/config/history", Handle: s.getPluginConfigHistory},
 		// swagger:route GET /metrics plugins getMetrics
 		//
 		// Get Metrics
@@ -201,6 +269,21 @@ func (s *apiV2) GetRoutes() []api.Route {
 		// 500: ErrorResponse
 		// 401: UnauthResponse
 		api.Route{Method: "GET", Path: prefix + "/metrics", Handle: s.getMetrics},
+		// swagger:route GET /cache plugins getCache
+		//
+		// Get Cache
+		//
+		// The usage of the metric cache is returned for each plugin.
+		//
+		// Produces:
+		// application/json
+		//
+		// Schemes: http, https
+		//
+		// Responses:
+		// 200: CacheResponse
+		// 401: UnauthResponse
+		api.Route{Method: "GET", Path: prefix + "/cache", Handle: s.getCache},
 		// swagger:route GET /tasks tasks getTasks
 		//
 		// Get All
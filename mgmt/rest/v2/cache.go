@@ -0,0 +1,67 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// CacheResponse is the representation of the metric cache usage.
+//
+// swagger:response CacheResponse
+type CacheResp struct {
+	// in: body
+	Body CacheResponse
+}
+
+type CacheResponse struct {
+	Cache []CacheStats `json:"cache"`
+}
+
+// CacheStats represents the usage of the metric cache by a plugin.
+type CacheStats struct {
+	// Plugin is the key of the plugin: {type}:{name}:{version}
+	Plugin    string  `json:"plugin"`
+	Entries   int     `json:"entries"`
+	Bytes     int64   `json:"bytes"`
+	Hits      uint64  `json:"hits"`
+	Misses    uint64  `json:"misses"`
+	Evictions uint64  `json:"evictions"`
+	HitRatio  float64 `json:"hit_ratio"`
+}
+
+func (s *apiV2) getCache(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	stats := s.metricManager.MetricCacheStats()
+	resp := CacheResponse{Cache: make([]CacheStats, len(stats))}
+	for i, st := range stats {
+		resp.Cache[i] = CacheStats{
+			Plugin:    st.Plugin,
+			Entries:   st.Entries,
+			Bytes:     st.Bytes,
+			Hits:      st.Hits,
+			Misses:    st.Misses,
+			Evictions: st.Evictions,
+			HitRatio:  st.HitRatio(),
+		}
+	}
+	Write(200, resp, w)
+}
@@ -72,6 +72,21 @@ type PluginConfigDeleteParams struct {
 	Config []string `json:"config"`
 }
 
+// PluginConfigHistoryResponse represents the changes made to the global
+// plugin config.
+//
+// swagger:response PluginConfigHistoryResponse
+type PluginConfigHistoryResponse struct {
+	// in: body
+	Body PluginConfigHistory
+}
+
+// PluginConfigHistory lists the changes made to the global plugin config,
+// the oldest first.
+type PluginConfigHistory struct {
+	Changes []core.PluginConfigChange `json:"changes"`
+}
+
 func (s *apiV2) getPluginConfigItem(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
 	var err error
 	styp := p.ByName("type")
@@ -104,25 +119,10 @@ func (s *apiV2) getPluginConfigItem(w http.ResponseWriter, r *http.Request, p ht
 }
 
 func (s *apiV2) deletePluginConfigItem(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
-	var err error
-	var typ core.PluginType
-	styp := p.ByName("type")
-	if styp != "" {
-		typ, err = core.GetPluginType(styp)
-		if err != nil {
-			Write(400, FromError(err), w)
-			return
-		}
-	}
-
-	name := p.ByName("name")
-	sver := p.ByName("version")
-	iver := -2
-	if sver != "" {
-		if iver, err = strconv.Atoi(sver); err != nil {
-			Write(400, FromError(err), w)
-			return
-		}
+	change, err := pluginConfigChange(core.PluginConfigDelete, r, p)
+	if err != nil {
+		Write(400, FromError(err), w)
+		return
 	}
 
 	src := []string{}
@@ -131,12 +131,12 @@ func (s *apiV2) deletePluginConfigItem(w http.ResponseWriter, r *http.Request, p
 		Write(400, FromError(err), w)
 		return
 	}
+	change.Fields = src
 
-	var res cdata.ConfigDataNode
-	if styp == "" {
-		res = s.configManager.DeletePluginConfigDataNodeFieldAll(src...)
-	} else {
-		res = s.configManager.DeletePluginConfigDataNodeField(typ, name, iver, src...)
+	res, err := s.configManager.ChangePluginConfig(change)
+	if err != nil {
+		Write(500, FromError(err), w)
+		return
 	}
 
 	item := &PluginConfigItem{res}
@@ -144,25 +144,10 @@ func (s *apiV2) deletePluginConfigItem(w http.ResponseWriter, r *http.Request, p
 }
 
 func (s *apiV2) setPluginConfigItem(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
-	var err error
-	var typ core.PluginType
-	styp := p.ByName("type")
-	if styp != "" {
-		typ, err = core.GetPluginType(styp)
-		if err != nil {
-			Write(400, FromError(err), w)
-			return
-		}
-	}
-
-	name := p.ByName("name")
-	sver := p.ByName("version")
-	iver := -2
-	if sver != "" {
-		if iver, err = strconv.Atoi(sver); err != nil {
-			Write(400, FromError(err), w)
-			return
-		}
+	change, err := pluginConfigChange(core.PluginConfigSet, r, p)
+	if err != nil {
+		Write(400, FromError(err), w)
+		return
 	}
 
 	src := cdata.NewNode()
@@ -171,14 +156,46 @@ func (s *apiV2) setPluginConfigItem(w http.ResponseWriter, r *http.Request, p ht
 		Write(400, FromError(err), w)
 		return
 	}
+	change.Config = src
 
-	var res cdata.ConfigDataNode
-	if styp == "" {
-		res = s.configManager.MergePluginConfigDataNodeAll(src)
-	} else {
-		res = s.configManager.MergePluginConfigDataNode(typ, name, iver, src)
+	res, err := s.configManager.ChangePluginConfig(change)
+	if err != nil {
+		Write(500, FromError(err), w)
+		return
 	}
 
 	item := &PluginConfigItem{res}
 	Write(200, item, w)
 }
+
+func (s *apiV2) getPluginConfigHistory(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	Write(200, PluginConfigHistory{Changes: s.configManager.PluginConfigHistory()}, w)
+}
+
+// pluginConfigChange returns the change of the global plugin config at the
+// level of the path parameters, made by the client of the request
+func pluginConfigChange(action string, r *http.Request, p httprouter.Params) (core.PluginConfigChange, error) {
+	change := core.PluginConfigChange{
+		Action:  action,
+		Address: r.RemoteAddr,
+		Name:    p.ByName("name"),
+	}
+	if user, _, ok := r.BasicAuth(); ok {
+		change.User = user
+	}
+	if styp := p.ByName("type"); styp != "" {
+		typ, err := core.GetPluginType(styp)
+		if err != nil {
+			return change, err
+		}
+		change.Type = typ.String()
+	}
+	if sver := p.ByName("version"); sver != "" {
+		ver, err := strconv.Atoi(sver)
+		if err != nil {
+			return change, err
+		}
+		change.Version = ver
+	}
+	return change, nil
+}
@@ -58,10 +58,12 @@ func (m MockLoadedPlugin) Version() int     { return m.MyVersion }
 func (m MockLoadedPlugin) Key() string {
 	return fmt.Sprintf("%s"+core.Separator+"%s"+core.Separator+"%d", m.MyType, m.MyName, m.MyVersion)
 }
-func (m MockLoadedPlugin) Plugin() string     { return "" }
-func (m MockLoadedPlugin) IsSigned() bool     { return false }
-func (m MockLoadedPlugin) Status() string     { return "" }
-func (m MockLoadedPlugin) PluginPath() string { return "" }
+func (m MockLoadedPlugin) Plugin() string                 { return "" }
+func (m MockLoadedPlugin) IsSigned() bool                 { return false }
+func (m MockLoadedPlugin) Signer() *core.PluginSigner     { return nil }
+func (m MockLoadedPlugin) Metadata() *core.PluginMetadata { return nil }
+func (m MockLoadedPlugin) Status() string                 { return "" }
+func (m MockLoadedPlugin) PluginPath() string             { return "" }
 func (m MockLoadedPlugin) LoadedTimestamp() *time.Time {
 	t := time.Date(2016, time.September, 6, 0, 0, 0, 0, time.UTC)
 	return &t
@@ -127,6 +129,48 @@ func (m MockManagesMetrics) AvailablePlugins() []core.AvailablePlugin {
 		MockLoadedPlugin{MyName: "foobar", MyType: "processor", MyVersion: 1},
 	}
 }
+func (m MockManagesMetrics) CanarySwap(*core.RequestedPlugin, core.Plugin, core.CanaryOptions) (core.CatalogedPlugin, serror.SnapError) {
+	return MockLoadedPlugin{"foo", "collector", 2}, nil
+}
+func (m MockManagesMetrics) CanaryStatus(typeName, name string) (core.CanaryStatus, serror.SnapError) {
+	return core.CanaryStatus{
+		Type: typeName,
+		Name: name,
+		From: core.CanaryVersionStats{Version: 1, Calls: 10},
+		To:   core.CanaryVersionStats{Version: 2, Calls: 2, Failures: 1},
+		Options: core.CanaryOptions{
+			Fraction:       0.1,
+			Window:         5 * time.Minute,
+			MaxFailureRate: 0.1,
+			MinCalls:       10,
+		},
+		State:   core.CanaryRunning,
+		Started: time.Unix(1473120000, 0),
+	}, nil
+}
+func (m MockManagesMetrics) PluginRepository() ([]core.RepositoryPlugin, serror.SnapError) {
+	return []core.RepositoryPlugin{
+		{
+			CheckSum: "4f2d8c1a6e0b9f3d2c5a7e8b1d0f6a4c3b2e9d8f7a6c5b4e3d2c1b0a9f8e7d6c",
+			FileName: "snap-plugin-collector-foo",
+			Type:     "collector",
+			Name:     "foo",
+			Version:  2,
+			Size:     1024,
+			Loaded:   true,
+			Stored:   time.Unix(1473120000, 0),
+		},
+	}, nil
+}
+func (m MockManagesMetrics) GCPluginRepository(int) ([]core.RepositoryPlugin, serror.SnapError) {
+	return []core.RepositoryPlugin{}, nil
+}
+func (m MockManagesMetrics) ExportPlugin(checkSum string) (core.RepositoryPlugin, string, serror.SnapError) {
+	return core.RepositoryPlugin{}, "", serror.New(errors.New("plugin not found in repository"))
+}
+func (m MockManagesMetrics) RemotePluginEndpoints(core.Plugin) ([]core.RemoteEndpointStatus, serror.SnapError) {
+	return nil, nil
+}
 func (m MockManagesMetrics) GetAutodiscoverPaths() []string {
 	return nil
 }
@@ -135,6 +179,12 @@ func (m MockManagesMetrics) GetTempDir() string {
 	return ""
 }
 
+func (m MockManagesMetrics) MetricCacheStats() []core.CacheStats {
+	return []core.CacheStats{
+		{Plugin: "collector:foo:2", Entries: 3, Bytes: 1024, Hits: 30, Misses: 10, Evictions: 1},
+	}
+}
+
 // These constants are the expected plugin responses from running
 // rest_v2_test.go on the plugin routes found in mgmt/rest/server.go
 const (
@@ -260,6 +310,21 @@ const (
     }
   ]
 }
+`
+
+	GET_CACHE_RESPONSE = `{
+  "cache": [
+    {
+      "plugin": "collector:foo:2",
+      "entries": 3,
+      "bytes": 1024,
+      "hits": 30,
+      "misses": 10,
+      "evictions": 1,
+      "hit_ratio": 0.75
+    }
+  ]
+}
 `
 
 	UNLOAD_PLUGIN_RESPONSE = ``
@@ -23,6 +23,8 @@ import (
 	"bytes"
 	"compress/gzip"
 	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -91,11 +93,23 @@ type Plugin struct {
 	LastHitTimestamp int64         `json:"last_hit_timestamp,omitempty"`
 	ID               uint32        `json:"id,omitempty"`
 	PprofPort        string        `json:"pprof_port,omitempty"`
+	Signer           *PluginSigner `json:"signer,omitempty"`
+	// Endpoints of a standalone plugin and their health
+	Endpoints []core.RemoteEndpointStatus `json:"endpoints,omitempty"`
+	// Metadata the plugin returned when loaded
+	Metadata *core.PluginMetadata `json:"metadata,omitempty"`
+}
+
+// PluginSigner represents the key a plugin was signed with.
+type PluginSigner struct {
+	KeyID     string `json:"key_id"`
+	Algorithm string `json:"algorithm"`
+	Identity  string `json:"identity,omitempty"`
 }
 
 // PluginParams represents the request path plugin name, version and type.
 //
-// swagger:parameters getPlugin unloadPlugin getPluginConfigItem setPluginConfigItem
+// swagger:parameters getPlugin unloadPlugin getPluginConfigItem setPluginConfigItem getPluginPolicy validatePluginPolicy
 type PluginParams struct {
 	// required: true
 	// in: path
@@ -147,11 +161,16 @@ type PluginPostParams struct {
 	// in: formData
 	//
 	CACerts string `json:"ca_certs"`
-	// Stand-alone plugin URI
+	// Stand-alone plugin URI, or comma separated URIs of its endpoints
 	//
 	// in: formData
 	//
 	PluginURI string `json:"plugin_uri"`
+	// Plugin lifecycle: lazy, eager[:instances] or on-demand[:idle-timeout]
+	//
+	// in: formData
+	//
+	Lifecycle string `json:"lifecycle"`
 }
 
 // Map for collecting HTTP form field data
@@ -186,7 +205,7 @@ func (s *apiV2) loadPlugin(w http.ResponseWriter, r *http.Request, _ httprouter.
 	}
 
 	if strings.HasPrefix(mediaType, "multipart/") {
-		var certPath, keyPath, caCertPaths string
+		var certPath, keyPath, caCertPaths, lifecycle string
 		var signature []byte
 		var checkSum [sha256.Size]byte
 
@@ -259,6 +278,8 @@ func (s *apiV2) loadPlugin(w http.ResponseWriter, r *http.Request, _ httprouter.
 			case "plugin_cert":
 				certPath = string(field.data)
 				handleError(certPath, w)
+			case "lifecycle":
+				lifecycle = string(field.data)
 			//plugin_data is from REST API and snap-plugins is from rest_v2_test.go.
 			case "plugin_data", "snap-plugins":
 				rp, err = core.NewRequestedPlugin(field.fileName, s.metricManager.GetTempDir(), field.data)
@@ -268,8 +289,12 @@ func (s *apiV2) loadPlugin(w http.ResponseWriter, r *http.Request, _ httprouter.
 				}
 				checkSum = sha256.Sum256(field.data)
 			case "plugin_uri":
-				pluginURI := string(field.data)
-				rp, err = core.NewRequestedPlugin(pluginURI, "", nil)
+				// several endpoints of a standalone plugin are comma separated
+				var endpoints []core.RemoteEndpoint
+				for _, uri := range strings.Split(string(field.data), ",") {
+					endpoints = append(endpoints, core.RemoteEndpoint{URI: strings.TrimSpace(uri)})
+				}
+				rp, err = core.NewRequestedRemotePlugin(endpoints)
 				if err != nil {
 					Write(500, FromError(err), w)
 					return
@@ -293,45 +318,119 @@ func (s *apiV2) loadPlugin(w http.ResponseWriter, r *http.Request, _ httprouter.
 			return
 		}
 
-		// check if one of TLS params (cert or key) has been provided; if not, skip the part related to TLS
-		if hasTLS(certPath, keyPath) {
-			// check if both of required params have been provided to setup TLS connection;
-			// if not, return an appropriate error
-			if isTLSEnabled(certPath, keyPath) {
-				rp.SetTLSEnabled(true)
-				rp.SetCACertPaths(caCertPaths)
-				rp.SetCertPath(certPath)
-				rp.SetKeyPath(keyPath)
-			} else {
-				e := errors.New("Error: TLS setup incomplete - Both plugin TLS certificate and the key are required")
-				Write(500, FromError(e), w)
-				return
-			}
-		}
 		rp.SetSignature(signature)
+		s.loadRequestedPlugin(w, r, rp, certPath, keyPath, caCertPaths, lifecycle)
+	} else if mediaType == "application/json" {
+		d := PluginDownload{}
+		if err := json.NewDecoder(r.Body).Decode(&d); err != nil {
+			Write(400, FromError(err), w)
+			return
+		}
+		rp, err := d.requestedPlugin()
+		if err != nil {
+			Write(400, FromError(err), w)
+			return
+		}
+		s.loadRequestedPlugin(w, r, rp, d.CertPath, d.KeyPath, d.CACertPaths, d.Lifecycle)
+	} else {
+		Write(415, FromError(fmt.Errorf("Error: unsupported media type %s", mediaType)), w)
+	}
+}
 
-		restLogger.Info("Loading plugin: ", rp.Path())
-		pl, err := s.metricManager.Load(rp)
+// PluginDownload represents the request to load a plugin, or a plugin bundle,
+// which is downloaded by snapteld and verified against its checksum, or the
+// request to load a standalone plugin served by the given endpoints.
+type PluginDownload struct {
+	// HTTP(S) URL of the plugin
+	URL string `json:"url"`
+	// Hex encoded SHA-256 checksum of the plugin
+	SHA256 string `json:"sha256"`
+	// HTTP(S) URL of the detached signature of the plugin
+	SignatureURL string `json:"signature_url,omitempty"`
+	CertPath     string `json:"plugin_cert,omitempty"`
+	KeyPath      string `json:"plugin_key,omitempty"`
+	CACertPaths  string `json:"ca_certs,omitempty"`
+	Lifecycle    string `json:"lifecycle,omitempty"`
+	// Endpoints of a standalone plugin, with their TLS settings
+	Endpoints []core.RemoteEndpoint `json:"endpoints,omitempty"`
+}
+
+func (d PluginDownload) requestedPlugin() (*core.RequestedPlugin, error) {
+	if d.URL == "" && len(d.Endpoints) > 0 {
+		return core.NewRequestedRemotePlugin(d.Endpoints)
+	}
+	u, err := url.ParseRequestURI(d.URL)
+	if err != nil {
+		return nil, err
+	}
+	b, err := hex.DecodeString(d.SHA256)
+	if err != nil || len(b) != sha256.Size {
+		return nil, errors.New("Error: sha256 must be a hex encoded SHA-256 checksum")
+	}
+	var checkSum [sha256.Size]byte
+	copy(checkSum[:], b)
+	rp := core.NewRequestedPluginDownload(u, checkSum)
+	if d.SignatureURL != "" {
+		su, err := url.ParseRequestURI(d.SignatureURL)
 		if err != nil {
-			var ec int
-			restLogger.Error(err)
+			return nil, err
+		}
+		rp.SetSignatureUrl(su)
+	}
+	return rp, nil
+}
+
+// loadRequestedPlugin applies the TLS settings and lifecycle to the requested
+// plugin and loads it.
+func (s *apiV2) loadRequestedPlugin(w http.ResponseWriter, r *http.Request, rp *core.RequestedPlugin, certPath, keyPath, caCertPaths, lifecycle string) {
+	// check if one of TLS params (cert or key) has been provided; if not, skip the part related to TLS
+	if hasTLS(certPath, keyPath) {
+		// check if both of required params have been provided to setup TLS connection;
+		// if not, return an appropriate error
+		if isTLSEnabled(certPath, keyPath) {
+			rp.SetTLSEnabled(true)
+			rp.SetCACertPaths(caCertPaths)
+			rp.SetCertPath(certPath)
+			rp.SetKeyPath(keyPath)
+		} else {
+			e := errors.New("Error: TLS setup incomplete - Both plugin TLS certificate and the key are required")
+			Write(500, FromError(e), w)
+			return
+		}
+	}
+	rp.SetLifecycle(lifecycle)
+
+	if rp.DownloadUrl() != nil {
+		restLogger.Info("Loading plugin: ", rp.DownloadUrl())
+	} else if rp.Uri() != nil {
+		restLogger.Info("Loading standalone plugin: ", rp.Uri())
+	} else {
+		restLogger.Info("Loading plugin: ", rp.Path())
+	}
+	pl, err := s.metricManager.Load(rp)
+	if err != nil {
+		var ec int
+		restLogger.Error(err)
+		if rp.Path() != "" {
 			restLogger.Debugf("Removing file (%s)", rp.Path())
 			err2 := os.RemoveAll(filepath.Dir(rp.Path()))
 			if err2 != nil {
 				restLogger.Error(err2)
 			}
-			rb := FromError(err)
-			switch rb.ErrorMessage {
-			case ErrPluginAlreadyLoaded:
-				ec = 409
-			default:
-				ec = 500
-			}
-			Write(ec, rb, w)
-			return
 		}
-		Write(201, catalogedPluginBody(r.Host, pl), w)
+		rb := FromError(err)
+		switch rb.ErrorMessage {
+		case ErrPluginAlreadyLoaded:
+			ec = 409
+		case control.ErrDownloadCheckSumMismatch.Error():
+			ec = 400
+		default:
+			ec = 500
+		}
+		Write(ec, rb, w)
+		return
 	}
+	Write(201, catalogedPluginBody(r.Host, pl), w)
 }
 
 func handleError(p string, w http.ResponseWriter) {
@@ -458,6 +557,19 @@ func catalogedPluginBody(host string, c core.CatalogedPlugin) Plugin {
 		Status:          c.Status(),
 		LoadedTimestamp: c.LoadedTimestamp().Unix(),
 		Href:            pluginURI(host, c),
+		Signer:          pluginSignerBody(c.Signer()),
+		Metadata:        c.Metadata(),
+	}
+}
+
+func pluginSignerBody(s *core.PluginSigner) *PluginSigner {
+	if s == nil {
+		return nil
+	}
+	return &PluginSigner{
+		KeyID:     s.KeyID,
+		Algorithm: s.Algorithm,
+		Identity:  s.Identity,
 	}
 }
 
@@ -483,32 +595,21 @@ func pluginURI(host string, c core.Plugin) string {
 }
 
 func (s *apiV2) getPlugin(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
-	plType, plName, plVersion, f, se := pluginParameters(p)
+	plugin, code, se := s.catalogedPlugin(p)
 	if se != nil {
-		Write(400, FromSnapError(se), w)
+		Write(code, FromSnapError(se), w)
 		return
 	}
-
-	pluginCatalog := s.metricManager.PluginCatalog()
-	var plugin core.CatalogedPlugin
-	for _, item := range pluginCatalog {
-		if item.Name() == plName &&
-			item.Version() == int(plVersion) &&
-			item.TypeName() == plType {
-			plugin = item
-			break
-		}
-	}
-	if plugin == nil {
-		se := serror.New(ErrPluginNotFound, f)
-		Write(404, FromSnapError(se), w)
-		return
+	f := map[string]interface{}{
+		"plugin-name":    plugin.Name(),
+		"plugin-version": plugin.Version(),
+		"plugin-type":    plugin.TypeName(),
 	}
 
 	rd := r.FormValue("download")
 	d, _ := strconv.ParseBool(rd)
 	var configPolicy []PolicyTable
-	if plugin.TypeName() == "processor" || plugin.TypeName() == "publisher" {
+	if plugin.TypeName() == "processor" || plugin.TypeName() == "publisher" || plugin.TypeName() == "streaming-publisher" {
 		rules := plugin.Policy().Get([]string{""}).RulesAsTable()
 		configPolicy = make([]PolicyTable, 0, len(rules))
 		for _, r := range rules {
@@ -556,6 +657,12 @@ func (s *apiV2) getPlugin(w http.ResponseWriter, r *http.Request, p httprouter.P
 		LoadedTimestamp: plugin.LoadedTimestamp().Unix(),
 		Href:            pluginURI(r.Host, plugin),
 		ConfigPolicy:    configPolicy,
+		Signer:          pluginSignerBody(plugin.Signer()),
+		Metadata:        plugin.Metadata(),
+	}
+	if pluginRet.Endpoints, se = s.metricManager.RemotePluginEndpoints(plugin); se != nil {
+		Write(500, FromSnapError(se), w)
+		return
 	}
 	Write(200, pluginRet, w)
 }
@@ -0,0 +1,258 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/intelsdi-x/snap/control/plugin/cpolicy"
+	"github.com/intelsdi-x/snap/core"
+	"github.com/intelsdi-x/snap/core/cdata"
+	"github.com/intelsdi-x/snap/core/ctypes"
+	"github.com/intelsdi-x/snap/core/serror"
+	"github.com/julienschmidt/httprouter"
+)
+
+// jsonSchemaVersion is the JSON Schema draft the config policies are rendered with
+const jsonSchemaVersion = "http://json-schema.org/draft-04/schema#"
+
+// jsonSchemaTypes maps the types of the config policy rules to JSON Schema types
+var jsonSchemaTypes = map[string]string{
+	cpolicy.StringType:  "string",
+	cpolicy.IntegerType: "integer",
+	cpolicy.FloatType:   "number",
+	cpolicy.BoolType:    "boolean",
+}
+
+// PluginPolicyResponse represents the config policy of a plugin.
+//
+// swagger:response PluginPolicyResponse
+type PluginPolicyResponse struct {
+	// in: body
+	Body PluginPolicy
+}
+
+// PluginPolicy lists the config policy of a plugin per namespace.
+type PluginPolicy struct {
+	Policies []NamespacePolicy `json:"policies"`
+}
+
+// NamespacePolicy is the JSON Schema of the config of the metrics under a
+// namespace, the namespace of processors and publishers is empty.
+type NamespacePolicy struct {
+	Namespace string     `json:"namespace"`
+	Schema    JSONSchema `json:"schema"`
+}
+
+// JSONSchema is the JSON Schema of a config map.
+type JSONSchema struct {
+	Schema     string                        `json:"$schema"`
+	Type       string                        `json:"type"`
+	Properties map[string]JSONSchemaProperty `json:"properties"`
+	Required   []string                      `json:"required,omitempty"`
+}
+
+// JSONSchemaProperty is the JSON Schema of a config item.
+type JSONSchemaProperty struct {
+	Type    string             `json:"type"`
+	Default ctypes.ConfigValue `json:"default,omitempty"`
+	Minimum ctypes.ConfigValue `json:"minimum,omitempty"`
+	Maximum ctypes.ConfigValue `json:"maximum,omitempty"`
+}
+
+// PluginPolicyValidateParams defines the config validated against the config
+// policy of a plugin.
+//
+// swagger:parameters validatePluginPolicy
+type PluginPolicyValidateParams struct {
+	// in: body
+	Body PolicyValidation
+}
+
+// PolicyValidation is a config to validate, against the policy of namespace
+// and its parents or against the policy of all the namespaces if it is empty.
+type PolicyValidation struct {
+	Namespace string                `json:"namespace"`
+	Config    *cdata.ConfigDataNode `json:"config"`
+}
+
+// PolicyValidationResponse represents the validation report of a config.
+//
+// swagger:response PolicyValidationResponse
+type PolicyValidationResponse struct {
+	// in: body
+	Body PolicyValidationReport
+}
+
+// PolicyValidationReport tells whether a config is valid for each namespace.
+type PolicyValidationReport struct {
+	Valid   bool                        `json:"valid"`
+	Results []NamespaceValidationResult `json:"results"`
+}
+
+// NamespaceValidationResult lists the errors of a config for a namespace.
+type NamespaceValidationResult struct {
+	Namespace string   `json:"namespace"`
+	Valid     bool     `json:"valid"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+func (s *apiV2) getPluginPolicy(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	plugin, code, se := s.catalogedPlugin(p)
+	if se != nil {
+		Write(code, FromSnapError(se), w)
+		return
+	}
+	Write(200, policyBody(plugin.Policy()), w)
+}
+
+func (s *apiV2) validatePluginPolicy(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	plugin, code, se := s.catalogedPlugin(p)
+	if se != nil {
+		Write(code, FromSnapError(se), w)
+		return
+	}
+	v := PolicyValidation{}
+	if errCode, err := core.UnmarshalBody(&v, r.Body); errCode != 0 && err != nil {
+		Write(400, FromError(err), w)
+		return
+	}
+	if v.Config == nil {
+		v.Config = cdata.NewNode()
+	}
+	Write(200, validatePolicy(plugin.Policy(), v), w)
+}
+
+// policyBody renders the JSON Schema of each namespace of a config policy
+func policyBody(cp *cpolicy.ConfigPolicy) PluginPolicy {
+	pp := PluginPolicy{Policies: []NamespacePolicy{}}
+	if cp == nil {
+		return pp
+	}
+	for _, kn := range cp.GetAll() {
+		pp.Policies = append(pp.Policies, NamespacePolicy{
+			Namespace: policyNamespace(kn.Key),
+			Schema:    jsonSchema(kn.ConfigPolicyNode),
+		})
+	}
+	sort.Sort(byNamespace(pp.Policies))
+	return pp
+}
+
+type byNamespace []NamespacePolicy
+
+func (b byNamespace) Len() int           { return len(b) }
+func (b byNamespace) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b byNamespace) Less(i, j int) bool { return b[i].Namespace < b[j].Namespace }
+
+func jsonSchema(n *cpolicy.ConfigPolicyNode) JSONSchema {
+	js := JSONSchema{
+		Schema:     jsonSchemaVersion,
+		Type:       "object",
+		Properties: map[string]JSONSchemaProperty{},
+	}
+	for _, rt := range n.RulesAsTable() {
+		js.Properties[rt.Name] = JSONSchemaProperty{
+			Type:    jsonSchemaTypes[rt.Type],
+			Default: configValue(rt.Default),
+			Minimum: configValue(rt.Minimum),
+			Maximum: configValue(rt.Maximum),
+		}
+		if rt.Required {
+			js.Required = append(js.Required, rt.Name)
+		}
+	}
+	sort.Strings(js.Required)
+	return js
+}
+
+// configValue returns the config value of a rule table field, nil if unset
+func configValue(i interface{}) ctypes.ConfigValue {
+	cv, _ := i.(ctypes.ConfigValue)
+	return cv
+}
+
+// validatePolicy processes a config the way it is processed when a task is
+// created
+func validatePolicy(cp *cpolicy.ConfigPolicy, v PolicyValidation) PolicyValidationReport {
+	report := PolicyValidationReport{Valid: true, Results: []NamespaceValidationResult{}}
+	if cp == nil {
+		return report
+	}
+	var nodes []NamespacePolicy
+	if v.Namespace != "" {
+		nodes = append(nodes, NamespacePolicy{Namespace: v.Namespace})
+	} else {
+		nodes = policyBody(cp).Policies
+	}
+	for _, np := range nodes {
+		res := NamespaceValidationResult{Namespace: np.Namespace, Valid: true}
+		table := map[string]ctypes.ConfigValue{}
+		for k, cv := range v.Config.Table() {
+			table[k] = cv
+		}
+		if _, errs := cp.Get(policyKey(np.Namespace)).Process(table); errs.HasErrors() {
+			res.Valid = false
+			report.Valid = false
+			for _, err := range errs.Errors() {
+				res.Errors = append(res.Errors, err.Error())
+			}
+			sort.Strings(res.Errors)
+		}
+		report.Results = append(report.Results, res)
+	}
+	return report
+}
+
+// policyNamespace renders the key of a config policy node as a namespace
+func policyNamespace(key []string) string {
+	if len(key) == 0 || (len(key) == 1 && key[0] == "") {
+		return ""
+	}
+	return "/" + strings.Join(key, "/")
+}
+
+// policyKey returns the key of the config policy node of a namespace
+func policyKey(ns string) []string {
+	ns = strings.Trim(ns, "/")
+	if ns == "" {
+		return []string{""}
+	}
+	return strings.Split(ns, "/")
+}
+
+// catalogedPlugin returns the cataloged plugin of the path parameters, or
+// the status code and the error to return
+func (s *apiV2) catalogedPlugin(p httprouter.Params) (core.CatalogedPlugin, int, serror.SnapError) {
+	plType, plName, plVersion, f, se := pluginParameters(p)
+	if se != nil {
+		return nil, 400, se
+	}
+	for _, item := range s.metricManager.PluginCatalog() {
+		if item.Name() == plName &&
+			item.Version() == plVersion &&
+			item.TypeName() == plType {
+			return item, 0, nil
+		}
+	}
+	return nil, 404, serror.New(ErrPluginNotFound, f)
+}
@@ -0,0 +1,89 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/intelsdi-x/snap/control/plugin/cpolicy"
+	"github.com/intelsdi-x/snap/core/cdata"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func mockPolicy() *cpolicy.ConfigPolicy {
+	cp := cpolicy.New()
+	name, _ := cpolicy.NewStringRule("name", true)
+	count, _ := cpolicy.NewIntegerRule("count", false, 1)
+	count.SetMinimum(0)
+	count.SetMaximum(10)
+	n := cpolicy.NewPolicyNode()
+	n.Add(name, count)
+	cp.Add([]string{"intel", "mock"}, n)
+	ratio, _ := cpolicy.NewFloatRule("ratio", true)
+	n = cpolicy.NewPolicyNode()
+	n.Add(ratio)
+	cp.Add([]string{"intel", "mock", "foo"}, n)
+	return cp
+}
+
+func TestPluginPolicy(t *testing.T) {
+	Convey("Given a config policy", t, func() {
+		cp := mockPolicy()
+
+		Convey("It is rendered as a JSON Schema per namespace", func() {
+			pp := policyBody(cp)
+			So(pp.Policies, ShouldHaveLength, 2)
+			So(pp.Policies[0].Namespace, ShouldEqual, "/intel/mock")
+			So(pp.Policies[1].Namespace, ShouldEqual, "/intel/mock/foo")
+			b, err := json.Marshal(pp.Policies[0].Schema)
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, `{"$schema":"http://json-schema.org/draft-04/schema#","type":"object",`+
+				`"properties":{"count":{"type":"integer","default":1,"minimum":0,"maximum":10},"name":{"type":"string"}},`+
+				`"required":["name"]}`)
+		})
+		Convey("A config is validated against a namespace and its parents", func() {
+			cfg := cdata.NewNode()
+			So(json.Unmarshal([]byte(`{"name": "mock", "count": 11}`), cfg), ShouldBeNil)
+			report := validatePolicy(cp, PolicyValidation{Namespace: "/intel/mock/foo/bar", Config: cfg})
+			So(report.Valid, ShouldBeFalse)
+			So(report.Results, ShouldHaveLength, 1)
+			So(report.Results[0].Errors, ShouldHaveLength, 2)
+		})
+		Convey("A config is validated against all the namespaces", func() {
+			cfg := cdata.NewNode()
+			So(json.Unmarshal([]byte(`{"name": "mock", "count": 2}`), cfg), ShouldBeNil)
+			report := validatePolicy(cp, PolicyValidation{Config: cfg})
+			So(report.Valid, ShouldBeFalse)
+			So(report.Results, ShouldHaveLength, 2)
+			So(report.Results[0].Valid, ShouldBeTrue)
+			So(report.Results[1].Valid, ShouldBeFalse)
+			So(report.Results[1].Errors, ShouldResemble, []string{"required key missing (ratio)"})
+			So(cfg.Table(), ShouldNotContainKey, "ratio")
+		})
+	})
+	Convey("The root namespace of processors and publishers is empty", t, func() {
+		So(policyNamespace([]string{""}), ShouldEqual, "")
+		So(policyKey(""), ShouldResemble, []string{""})
+		So(policyKey("/intel/mock/"), ShouldResemble, []string{"intel", "mock"})
+	})
+}
@@ -108,12 +108,14 @@ type Task struct {
 	LastRunTimestamp   int64             `json:"last_run_timestamp,omitempty"`
 	HitCount           int               `json:"hit_count,omitempty"`
 	MissCount          int               `json:"miss_count,omitempty"`
+	DroppedCount       int               `json:"dropped_count,omitempty"`
 	FailedCount        int               `json:"failed_count,omitempty"`
 	LastFailureMessage string            `json:"last_failure_message,omitempty"`
 	TaskState          string            `json:"task_state,omitempty"`
 	Href               string            `json:"href,omitempty"`
 	Start              bool              `json:"start,omitempty"`
 	MaxFailures        int               `json:"max-failures,omitempty"`
+	CacheBypass        bool              `json:"cache-bypass,omitempty"`
 }
 
 type Tasks []Task
@@ -249,9 +251,11 @@ func SchedulerTaskFromTask(t core.Task) Task {
 		LastRunTimestamp:   t.LastRunTime().Unix(),
 		HitCount:           int(t.HitCount()),
 		MissCount:          int(t.MissedCount()),
+		DroppedCount:       int(t.DroppedCount()),
 		FailedCount:        int(t.FailedCount()),
 		LastFailureMessage: t.LastFailureMessage(),
 		TaskState:          t.State().String(),
+		CacheBypass:        t.CacheBypass(),
 	}
 	if st.LastRunTimestamp < 0 {
 		st.LastRunTimestamp = -1
@@ -275,5 +279,13 @@ func (t *Task) assertSchedule(s schedule.Schedule) {
 			Interval: v.Entry(),
 		}
 		return
+	case *schedule.StreamingSchedule:
+		t.Schedule = &core.Schedule{
+			Type: "streaming",
+		}
+		if v.Interval > 0 {
+			t.Schedule.Interval = v.Interval.String()
+		}
+		return
 	}
 }
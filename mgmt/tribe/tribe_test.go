@@ -75,6 +75,13 @@ func (t *mockTask) Schedule() schedule.Schedule               { return nil }
 func (t *mockTask) MaxFailures() int                          { return 10 }
 func (t *mockTask) MaxMetricsBuffer() int64                   { return 0 }
 func (t *mockTask) SetMaxMetricsBuffer(int64)                 {}
+func (t *mockTask) CacheBypass() bool                         { return false }
+func (t *mockTask) SetCacheBypass(bool)                       {}
+func (t *mockTask) StreamBuffer() int                         { return 0 }
+func (t *mockTask) SetStreamBuffer(int)                       {}
+func (t *mockTask) StreamDropPolicy() string                  { return "" }
+func (t *mockTask) SetStreamDropPolicy(string)                {}
+func (t *mockTask) DroppedCount() uint                        { return 0 }
 func (t *mockTask) MaxCollectDuration() time.Duration         { return time.Second }
 func (t *mockTask) SetMaxCollectDuration(time.Duration)       {}
 
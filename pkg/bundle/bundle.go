@@ -0,0 +1,200 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bundle reads plugin bundles: gzipped tar archives containing the
+// plugin binary, its files, an optional detached signature of the binary and
+// a metadata manifest named manifest.json.
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// ManifestFile is the name of the manifest in a bundle
+	ManifestFile = "manifest.json"
+	// RootFS is the directory, inside the directory returned by Extract,
+	// where the files of a bundle are extracted
+	RootFS = "rootfs"
+)
+
+var (
+	// ErrManifestNotFound - Error message when a bundle has no manifest
+	ErrManifestNotFound = errors.New("Bundle manifest not found")
+	// ErrMissingExec - Error message when the manifest has no exec
+	ErrMissingExec = errors.New("Bundle manifest is missing exec")
+	// ErrExecNotFound - Error message when the exec of the manifest is not in the bundle
+	ErrExecNotFound = errors.New("Bundle exec not found")
+	// ErrSignatureNotFound - Error message when the signature of the manifest is not in the bundle
+	ErrSignatureNotFound = errors.New("Bundle signature not found")
+	// ErrUntar - Error message for error untarring file
+	ErrUntar = errors.New("Error untarring file")
+)
+
+var bundleLogger = log.WithField("_module", "bundle")
+
+// Manifest describes the content of a bundle
+type Manifest struct {
+	// Name of the plugin
+	Name string `json:"name"`
+	// Exec is the path of the plugin binary in the bundle followed by its
+	// arguments
+	Exec []string `json:"exec"`
+	// Signature is the path in the bundle of the detached signature of the
+	// plugin binary, if any
+	Signature string `json:"signature,omitempty"`
+}
+
+// IsBundle returns true if the file name has the extension of a bundle
+func IsBundle(fileName string) bool {
+	return strings.HasSuffix(fileName, ".tar.gz") || strings.HasSuffix(fileName, ".tgz")
+}
+
+// ReadManifest returns the manifest of the bundle
+func ReadManifest(f io.ReadSeeker) (*Manifest, error) {
+	var m *Manifest
+	err := walk(f, func(hdr *tar.Header, r io.Reader) error {
+		if Clean(hdr.Name) != ManifestFile {
+			return nil
+		}
+		m = &Manifest{}
+		return json.NewDecoder(r).Decode(m)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, ErrManifestNotFound
+	}
+	return m, nil
+}
+
+// Validate makes sure the bundle is valid: its manifest names an exec and
+// the exec and signature are files of the bundle.  Otherwise, an error is
+// returned
+func Validate(f io.ReadSeeker) error {
+	m, err := ReadManifest(f)
+	if err != nil {
+		return err
+	}
+	if len(m.Exec) == 0 || m.Exec[0] == "" {
+		return ErrMissingExec
+	}
+	files := map[string]bool{}
+	err = walk(f, func(hdr *tar.Header, _ io.Reader) error {
+		if hdr.Typeflag == tar.TypeReg || hdr.Typeflag == tar.TypeRegA {
+			files[Clean(hdr.Name)] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !files[Clean(m.Exec[0])] {
+		return fmt.Errorf("%v: %v", ErrExecNotFound, m.Exec[0])
+	}
+	if m.Signature != "" && !files[Clean(m.Signature)] {
+		return fmt.Errorf("%v: %v", ErrSignatureNotFound, m.Signature)
+	}
+	return nil
+}
+
+// Extract expands the bundle to the RootFS directory of a temporary
+// directory created in tempDir, returning the temporary directory path or an
+// error
+func Extract(f io.ReadSeeker, tempDir string) (string, error) {
+	dir, err := ioutil.TempDir(tempDir, "snap-bundle-")
+	if err != nil {
+		return "", err
+	}
+	root := filepath.Join(dir, RootFS)
+	bundleLogger.WithField("directory", dir).Debugf(
+		"Extracting bundle to temporary directory")
+	err = walk(f, func(hdr *tar.Header, r io.Reader) error {
+		file := filepath.Join(root, filepath.FromSlash(Clean(hdr.Name)))
+		switch hdr.Typeflag {
+		case tar.TypeReg, tar.TypeRegA:
+			if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+				return err
+			}
+			w, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0755)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(w, r); err != nil {
+				w.Close()
+				return err
+			}
+			return w.Close()
+		case tar.TypeDir:
+			return os.MkdirAll(file, 0755)
+		default:
+			return fmt.Errorf("%v (type: %d): %v", ErrUntar, hdr.Typeflag, hdr.Name)
+		}
+	})
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return dir, nil
+}
+
+// walk calls fn for every entry of the bundle
+func walk(f io.ReadSeeker, fn func(*tar.Header, io.Reader) error) error {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	g, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer g.Close()
+	tr := tar.NewReader(g)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(hdr, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// Clean returns the slash separated path of a bundle entry relative to the
+// root of the bundle, so that no entry is extracted outside of it.  The paths
+// named by the manifest are cleaned the same way before they are used.
+func Clean(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
@@ -0,0 +1,94 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func newBundle(files map[string]string) *bytes.Reader {
+	var b bytes.Buffer
+	g := gzip.NewWriter(&b)
+	tw := tar.NewWriter(g)
+	for name, content := range files {
+		So(tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}), ShouldBeNil)
+		_, err := tw.Write([]byte(content))
+		So(err, ShouldBeNil)
+	}
+	So(tw.Close(), ShouldBeNil)
+	So(g.Close(), ShouldBeNil)
+	return bytes.NewReader(b.Bytes())
+}
+
+func TestBundle(t *testing.T) {
+	Convey("IsBundle", t, func() {
+		So(IsBundle("snap-plugin-collector-mock1.tar.gz"), ShouldBeTrue)
+		So(IsBundle("snap-plugin-collector-mock1.tgz"), ShouldBeTrue)
+		So(IsBundle("snap-plugin-collector-mock1"), ShouldBeFalse)
+	})
+	Convey("Given a bundle", t, func() {
+		f := newBundle(map[string]string{
+			ManifestFile:                          `{"name": "mock1", "exec": ["bin/snap-plugin-collector-mock1"], "signature": "bin/snap-plugin-collector-mock1.asc"}`,
+			"bin/snap-plugin-collector-mock1":     "plugin",
+			"bin/snap-plugin-collector-mock1.asc": "signature",
+		})
+		Convey("The manifest is read", func() {
+			m, err := ReadManifest(f)
+			So(err, ShouldBeNil)
+			So(m.Name, ShouldEqual, "mock1")
+			So(m.Exec, ShouldResemble, []string{"bin/snap-plugin-collector-mock1"})
+			So(Validate(f), ShouldBeNil)
+		})
+		Convey("The files are extracted", func() {
+			dir, err := Extract(f, "")
+			So(err, ShouldBeNil)
+			defer os.RemoveAll(dir)
+			b, err := ioutil.ReadFile(filepath.Join(dir, RootFS, "bin", "snap-plugin-collector-mock1"))
+			So(err, ShouldBeNil)
+			So(string(b), ShouldEqual, "plugin")
+		})
+	})
+	Convey("Given invalid bundles", t, func() {
+		So(Validate(newBundle(map[string]string{"snap-plugin-collector-mock1": "plugin"})), ShouldEqual, ErrManifestNotFound)
+		So(Validate(newBundle(map[string]string{ManifestFile: `{"name": "mock1"}`})), ShouldEqual, ErrMissingExec)
+		So(Validate(newBundle(map[string]string{ManifestFile: `{"exec": ["mock1"]}`})), ShouldNotBeNil)
+	})
+	Convey("Entries are not extracted outside of the bundle", t, func() {
+		dir, err := Extract(newBundle(map[string]string{"../../escaped": "plugin"}), "")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		_, err = os.Stat(filepath.Join(dir, RootFS, "escaped"))
+		So(err, ShouldBeNil)
+	})
+	Convey("The paths of the manifest are cleaned into the bundle", t, func() {
+		So(Clean("../../../bin/snap-plugin-collector-mock1"), ShouldEqual, "bin/snap-plugin-collector-mock1")
+		So(Clean("/bin/../bin/snap-plugin-collector-mock1"), ShouldEqual, "bin/snap-plugin-collector-mock1")
+	})
+}
@@ -0,0 +1,176 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dirwatch notifies the changes of the files of a directory once
+// they settled, using inotify (or the equivalent of the platform).
+package dirwatch
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultSettleDelay is the time without change after which the change of a
+// file is notified, so that files being copied are notified once complete
+var DefaultSettleDelay = time.Second
+
+// Op is the kind of change of a file
+type Op int
+
+const (
+	// Changed - the file was created or written
+	Changed Op = iota
+	// Removed - the file was removed or renamed
+	Removed
+)
+
+func (o Op) String() string {
+	if o == Removed {
+		return "removed"
+	}
+	return "changed"
+}
+
+// Event is the change of a file of a watched directory
+type Event struct {
+	// Path of the file
+	Path string
+	Op   Op
+}
+
+// Watcher watches the files of a directory
+type Watcher struct {
+	sync.Mutex
+	dir     string
+	delay   time.Duration
+	handle  func(Event)
+	watcher *fsnotify.Watcher
+	pending map[string]*time.Timer
+	// serializes the calls to handle
+	handleMutex sync.Mutex
+	done        chan struct{}
+}
+
+var watchLogger = log.WithField("_module", "dirwatch")
+
+// Watch calls handle for every file of dir which changed or was removed, once
+// no other change of the file happened for delay.  Calls to handle are
+// serialized.
+func Watch(dir string, delay time.Duration, handle func(Event)) (*Watcher, error) {
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := fw.Add(dir); err != nil {
+		fw.Close()
+		return nil, err
+	}
+	w := &Watcher{
+		dir:     dir,
+		delay:   delay,
+		handle:  handle,
+		watcher: fw,
+		pending: map[string]*time.Timer{},
+		done:    make(chan struct{}),
+	}
+	go w.run()
+	return w, nil
+}
+
+// Close stops watching the directory, dropping the pending changes
+func (w *Watcher) Close() error {
+	w.Lock()
+	select {
+	case <-w.done:
+		w.Unlock()
+		return nil
+	default:
+	}
+	close(w.done)
+	for name, t := range w.pending {
+		t.Stop()
+		delete(w.pending, name)
+	}
+	w.Unlock()
+	return w.watcher.Close()
+}
+
+func (w *Watcher) run() {
+	for {
+		select {
+		case e, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			w.settle(e.Name)
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			watchLogger.WithFields(log.Fields{
+				"_block": "run",
+				"dir":    w.dir,
+			}).Error(err)
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// settle (re)starts the timer notifying the change of a file
+func (w *Watcher) settle(name string) {
+	w.Lock()
+	defer w.Unlock()
+	select {
+	case <-w.done:
+		return
+	default:
+	}
+	if t, ok := w.pending[name]; ok {
+		t.Reset(w.delay)
+		return
+	}
+	w.pending[name] = time.AfterFunc(w.delay, func() { w.notify(name) })
+}
+
+// notify calls handle with the current state of a file
+func (w *Watcher) notify(name string) {
+	w.Lock()
+	select {
+	case <-w.done:
+		w.Unlock()
+		return
+	default:
+	}
+	delete(w.pending, name)
+	w.Unlock()
+
+	e := Event{Path: filepath.Clean(name), Op: Changed}
+	if _, err := os.Stat(name); os.IsNotExist(err) {
+		e.Op = Removed
+	}
+	w.handleMutex.Lock()
+	defer w.handleMutex.Unlock()
+	w.handle(e)
+}
@@ -0,0 +1,62 @@
+// +build medium
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dirwatch
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWatch(t *testing.T) {
+	Convey("Given a watched directory", t, func() {
+		dir, err := ioutil.TempDir("", "snap-dirwatch-")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		events := make(chan Event, 10)
+		w, err := Watch(dir, 100*time.Millisecond, func(e Event) { events <- e })
+		So(err, ShouldBeNil)
+		defer w.Close()
+		file := filepath.Join(dir, "task.yaml")
+
+		Convey("Successive writes of a file are notified once", func() {
+			for i := 0; i < 3; i++ {
+				So(ioutil.WriteFile(file, []byte("task"), 0644), ShouldBeNil)
+			}
+			So(<-events, ShouldResemble, Event{Path: file, Op: Changed})
+			select {
+			case e := <-events:
+				t.Fatalf("unexpected event %v", e)
+			case <-time.After(300 * time.Millisecond):
+			}
+
+			Convey("The removal of a file is notified", func() {
+				So(os.Remove(file), ShouldBeNil)
+				So(<-events, ShouldResemble, Event{Path: file, Op: Removed})
+			})
+		})
+	})
+}
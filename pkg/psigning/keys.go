@@ -0,0 +1,241 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package psigning
+
+import (
+	"bufio"
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+const (
+	// AlgorithmEd25519 - ed25519 signature of the whole file
+	AlgorithmEd25519 = "ed25519"
+	// AlgorithmECDSA - ASN.1 DER ECDSA signature of the SHA-2 digest of the
+	// file, the digest size follows the curve size
+	AlgorithmECDSA = "ecdsa"
+
+	// pemKeyIDHeader sets the key ID of a PEM public key, the key ID is its
+	// fingerprint otherwise
+	pemKeyIDHeader = "Key-Id"
+	// pemCommentHeader sets the identity of a PEM public key
+	pemCommentHeader = "Comment"
+)
+
+var (
+	// ErrUnknownAlgorithm - Error message for an unsupported signature algorithm
+	ErrUnknownAlgorithm = errors.New("Unknown signature algorithm")
+	// ErrBadKeySignature - Error message for a malformed ed25519 or ECDSA signature
+	ErrBadKeySignature = errors.New("Malformed signature")
+	// ErrUnsupportedKey - Error message for a PEM public key neither ed25519 nor ECDSA
+	ErrUnsupportedKey = errors.New("Unsupported public key")
+
+	oidEd25519 = asn1.ObjectIdentifier{1, 3, 101, 112}
+)
+
+// KeySignature is the detached signature of a plugin made with an ed25519 or
+// ECDSA key, stored as JSON in the signature file of the plugin
+type KeySignature struct {
+	Algorithm string `json:"algorithm"`
+	// KeyID of the signing key, all the keys are tried if empty
+	KeyID string `json:"key_id,omitempty"`
+	// Signature encoded in standard base64
+	Signature string `json:"signature"`
+}
+
+// publicKey is a PEM encoded ed25519 or ECDSA public key
+type publicKey struct {
+	keyID     string
+	algorithm string
+	identity  string
+	key       crypto.PublicKey
+}
+
+type subjectPublicKeyInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+func isKeySignature(signature []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(signature), []byte("{"))
+}
+
+// isPEMKeyFile returns true when f holds PEM public keys, f is rewound
+func isPEMKeyFile(f io.ReadSeeker) bool {
+	defer f.Seek(0, 0)
+	line, _ := bufio.NewReader(f).ReadString('\n')
+	return strings.HasPrefix(strings.TrimSpace(line), "-----BEGIN PUBLIC KEY-----")
+}
+
+func verifyKeySignature(keyringFiles []string, signedFile string, signature []byte) (*Signer, error) {
+	var ks KeySignature
+	if err := json.Unmarshal(signature, &ks); err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrBadKeySignature, err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(ks.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrBadKeySignature, err)
+	}
+	if ks.Algorithm != AlgorithmEd25519 && ks.Algorithm != AlgorithmECDSA {
+		return nil, fmt.Errorf("%v: %v", ErrUnknownAlgorithm, ks.Algorithm)
+	}
+	signed, err := ioutil.ReadFile(signedFile)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v\n%v", ErrSignedFileNotFound, signedFile, err)
+	}
+	for _, keyringFile := range keyringFiles {
+		keys, err := readPublicKeys(keyringFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range keys {
+			if k.algorithm != ks.Algorithm || (ks.KeyID != "" && !strings.EqualFold(k.keyID, ks.KeyID)) {
+				continue
+			}
+			if k.verify(signed, sig) {
+				return &Signer{
+					KeyID:     k.keyID,
+					Algorithm: k.algorithm,
+					Identity:  k.identity,
+				}, nil
+			}
+		}
+	}
+	return nil, ErrCheckSignature
+}
+
+// readPublicKeys returns the PEM public keys of a keyring file, an OpenPGP
+// keyring has none
+func readPublicKeys(keyringFile string) ([]publicKey, error) {
+	b, err := ioutil.ReadFile(keyringFile)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v\n%v", ErrKeyringFileNotFound, keyringFile, err)
+	}
+	if !isPEMKeyFile(bytes.NewReader(b)) {
+		return nil, nil
+	}
+	var keys []publicKey
+	for {
+		var block *pem.Block
+		block, b = pem.Decode(b)
+		if block == nil {
+			break
+		}
+		if block.Type != "PUBLIC KEY" {
+			continue
+		}
+		k, err := parsePublicKey(block)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v\n%v", ErrUnableToReadKeyring, keyringFile, err)
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+func parsePublicKey(block *pem.Block) (publicKey, error) {
+	k := publicKey{
+		keyID:    strings.ToUpper(block.Headers[pemKeyIDHeader]),
+		identity: block.Headers[pemCommentHeader],
+	}
+	if k.keyID == "" {
+		k.keyID = Fingerprint(block.Bytes)
+	}
+	var spki subjectPublicKeyInfo
+	if _, err := asn1.Unmarshal(block.Bytes, &spki); err != nil {
+		return k, err
+	}
+	if spki.Algorithm.Algorithm.Equal(oidEd25519) {
+		if len(spki.PublicKey.Bytes) != ed25519.PublicKeySize {
+			return k, ErrUnsupportedKey
+		}
+		k.algorithm = AlgorithmEd25519
+		k.key = ed25519.PublicKey(spki.PublicKey.Bytes)
+		return k, nil
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return k, err
+	}
+	ecdsaPub, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		return k, ErrUnsupportedKey
+	}
+	k.algorithm = AlgorithmECDSA
+	k.key = ecdsaPub
+	return k, nil
+}
+
+func (k publicKey) verify(signed, sig []byte) bool {
+	switch pub := k.key.(type) {
+	case ed25519.PublicKey:
+		return ed25519.Verify(pub, signed, sig)
+	case *ecdsa.PublicKey:
+		var esig struct {
+			R, S *big.Int
+		}
+		if rest, err := asn1.Unmarshal(sig, &esig); err != nil || len(rest) != 0 {
+			return false
+		}
+		return ecdsa.Verify(pub, digest(pub.Curve, signed), esig.R, esig.S)
+	}
+	return false
+}
+
+// digest returns the SHA-2 digest of data matching the size of curve
+func digest(curve elliptic.Curve, data []byte) []byte {
+	switch size := curve.Params().BitSize; {
+	case size <= 256:
+		sum := sha256.Sum256(data)
+		return sum[:]
+	case size <= 384:
+		sum := sha512.Sum384(data)
+		return sum[:]
+	default:
+		sum := sha512.Sum512(data)
+		return sum[:]
+	}
+}
+
+// Fingerprint returns the key ID of a DER encoded public key, the first
+// 8 bytes of its SHA-256 digest in upper case hexadecimal
+func Fingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	return strings.ToUpper(hex.EncodeToString(sum[:8]))
+}
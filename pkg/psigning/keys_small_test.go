@@ -0,0 +1,213 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package psigning
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/crypto/ed25519"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func writeFile(dir, name string, b []byte) string {
+	p := filepath.Join(dir, name)
+	So(ioutil.WriteFile(p, b, 0644), ShouldBeNil)
+	return p
+}
+
+func keySignature(algorithm, keyID string, sig []byte) []byte {
+	b, err := json.Marshal(KeySignature{
+		Algorithm: algorithm,
+		KeyID:     keyID,
+		Signature: base64.StdEncoding.EncodeToString(sig),
+	})
+	So(err, ShouldBeNil)
+	return b
+}
+
+func TestKeySignatures(t *testing.T) {
+	s := SigningManager{}
+	Convey("Given a plugin and PEM public keys", t, func() {
+		dir, err := ioutil.TempDir("", "psigning")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		content := []byte("plugin content")
+		signed := writeFile(dir, "snap-plugin-collector-test", content)
+
+		edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+		So(err, ShouldBeNil)
+		edDER, err := asn1.Marshal(subjectPublicKeyInfo{
+			Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidEd25519},
+			PublicKey: asn1.BitString{Bytes: edPub, BitLength: 8 * len(edPub)},
+		})
+		So(err, ShouldBeNil)
+		edKeyring := writeFile(dir, "ed25519.pem", pem.EncodeToMemory(&pem.Block{
+			Type:    "PUBLIC KEY",
+			Headers: map[string]string{pemCommentHeader: "snap team"},
+			Bytes:   edDER,
+		}))
+
+		ecPriv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		So(err, ShouldBeNil)
+		ecDER, err := x509.MarshalPKIXPublicKey(&ecPriv.PublicKey)
+		So(err, ShouldBeNil)
+		ecKeyring := writeFile(dir, "ecdsa.pem", pem.EncodeToMemory(&pem.Block{
+			Type:    "PUBLIC KEY",
+			Headers: map[string]string{pemKeyIDHeader: "0badc0de"},
+			Bytes:   ecDER,
+		}))
+		keyrings := []string{edKeyring, ecKeyring}
+
+		Convey("An ed25519 signature is verified", func() {
+			sig := keySignature(AlgorithmEd25519, "", ed25519.Sign(edPriv, content))
+			signer, err := s.Verify(keyrings, signed, sig)
+			So(err, ShouldBeNil)
+			So(signer.Algorithm, ShouldEqual, AlgorithmEd25519)
+			So(signer.KeyID, ShouldEqual, Fingerprint(edDER))
+			So(signer.Identity, ShouldEqual, "snap team")
+		})
+		Convey("An ECDSA signature is verified", func() {
+			digest := sha256.Sum256(content)
+			r, ss, err := ecdsa.Sign(rand.Reader, ecPriv, digest[:])
+			So(err, ShouldBeNil)
+			der, err := asn1.Marshal(struct{ R, S interface{} }{r, ss})
+			So(err, ShouldBeNil)
+			signer, err := s.Verify(keyrings, signed, keySignature(AlgorithmECDSA, "0BADC0DE", der))
+			So(err, ShouldBeNil)
+			So(signer.Algorithm, ShouldEqual, AlgorithmECDSA)
+			So(signer.KeyID, ShouldEqual, "0BADC0DE")
+		})
+		Convey("A signature of another content is rejected", func() {
+			sig := keySignature(AlgorithmEd25519, "", ed25519.Sign(edPriv, []byte("other content")))
+			_, err := s.Verify(keyrings, signed, sig)
+			So(err, ShouldEqual, ErrCheckSignature)
+		})
+		Convey("A signature with another key ID is rejected", func() {
+			sig := keySignature(AlgorithmEd25519, "0BADC0DE", ed25519.Sign(edPriv, content))
+			_, err := s.Verify(keyrings, signed, sig)
+			So(err, ShouldEqual, ErrCheckSignature)
+		})
+		Convey("An unknown algorithm is rejected", func() {
+			_, err := s.Verify(keyrings, signed, keySignature("rsa", "", []byte("sig")))
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestTrustPolicy(t *testing.T) {
+	Convey("Given a trust policy", t, func() {
+		dir, err := ioutil.TempDir("", "psigning")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		writeFile(dir, "revoked", []byte("# compromised\n\n00000000DEADBEEF\n"))
+		policy := writeFile(dir, "policy.yaml", []byte(`
+plugins:
+  - name: collector:mock*
+    signers: [0123456789abcdef]
+  - name: file
+    signers: [FEDCBA9876543210]
+  - name: processor:*
+    signers: [AAAABBBBCCCCDDDDEEEEFFFF0000111122223333]
+revoked_keys: [1111111111111111]
+revocation_lists: [revoked]
+`))
+		tp, err := LoadTrustPolicy(policy)
+		So(err, ShouldBeNil)
+		So(tp.RevokedKeys, ShouldResemble, []string{"1111111111111111", "00000000DEADBEEF"})
+
+		Convey("Allowed signers are matched by key ID", func() {
+			So(tp.Allowed("collector", "mock1", &Signer{KeyID: "0123456789ABCDEF"}), ShouldBeNil)
+			So(tp.Allowed("publisher", "file", &Signer{KeyID: "FEDCBA9876543210"}), ShouldBeNil)
+		})
+		Convey("Allowed signers are matched by fingerprint", func() {
+			So(tp.Allowed("processor", "passthru", &Signer{KeyID: "0000111122223333"}), ShouldBeNil)
+		})
+		Convey("Short key IDs of signers do not match", func() {
+			So(tp.Allowed("collector", "mock1", &Signer{KeyID: "89ABCDEF"}), ShouldNotBeNil)
+		})
+		Convey("Other signers are rejected", func() {
+			So(tp.Allowed("collector", "mock1", &Signer{KeyID: "FEDCBA9876543210"}), ShouldNotBeNil)
+		})
+		Convey("Unsigned plugins with a rule are rejected", func() {
+			So(tp.Allowed("collector", "mock1", nil), ShouldEqual, ErrPluginNotSigned)
+		})
+		Convey("Plugins without a rule are allowed", func() {
+			So(tp.Allowed("streaming-collector", "rand", nil), ShouldBeNil)
+			So(tp.Allowed("publisher", "mock", &Signer{KeyID: "FEDCBA9876543210"}), ShouldBeNil)
+		})
+		Convey("Revoked keys are rejected", func() {
+			So(tp.Revoked("00000000DEADBEEF"), ShouldBeTrue)
+			So(tp.Allowed("publisher", "mock", &Signer{KeyID: "1111111111111111"}), ShouldNotBeNil)
+		})
+		Convey("Plugins of unknown name are allowed when a rule allows their signer", func() {
+			So(tp.AllowedByAnyRule(&Signer{KeyID: "FEDCBA9876543210"}), ShouldBeNil)
+			So(tp.AllowedByAnyRule(&Signer{KeyID: "0000111122223333"}), ShouldBeNil)
+			So(tp.AllowedByAnyRule(&Signer{KeyID: "2222222222222222"}), ShouldNotBeNil)
+			So(tp.AllowedByAnyRule(&Signer{KeyID: "00000000DEADBEEF"}), ShouldNotBeNil)
+			So(tp.AllowedByAnyRule(nil), ShouldEqual, ErrPluginNotSigned)
+		})
+		Convey("Plugins of unknown name are allowed by a policy without rules", func() {
+			n := &TrustPolicy{RevokedKeys: []string{"1111111111111111"}}
+			So(n.AllowedByAnyRule(&Signer{KeyID: "2222222222222222"}), ShouldBeNil)
+			So(n.AllowedByAnyRule(&Signer{KeyID: "1111111111111111"}), ShouldNotBeNil)
+		})
+		Convey("A nil policy allows everything", func() {
+			var n *TrustPolicy
+			So(n.Allowed("collector", "mock1", nil), ShouldBeNil)
+			So(n.AllowedByAnyRule(nil), ShouldBeNil)
+			So(n.Revoked("00000000DEADBEEF"), ShouldBeFalse)
+		})
+	})
+	Convey("A policy with a short key ID is rejected", t, func() {
+		dir, err := ioutil.TempDir("", "psigning")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		policy := writeFile(dir, "policy.json", []byte(`{"plugins": [{"name": "mock", "signers": ["89ABCDEF"]}]}`))
+		_, err = LoadTrustPolicy(policy)
+		So(err, ShouldNotBeNil)
+		policy = writeFile(dir, "policy.json", []byte(`{"revoked_keys": ["DEADBEEF"]}`))
+		_, err = LoadTrustPolicy(policy)
+		So(err, ShouldNotBeNil)
+	})
+	Convey("A policy with a bad pattern is rejected", t, func() {
+		dir, err := ioutil.TempDir("", "psigning")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		policy := writeFile(dir, "policy.json", []byte(`{"plugins": [{"name": "[mock", "signers": ["89ABCDEF"]}]}`))
+		_, err = LoadTrustPolicy(policy)
+		So(err, ShouldNotBeNil)
+	})
+}
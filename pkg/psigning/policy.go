@@ -0,0 +1,228 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package psigning
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+var (
+	// ErrKeyRevoked - Error message for a plugin signed with a revoked key
+	ErrKeyRevoked = errors.New("Plugin signed with a revoked key")
+	// ErrSignerNotAllowed - Error message for a plugin signed with a key not allowed by the trust policy
+	ErrSignerNotAllowed = errors.New("Plugin signer not allowed by the trust policy")
+	// ErrPluginNotSigned - Error message for an unsigned plugin the trust policy requires to be signed
+	ErrPluginNotSigned = errors.New("Plugin must be signed according to the trust policy")
+	// ErrShortKeyID - Error message for a key ID of a trust policy shorter than a long key ID
+	ErrShortKeyID = errors.New("Key IDs of a trust policy must be fingerprints or long key IDs of at least 16 hexadecimal digits")
+)
+
+// minKeyIDLength is the number of hexadecimal digits of a long key ID, the
+// shorter key IDs are too easily forged to identify a key
+const minKeyIDLength = 16
+
+// TrustPolicy maps plugins to the keys allowed to sign them.  The plugins
+// without a rule may be signed by any key of the keyrings.
+type TrustPolicy struct {
+	Plugins []PluginTrust `json:"plugins"`
+	// RevokedKeys lists the IDs of the keys no plugin may be signed with
+	RevokedKeys []string `json:"revoked_keys"`
+	// RevocationLists are files of revoked key IDs, one per line, relative
+	// to the policy file
+	RevocationLists []string `json:"revocation_lists"`
+}
+
+// PluginTrust is the rule of a trust policy for the plugins matching Name
+type PluginTrust struct {
+	// Name of the plugin or a pattern as accepted by path.Match, optionally
+	// prefixed by the plugin type as in collector:mock
+	Name string `json:"name"`
+	// Signers are the key IDs allowed to sign the plugin, fingerprints or
+	// long key IDs, a key ID matches the keys it is a suffix of or which are
+	// a suffix of it
+	Signers []string `json:"signers"`
+}
+
+// LoadTrustPolicy reads a JSON or YAML trust policy and its revocation lists
+func LoadTrustPolicy(policyFile string) (*TrustPolicy, error) {
+	b, err := ioutil.ReadFile(policyFile)
+	if err != nil {
+		return nil, err
+	}
+	t := &TrustPolicy{}
+	if err := yaml.Unmarshal(b, t); err != nil {
+		return nil, fmt.Errorf("%v: %v", policyFile, err)
+	}
+	for _, pt := range t.Plugins {
+		if _, err := path.Match(pt.Name, ""); err != nil {
+			return nil, fmt.Errorf("%v: %v: %v", policyFile, pt.Name, err)
+		}
+		if err := checkKeyIDs(pt.Signers); err != nil {
+			return nil, fmt.Errorf("%v: %v: %v", policyFile, pt.Name, err)
+		}
+	}
+	for _, rl := range t.RevocationLists {
+		if !filepath.IsAbs(rl) {
+			rl = filepath.Join(filepath.Dir(policyFile), rl)
+		}
+		ids, err := readKeyIDs(rl)
+		if err != nil {
+			return nil, err
+		}
+		t.RevokedKeys = append(t.RevokedKeys, ids...)
+	}
+	if err := checkKeyIDs(t.RevokedKeys); err != nil {
+		return nil, fmt.Errorf("%v: %v", policyFile, err)
+	}
+	return t, nil
+}
+
+// checkKeyIDs returns an error for a key ID which is not a fingerprint or a
+// long key ID
+func checkKeyIDs(ids []string) error {
+	for _, id := range ids {
+		if len(id) < minKeyIDLength {
+			return fmt.Errorf("%v: %v", ErrShortKeyID, id)
+		}
+		if _, err := hex.DecodeString(id); err != nil {
+			return fmt.Errorf("%v: %v", ErrShortKeyID, id)
+		}
+	}
+	return nil
+}
+
+// Revoked returns true when the key is revoked
+func (t *TrustPolicy) Revoked(keyID string) bool {
+	if t == nil {
+		return false
+	}
+	for _, id := range t.RevokedKeys {
+		if matchKeyID(keyID, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// Allowed checks a plugin signed by signer, nil if unsigned, against the
+// first rule matching the plugin
+func (t *TrustPolicy) Allowed(typeName, name string, signer *Signer) error {
+	if t == nil {
+		return nil
+	}
+	if signer != nil && t.Revoked(signer.KeyID) {
+		return fmt.Errorf("%v: %v", ErrKeyRevoked, signer.KeyID)
+	}
+	for _, pt := range t.Plugins {
+		if !pt.matches(typeName, name) {
+			continue
+		}
+		if signer == nil {
+			return ErrPluginNotSigned
+		}
+		for _, id := range pt.Signers {
+			if matchKeyID(signer.KeyID, id) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%v: %v", ErrSignerNotAllowed, signer.KeyID)
+	}
+	return nil
+}
+
+// AllowedByAnyRule checks a plugin of unknown type and name signed by signer,
+// nil if unsigned, against every rule since any of them could match the
+// plugin.  The signer must be allowed by one of the rules, unless the policy
+// has none.
+func (t *TrustPolicy) AllowedByAnyRule(signer *Signer) error {
+	if t == nil {
+		return nil
+	}
+	if signer != nil && t.Revoked(signer.KeyID) {
+		return fmt.Errorf("%v: %v", ErrKeyRevoked, signer.KeyID)
+	}
+	if len(t.Plugins) == 0 {
+		return nil
+	}
+	if signer == nil {
+		return ErrPluginNotSigned
+	}
+	for _, pt := range t.Plugins {
+		for _, id := range pt.Signers {
+			if matchKeyID(signer.KeyID, id) {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%v: %v", ErrSignerNotAllowed, signer.KeyID)
+}
+
+func (pt PluginTrust) matches(typeName, name string) bool {
+	pattern := pt.Name
+	if i := strings.Index(pattern, ":"); i >= 0 {
+		if pattern[:i] != typeName {
+			return false
+		}
+		pattern = pattern[i+1:]
+	}
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+// matchKeyID returns true when the key ID of a signer and a key ID of the
+// policy are the same key, one being the long key ID and the other the
+// fingerprint of the key.  Short key IDs never match.
+func matchKeyID(keyID, id string) bool {
+	if len(keyID) < minKeyIDLength || len(id) < minKeyIDLength {
+		return false
+	}
+	keyID, id = strings.ToUpper(keyID), strings.ToUpper(id)
+	return strings.HasSuffix(keyID, id) || strings.HasSuffix(id, keyID)
+}
+
+// readKeyIDs reads a revocation list, one key ID per line, blank lines and
+// lines starting with # are skipped
+func readKeyIDs(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var ids []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ids = append(ids, line)
+	}
+	return ids, scanner.Err()
+}
@@ -45,15 +45,45 @@ var (
 	ErrCheckSignature = errors.New("Error checking signature")
 )
 
+// Signer describes the key a plugin was signed with
+type Signer struct {
+	// KeyID of the signing key, in upper case hexadecimal
+	KeyID string `json:"key_id"`
+	// Algorithm of the signature: openpgp, ed25519 or ecdsa
+	Algorithm string `json:"algorithm"`
+	// Identity of the owner of the key, if known
+	Identity string `json:"identity,omitempty"`
+}
+
 //ValidateSignature is exported for plugin authoring
 func (s *SigningManager) ValidateSignature(keyringFiles []string, signedFile string, signature []byte) error {
+	signer, err := s.Verify(keyringFiles, signedFile, signature)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Signature made %v using %v key ID %v\nGood signature from %v\n", time.Now().Format(time.RFC1123), signer.Algorithm, signer.KeyID, signer.Identity)
+	return nil
+}
+
+// Verify checks the detached signature of signedFile against the keys of
+// keyringFiles and returns the signer.  The signature is either an armored
+// OpenPGP signature or an ed25519/ECDSA signature (see KeySignature), the
+// keyring files either OpenPGP keyrings or PEM encoded public keys.
+func (s *SigningManager) Verify(keyringFiles []string, signedFile string, signature []byte) (*Signer, error) {
+	if isKeySignature(signature) {
+		return verifyKeySignature(keyringFiles, signedFile, signature)
+	}
+	return verifyPGPSignature(keyringFiles, signedFile, signature)
+}
+
+func verifyPGPSignature(keyringFiles []string, signedFile string, signature []byte) (*Signer, error) {
 	var signedby string
 	var e error
 	var checked *openpgp.Entity
 
 	signed, err := os.Open(signedFile)
 	if err != nil {
-		return fmt.Errorf("%v: %v\n%v", ErrSignedFileNotFound, signedFile, err)
+		return nil, fmt.Errorf("%v: %v\n%v", ErrSignedFileNotFound, signedFile, err)
 	}
 	defer signed.Close()
 
@@ -61,17 +91,22 @@ func (s *SigningManager) ValidateSignature(keyringFiles []string, signedFile str
 	for _, keyringFile := range keyringFiles {
 		keyringf, err := os.Open(keyringFile)
 		if err != nil {
-			return fmt.Errorf("%v: %v\n%v", ErrKeyringFileNotFound, keyringFile, err)
+			return nil, fmt.Errorf("%v: %v\n%v", ErrKeyringFileNotFound, keyringFile, err)
 		}
 		defer keyringf.Close()
 
+		// PEM public keys are used by the ed25519 and ECDSA signatures
+		if isPEMKeyFile(keyringf) {
+			continue
+		}
+
 		//Read both armored and unarmored keyrings
 		keyring, err := openpgp.ReadArmoredKeyRing(keyringf)
 		if err != nil {
 			keyringf.Seek(0, 0)
 			keyring, err = openpgp.ReadKeyRing(keyringf)
 			if err != nil {
-				return fmt.Errorf("%v: %v\n%v", ErrUnableToReadKeyring, keyringFile, err)
+				return nil, fmt.Errorf("%v: %v\n%v", ErrUnableToReadKeyring, keyringFile, err)
 			}
 		}
 
@@ -81,10 +116,13 @@ func (s *SigningManager) ValidateSignature(keyringFiles []string, signedFile str
 			for k := range checked.Identities {
 				signedby = signedby + k
 			}
-			fmt.Printf("Signature made %v using RSA key ID %v\nGood signature from %v\n", time.Now().Format(time.RFC1123), checked.PrimaryKey.KeyIdShortString(), signedby)
-			return nil
+			return &Signer{
+				KeyID:     checked.PrimaryKey.KeyIdString(),
+				Algorithm: "openpgp",
+				Identity:  signedby,
+			}, nil
 		}
 		signed.Seek(0, 0)
 	}
-	return fmt.Errorf("%v\n%v", ErrCheckSignature, e)
+	return nil, fmt.Errorf("%v\n%v", ErrCheckSignature, e)
 }
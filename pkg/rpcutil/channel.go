@@ -0,0 +1,220 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rpcutil
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// CompressionGzip is the name of the gzip compressor
+	CompressionGzip = "gzip"
+	// CompressionSnappy is the name of the snappy compressor
+	CompressionSnappy = "snappy"
+	// CompressionZstd is the name of the zstd compressor
+	CompressionZstd = "zstd"
+
+	// CompressionHeader is the header of the replies of a gRPC server served
+	// with AnnounceCompression naming the compressor it accepts
+	CompressionHeader = "snap-compression"
+)
+
+var (
+	// ErrUnknownCompression - error message when a compressor was not registered
+	ErrUnknownCompression = errors.New("unknown compression")
+
+	compressionsMutex sync.RWMutex
+	compressions      = map[string]compression{
+		CompressionGzip: {
+			compressor:   grpc.NewGZIPCompressor,
+			decompressor: grpc.NewGZIPDecompressor,
+		},
+		CompressionSnappy: {
+			compressor:   func() grpc.Compressor { return snappyCompressor{} },
+			decompressor: func() grpc.Decompressor { return snappyDecompressor{} },
+		},
+		CompressionZstd: {
+			compressor:   func() grpc.Compressor { return zstdCompressor{} },
+			decompressor: func() grpc.Decompressor { return zstdDecompressor{} },
+		},
+	}
+
+	// the zstd encoder and decoder are safe for concurrent use by EncodeAll
+	// and DecodeAll
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil)
+)
+
+type compression struct {
+	compressor   func() grpc.Compressor
+	decompressor func() grpc.Decompressor
+}
+
+// RegisterCompression makes a compressor available to gRPC channels under the
+// given name.  Gzip, snappy and zstd are registered by default.
+func RegisterCompression(name string, compressor func() grpc.Compressor, decompressor func() grpc.Decompressor) {
+	compressionsMutex.Lock()
+	defer compressionsMutex.Unlock()
+	compressions[name] = compression{compressor: compressor, decompressor: decompressor}
+}
+
+func getCompression(name string) (compression, bool) {
+	compressionsMutex.RLock()
+	defer compressionsMutex.RUnlock()
+	c, ok := compressions[name]
+	return c, ok
+}
+
+// IsCompression returns whether a compressor is registered under the given
+// name.
+func IsCompression(name string) bool {
+	_, ok := getCompression(name)
+	return ok
+}
+
+// snappyCompressor compresses the messages of a channel in the snappy block
+// format.
+type snappyCompressor struct{}
+
+func (snappyCompressor) Do(w io.Writer, p []byte) error {
+	_, err := w.Write(snappy.Encode(nil, p))
+	return err
+}
+
+func (snappyCompressor) Type() string {
+	return CompressionSnappy
+}
+
+type snappyDecompressor struct{}
+
+func (snappyDecompressor) Do(r io.Reader) ([]byte, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return snappy.Decode(nil, b)
+}
+
+func (snappyDecompressor) Type() string {
+	return CompressionSnappy
+}
+
+// zstdCompressor compresses the messages of a channel in the zstd format.
+type zstdCompressor struct{}
+
+func (zstdCompressor) Do(w io.Writer, p []byte) error {
+	_, err := w.Write(zstdEncoder.EncodeAll(p, nil))
+	return err
+}
+
+func (zstdCompressor) Type() string {
+	return CompressionZstd
+}
+
+type zstdDecompressor struct{}
+
+func (zstdDecompressor) Do(r io.Reader) ([]byte, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return zstdDecoder.DecodeAll(b, nil)
+}
+
+func (zstdDecompressor) Type() string {
+	return CompressionZstd
+}
+
+// NegotiateCompression returns the first compressor of preferred which is
+// registered and accepted by the other side of a channel, or an empty string
+// when there is none and the channel is not compressed.
+func NegotiateCompression(preferred, accepted []string) string {
+	for _, p := range preferred {
+		if _, ok := getCompression(p); !ok {
+			continue
+		}
+		for _, a := range accepted {
+			if a == p {
+				return p
+			}
+		}
+	}
+	return ""
+}
+
+// ChannelDialOptions returns the options dialing a gRPC channel compressing
+// its calls with the given compressor and raising the maximum size of its
+// messages.  An empty compression leaves calls uncompressed and a
+// maxMessageSize of 0 keeps the gRPC default.
+func ChannelDialOptions(compression string, maxMessageSize int) ([]grpc.DialOption, error) {
+	var opts []grpc.DialOption
+	if compression != "" {
+		c, ok := getCompression(compression)
+		if !ok {
+			return nil, ErrUnknownCompression
+		}
+		opts = append(opts, grpc.WithCompressor(c.compressor()), grpc.WithDecompressor(c.decompressor()))
+	}
+	if maxMessageSize > 0 {
+		opts = append(opts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxMessageSize), grpc.MaxCallSendMsgSize(maxMessageSize)))
+	}
+	return opts, nil
+}
+
+// ChannelServerOptions returns the options of a gRPC server accepting the
+// calls of clients dialed with ChannelDialOptions.  Its replies are not
+// compressed, so clients which did not install the compressor can still call
+// the server.
+func ChannelServerOptions(compression string, maxMessageSize int) ([]grpc.ServerOption, error) {
+	var opts []grpc.ServerOption
+	if compression != "" {
+		c, ok := getCompression(compression)
+		if !ok {
+			return nil, ErrUnknownCompression
+		}
+		opts = append(opts, grpc.RPCDecompressor(c.decompressor()))
+	}
+	if maxMessageSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(maxMessageSize), grpc.MaxSendMsgSize(maxMessageSize))
+	}
+	return opts, nil
+}
+
+// AnnounceCompression returns the option of a gRPC server naming the
+// compressor it accepts, if any, in the CompressionHeader of the replies to
+// its unary calls, so that its clients can negotiate the compression of their
+// calls.
+func AnnounceCompression(compression string) grpc.ServerOption {
+	return grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if compression != "" {
+			grpc.SetHeader(ctx, metadata.Pairs(CompressionHeader, compression))
+		}
+		return handler(ctx, req)
+	})
+}
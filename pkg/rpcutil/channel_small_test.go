@@ -0,0 +1,99 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rpcutil
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc"
+)
+
+func TestNegotiateCompression(t *testing.T) {
+	Convey("Given the compressors preferred by snap", t, func() {
+		RegisterCompression("test", grpc.NewGZIPCompressor, grpc.NewGZIPDecompressor)
+		preferred := []string{"unknown", "test", CompressionGzip}
+
+		Convey("the first registered one accepted by the plugin is used", func() {
+			So(NegotiateCompression(preferred, []string{CompressionGzip, "test"}), ShouldEqual, "test")
+			So(NegotiateCompression(preferred, []string{CompressionGzip}), ShouldEqual, CompressionGzip)
+		})
+		Convey("compressors which are not registered are skipped", func() {
+			So(NegotiateCompression(preferred, []string{"unknown"}), ShouldEqual, "")
+		})
+		Convey("calls are not compressed for a plugin accepting none", func() {
+			So(NegotiateCompression(preferred, nil), ShouldEqual, "")
+		})
+	})
+}
+
+func TestChannelOptions(t *testing.T) {
+	Convey("Given the settings of a channel", t, func() {
+		Convey("the defaults add no options", func() {
+			dopts, err := ChannelDialOptions("", 0)
+			So(err, ShouldBeNil)
+			So(dopts, ShouldBeEmpty)
+			sopts, err := ChannelServerOptions("", 0)
+			So(err, ShouldBeNil)
+			So(sopts, ShouldBeEmpty)
+		})
+		Convey("compression and message size add options", func() {
+			dopts, err := ChannelDialOptions(CompressionGzip, 1<<24)
+			So(err, ShouldBeNil)
+			So(len(dopts), ShouldEqual, 3)
+			sopts, err := ChannelServerOptions(CompressionGzip, 1<<24)
+			So(err, ShouldBeNil)
+			So(len(sopts), ShouldEqual, 3)
+		})
+		Convey("an unknown compressor is an error", func() {
+			_, err := ChannelDialOptions("unknown", 0)
+			So(err, ShouldEqual, ErrUnknownCompression)
+			_, err = ChannelServerOptions("unknown", 0)
+			So(err, ShouldEqual, ErrUnknownCompression)
+		})
+	})
+}
+
+func TestCompressions(t *testing.T) {
+	Convey("Given the built in compressors", t, func() {
+		msg := []byte(strings.Repeat("snap metric ", 1000))
+		for _, name := range []string{CompressionGzip, CompressionSnappy, CompressionZstd} {
+			So(IsCompression(name), ShouldBeTrue)
+			c, _ := getCompression(name)
+			Convey(name+" decompresses what it compressed", func() {
+				var b bytes.Buffer
+				So(c.compressor().Do(&b, msg), ShouldBeNil)
+				So(b.Len(), ShouldBeLessThan, len(msg))
+				got, err := c.decompressor().Do(&b)
+				So(err, ShouldBeNil)
+				So(got, ShouldResemble, msg)
+				So(c.compressor().Type(), ShouldEqual, name)
+				So(c.decompressor().Type(), ShouldEqual, name)
+			})
+		}
+		Convey("an unknown compressor is not registered", func() {
+			So(IsCompression("lz4"), ShouldBeFalse)
+		})
+	})
+}
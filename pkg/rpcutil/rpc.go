@@ -21,6 +21,7 @@ package rpcutil
 
 import (
 	"fmt"
+	"net"
 	"time"
 
 	"golang.org/x/net/context"
@@ -37,18 +38,34 @@ func GetClientConnection(ctx context.Context, addr string, port int) (*grpc.Clie
 }
 
 // GetClientConnectionWithCreds returns a grcp.ClientConn with optional TLS
-// security (if creds != nil)
-func GetClientConnectionWithCreds(ctx context.Context, addr string, port int, creds credentials.TransportCredentials) (*grpc.ClientConn, error) {
+// security (if creds != nil) and the given additional dial options
+func GetClientConnectionWithCreds(ctx context.Context, addr string, port int, creds credentials.TransportCredentials, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	return dial(ctx, fmt.Sprintf("%v:%v", addr, port), creds, opts...)
+}
+
+// GetUnixClientConnection returns a grpc.ClientConn to a server listening on
+// the Unix socket at path, with optional TLS security (if creds != nil) and
+// the given additional dial options.  A secured server is verified as
+// localhost.
+func GetUnixClientConnection(ctx context.Context, path string, creds credentials.TransportCredentials, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	opts = append(opts, grpc.WithDialer(func(_ string, timeout time.Duration) (net.Conn, error) {
+		return net.DialTimeout("unix", path, timeout)
+	}))
+	return dial(ctx, "localhost", creds, opts...)
+}
+
+func dial(ctx context.Context, target string, creds credentials.TransportCredentials, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
 	grpcDialOpts := []grpc.DialOption{
 		grpc.WithTimeout(grpcDialDefaultTimeout),
 	}
+	grpcDialOpts = append(grpcDialOpts, opts...)
 	if creds != nil {
 		grpcDialOpts = append(grpcDialOpts, grpc.WithTransportCredentials(creds))
 	} else {
 		grpcDialOpts = append(grpcDialOpts, grpc.WithInsecure())
 	}
 
-	conn, err := grpc.DialContext(ctx, fmt.Sprintf("%v:%v", addr, port), grpcDialOpts...)
+	conn, err := grpc.DialContext(ctx, target, grpcDialOpts...)
 	if err != nil {
 		return nil, err
 	}
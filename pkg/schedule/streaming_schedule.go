@@ -23,7 +23,10 @@ import "time"
 
 // StreamingSchedule is a schedule that only implements an endless repeating interval
 type StreamingSchedule struct {
-	state ScheduleState
+	// Interval on which the collectors of the task which are not streaming
+	// are polled, zero when the task only streams
+	Interval time.Duration
+	state    ScheduleState
 }
 
 // NewStreamingSchedule returns the SimpleSchedule given the time interval
@@ -31,24 +34,41 @@ func NewStreamingSchedule() *StreamingSchedule {
 	return &StreamingSchedule{}
 }
 
+// NewPollingStreamingSchedule returns a StreamingSchedule which also polls
+// the collectors of the task which are not streaming on the given interval
+func NewPollingStreamingSchedule(i time.Duration) *StreamingSchedule {
+	return &StreamingSchedule{Interval: i}
+}
+
 // GetState returns the schedule state
 func (s *StreamingSchedule) GetState() ScheduleState {
 	return Active
 }
 
-// Validate returns an error if the interval of schedule is less
-// or equals zero
+// Validate returns an error if the polling interval of the schedule is less
+// than zero
 func (s *StreamingSchedule) Validate() error {
+	if s.Interval < 0 {
+		return ErrInvalidInterval
+	}
 	return nil
 }
 
-// Wait returns the StreamingSchedule state, misses and the last schedule ran
+// Wait returns the StreamingSchedule state, misses and the last schedule ran.
+// It blocks until the next poll when the schedule has an interval.
 func (s *StreamingSchedule) Wait(last time.Time) Response {
-	return &StreamingScheduleResponse{}
+	if s.Interval == 0 {
+		return &StreamingScheduleResponse{}
+	}
+	missed, lastTime := waitOnInterval(last, s.Interval)
+	return &StreamingScheduleResponse{missed: missed, lastTime: lastTime}
 }
 
 // StreamingScheduleResponse a response from SimpleSchedule conforming to ScheduleResponse interface
-type StreamingScheduleResponse struct{}
+type StreamingScheduleResponse struct {
+	missed   uint
+	lastTime time.Time
+}
 
 // State returns the state of the Schedule
 func (s *StreamingScheduleResponse) State() ScheduleState {
@@ -62,10 +82,10 @@ func (s *StreamingScheduleResponse) Error() error {
 
 // Missed returns any missed intervals
 func (s *StreamingScheduleResponse) Missed() uint {
-	return 0
+	return s.missed
 }
 
 // LastTime returns the last response time
 func (s *StreamingScheduleResponse) LastTime() time.Time {
-	return time.Time{}
+	return s.lastTime
 }
@@ -0,0 +1,62 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestStreamingSchedule(t *testing.T) {
+	Convey("a streaming schedule without interval", t, func() {
+		s := NewStreamingSchedule()
+		So(s.Validate(), ShouldBeNil)
+		Convey("does not wait", func() {
+			r := s.Wait(time.Now())
+			So(r.State(), ShouldEqual, Active)
+			So(r.Missed(), ShouldEqual, 0)
+		})
+	})
+	Convey("a streaming schedule with a negative interval is invalid", t, func() {
+		s := NewPollingStreamingSchedule(-time.Second)
+		So(s.Validate(), ShouldEqual, ErrInvalidInterval)
+	})
+	Convey("a streaming schedule with an interval", t, func() {
+		s := NewPollingStreamingSchedule(50 * time.Millisecond)
+		So(s.Validate(), ShouldBeNil)
+		Convey("polls at once the first time", func() {
+			before := time.Now()
+			r := s.Wait(time.Time{})
+			So(r.State(), ShouldEqual, Active)
+			So(time.Since(before), ShouldBeLessThan, 50*time.Millisecond)
+		})
+		Convey("waits on the interval since the last poll", func() {
+			last := time.Now()
+			r := s.Wait(last)
+			So(r.State(), ShouldEqual, Active)
+			So(r.Missed(), ShouldEqual, 0)
+			So(r.LastTime().Sub(last), ShouldBeGreaterThanOrEqualTo, 50*time.Millisecond)
+		})
+	})
+}
@@ -0,0 +1,157 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/intelsdi-x/snap/core"
+	"github.com/intelsdi-x/snap/pkg/dirwatch"
+)
+
+// autoDiscoverStopTimeout is how long a task created from a modified or
+// removed file is waited for to stop before being removed
+const autoDiscoverStopTimeout = 10 * time.Second
+
+// autoDiscoveredTasks keeps track of the tasks created from the files of the
+// auto discover paths.  When watching is enabled, a task is created again
+// when its file is modified and removed when its file is removed.
+type autoDiscoveredTasks struct {
+	sync.Mutex
+	enabled bool
+	// task IDs keyed by task file path
+	tasks    map[string]string
+	watchers []*dirwatch.Watcher
+}
+
+func newAutoDiscoveredTasks(enabled bool) *autoDiscoveredTasks {
+	return &autoDiscoveredTasks{
+		enabled: enabled,
+		tasks:   map[string]string{},
+	}
+}
+
+// isTaskFile returns true for the JSON and YAML files, editors temporary
+// files are left out
+func isTaskFile(name string) bool {
+	if strings.HasPrefix(name, ".") {
+		return false
+	}
+	fname := strings.ToLower(name)
+	return strings.HasSuffix(fname, ".json") || strings.HasSuffix(fname, ".yaml") || strings.HasSuffix(fname, ".yml")
+}
+
+// watch records the tasks created from fullPath and starts watching it
+func (a *autoDiscoveredTasks) watch(s *scheduler, fullPath string, taskIDs map[string]string) {
+	if !a.enabled {
+		return
+	}
+	a.Lock()
+	defer a.Unlock()
+	for name, id := range taskIDs {
+		a.tasks[filepath.Join(fullPath, name)] = id
+	}
+	w, err := dirwatch.Watch(fullPath, dirwatch.DefaultSettleDelay, func(e dirwatch.Event) {
+		a.handle(s, fullPath, e)
+	})
+	if err != nil {
+		schedulerLogger.WithFields(log.Fields{
+			"_block":           "watch-autodiscover-path",
+			"autodiscoverpath": fullPath,
+		}).Error(err)
+		return
+	}
+	a.watchers = append(a.watchers, w)
+	schedulerLogger.WithFields(log.Fields{
+		"_block": "watch-autodiscover-path",
+	}).Info("watching tasks in: ", fullPath)
+}
+
+func (a *autoDiscoveredTasks) handle(s *scheduler, fullPath string, e dirwatch.Event) {
+	if !isTaskFile(filepath.Base(e.Path)) {
+		return
+	}
+	a.Lock()
+	defer a.Unlock()
+	if s.state != schedulerStarted {
+		return
+	}
+	logger := schedulerLogger.WithFields(log.Fields{
+		"_block":           "handle-autodiscover-event",
+		"autodiscoverpath": fullPath,
+		"task-file-name":   filepath.Base(e.Path),
+	})
+	if id, ok := a.tasks[e.Path]; ok {
+		delete(a.tasks, e.Path)
+		if err := s.removeAutoDiscoveredTask(id); err != nil {
+			logger.WithFields(log.Fields{
+				"task-id": id,
+			}).Error("removing task ", err)
+		} else {
+			logger.WithFields(log.Fields{
+				"task-id": id,
+			}).Info("task removed")
+		}
+	}
+	if e.Op == dirwatch.Removed {
+		return
+	}
+	file, err := os.Stat(e.Path)
+	if err != nil || file.IsDir() {
+		return
+	}
+	taskIDs := autoDiscoverTasks([]os.FileInfo{file}, fullPath, s.CreateTask)
+	for name, id := range taskIDs {
+		a.tasks[filepath.Join(fullPath, name)] = id
+	}
+}
+
+// close stops watching the auto discover paths
+func (a *autoDiscoveredTasks) close() {
+	a.Lock()
+	defer a.Unlock()
+	for _, w := range a.watchers {
+		w.Close()
+	}
+	a.watchers = nil
+}
+
+// removeAutoDiscoveredTask stops the task if needed and removes it
+func (s *scheduler) removeAutoDiscoveredTask(id string) error {
+	t, err := s.getTask(id)
+	if err != nil {
+		// already removed through the API
+		return nil
+	}
+	if t.State() == core.TaskSpinning || t.State() == core.TaskFiring {
+		s.stopTask(id, "autodiscover")
+	}
+	deadline := time.Now().Add(autoDiscoverStopTimeout)
+	for t.State() == core.TaskStopping && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+	}
+	return s.removeTask(id, "autodiscover")
+}
@@ -0,0 +1,46 @@
+// +build legacy
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAutoDiscoveredTasks(t *testing.T) {
+	Convey("Task files", t, func() {
+		So(isTaskFile("task.json"), ShouldBeTrue)
+		So(isTaskFile("task.YAML"), ShouldBeTrue)
+		So(isTaskFile("task.yml"), ShouldBeTrue)
+		So(isTaskFile(".task.json"), ShouldBeFalse)
+		So(isTaskFile("task.json.swp"), ShouldBeFalse)
+		So(isTaskFile("snap-plugin-collector-mock1"), ShouldBeFalse)
+	})
+	Convey("Watching disabled", t, func() {
+		a := newAutoDiscoveredTasks(false)
+		a.watch(nil, "/tmp", map[string]string{"task.json": "1234"})
+		So(a.tasks, ShouldBeEmpty)
+		So(a.watchers, ShouldBeEmpty)
+		a.close()
+	})
+}
@@ -37,6 +37,9 @@ const (
 type Config struct {
 	WorkManagerQueueSize uint `json:"work_manager_queue_size"yaml:"work_manager_queue_size"`
 	WorkManagerPoolSize  uint `json:"work_manager_pool_size"yaml:"work_manager_pool_size"`
+	// AutoDiscoverWatch is set from the control configuration, tasks files
+	// added, modified or removed in the auto discover paths are then handled
+	AutoDiscoverWatch bool `json:"-"yaml:"-"`
 }
 
 const (
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
 
 	"github.com/intelsdi-x/snap/core"
 	"github.com/intelsdi-x/snap/core/cdata"
@@ -81,6 +82,7 @@ type job interface {
 	Errors() []error
 	StartTime() time.Time
 	Deadline() time.Time
+	Stopped() <-chan struct{}
 	Name() string
 	Version() int
 	Type() jobType
@@ -99,16 +101,18 @@ type coreJob struct {
 	taskID    string
 	jtype     jobType
 	deadline  time.Time
+	stop      <-chan struct{}
 	starttime time.Time
 	errors    []error
 }
 
-func newCoreJob(t jobType, deadline time.Time, taskID string, name string, version int) *coreJob {
+func newCoreJob(t jobType, deadline time.Time, stop <-chan struct{}, taskID string, name string, version int) *coreJob {
 	return &coreJob{
 		jtype:     t,
 		name:      name,
 		version:   version,
 		deadline:  deadline,
+		stop:      stop,
 		taskID:    taskID,
 		errors:    make([]error, 0),
 		starttime: time.Now(),
@@ -123,6 +127,30 @@ func (c *coreJob) Deadline() time.Time {
 	return c.deadline
 }
 
+// Stopped returns the channel closed when the task owning the job is stopped
+// or killed. It is nil for jobs which cannot be stopped.
+func (c *coreJob) Stopped() <-chan struct{} {
+	return c.stop
+}
+
+// context returns the context handed to the plugins run by the job. It ends
+// at the job's deadline or as soon as the task owning the job is stopped,
+// whichever comes first, so plugins are told to give up on work nobody
+// waits for anymore.
+func (c *coreJob) context() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithDeadline(context.Background(), c.deadline)
+	if c.stop != nil {
+		go func() {
+			select {
+			case <-c.stop:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+	}
+	return ctx, cancel
+}
+
 func (c *coreJob) Name() string {
 	return c.name
 }
@@ -175,6 +203,7 @@ type collectorJob struct {
 func newCollectorJob(
 	metricTypes []core.RequestedMetric,
 	deadlineDuration time.Duration,
+	stop <-chan struct{},
 	collector collectsMetrics,
 	cdt *cdata.ConfigDataTree,
 	taskID string,
@@ -184,16 +213,17 @@ func newCollectorJob(
 		collector:      collector,
 		metricTypes:    metricTypes,
 		metrics:        []core.Metric{},
-		coreJob:        newCoreJob(collectJobType, time.Now().Add(deadlineDuration), taskID, "", 0),
+		coreJob:        newCoreJob(collectJobType, time.Now().Add(deadlineDuration), stop, taskID, "", 0),
 		configDataTree: cdt,
 		tags:           tags,
 	}
 }
 
 type metric struct {
-	namespace core.Namespace
-	version   int
-	config    *cdata.ConfigDataNode
+	namespace  core.Namespace
+	version    int
+	constraint *core.VersionConstraint
+	config     *cdata.ConfigDataNode
 }
 
 func (m *metric) Namespace() core.Namespace {
@@ -208,12 +238,17 @@ func (m *metric) Version() int {
 	return m.version
 }
 
+func (m *metric) VersionConstraint() *core.VersionConstraint {
+	return m.constraint
+}
+
 func (m *metric) Data() interface{}             { return nil }
 func (m *metric) Description() string           { return "" }
 func (m *metric) Unit() string                  { return "" }
 func (m *metric) Tags() map[string]string       { return nil }
 func (m *metric) LastAdvertisedTime() time.Time { return time.Unix(0, 0) }
 func (m *metric) Timestamp() time.Time          { return time.Unix(0, 0) }
+func (m *metric) Kind() core.MetricKind         { return core.UnknownMetricKind }
 
 func (c *collectorJob) Metrics() []core.Metric {
 	return c.metrics
@@ -240,7 +275,9 @@ func (c *collectorJob) Run() {
 		}
 	}
 
-	ret, errs := c.collector.CollectMetrics(c.TaskID(), c.tags)
+	ctx, cancel := c.context()
+	defer cancel()
+	ret, errs := c.collector.CollectMetrics(ctx, c.TaskID(), c.tags)
 
 	log.WithFields(log.Fields{
 		"_module":      "scheduler-job",
@@ -279,7 +316,7 @@ func newProcessJob(parentJob job, pluginName string, pluginVersion int, contentT
 	return &processJob{
 		parentJob: parentJob,
 		metrics:   []core.Metric{},
-		coreJob:   newCoreJob(processJobType, parentJob.Deadline(), taskID, pluginName, pluginVersion),
+		coreJob:   newCoreJob(processJobType, parentJob.Deadline(), parentJob.Stopped(), taskID, pluginName, pluginVersion),
 		config:    config,
 		processor: processor,
 	}
@@ -295,7 +332,9 @@ func (p *processJob) Run() {
 		"plugin-config":  p.config,
 	}).Debug("starting processor job")
 
-	mts, errs := p.processor.ProcessMetrics(p.parentJob.Metrics(), p.config, p.taskID, p.name, p.version)
+	ctx, cancel := p.context()
+	defer cancel()
+	mts, errs := p.processor.ProcessMetrics(ctx, p.parentJob.Metrics(), p.config, p.taskID, p.name, p.version)
 	if errs != nil {
 		for _, e := range errs {
 			log.WithFields(log.Fields{
@@ -328,7 +367,7 @@ func newPublishJob(parentJob job, pluginName string, pluginVersion int, contentT
 	return &publisherJob{
 		parentJob: parentJob,
 		publisher: publisher,
-		coreJob:   newCoreJob(publishJobType, parentJob.Deadline(), taskID, pluginName, pluginVersion),
+		coreJob:   newCoreJob(publishJobType, parentJob.Deadline(), parentJob.Stopped(), taskID, pluginName, pluginVersion),
 		config:    config,
 	}
 }
@@ -343,7 +382,9 @@ func (p *publisherJob) Run() {
 		"plugin-config":  p.config,
 	}).Debug("starting publisher job")
 
-	errs := p.publisher.PublishMetrics(p.parentJob.Metrics(), p.config, p.taskID, p.name, p.version)
+	ctx, cancel := p.context()
+	defer cancel()
+	errs := p.publisher.PublishMetrics(ctx, p.parentJob.Metrics(), p.config, p.taskID, p.name, p.version)
 	if errs != nil {
 		for _, e := range errs {
 			log.WithFields(log.Fields{
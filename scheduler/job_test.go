@@ -32,11 +32,12 @@ import (
 	"github.com/intelsdi-x/snap/core/serror"
 	log "github.com/sirupsen/logrus"
 	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/net/context"
 )
 
 type mockCollector struct{}
 
-func (m *mockCollector) CollectMetrics(string, map[string]map[string]string) ([]core.Metric, []error) {
+func (m *mockCollector) CollectMetrics(context.Context, string, map[string]map[string]string) ([]core.Metric, []error) {
 	return nil, nil
 }
 
@@ -44,6 +45,15 @@ func (m *mockCollector) ExpandWildcards(core.Namespace) ([]core.Namespace, serro
 	return nil, nil
 }
 
+// blockingCollector never replies and only returns once the context of the
+// collection ends.
+type blockingCollector struct{}
+
+func (m *blockingCollector) CollectMetrics(ctx context.Context, _ string, _ map[string]map[string]string) ([]core.Metric, []error) {
+	<-ctx.Done()
+	return nil, []error{ctx.Err()}
+}
+
 func TestCollectorJob(t *testing.T) {
 	log.SetLevel(log.FatalLevel)
 	cdt := cdata.NewTree()
@@ -51,37 +61,37 @@ func TestCollectorJob(t *testing.T) {
 	tags := map[string]map[string]string{}
 	Convey("newCollectorJob()", t, func() {
 		Convey("it returns an init-ed collectorJob", func() {
-			cj := newCollectorJob([]core.RequestedMetric{}, defaultDeadline, &mockCollector{}, cdt, "taskid", tags)
+			cj := newCollectorJob([]core.RequestedMetric{}, defaultDeadline, nil, &mockCollector{}, cdt, "taskid", tags)
 			So(cj, ShouldHaveSameTypeAs, &collectorJob{})
 		})
 	})
 	Convey("StartTime()", t, func() {
 		Convey("it should return the job starttime", func() {
-			cj := newCollectorJob([]core.RequestedMetric{}, defaultDeadline, &mockCollector{}, cdt, "taskid", tags)
+			cj := newCollectorJob([]core.RequestedMetric{}, defaultDeadline, nil, &mockCollector{}, cdt, "taskid", tags)
 			So(cj.StartTime(), ShouldHaveSameTypeAs, time.Now())
 		})
 	})
 	Convey("Deadline()", t, func() {
 		Convey("it should return the job daedline", func() {
-			cj := newCollectorJob([]core.RequestedMetric{}, defaultDeadline, &mockCollector{}, cdt, "taskid", tags)
+			cj := newCollectorJob([]core.RequestedMetric{}, defaultDeadline, nil, &mockCollector{}, cdt, "taskid", tags)
 			So(cj.Deadline(), ShouldResemble, cj.(*collectorJob).deadline)
 		})
 	})
 	Convey("Type()", t, func() {
 		Convey("it should return the job type", func() {
-			cj := newCollectorJob([]core.RequestedMetric{}, defaultDeadline, &mockCollector{}, cdt, "taskid", tags)
+			cj := newCollectorJob([]core.RequestedMetric{}, defaultDeadline, nil, &mockCollector{}, cdt, "taskid", tags)
 			So(cj.Type(), ShouldEqual, collectJobType)
 		})
 	})
 	Convey("Errors()", t, func() {
 		Convey("it should return the errors from the job", func() {
-			cj := newCollectorJob([]core.RequestedMetric{}, defaultDeadline, &mockCollector{}, cdt, "taskid", tags)
+			cj := newCollectorJob([]core.RequestedMetric{}, defaultDeadline, nil, &mockCollector{}, cdt, "taskid", tags)
 			So(cj.Errors(), ShouldResemble, []error{})
 		})
 	})
 	Convey("AddErrors()", t, func() {
 		Convey("it should append errors to the job", func() {
-			cj := newCollectorJob([]core.RequestedMetric{}, defaultDeadline, &mockCollector{}, cdt, "taskid", tags)
+			cj := newCollectorJob([]core.RequestedMetric{}, defaultDeadline, nil, &mockCollector{}, cdt, "taskid", tags)
 			So(cj.Errors(), ShouldResemble, []error{})
 
 			e1 := errors.New("1")
@@ -96,10 +106,33 @@ func TestCollectorJob(t *testing.T) {
 	})
 	Convey("Run()", t, func() {
 		Convey("it should complete without errors", func() {
-			cj := newCollectorJob([]core.RequestedMetric{}, defaultDeadline, &mockCollector{}, cdt, "taskid", tags)
+			cj := newCollectorJob([]core.RequestedMetric{}, defaultDeadline, nil, &mockCollector{}, cdt, "taskid", tags)
 			cj.(*collectorJob).Run()
 			So(cj.Errors(), ShouldResemble, []error{})
 		})
+		Convey("it should stop waiting on the collector past the job deadline", func() {
+			cj := newCollectorJob([]core.RequestedMetric{}, 50*time.Millisecond, nil, &blockingCollector{}, cdt, "taskid", tags)
+			cj.(*collectorJob).Run()
+			So(cj.Errors(), ShouldResemble, []error{context.DeadlineExceeded})
+		})
+		Convey("it should stop waiting on the collector when the task is stopped", func() {
+			stop := make(chan struct{})
+			cj := newCollectorJob([]core.RequestedMetric{}, defaultDeadline, stop, &blockingCollector{}, cdt, "taskid", tags)
+			time.AfterFunc(50*time.Millisecond, func() { close(stop) })
+			start := time.Now()
+			cj.(*collectorJob).Run()
+			So(time.Since(start), ShouldBeLessThan, defaultDeadline)
+			So(cj.Errors(), ShouldResemble, []error{context.Canceled})
+		})
+	})
+	Convey("newProcessJob()", t, func() {
+		Convey("it should inherit the deadline and stop channel of its parent", func() {
+			stop := make(chan struct{})
+			cj := newCollectorJob([]core.RequestedMetric{}, defaultDeadline, stop, &mockCollector{}, cdt, "taskid", tags)
+			pj := newProcessJob(cj, "passthru", 1, "", nil, nil, "taskid")
+			So(pj.Deadline(), ShouldResemble, cj.Deadline())
+			So(pj.Stopped(), ShouldEqual, cj.Stopped())
+		})
 	})
 }
 
@@ -109,14 +142,14 @@ func TestQueuedJob(t *testing.T) {
 	tags := map[string]map[string]string{}
 	Convey("Job()", t, func() {
 		Convey("it should return the underlying job", func() {
-			cj := newCollectorJob([]core.RequestedMetric{}, defaultDeadline, &mockCollector{}, cdt, "taskid", tags)
+			cj := newCollectorJob([]core.RequestedMetric{}, defaultDeadline, nil, &mockCollector{}, cdt, "taskid", tags)
 			qj := newQueuedJob(cj)
 			So(qj.Job(), ShouldEqual, cj)
 		})
 	})
 	Convey("Promise()", t, func() {
 		Convey("it should return the underlying promise", func() {
-			cj := newCollectorJob([]core.RequestedMetric{}, defaultDeadline, &mockCollector{}, cdt, "taskid", tags)
+			cj := newCollectorJob([]core.RequestedMetric{}, defaultDeadline, nil, &mockCollector{}, cdt, "taskid", tags)
 			qj := newQueuedJob(cj)
 			So(qj.Promise().IsComplete(), ShouldBeFalse)
 		})
@@ -30,6 +30,7 @@ import (
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
 
 	"github.com/ghodss/yaml"
 
@@ -70,8 +71,8 @@ var (
 	ErrTaskEndedNotStoppable = errors.New("Task is ended. Only running tasks can be stopped.")
 	// ErrPluginIncompatibleWithScheduleType - The error message for when a streaming schedule type references a non streaming plugin or vice versa.
 	ErrPluginIncompatibleWithScheduleType = errors.New("Plugin is incompatible with the tasks schedule type.")
-	// ErrMultipleStreamingPlugins - The error message when a task with a streaming schedule refers to multiple streaming plugins.
-	ErrMultipleStreamingPlugins = errors.New("Multiple streaming plugins within the same task is not supported.")
+	// ErrNoStreamingPlugins - The error message when a task with a streaming schedule refers to no streaming plugin.
+	ErrNoStreamingPlugins = errors.New("A task with a streaming schedule must refer to at least one streaming plugin.")
 )
 
 type schedulerState int
@@ -104,7 +105,7 @@ type managesMetrics interface {
 }
 
 type collectsMetrics interface {
-	CollectMetrics(string, map[string]map[string]string) ([]core.Metric, []error)
+	CollectMetrics(context.Context, string, map[string]map[string]string) ([]core.Metric, []error)
 }
 
 type streamsMetrics interface {
@@ -112,11 +113,11 @@ type streamsMetrics interface {
 }
 
 type publishesMetrics interface {
-	PublishMetrics([]core.Metric, map[string]ctypes.ConfigValue, string, string, int) []error
+	PublishMetrics(context.Context, []core.Metric, map[string]ctypes.ConfigValue, string, string, int) []error
 }
 
 type processesMetrics interface {
-	ProcessMetrics([]core.Metric, map[string]ctypes.ConfigValue, string, string, int) ([]core.Metric, []error)
+	ProcessMetrics(context.Context, []core.Metric, map[string]ctypes.ConfigValue, string, string, int) ([]core.Metric, []error)
 }
 
 type scheduler struct {
@@ -126,6 +127,9 @@ type scheduler struct {
 	state           schedulerState
 	eventManager    *gomit.EventController
 	taskWatcherColl *taskWatcherCollection
+
+	// tasks created from the auto discover paths
+	autoDiscover *autoDiscoveredTasks
 }
 
 type managesWork interface {
@@ -135,11 +139,13 @@ type managesWork interface {
 // Implemented as a separate function so that defer calls
 // are properly handled and cleanup done properly
 // (like for the removal of temporary Yaml to JSON conversion)
+// Returns the IDs of the created tasks keyed by task file name.
 func autoDiscoverTasks(taskFiles []os.FileInfo, fullPath string,
 	fp func(sch schedule.Schedule,
 		wfMap *wmap.WorkflowMap,
 		startOnCreate bool,
-		opts ...core.TaskOption) (core.Task, core.TaskErrors)) {
+		opts ...core.TaskOption) (core.Task, core.TaskErrors)) map[string]string {
+	taskIDs := map[string]string{}
 	// Note that the list of files is sorted by name due to ioutil.ReadDir
 	// default behaviour. See go doc ioutil.ReadDir
 	for _, file := range taskFiles {
@@ -234,7 +240,9 @@ func autoDiscoverTasks(taskFiles []os.FileInfo, fullPath string,
 			"task-file-name":   file.Name(),
 			"task-ID":          task.ID(),
 		}).Info("Loading task")
+		taskIDs[file.Name()] = task.ID()
 	}
+	return taskIDs
 }
 
 // New returns an instance of the scheduler
@@ -261,6 +269,7 @@ func New(cfg *Config) *scheduler {
 		tasks:           newTaskCollection(),
 		eventManager:    gomit.NewEventController(),
 		taskWatcherColl: newTaskWatcherCollection(),
+		autoDiscover:    newAutoDiscoveredTasks(cfg.AutoDiscoverWatch),
 	}
 
 	// we are setting the size of the queue and number of workers for
@@ -352,36 +361,44 @@ func (s *scheduler) createTask(sch schedule.Schedule, wfMap *wmap.WorkflowMap, s
 	for k, group := range depGroups {
 
 		// populate subscribedPluginAsserts
-		switch sch.(type) {
+		switch v := sch.(type) {
 		case *schedule.StreamingSchedule:
-			// assert no non-streaming plugins
+			if v.Interval == 0 {
+				// assert no non-streaming plugins, they are only polled
+				// when the schedule has an interval
+				subscribedPluginAsserts = append(subscribedPluginAsserts, func(plugins []core.SubscribedPlugin) serror.SnapError {
+					for _, plg := range plugins {
+						if plg.TypeName() != plugin.StreamCollectorPluginType.String() {
+							return serror.New(
+								ErrPluginIncompatibleWithScheduleType,
+								map[string]interface{}{
+									"schedule_type": fmt.Sprintf("%T", sch),
+									"plugin_name":   plg.Name(),
+									"plugin_type":   plg.TypeName(),
+								},
+							)
+						}
+					}
+					return nil
+				})
+			}
+			// assert at least one streaming plugin
 			subscribedPluginAsserts = append(subscribedPluginAsserts, func(plugins []core.SubscribedPlugin) serror.SnapError {
+				if len(plugins) == 0 {
+					return nil
+				}
 				for _, plg := range plugins {
-					if plg.TypeName() != plugin.StreamCollectorPluginType.String() {
-						return serror.New(
-							ErrPluginIncompatibleWithScheduleType,
-							map[string]interface{}{
-								"schedule_type": fmt.Sprintf("%T", sch),
-								"plugin_name":   plg.Name(),
-								"plugin_type":   plg.TypeName(),
-							},
-						)
+					if plg.TypeName() == plugin.StreamCollectorPluginType.String() {
+						return nil
 					}
 				}
-				return nil
-			})
-			// assert only a single streaming plugin
-			subscribedPluginAsserts = append(subscribedPluginAsserts, func(plugins []core.SubscribedPlugin) serror.SnapError {
-				if len(plugins) > 1 {
-					return serror.New(
-						ErrMultipleStreamingPlugins,
-						map[string]interface{}{
-							"schedule_type":     fmt.Sprintf("%T", sch),
-							"num_of_collectors": len(plugins),
-						},
-					)
-				}
-				return nil
+				return serror.New(
+					ErrNoStreamingPlugins,
+					map[string]interface{}{
+						"schedule_type":     fmt.Sprintf("%T", sch),
+						"num_of_collectors": len(plugins),
+					},
+				)
 			})
 		default:
 			// assert no streaming plugins
@@ -723,7 +740,8 @@ func (s *scheduler) Start() error {
 				}
 				taskFiles = append(taskFiles, file)
 			}
-			autoDiscoverTasks(taskFiles, fullPath, s.CreateTask)
+			taskIDs := autoDiscoverTasks(taskFiles, fullPath, s.CreateTask)
+			s.autoDiscover.watch(s, fullPath, taskIDs)
 		}
 	} else {
 		schedulerLogger.WithFields(log.Fields{
@@ -736,6 +754,7 @@ func (s *scheduler) Start() error {
 
 func (s *scheduler) Stop() {
 	s.state = schedulerStopped
+	s.autoDiscover.close()
 	// stop all tasks that are not already stopped
 	for _, t := range s.tasks.table {
 		// Kill ensure another task can't turn it back on while we are shutting down
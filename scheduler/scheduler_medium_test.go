@@ -28,6 +28,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/net/context"
 
 	"github.com/intelsdi-x/snap/core"
 	"github.com/intelsdi-x/snap/core/cdata"
@@ -50,16 +51,16 @@ func (m *mockMetricManager) StreamMetrics(string, map[string]map[string]string,
 	return nil, nil, nil
 }
 
-func (m *mockMetricManager) CollectMetrics(string, map[string]map[string]string) ([]core.Metric, []error) {
+func (m *mockMetricManager) CollectMetrics(context.Context, string, map[string]map[string]string) ([]core.Metric, []error) {
 	time.Sleep(m.timeToWait)
 	return nil, nil
 }
 
-func (m *mockMetricManager) PublishMetrics([]core.Metric, map[string]ctypes.ConfigValue, string, string, int) []error {
+func (m *mockMetricManager) PublishMetrics(context.Context, []core.Metric, map[string]ctypes.ConfigValue, string, string, int) []error {
 	return nil
 }
 
-func (m *mockMetricManager) ProcessMetrics([]core.Metric, map[string]ctypes.ConfigValue, string, string, int) ([]core.Metric, []error) {
+func (m *mockMetricManager) ProcessMetrics(context.Context, []core.Metric, map[string]ctypes.ConfigValue, string, string, int) ([]core.Metric, []error) {
 	return nil, nil
 }
 
@@ -29,6 +29,7 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/net/context"
 
 	"github.com/intelsdi-x/gomit"
 	"github.com/intelsdi-x/snap/core"
@@ -51,15 +52,15 @@ func (m *mockMetricManager) StreamMetrics(string, map[string]map[string]string,
 	return nil, nil, nil
 }
 
-func (m *mockMetricManager) CollectMetrics(string, map[string]map[string]string) ([]core.Metric, []error) {
+func (m *mockMetricManager) CollectMetrics(context.Context, string, map[string]map[string]string) ([]core.Metric, []error) {
 	return nil, nil
 }
 
-func (m *mockMetricManager) PublishMetrics([]core.Metric, map[string]ctypes.ConfigValue, string, string, int) []error {
+func (m *mockMetricManager) PublishMetrics(context.Context, []core.Metric, map[string]ctypes.ConfigValue, string, string, int) []error {
 	return nil
 }
 
-func (m *mockMetricManager) ProcessMetrics([]core.Metric, map[string]ctypes.ConfigValue, string, string, int) ([]core.Metric, []error) {
+func (m *mockMetricManager) ProcessMetrics(context.Context, []core.Metric, map[string]ctypes.ConfigValue, string, string, int) ([]core.Metric, []error) {
 	return nil, nil
 }
 func (m *mockMetricManager) ValidateDeps(mts []core.RequestedMetric, prs []core.SubscribedPlugin, cdt *cdata.ConfigDataTree, asserts ...core.SubscribedPluginAssert) []serror.SnapError {
@@ -284,6 +285,12 @@ func TestScheduler(t *testing.T) {
 			So(len(err.Errors()), ShouldEqual, 0)
 			So(tsk.(*task).maxMetricsBuffer, ShouldEqual, 100)
 		})
+		Convey("Returns a task bypassing the metric cache", func() {
+			sch := schedule.NewWindowedSchedule(time.Second*5, nil, nil, 0)
+			tsk, err := s.CreateTask(sch, w, false, core.SetCacheBypass(true))
+			So(len(err.Errors()), ShouldEqual, 0)
+			So(tsk.(*task).cacheBypass, ShouldBeTrue)
+		})
 	})
 	Convey("Stop()", t, func() {
 		Convey("Should set scheduler state to SchedulerStopped", func() {
@@ -0,0 +1,73 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import "github.com/intelsdi-x/snap/core"
+
+// streamBuffer holds the batches of metrics streamed to a task while its
+// workflow is busy with earlier ones.
+type streamBuffer struct {
+	batches chan []core.Metric
+	policy  string
+}
+
+func newStreamBuffer(size int, policy string) *streamBuffer {
+	if size < 1 {
+		size = 1
+	}
+	return &streamBuffer{
+		batches: make(chan []core.Metric, size),
+		policy:  policy,
+	}
+}
+
+// push adds a batch to the buffer following the drop policy of the task when
+// the buffer is full.  It returns the number of metrics dropped.
+func (b *streamBuffer) push(mts []core.Metric, stop <-chan struct{}) int {
+	switch b.policy {
+	case core.StreamDropNewest:
+		select {
+		case b.batches <- mts:
+			return 0
+		default:
+			return len(mts)
+		}
+	case core.StreamDropOldest:
+		dropped := 0
+		for {
+			select {
+			case b.batches <- mts:
+				return dropped
+			default:
+			}
+			select {
+			case old := <-b.batches:
+				dropped += len(old)
+			default:
+			}
+		}
+	default:
+		select {
+		case b.batches <- mts:
+		case <-stop:
+		}
+		return 0
+	}
+}
@@ -0,0 +1,105 @@
+// +build legacy
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+
+Copyright 2017 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/intelsdi-x/gomit"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"github.com/intelsdi-x/snap/control/plugin"
+	"github.com/intelsdi-x/snap/core"
+)
+
+func TestStreamBuffer(t *testing.T) {
+	batch := func(n int) []core.Metric {
+		mts := make([]core.Metric, n)
+		for i := range mts {
+			mts[i] = plugin.MetricType{}
+		}
+		return mts
+	}
+	stop := make(chan struct{})
+	Convey("Given a full stream buffer", t, func() {
+		Convey("dropping the oldest batch makes room for the new one", func() {
+			buf := newStreamBuffer(2, core.StreamDropOldest)
+			So(buf.push(batch(1), stop), ShouldEqual, 0)
+			So(buf.push(batch(2), stop), ShouldEqual, 0)
+			So(buf.push(batch(3), stop), ShouldEqual, 1)
+			So(<-buf.batches, ShouldHaveLength, 2)
+			So(<-buf.batches, ShouldHaveLength, 3)
+		})
+		Convey("dropping the newest batch keeps the buffered ones", func() {
+			buf := newStreamBuffer(2, core.StreamDropNewest)
+			So(buf.push(batch(1), stop), ShouldEqual, 0)
+			So(buf.push(batch(2), stop), ShouldEqual, 0)
+			So(buf.push(batch(3), stop), ShouldEqual, 3)
+			So(<-buf.batches, ShouldHaveLength, 1)
+			So(<-buf.batches, ShouldHaveLength, 2)
+		})
+		Convey("blocking waits for room without dropping", func() {
+			buf := newStreamBuffer(1, core.StreamDropBlock)
+			So(buf.push(batch(1), stop), ShouldEqual, 0)
+			pushed := make(chan int)
+			go func() { pushed <- buf.push(batch(2), stop) }()
+			So(<-buf.batches, ShouldHaveLength, 1)
+			So(<-pushed, ShouldEqual, 0)
+			So(<-buf.batches, ShouldHaveLength, 2)
+		})
+		Convey("blocking gives up once the task is stopped", func() {
+			buf := newStreamBuffer(1, core.StreamDropBlock)
+			So(buf.push(batch(1), stop), ShouldEqual, 0)
+			stopped := make(chan struct{})
+			close(stopped)
+			So(buf.push(batch(2), stopped), ShouldEqual, 0)
+			So(len(buf.batches), ShouldEqual, 1)
+		})
+	})
+	Convey("Given a streaming task draining its buffer", t, func() {
+		tsk := &task{
+			workflow: &schedulerWorkflow{eventEmitter: gomit.NewEventController()},
+			killChan: make(chan struct{}),
+		}
+		buf := newStreamBuffer(2, core.StreamDropBlock)
+		drained := make(chan struct{})
+		go tsk.drain(buf, drained)
+		defer close(drained)
+
+		Convey("each batch runs the workflow under the lock of the task", func() {
+			for i := 0; i < 10; i++ {
+				So(buf.push(batch(1), stop), ShouldEqual, 0)
+			}
+			hits := func() uint {
+				tsk.Lock()
+				defer tsk.Unlock()
+				return tsk.hitCount
+			}
+			deadline := time.Now().Add(5 * time.Second)
+			for hits() < 10 && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+			}
+			So(hits(), ShouldEqual, 10)
+		})
+	})
+}
@@ -25,6 +25,7 @@ import (
 	"net"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/intelsdi-x/gomit"
@@ -44,6 +45,8 @@ const (
 	DefaultDeadlineDuration = time.Second * 5
 	// DefaultStopOnFailure is used to set the number of failures before a task is disabled
 	DefaultStopOnFailure = 10
+	// DefaultStreamBuffer is the number of batches streamed to a task which are buffered while its workflow is busy
+	DefaultStreamBuffer = 16
 )
 
 var (
@@ -62,6 +65,10 @@ var (
 )
 
 type task struct {
+	// droppedCount is the number of streamed metrics dropped, it is updated
+	// atomically so is kept first for its alignment
+	droppedCount uint64
+
 	sync.Mutex //protects state
 
 	id                 string
@@ -89,6 +96,9 @@ type task struct {
 
 	maxCollectDuration time.Duration
 	maxMetricsBuffer   int64
+	cacheBypass        bool
+	streamBuffer       int
+	streamDropPolicy   string
 }
 
 //NewTask creates a Task
@@ -122,6 +132,8 @@ func newTask(s schedule.Schedule, wf *schedulerWorkflow, m *workManager, mm mana
 		eventEmitter:     emitter,
 		RemoteManagers:   mgrs,
 		isStream:         stream,
+		streamBuffer:     DefaultStreamBuffer,
+		streamDropPolicy: core.StreamDropBlock,
 	}
 	//set options
 	for _, opt := range opts {
@@ -156,6 +168,14 @@ func (t *task) SetMaxMetricsBuffer(i int64) {
 	t.maxMetricsBuffer = i
 }
 
+func (t *task) CacheBypass() bool {
+	return t.cacheBypass
+}
+
+func (t *task) SetCacheBypass(b bool) {
+	t.cacheBypass = b
+}
+
 //Returns the name of the task
 func (t *task) GetName() string {
 	return t.name
@@ -197,6 +217,36 @@ func (t *task) LastRunTime() *time.Time {
 	return &t.lastFireTime
 }
 
+// StreamBuffer returns the number of batches streamed to the task which are
+// buffered while its workflow is busy.
+func (t *task) StreamBuffer() int {
+	return t.streamBuffer
+}
+
+// SetStreamBuffer sets the number of batches streamed to the task which are
+// buffered while its workflow is busy.
+func (t *task) SetStreamBuffer(n int) {
+	t.streamBuffer = n
+}
+
+// StreamDropPolicy returns what the task does with the batches streamed to
+// it while its buffer is full.
+func (t *task) StreamDropPolicy() string {
+	return t.streamDropPolicy
+}
+
+// SetStreamDropPolicy sets what the task does with the batches streamed to
+// it while its buffer is full.
+func (t *task) SetStreamDropPolicy(p string) {
+	t.streamDropPolicy = p
+}
+
+// DroppedCount returns the number of streamed metrics the task dropped
+// because its buffer was full.
+func (t *task) DroppedCount() uint {
+	return uint(atomic.LoadUint64(&t.droppedCount))
+}
+
 // MissedCount returns the number of intervals missed.
 func (t *task) MissedCount() uint {
 	return t.missedIntervals
@@ -245,6 +295,10 @@ func (t *task) Spin() {
 		t.state = core.TaskSpinning
 		t.killChan = make(chan struct{})
 		go t.stream()
+		if sch := t.schedule.(*schedule.StreamingSchedule); sch.Interval > 0 {
+			t.lastFireTime = time.Time{}
+			go t.poll()
+		}
 		return
 	}
 
@@ -267,6 +321,10 @@ func (t *task) Spin() {
 func (t *task) stream() {
 	var consecutiveFailures int
 	resetTime := time.Second * 3
+	// the workflow runs on the batches buffered while the task keeps
+	// receiving from the stream
+	buf := newStreamBuffer(t.streamBuffer, t.streamDropPolicy)
+	go t.drain(buf, t.killChan)
 	for {
 		metricsChan, errChan, err := t.metricsManager.StreamMetrics(
 			t.id,
@@ -320,9 +378,17 @@ func (t *task) stream() {
 				if len(mts) == 0 {
 					continue
 				}
-				t.hitCount++
 				consecutiveFailures = 0
-				t.workflow.StreamStart(t, mts)
+				if dropped := buf.push(mts, t.killChan); dropped > 0 {
+					atomic.AddUint64(&t.droppedCount, uint64(dropped))
+					taskLogger.WithFields(log.Fields{
+						"_block":      "stream",
+						"task-id":     t.id,
+						"task-name":   t.name,
+						"drop-policy": t.streamDropPolicy,
+						"dropped":     dropped,
+					}).Warn("stream buffer full, metrics dropped")
+				}
 			case err := <-errChan:
 				taskLogger.WithFields(log.Fields{
 					"_block":    "stream",
@@ -354,6 +420,50 @@ func (t *task) stream() {
 	}
 }
 
+// poll fires the workflow of a streaming task on the interval of its
+// schedule, collecting the metrics of its collectors which are not streaming.
+// Stopping the task is left to the stream.
+func (t *task) poll() {
+	for {
+		go t.waitForSchedule()
+		select {
+		case sr := <-t.schResponseChan:
+			if sr.State() != schedule.Active {
+				return
+			}
+			t.missedIntervals += sr.Missed()
+			t.fire()
+			if t.lastFailureTime == t.lastFireTime {
+				taskLogger.WithFields(log.Fields{
+					"_block":    "poll",
+					"task-id":   t.id,
+					"task-name": t.name,
+					"error":     t.lastFailureMessage,
+				}).Warn("Task failed to poll its collectors")
+			}
+		case <-t.killChan:
+			return
+		}
+	}
+}
+
+// drain runs the workflow of a streaming task on the batches buffered for it
+// until the task is stopped.  The workflow runs under the lock of the task,
+// like the runs fired by poll.
+func (t *task) drain(buf *streamBuffer, stop <-chan struct{}) {
+	for {
+		select {
+		case mts := <-buf.batches:
+			t.Lock()
+			t.workflow.StreamStart(t, mts)
+			t.hitCount++
+			t.Unlock()
+		case <-stop:
+			return
+		}
+	}
+}
+
 func (t *task) Stop() {
 	t.Lock()
 	defer t.Unlock()
@@ -394,6 +504,28 @@ func (t *task) UnsubscribePlugins() []serror.SnapError {
 	return errs
 }
 
+// cacheBypasser is implemented by metric managers which can collect the
+// metrics of a task without serving them from the metric cache.
+type cacheBypasser interface {
+	SetCacheBypass(string, bool)
+}
+
+// bypassCache asks the metric manager to always collect the metrics of the
+// task from the plugins.
+func (t *task) bypassCache(key string, mgr managesMetrics) {
+	cb, ok := mgr.(cacheBypasser)
+	if !ok {
+		taskLogger.WithFields(log.Fields{
+			"_block":    "bypassCache",
+			"task-id":   t.id,
+			"task-name": t.name,
+			"manager":   key,
+		}).Warn("metric manager does not support bypassing the metric cache")
+		return
+	}
+	cb.SetCacheBypass(t.ID(), true)
+}
+
 // SubscribePlugins groups task dependencies by the node they live in workflow and subscribe them.
 // If there are errors with subscribing any deps, manage unsubscribing all other deps that may have already been subscribed
 // and then return the errors.
@@ -407,6 +539,9 @@ func (t *task) SubscribePlugins() ([]string, []serror.SnapError) {
 			errs = append(errs, serror.New(err))
 		} else {
 			errs = mgr.SubscribeDeps(t.ID(), depGroups[k].requestedMetrics, depGroups[k].subscribedPlugins, t.workflow.configTree)
+			if len(errs) == 0 && t.cacheBypass {
+				t.bypassCache(k, mgr)
+			}
 		}
 		// If there are errors with subscribing any deps, go through and unsubscribe all other
 		// deps that may have already been subscribed then return the errors.
@@ -663,10 +798,20 @@ func (t *taskCollection) Table() map[string]*task {
 // createTaskClients walks the workflowmap and creates clients for this task
 // remoteManagers so that nodes that require proxy request can make them.
 func createTaskClients(mgrs *managers, wf *schedulerWorkflow) error {
-	return walkWorkflow(wf.processNodes, wf.publishNodes, mgrs)
+	var channel controlproxy.Channel
+	if pc, ok := mgrs.local.(proxyChanneler); ok {
+		channel = pc.ProxyChannel()
+	}
+	return walkWorkflow(wf.processNodes, wf.publishNodes, mgrs, channel)
+}
+
+// proxyChanneler is implemented by metric managers which set the gRPC channels
+// of the proxies to remote metric managers.
+type proxyChanneler interface {
+	ProxyChannel() controlproxy.Channel
 }
 
-func walkWorkflow(prnodes []*processNode, pbnodes []*publishNode, mgrs *managers) error {
+func walkWorkflow(prnodes []*processNode, pbnodes []*publishNode, mgrs *managers, channel controlproxy.Channel) error {
 	for _, pr := range prnodes {
 		if pr.Target != "" {
 			host, port, err := net.SplitHostPort(pr.Target)
@@ -677,13 +822,13 @@ func walkWorkflow(prnodes []*processNode, pbnodes []*publishNode, mgrs *managers
 			if err != nil {
 				return err
 			}
-			proxy, err := controlproxy.New(host, p)
+			proxy, err := controlproxy.New(host, p, channel)
 			if err != nil {
 				return err
 			}
 			mgrs.Add(pr.Target, proxy)
 		}
-		err := walkWorkflow(pr.ProcessNodes, pr.PublishNodes, mgrs)
+		err := walkWorkflow(pr.ProcessNodes, pr.PublishNodes, mgrs, channel)
 		if err != nil {
 			return err
 		}
@@ -699,7 +844,7 @@ func walkWorkflow(prnodes []*processNode, pbnodes []*publishNode, mgrs *managers
 			if err != nil {
 				return err
 			}
-			proxy, err := controlproxy.New(host, p)
+			proxy, err := controlproxy.New(host, p, channel)
 			if err != nil {
 				return err
 			}
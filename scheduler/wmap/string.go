@@ -99,6 +99,9 @@ func (p *PublishWorkflowMapNode) String(pad string) string {
 	var out string
 	out += pad + fmt.Sprintf("   Name: %s\n", p.PluginName)
 	out += pad + fmt.Sprintf("   Version: %d\n", p.PluginVersion)
+	if p.Streaming {
+		out += pad + "   Streaming: true\n"
+	}
 
 	out += pad + "   Config:\n"
 	for k, v := range p.Config {
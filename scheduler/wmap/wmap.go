@@ -223,8 +223,9 @@ func (c *CollectWorkflowMapNode) GetMetrics() []Metric {
 		firstChar := stringutils.GetFirstChar(k)
 		ns := strings.Trim(k, firstChar)
 		metrics[i] = Metric{
-			namespace: strings.Split(ns, firstChar),
-			version:   v.Version_,
+			namespace:  strings.Split(ns, firstChar),
+			version:    v.Version_,
+			constraint: v.VersionConstraint,
 		}
 		i++
 	}
@@ -286,10 +287,13 @@ func (c *CollectWorkflowMapNode) AddConfigItem(ns, key string, value interface{}
 
 type ProcessWorkflowMapNode struct {
 	// required: true
-	PluginName    string                   `json:"plugin_name"yaml:"plugin_name"`
-	PluginVersion int                      `json:"plugin_version"yaml:"plugin_version"`
-	Process       []ProcessWorkflowMapNode `json:"process,omitempty"yaml:"process"`
-	Publish       []PublishWorkflowMapNode `json:"publish,omitempty"yaml:"publish"`
+	PluginName    string `json:"plugin_name"yaml:"plugin_name"`
+	PluginVersion int    `json:"plugin_version"yaml:"plugin_version"`
+	// PluginVersionConstraint restricts the versions of the plugin used when
+	// no version is given, e.g. ">=2,<4"
+	PluginVersionConstraint string                   `json:"plugin_version_constraint,omitempty"yaml:"plugin_version_constraint"`
+	Process                 []ProcessWorkflowMapNode `json:"process,omitempty"yaml:"process"`
+	Publish                 []PublishWorkflowMapNode `json:"publish,omitempty"yaml:"publish"`
 	// Config the configuration of a processor.
 	Config map[string]interface{} `json:"config,omitempty"yaml:"config"`
 	Target string                 `json:"target"yaml:"target"`
@@ -310,6 +314,10 @@ func (pw *ProcessWorkflowMapNode) UnmarshalJSON(data []byte) error {
 			if err := json.Unmarshal(v, &pw.PluginVersion); err != nil {
 				return fmt.Errorf("%v (while parsing 'plugin_version')", err)
 			}
+		case "plugin_version_constraint":
+			if err := json.Unmarshal(v, &pw.PluginVersionConstraint); err != nil {
+				return fmt.Errorf("%v (while parsing 'plugin_version_constraint')", err)
+			}
 		case "process":
 			if err := json.Unmarshal(v, &pw.Process); err != nil {
 				return err
@@ -372,10 +380,16 @@ type PublishWorkflowMapNode struct {
 	// required: true
 	PluginName    string `json:"plugin_name"yaml:"plugin_name"`
 	PluginVersion int    `json:"plugin_version"yaml:"plugin_version"`
+	// PluginVersionConstraint restricts the versions of the plugin used when
+	// no version is given, e.g. ">=2,<4"
+	PluginVersionConstraint string `json:"plugin_version_constraint,omitempty"yaml:"plugin_version_constraint"`
 	// required: true
 	// Config the config of a publisher
 	Config map[string]interface{} `json:"config,omitempty"yaml:"config"`
 	Target string                 `json:"target"yaml:"target"`
+	// Streaming sends the metrics to a streaming publisher over a stream
+	// kept open for the task instead of a call per batch
+	Streaming bool `json:"streaming,omitempty"yaml:"streaming"`
 }
 
 func (pw *PublishWorkflowMapNode) UnmarshalJSON(data []byte) error {
@@ -393,6 +407,10 @@ func (pw *PublishWorkflowMapNode) UnmarshalJSON(data []byte) error {
 			if err := json.Unmarshal(v, &pw.PluginVersion); err != nil {
 				return fmt.Errorf("%v (while parsing 'plugin_version')", err)
 			}
+		case "plugin_version_constraint":
+			if err := json.Unmarshal(v, &pw.PluginVersionConstraint); err != nil {
+				return fmt.Errorf("%v (while parsing 'plugin_version_constraint')", err)
+			}
 		case "config":
 			if err := json.Unmarshal(v, &pw.Config); err != nil {
 				return fmt.Errorf("%v (while parsing 'config')", err)
@@ -401,6 +419,10 @@ func (pw *PublishWorkflowMapNode) UnmarshalJSON(data []byte) error {
 			if err := json.Unmarshal(v, &pw.Target); err != nil {
 				return fmt.Errorf("%v (while parsing 'target')", err)
 			}
+		case "streaming":
+			if err := json.Unmarshal(v, &pw.Streaming); err != nil {
+				return fmt.Errorf("%v (while parsing 'streaming')", err)
+			}
 		default:
 			return fmt.Errorf("Unrecognized key '%v' in publish workflow of task.", k)
 		}
@@ -432,6 +454,9 @@ func (p *PublishWorkflowMapNode) GetConfigNode() (*cdata.ConfigDataNode, error)
 
 type metricInfo struct {
 	Version_ int `json:"version"yaml:"version"`
+	// VersionConstraint restricts the versions of the plugin exposing the
+	// metric used when no version is given, e.g. ">=2,<4"
+	VersionConstraint string `json:"version_constraint,omitempty"yaml:"version_constraint"`
 }
 
 func (m *metricInfo) UnmarshalJSON(data []byte) error {
@@ -445,6 +470,10 @@ func (m *metricInfo) UnmarshalJSON(data []byte) error {
 			if err := json.Unmarshal(v, &m.Version_); err != nil {
 				return fmt.Errorf("%v (while parsing 'version')", err)
 			}
+		case "version_constraint":
+			if err := json.Unmarshal(v, &m.VersionConstraint); err != nil {
+				return fmt.Errorf("%v (while parsing 'version_constraint')", err)
+			}
 		default:
 			return fmt.Errorf("Unrecognized key '%v' in metrics in collect workflow of task", k)
 		}
@@ -453,8 +482,9 @@ func (m *metricInfo) UnmarshalJSON(data []byte) error {
 }
 
 type Metric struct {
-	namespace []string
-	version   int
+	namespace  []string
+	version    int
+	constraint string
 }
 
 func (m Metric) Namespace() []string {
@@ -465,6 +495,11 @@ func (m Metric) Version() int {
 	return m.version
 }
 
+// VersionConstraint returns the version constraint declared for the metric.
+func (m Metric) VersionConstraint() string {
+	return m.constraint
+}
+
 func configtoConfigDataNode(cmap map[string]interface{}, ns string) (*cdata.ConfigDataNode, error) {
 	cdn := cdata.NewNode()
 	for ck, cv := range cmap {
@@ -208,5 +208,24 @@ func TestWorkflow(t *testing.T) {
 			So(err, ShouldNotBeNil)
 		})
 
+		Convey("Parses the version constraints", func() {
+			wmap, err := FromJson([]byte(`{
+				"collect": {
+					"metrics": {"/foo/bar": {"version_constraint": ">=2"}},
+					"process": [{
+						"plugin_name": "passthru",
+						"plugin_version_constraint": "<3",
+						"publish": [{"plugin_name": "file", "plugin_version_constraint": "1"}]
+					}]
+				}
+			}`))
+			So(err, ShouldBeNil)
+			mts := wmap.Collect.GetMetrics()
+			So(mts, ShouldHaveLength, 1)
+			So(mts[0].VersionConstraint(), ShouldEqual, ">=2")
+			So(wmap.Collect.Process[0].PluginVersionConstraint, ShouldEqual, "<3")
+			So(wmap.Collect.Process[0].Publish[0].PluginVersionConstraint, ShouldEqual, "1")
+		})
+
 	})
 }
@@ -80,12 +80,13 @@ func (mj *mockJob) AddErrors(errs ...error) {
 	defer mj.Unlock()
 	mj.errors = append(mj.errors, errs...)
 }
-func (mj *mockJob) Errors() []error      { return mj.errors }
-func (mj *mockJob) StartTime() time.Time { return mj.starttime }
-func (mj *mockJob) Deadline() time.Time  { return mj.deadline }
-func (mj *mockJob) Type() jobType        { return collectJobType }
-func (mj *mockJob) TypeString() string   { return "" }
-func (mj *mockJob) TaskID() string       { return "" }
+func (mj *mockJob) Errors() []error          { return mj.errors }
+func (mj *mockJob) StartTime() time.Time     { return mj.starttime }
+func (mj *mockJob) Deadline() time.Time      { return mj.deadline }
+func (mj *mockJob) Stopped() <-chan struct{} { return nil }
+func (mj *mockJob) Type() jobType            { return collectJobType }
+func (mj *mockJob) TypeString() string       { return "" }
+func (mj *mockJob) TaskID() string           { return "" }
 
 // Complete the first incomplete rendez-vous (if there is one)
 func (mj *mockJob) RendezVous() {
@@ -54,6 +54,7 @@ var (
 
 	ErrNullCollectNode        = errors.New("Missing collection node in workflow map")
 	ErrNoMetricsInCollectNode = errors.New("Collection node has not metrics defined to collect")
+	ErrVersionAndConstraint   = errors.New("A version and a version constraint cannot be both given")
 )
 
 // WmapToWorkflow attempts to convert a wmap.WorkflowMap to a schedulerWorkflow instance.
@@ -87,7 +88,15 @@ func convertCollectionNode(cnode *wmap.CollectWorkflowMapNode, wf *schedulerWork
 	mts := cnode.GetMetrics()
 	wf.metrics = make([]core.RequestedMetric, len(mts))
 	for i, m := range mts {
-		wf.metrics[i] = &metric{namespace: core.NewNamespace(m.Namespace()...), version: m.Version()}
+		constraint, err := versionConstraint(m.Version(), m.VersionConstraint())
+		if err != nil {
+			return err
+		}
+		wf.metrics[i] = &metric{
+			namespace:  core.NewNamespace(m.Namespace()...),
+			version:    m.Version(),
+			constraint: constraint,
+		}
 	}
 	// get tags defined
 	wf.tags = cnode.GetTags()
@@ -135,10 +144,15 @@ func convertProcessNode(pr []wmap.ProcessWorkflowMapNode) ([]*processNode, error
 		if p.PluginVersion < 1 {
 			p.PluginVersion = -1
 		}
+		constraint, err := versionConstraint(p.PluginVersion, p.PluginVersionConstraint)
+		if err != nil {
+			return nil, err
+		}
 		p.PluginName = strings.ToLower(p.PluginName)
 		prNodes[i] = &processNode{
 			name:         p.PluginName,
 			version:      p.PluginVersion,
+			constraint:   constraint,
 			config:       cdn,
 			Target:       p.Target,
 			ProcessNodes: prC,
@@ -162,17 +176,32 @@ func convertPublishNode(pu []wmap.PublishWorkflowMapNode) ([]*publishNode, error
 		if p.PluginVersion < 1 {
 			p.PluginVersion = -1
 		}
+		constraint, err := versionConstraint(p.PluginVersion, p.PluginVersionConstraint)
+		if err != nil {
+			return nil, err
+		}
 		p.PluginName = strings.ToLower(p.PluginName)
 		puNodes[i] = &publishNode{
-			name:    p.PluginName,
-			version: p.PluginVersion,
-			config:  cdn,
-			Target:  p.Target,
+			name:       p.PluginName,
+			version:    p.PluginVersion,
+			constraint: constraint,
+			config:     cdn,
+			Target:     p.Target,
+			streaming:  p.Streaming,
 		}
 	}
 	return puNodes, nil
 }
 
+// versionConstraint parses the version constraint of a plugin or a metric
+// which may only be given when no version is.
+func versionConstraint(version int, constraint string) (*core.VersionConstraint, error) {
+	if constraint != "" && version > 0 {
+		return nil, ErrVersionAndConstraint
+	}
+	return core.ParseVersionConstraint(constraint)
+}
+
 type schedulerWorkflow struct {
 	state WorkflowState
 	// Metrics to collect
@@ -190,6 +219,7 @@ type schedulerWorkflow struct {
 type processNode struct {
 	name               string
 	version            int
+	constraint         *core.VersionConstraint
 	config             *cdata.ConfigDataNode
 	Target             string
 	ProcessNodes       []*processNode
@@ -205,6 +235,10 @@ func (p *processNode) Version() int {
 	return p.version
 }
 
+func (p *processNode) VersionConstraint() *core.VersionConstraint {
+	return p.constraint
+}
+
 func (p *processNode) Config() *cdata.ConfigDataNode {
 	return p.config
 }
@@ -216,9 +250,12 @@ func (p *processNode) TypeName() string {
 type publishNode struct {
 	name               string
 	version            int
+	constraint         *core.VersionConstraint
 	config             *cdata.ConfigDataNode
 	Target             string
 	InboundContentType string
+	// streaming publishers are sent the metrics of the task over a stream
+	streaming bool
 }
 
 func (p *publishNode) Name() string {
@@ -229,11 +266,18 @@ func (p *publishNode) Version() int {
 	return p.version
 }
 
+func (p *publishNode) VersionConstraint() *core.VersionConstraint {
+	return p.constraint
+}
+
 func (p *publishNode) Config() *cdata.ConfigDataNode {
 	return p.config
 }
 
 func (p *publishNode) TypeName() string {
+	if p.streaming {
+		return core.StreamingPublisherPluginType.String()
+	}
 	return "publisher"
 }
 
@@ -247,7 +291,7 @@ func (s *schedulerWorkflow) Start(t *task) {
 		"task-name": t.name,
 	}).Debug("Starting workflow")
 	s.state = WorkflowStarted
-	j := newCollectorJob(s.metrics, t.deadlineDuration, t.metricsManager, t.workflow.configTree, t.id, s.tags)
+	j := newCollectorJob(s.metrics, t.deadlineDuration, t.killChan, t.metricsManager, t.workflow.configTree, t.id, s.tags)
 
 	// dispatch 'collect' job to be worked
 	// Block until the job has been either run or skipped.
@@ -285,7 +329,7 @@ func (s *schedulerWorkflow) StreamStart(t *task, metrics []core.Metric) {
 		collector:      t.metricsManager,
 		metricTypes:    []core.RequestedMetric{},
 		metrics:        metrics,
-		coreJob:        newCoreJob(collectJobType, time.Now().Add(t.deadlineDuration), t.id, "", 0),
+		coreJob:        newCoreJob(collectJobType, time.Now().Add(t.deadlineDuration), t.killChan, t.id, "", 0),
 		configDataTree: t.workflow.configTree,
 		tags:           t.workflow.tags,
 	}
@@ -40,11 +40,13 @@ import (
 	"golang.org/x/crypto/ssh/terminal"
 
 	"github.com/intelsdi-x/snap/control"
+	"github.com/intelsdi-x/snap/control/strategy"
 	"github.com/intelsdi-x/snap/core/serror"
 	"github.com/intelsdi-x/snap/mgmt/rest"
 	"github.com/intelsdi-x/snap/mgmt/tribe"
 	"github.com/intelsdi-x/snap/mgmt/tribe/agreement"
 	"github.com/intelsdi-x/snap/pkg/cfgfile"
+	"github.com/intelsdi-x/snap/pkg/rpcutil"
 	"github.com/intelsdi-x/snap/scheduler"
 	"google.golang.org/grpc/grpclog"
 )
@@ -323,6 +325,7 @@ func action(ctx *cli.Context) error {
 	// Set Max Processors for snapteld.
 	setMaxProcs(cfg.GoMaxProcs)
 
+	control.SnapVersion = gitversion
 	c := control.New(cfg.Control)
 	if c.Config.AutoDiscoverPath != "" && c.Config.IsTLSEnabled() {
 		log.Fatal("TLS security is not supported in autodiscovery mode")
@@ -331,6 +334,7 @@ func action(ctx *cli.Context) error {
 	coreModules = []coreModule{}
 
 	coreModules = append(coreModules, c)
+	cfg.Scheduler.AutoDiscoverWatch = cfg.Control.AutoDiscoverWatch
 	s := scheduler.New(cfg.Scheduler)
 	s.SetMetricManager(c)
 	coreModules = append(coreModules, s)
@@ -468,7 +472,7 @@ func action(ctx *cli.Context) error {
 					if keyringFile.IsDir() {
 						continue
 					}
-					if strings.HasSuffix(keyringFile.Name(), ".gpg") || (strings.HasSuffix(keyringFile.Name(), ".pub")) || (strings.HasSuffix(keyringFile.Name(), ".pubring")) {
+					if strings.HasSuffix(keyringFile.Name(), ".gpg") || (strings.HasSuffix(keyringFile.Name(), ".pub")) || (strings.HasSuffix(keyringFile.Name(), ".pubring")) || (strings.HasSuffix(keyringFile.Name(), ".pem")) {
 						f, err := os.Open(keyringPath)
 						if err != nil {
 							log.WithFields(
@@ -502,6 +506,19 @@ func action(ctx *cli.Context) error {
 			}
 		}
 	}
+	// Trust policy restricting the signers of each plugin
+	if cfg.Control.PluginTrust > 0 && cfg.Control.PluginTrustPolicy != "" {
+		if err := c.SetPluginTrustPolicy(cfg.Control.PluginTrustPolicy); err != nil {
+			log.WithFields(
+				log.Fields{
+					"block":       "main",
+					"_module":     logModule,
+					"error":       err.Error(),
+					"trustPolicy": cfg.Control.PluginTrustPolicy,
+				}).Fatal("unable to load plugin trust policy")
+		}
+		log.Info("setting plugin trust policy to: ", cfg.Control.PluginTrustPolicy)
+	}
 
 	log.WithFields(
 		log.Fields{
@@ -798,6 +815,14 @@ func checkCfgSettings(cfg *Config) (int, bool, error) {
 	if _, err := checkTLSEnabled(tlsCert, tlsKey, configFileErrorPrefix); err != nil {
 		return -1, false, err
 	}
+	if _, err := strategy.ParseHashKey(cfg.Control.RoutingHashKey); err != nil {
+		return -1, false, fmt.Errorf("%s %v", configFileErrorPrefix, err)
+	}
+	for _, name := range cfg.Control.PluginCompression {
+		if !rpcutil.IsCompression(name) {
+			return -1, false, fmt.Errorf("%s unknown plugin compression '%s'", configFileErrorPrefix, name)
+		}
+	}
 	addr := cfg.RestAPI.Address
 	var port int
 	if cfg.RestAPI.PortSetByConfigFile() {
@@ -848,8 +873,12 @@ func applyCmdLineFlags(cfg *Config, ctx runtimeFlagsContext) {
 	cfg.Control.PluginLoadTimeout = setIntVal(cfg.Control.PluginLoadTimeout, ctx, "plugin-load-timeout")
 	cfg.Control.PluginTrust = setIntVal(cfg.Control.PluginTrust, ctx, "plugin-trust")
 	cfg.Control.AutoDiscoverPath = setStringVal(cfg.Control.AutoDiscoverPath, ctx, "auto-discover")
+	cfg.Control.AutoDiscoverWatch = setBoolVal(cfg.Control.AutoDiscoverWatch, ctx, "auto-discover-watch")
 	cfg.Control.KeyringPaths = setStringVal(cfg.Control.KeyringPaths, ctx, "keyring-paths")
+	cfg.Control.PluginTrustPolicy = setStringVal(cfg.Control.PluginTrustPolicy, ctx, "plugin-trust-policy")
 	cfg.Control.CacheExpiration = jsonutil.Duration{setDurationVal(cfg.Control.CacheExpiration.Duration, ctx, "cache-expiration")}
+	cfg.Control.CacheMaxEntries = setIntVal(cfg.Control.CacheMaxEntries, ctx, "cache-max-entries")
+	cfg.Control.CacheMaxBytes = setIntVal(cfg.Control.CacheMaxBytes, ctx, "cache-max-bytes")
 	cfg.Control.ListenAddr = setStringVal(cfg.Control.ListenAddr, ctx, "control-listen-addr")
 	cfg.Control.ListenPort = setIntVal(cfg.Control.ListenPort, ctx, "control-listen-port")
 	cfg.Control.Pprof = setBoolVal(cfg.Control.Pprof, ctx, "pprof")
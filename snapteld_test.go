@@ -206,6 +206,11 @@ func (c *mockCfg) setCACertPaths(caCertPaths string) *mockCfg {
 	return c
 }
 
+func (c *mockCfg) setPluginCompression(compression ...string) *mockCfg {
+	c.Control.PluginCompression = compression
+	return c
+}
+
 func (c *mockCfg) setApiAddr(apiAddr string) *mockCfg {
 	c.RestAPI.Address = apiAddr
 	return c
@@ -364,6 +369,28 @@ func Test_checkCfgSettings(t *testing.T) {
 			wantErr:        true,
 			wantPort:       9000,
 			wantPortInAddr: true},
+		{name: "ConfigSettingsWithPluginCompressionValidateWell",
+			msg: func(f func(string)) {
+				f("Having config with the built in plugin compressors, config validation succeeds")
+			},
+			cfg: testCfg.getCopy().
+				setApiAddr("localhost:9000").
+				setPluginCompression("zstd", "snappy", "gzip").
+				export(),
+			wantErr:        false,
+			wantPort:       9000,
+			wantPortInAddr: true},
+		{name: "ConfigSettingsWithUnknownPluginCompression_Fail",
+			msg: func(f func(string)) {
+				f("Having config with an unknown plugin compressor, config fails to validate")
+			},
+			cfg: testCfg.getCopy().
+				setApiAddr("localhost:9000").
+				setPluginCompression("gzip", "lz4").
+				export(),
+			wantErr:        true,
+			wantPort:       9000,
+			wantPortInAddr: true},
 	}
 
 	for _, tc := range tests {